@@ -0,0 +1,528 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"github.com/src-doo/netbox-ssot/internal/audit"
+	"github.com/src-doo/netbox-ssot/internal/constants"
+	"github.com/src-doo/netbox-ssot/internal/netbox/inventory"
+	"github.com/src-doo/netbox-ssot/internal/netbox/objects"
+	"github.com/src-doo/netbox-ssot/internal/netbox/service"
+	"github.com/src-doo/netbox-ssot/internal/parser"
+)
+
+const defaultConfigPath = "config.yaml"
+
+// runSync syncs all (or only selected) sources to netbox.
+func runSync(args []string) error {
+	flags := flag.NewFlagSet("sync", flag.ExitOnError)
+	configPath := flags.String("config", defaultConfigPath, "Path to the configuration file")
+	var sourceNames sourceNamesFlag
+	flags.Var(
+		&sourceNames,
+		"source",
+		"Sync only the source with this name (repeatable). Orphans are not removed when set",
+	)
+	conflictsReport := flags.String(
+		"conflicts-report",
+		"",
+		"Write conflicts with existing netbox objects as json to this file",
+	)
+	recordDir, replayDir := snapshotFlags(flags)
+	_ = flags.Parse(args)
+	err := checkSnapshotFlags(*recordDir, *replayDir)
+	if err != nil {
+		return err
+	}
+
+	startTime := time.Now()
+	fmt.Printf("Netbox-SSOT has started at %s\n", startTime.Format(time.RFC3339))
+
+	config, err := parser.ParseConfig(*configPath)
+	if err != nil {
+		return fmt.Errorf("parser: %s", err)
+	}
+	a, err := newAppWithConfig(config, false, true)
+	if err != nil {
+		notifyFailure(config, startTime, err)
+		return err
+	}
+	defer a.close()
+	a.recordDir, a.replayDir = *recordDir, *replayDir
+	sourceConfigs, err := a.selectSources(sourceNames)
+	if err != nil {
+		a.notify(startTime, nil, err, service.WriteStats{}, 0)
+		return err
+	}
+
+	encounteredErrors := a.syncSources(sourceConfigs, false)
+	// Soft deletion of orphans also patches objects, so
+	// updates are counted before orphans are removed
+	writeStats := a.netboxInventory.NetboxAPI.WriteStats()
+	a.reportAdoption(len(sourceNames) > 0, encounteredErrors)
+	orphaned, err := a.cleanupOrphans(len(sourceNames) > 0, encounteredErrors)
+	if err == nil {
+		err = a.reportConflicts(*conflictsReport)
+	}
+	if err == nil {
+		err = a.finishBranch(encounteredErrors)
+	}
+	a.notify(startTime, encounteredErrors, err, writeStats, orphaned)
+	if err != nil {
+		return err
+	}
+
+	duration := time.Since(startTime)
+	minutes := int(duration.Minutes())
+	seconds := int((duration - time.Duration(minutes)*time.Minute).Seconds())
+	if len(encounteredErrors) > 0 {
+		for sourceName, err := range encounteredErrors {
+			a.logger.Infof(a.ctx, "%s syncing of source %s failed with: %v", constants.WarningSign, sourceName, err)
+		}
+		return fmt.Errorf("syncing of %d source(s) failed", len(encounteredErrors))
+	}
+	a.logger.Infof(
+		a.ctx,
+		"%s Syncing took %d min %d sec in total",
+		constants.Rocket,
+		minutes,
+		seconds,
+	)
+	return nil
+}
+
+// runPlan performs sync in dry run mode, and prints
+// all changes that would be written to netbox.
+func runPlan(args []string) error {
+	flags := flag.NewFlagSet("plan", flag.ExitOnError)
+	configPath := flags.String("config", defaultConfigPath, "Path to the configuration file")
+	asJSON := flags.Bool("json", false, "Print planned changes as json")
+	var sourceNames sourceNamesFlag
+	flags.Var(&sourceNames, "source", "Plan only the source with this name (repeatable)")
+	conflictsReport := flags.String(
+		"conflicts-report",
+		"",
+		"Write conflicts with existing netbox objects as json to this file",
+	)
+	recordDir, replayDir := snapshotFlags(flags)
+	_ = flags.Parse(args)
+	err := checkSnapshotFlags(*recordDir, *replayDir)
+	if err != nil {
+		return err
+	}
+
+	a, err := newApp(*configPath, true, false)
+	if err != nil {
+		return err
+	}
+	defer a.close()
+	a.recordDir, a.replayDir = *recordDir, *replayDir
+	sourceConfigs, err := a.selectSources(sourceNames)
+	if err != nil {
+		return err
+	}
+
+	encounteredErrors := a.syncSources(sourceConfigs, false)
+	a.reportAdoption(len(sourceNames) > 0, encounteredErrors)
+	_, err = a.cleanupOrphans(len(sourceNames) > 0, encounteredErrors)
+	if err != nil {
+		return err
+	}
+	err = a.reportConflicts(*conflictsReport)
+	if err != nil {
+		return err
+	}
+
+	err = printPlannedChanges(os.Stdout, a.netboxInventory.NetboxAPI.PlannedChanges(), *asJSON)
+	if err != nil {
+		return err
+	}
+	if len(encounteredErrors) > 0 {
+		for sourceName, err := range encounteredErrors {
+			fmt.Printf("%s source %s failed with: %v\n", constants.WarningSign, sourceName, err)
+		}
+		return fmt.Errorf("plan is incomplete, because %d source(s) failed", len(encounteredErrors))
+	}
+	return nil
+}
+
+// snapshotFlags defines flags for recording and replaying snapshots of sources.
+func snapshotFlags(flags *flag.FlagSet) (*string, *string) {
+	recordDir := flags.String(
+		"record",
+		"",
+		"Record data collected from each source to a sanitized snapshot <name>.json in this directory",
+	)
+	replayDir := flags.String(
+		"replay",
+		"",
+		"Load data of each source from its snapshot <name>.json in this directory instead of contacting it",
+	)
+	return recordDir, replayDir
+}
+
+// checkSnapshotFlags checks values of the snapshot flags and
+// creates the record directory, if it doesn't exist.
+func checkSnapshotFlags(recordDir string, replayDir string) error {
+	if recordDir != "" && replayDir != "" {
+		return errors.New("flags -record and -replay can't be used together")
+	}
+	if recordDir != "" {
+		err := os.MkdirAll(recordDir, 0o750) //nolint:mnd
+		if err != nil {
+			return fmt.Errorf("create record directory: %s", err)
+		}
+	}
+	return nil
+}
+
+// runValidate validates the config and checks connectivity and
+// credentials of netbox and all sources, without writing anything.
+func runValidate(args []string) error {
+	flags := flag.NewFlagSet("validate", flag.ExitOnError)
+	configPath := flags.String("config", defaultConfigPath, "Path to the configuration file")
+	_ = flags.Parse(args)
+
+	a, err := newApp(*configPath, true, false)
+	if err != nil {
+		return err
+	}
+	defer a.close()
+	fmt.Printf("%s config %s is valid\n", constants.CheckMark, *configPath)
+	fmt.Printf(
+		"%s netbox %s is reachable\n",
+		constants.CheckMark,
+		a.config.Netbox.Hostname,
+	)
+
+	sourceConfigs, err := a.selectSources(nil)
+	if err != nil {
+		return err
+	}
+	encounteredErrors := a.syncSources(sourceConfigs, true)
+	for _, sourceConfig := range sourceConfigs {
+		if err, failed := encounteredErrors[sourceConfig.Name]; failed {
+			fmt.Printf("%s source %s: %v\n", constants.WarningSign, sourceConfig.Name, err)
+		} else {
+			fmt.Printf("%s source %s is reachable\n", constants.CheckMark, sourceConfig.Name)
+		}
+	}
+	if len(encounteredErrors) > 0 {
+		return fmt.Errorf("validation of %d source(s) failed", len(encounteredErrors))
+	}
+	return nil
+}
+
+// runOrphans syncs all sources in dry run mode, so only orphaned objects
+// remain in the orphan manager. These can then be listed or purged.
+func runOrphans(args []string) error {
+	const orphansUsage = "usage: netbox-ssot orphans <list|purge> [flags]"
+	if len(args) == 0 {
+		return errors.New(orphansUsage)
+	}
+	action := args[0]
+	if action != "list" && action != "purge" {
+		return fmt.Errorf("unknown orphans action %q, %s", action, orphansUsage)
+	}
+
+	flags := flag.NewFlagSet("orphans "+action, flag.ExitOnError)
+	configPath := flags.String("config", defaultConfigPath, "Path to the configuration file")
+	asJSON := flags.Bool("json", false, "Print orphaned objects as json")
+	confirm := flags.Bool("yes", false, "Confirm hard deletion of all orphaned objects (purge only)")
+	_ = flags.Parse(args[1:])
+
+	a, err := newApp(*configPath, true, action == "purge")
+	if err != nil {
+		return err
+	}
+	defer a.close()
+	sourceConfigs, err := a.selectSources(nil)
+	if err != nil {
+		return err
+	}
+	encounteredErrors := a.syncSources(sourceConfigs, false)
+	if len(encounteredErrors) > 0 {
+		for sourceName, err := range encounteredErrors {
+			fmt.Printf("%s source %s failed with: %v\n", constants.WarningSign, sourceName, err)
+		}
+		return fmt.Errorf(
+			"can't determine orphaned objects, because %d source(s) failed",
+			len(encounteredErrors),
+		)
+	}
+
+	orphans := a.netboxInventory.ListOrphans()
+	err = printOrphans(os.Stdout, orphans, *asJSON)
+	if err != nil {
+		return err
+	}
+	if action == "list" {
+		return nil
+	}
+
+	if !*confirm {
+		return errors.New("refusing to delete orphaned objects without -yes flag")
+	}
+	a.netboxInventory.NetboxAPI.DryRun = false
+	a.logger.Info(a.ctx, "Purging orphaned objects...")
+	purged, err := a.netboxInventory.DeleteOrphans(true)
+	if err != nil {
+		return err
+	}
+	a.logger.Infof(a.ctx, "%s Successfully purged %d orphans", constants.CheckMark, purged)
+	return nil
+}
+
+// runUninstall removes everything netbox-ssot created, either for a single
+// source or for all sources.
+func runUninstall(args []string) error {
+	flags := flag.NewFlagSet("uninstall", flag.ExitOnError)
+	configPath := flags.String("config", defaultConfigPath, "Path to the configuration file")
+	sourceName := flags.String(
+		"source",
+		"",
+		"Remove only objects of the source with this name. The source doesn't have to be in the config anymore",
+	)
+	untag := flags.Bool(
+		"untag",
+		false,
+		"Only remove netbox-ssot tags and custom fields from objects instead of deleting them",
+	)
+	removeCustomFields := flags.Bool(
+		"remove-custom-fields",
+		false,
+		"Also remove custom fields and tags created by netbox-ssot (only the source tag when -source is set)",
+	)
+	dryRun := flags.Bool("dry-run", false, "Print changes instead of writing them to netbox")
+	confirm := flags.Bool("yes", false, "Confirm the uninstall")
+	_ = flags.Parse(args)
+
+	if !*dryRun && !*confirm {
+		return errors.New("refusing to uninstall without -yes flag, use -dry-run to see the changes")
+	}
+
+	a, err := newApp(*configPath, *dryRun, !*dryRun)
+	if err != nil {
+		return err
+	}
+	defer a.close()
+
+	options := inventory.UninstallOptions{
+		SourceName:                *sourceName,
+		Untag:                     *untag,
+		RemoveCustomFieldsAndTags: *removeCustomFields,
+	}
+	if *sourceName != "" {
+		// Default tag name of the source, see parser.ParseConfig
+		options.SourceTagName = fmt.Sprintf("Source: %s", *sourceName)
+		if sourceConfigs, err := a.selectSources([]string{*sourceName}); err == nil {
+			options.SourceTagName = sourceConfigs[0].Tag
+		}
+	}
+
+	a.logger.Info(a.ctx, "Uninstalling netbox-ssot objects...")
+	err = a.netboxInventory.Uninstall(a.ctx, options)
+	if *dryRun {
+		printErr := printPlannedChanges(os.Stdout, a.netboxInventory.NetboxAPI.PlannedChanges(), false)
+		if printErr != nil {
+			return printErr
+		}
+	}
+	if err != nil {
+		return err
+	}
+	a.logger.Infof(a.ctx, "%s Successfully uninstalled netbox-ssot objects", constants.CheckMark)
+	return nil
+}
+
+// runExport dumps the netbox inventory, as loaded by netbox-ssot, into json.
+func runExport(args []string) error {
+	flags := flag.NewFlagSet("export", flag.ExitOnError)
+	configPath := flags.String("config", defaultConfigPath, "Path to the configuration file")
+	output := flags.String("output", "netbox-inventory.json", "Output file. Use - for stdout")
+	_ = flags.Parse(args)
+
+	a, err := newApp(*configPath, true, false)
+	if err != nil {
+		return err
+	}
+	defer a.close()
+
+	var writer io.Writer = os.Stdout
+	if *output != "-" {
+		file, err := os.Create(*output)
+		if err != nil {
+			return fmt.Errorf("create output file: %s", err)
+		}
+		defer file.Close()
+		writer = file
+	}
+	encoder := json.NewEncoder(writer)
+	encoder.SetIndent("", "  ")
+	err = encoder.Encode(a.netboxInventory.Export())
+	if err != nil {
+		return fmt.Errorf("encode inventory: %s", err)
+	}
+	a.logger.Infof(a.ctx, "%s Successfully exported inventory to %s", constants.CheckMark, *output)
+	return nil
+}
+
+func printPlannedChanges(w io.Writer, changes []service.PlannedChange, asJSON bool) error {
+	if asJSON {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(changes)
+	}
+
+	actionCounts := map[string]int{}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0) //nolint:mnd
+	fmt.Fprintln(tw, "ACTION\tOBJECT TYPE\tID\tOBJECT\tDATA")
+	for _, change := range changes {
+		actionCounts[change.Action]++
+		data := ""
+		if change.Action == service.PlannedActionUpdate {
+			dataJSON, err := json.Marshal(change.Data)
+			if err != nil {
+				return err
+			}
+			data = string(dataJSON)
+		}
+		fmt.Fprintf(
+			tw,
+			"%s\t%s\t%d\t%s\t%s\n",
+			change.Action,
+			change.ObjectPath,
+			change.ObjectID,
+			change.Object,
+			data,
+		)
+	}
+	err := tw.Flush()
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(
+		w,
+		"\nPlan: %d to create, %d to update, %d to delete.\n",
+		actionCounts[service.PlannedActionCreate],
+		actionCounts[service.PlannedActionUpdate],
+		actionCounts[service.PlannedActionDelete],
+	)
+	return err
+}
+
+func printOrphans(
+	w io.Writer,
+	orphans map[constants.APIPath][]objects.OrphanItem,
+	asJSON bool,
+) error {
+	if asJSON {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(orphans)
+	}
+
+	apiPaths := make([]string, 0, len(orphans))
+	for apiPath := range orphans {
+		apiPaths = append(apiPaths, string(apiPath))
+	}
+	sort.Strings(apiPaths)
+
+	total := 0
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0) //nolint:mnd
+	fmt.Fprintln(tw, "OBJECT TYPE\tID\tOBJECT")
+	for _, apiPath := range apiPaths {
+		for _, orphan := range orphans[constants.APIPath(apiPath)] {
+			total++
+			fmt.Fprintf(tw, "%s\t%d\t%s\n", apiPath, orphan.GetID(), orphan)
+		}
+	}
+	err := tw.Flush()
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "\nFound %d orphaned object(s).\n", total)
+	return err
+}
+
+// printConflicts prints conflicts grouped by object type and source.
+// Conflicts must be sorted, as returned by service.NetboxClient.Conflicts.
+func printConflicts(w io.Writer, conflicts []service.Conflict, asJSON bool) error {
+	if asJSON {
+		grouped := map[constants.APIPath]map[string][]service.Conflict{}
+		for _, conflict := range conflicts {
+			if grouped[conflict.ObjectPath] == nil {
+				grouped[conflict.ObjectPath] = map[string][]service.Conflict{}
+			}
+			grouped[conflict.ObjectPath][conflict.Source] = append(
+				grouped[conflict.ObjectPath][conflict.Source],
+				conflict,
+			)
+		}
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(grouped)
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0) //nolint:mnd
+	fmt.Fprintln(tw, "OBJECT TYPE\tSOURCE\tACTION\tOBJECT\tERRORS")
+	for _, conflict := range conflicts {
+		fmt.Fprintf(
+			tw,
+			"%s\t%s\t%s\t%s\t%s\n",
+			conflict.ObjectPath,
+			conflict.Source,
+			conflict.Action,
+			conflict.Object,
+			conflict.Messages(),
+		)
+	}
+	err := tw.Flush()
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "\nFound %d conflict(s) with existing netbox objects.\n", len(conflicts))
+	return err
+}
+
+// runAudit verifies the hash chain of an audit log.
+func runAudit(args []string) error {
+	const auditUsage = "usage: netbox-ssot audit verify [flags]"
+	if len(args) == 0 || args[0] != "verify" {
+		return errors.New(auditUsage)
+	}
+	flags := flag.NewFlagSet("audit verify", flag.ExitOnError)
+	configPath := flags.String("config", defaultConfigPath, "Path to the configuration file")
+	file := flags.String("file", "", "Audit log to verify. Defaults to audit.dest of the config")
+	_ = flags.Parse(args[1:])
+
+	if *file == "" {
+		config, err := parser.ParseConfig(*configPath)
+		if err != nil {
+			return fmt.Errorf("parser: %s", err)
+		}
+		if config.Audit == nil || config.Audit.Dest == audit.DestStdout {
+			return errors.New("audit log file is not configured, use -file flag")
+		}
+		*file = config.Audit.Dest
+	}
+	auditFile, err := os.Open(*file)
+	if err != nil {
+		return fmt.Errorf("open audit log: %s", err)
+	}
+	defer auditFile.Close()
+	count, err := audit.Verify(auditFile)
+	if err != nil {
+		return fmt.Errorf("%s audit log %s is not intact after %d entries: %s", constants.WarningSign, *file, count, err)
+	}
+	fmt.Printf("%s audit log %s is intact (%d entries)\n", constants.CheckMark, *file, count)
+	return nil
+}
@@ -1,23 +1,11 @@
 package main
 
 import (
-	"context"
-	"flag"
 	"fmt"
 	"os"
-	"sync"
-	"time"
-
-	"github.com/src-doo/netbox-ssot/internal/constants"
-	"github.com/src-doo/netbox-ssot/internal/logger"
-	"github.com/src-doo/netbox-ssot/internal/netbox/inventory"
-	"github.com/src-doo/netbox-ssot/internal/parser"
-	"github.com/src-doo/netbox-ssot/internal/source"
-	"github.com/src-doo/netbox-ssot/internal/source/common"
+	"strings"
 )
 
-var configPath = flag.String("config", "config.yaml", "Path to the configuration file")
-
 // Build variables provided with ldflags.
 var (
 	version = "unknown"
@@ -25,132 +13,56 @@ var (
 	date    = "unknown"
 )
 
+const usage = `Usage: netbox-ssot [command] [flags]
+
+Commands:
+  sync       Sync sources to netbox (default command)
+  plan       Show changes that sync would make, without writing to netbox
+  validate   Validate the config and check connectivity to netbox and all sources
+  orphans    List (orphans list) or remove (orphans purge) orphaned objects
+  uninstall  Delete or untag everything netbox-ssot created for a source (or all sources)
+  export     Dump the netbox inventory loaded by netbox-ssot as json
+  audit      Verify the hash chain of the audit log (audit verify)
+  help       Show this message
+
+Run 'netbox-ssot <command> -h' to see flags of a command.
+`
+
 func main() {
 	// Print build information
 	fmt.Printf("Running version %s built on %s (commit %s)\n\n", version, date, commit)
 
-	startTime := time.Now()
-
-	// Parse configuration
-	fmt.Printf("Netbox-SSOT has started at %s\n", startTime.Format(time.RFC3339))
-	flag.Parse()
-	config, err := parser.ParseConfig(*configPath)
-	if err != nil {
-		fmt.Println("Parser:", err)
-		os.Exit(1)
+	// For backwards compatibility, netbox-ssot -config config.yaml runs sync
+	command := "sync"
+	args := os.Args[1:]
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		command, args = args[0], args[1:]
 	}
 
-	// Create our main context
-	mainCtx := context.Background()
-	mainCtx = context.WithValue(mainCtx, constants.CtxSourceKey, "main")
-
-	// Initialize Logger
-	ssotLogger, err := logger.New(config.Logger.Dest, config.Logger.Level)
-	if err != nil {
-		fmt.Println("Logger:", err)
-		os.Exit(1)
+	var err error
+	switch command {
+	case "sync":
+		err = runSync(args)
+	case "plan":
+		err = runPlan(args)
+	case "validate":
+		err = runValidate(args)
+	case "orphans":
+		err = runOrphans(args)
+	case "uninstall":
+		err = runUninstall(args)
+	case "export":
+		err = runExport(args)
+	case "audit":
+		err = runAudit(args)
+	case "help":
+		fmt.Print(usage)
+	default:
+		fmt.Printf("Unknown command %q\n\n%s", command, usage)
+		os.Exit(2) //nolint:mnd
 	}
-	ssotLogger.Debug(mainCtx, "Parsed Logger config: ", config.Logger)
-	ssotLogger.Debug(mainCtx, "Parsed Netbox config: ", config.Netbox)
-	ssotLogger.Debug(mainCtx, "Parsed Source config: ", config.Sources)
-
-	inventoryLogger, err := logger.New(config.Logger.Dest, config.Logger.Level)
 	if err != nil {
-		ssotLogger.Errorf(mainCtx, "inventoryLogger: %s", err)
-		os.Exit(1)
-	}
-	inventoryCtx := context.WithValue(context.Background(), constants.CtxSourceKey, "inventory")
-	netboxInventory := inventory.NewNetboxInventory(inventoryCtx, inventoryLogger, config.Netbox)
-	ssotLogger.Debug(mainCtx, "Netbox inventory: ", netboxInventory)
-
-	ssotLogger.Info(mainCtx, "Starting initializing netbox inventory")
-	err = netboxInventory.Init()
-	if err != nil {
-		ssotLogger.Error(mainCtx, err)
-		os.Exit(1)
-	}
-	ssotLogger.Debug(mainCtx, "Netbox inventory initialized: ", netboxInventory)
-
-	// Variable to store if the run was successful. If it wasn't we don't remove orphans.
-	successfullRun := true
-	// Variable to store failed sourcesFalse
-	encounteredErrors := map[string]error{}
-
-	// Go through all sources and sync data
-	var wg sync.WaitGroup
-	for i := range config.Sources {
-		sourceConfig := &config.Sources[i]
-		ssotLogger.Info(mainCtx, "Processing source ", sourceConfig.Name, "...")
-		sourceCtx := context.WithValue(mainCtx, constants.CtxSourceKey, sourceConfig.Name)
-		source, err := source.NewSource(sourceCtx, sourceConfig, ssotLogger, netboxInventory)
-		if err != nil {
-			ssotLogger.Error(sourceCtx, err)
-			os.Exit(1)
-		}
-		ssotLogger.Infof(sourceCtx, "Successfully created source %s", constants.CheckMark)
-		ssotLogger.Debugf(sourceCtx, "Source content: %s", source)
-		wg.Add(1)
-		// Run each source in parallel
-		go func(sourceCtx context.Context, source common.Source) {
-			defer wg.Done()
-			sourceName, ok := sourceCtx.Value(constants.CtxSourceKey).(string)
-			if !ok {
-				ssotLogger.Errorf(sourceCtx, "source ctx value is not set")
-				return
-			}
-			// Source initialization
-			ssotLogger.Info(sourceCtx, "Initializing source")
-			err = source.Init()
-			if err != nil {
-				ssotLogger.Error(sourceCtx, err)
-				successfullRun = false
-				encounteredErrors[sourceName] = err
-				return
-			}
-			ssotLogger.Infof(sourceCtx, "Successfully initialized source %s", constants.CheckMark)
-
-			// Source synchronization
-			ssotLogger.Info(sourceCtx, "Syncing source...")
-			err = source.Sync(netboxInventory)
-			if err != nil {
-				successfullRun = false
-				ssotLogger.Error(sourceCtx, err)
-				encounteredErrors[sourceName] = err
-				return
-			}
-			ssotLogger.Infof(sourceCtx, "Source synced successfully %s", constants.CheckMark)
-		}(sourceCtx, source)
-	}
-	wg.Wait()
-
-	// Orphan manager cleanup on successful run and if enabled
-	if successfullRun {
-		ssotLogger.Info(mainCtx, "Cleaning up orphaned objects...")
-		err = netboxInventory.DeleteOrphans(config.Netbox.RemoveOrphans)
-		if err != nil {
-			ssotLogger.Error(mainCtx, err)
-			os.Exit(1)
-		}
-		ssotLogger.Infof(mainCtx, "%s Successfully removed orphans", constants.CheckMark)
-	} else {
-		ssotLogger.Info(mainCtx, "Skipping removing orphaned objects because run failed...")
-	}
-
-	duration := time.Since(startTime)
-	minutes := int(duration.Minutes())
-	seconds := int((duration - time.Duration(minutes)*time.Minute).Seconds())
-	if successfullRun {
-		ssotLogger.Infof(
-			mainCtx,
-			"%s Syncing took %d min %d sec in total",
-			constants.Rocket,
-			minutes,
-			seconds,
-		)
-	} else {
-		for source, err := range encounteredErrors {
-			ssotLogger.Infof(mainCtx, "%s syncing of source %s failed with: %v", constants.WarningSign, source, err)
-		}
+		fmt.Println(err)
 		os.Exit(1)
 	}
 }
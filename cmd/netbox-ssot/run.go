@@ -0,0 +1,450 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/src-doo/netbox-ssot/internal/audit"
+	"github.com/src-doo/netbox-ssot/internal/constants"
+	"github.com/src-doo/netbox-ssot/internal/logger"
+	"github.com/src-doo/netbox-ssot/internal/netbox/inventory"
+	"github.com/src-doo/netbox-ssot/internal/netbox/service"
+	"github.com/src-doo/netbox-ssot/internal/notify"
+	"github.com/src-doo/netbox-ssot/internal/parser"
+	"github.com/src-doo/netbox-ssot/internal/runlock"
+	"github.com/src-doo/netbox-ssot/internal/source"
+	"github.com/src-doo/netbox-ssot/internal/source/common"
+	"github.com/src-doo/netbox-ssot/internal/tracing"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// app holds everything that is shared between commands.
+type app struct {
+	config          *parser.Config
+	logger          *logger.Logger
+	ctx             context.Context //nolint:containedctx
+	netboxInventory *inventory.NetboxInventory
+	// runLock is held from initialization until close, if configured.
+	runLock *runlock.RunLock
+	// shutdownTracing flushes spans that weren't exported yet.
+	shutdownTracing func(context.Context) error
+	// recordDir is the directory, where snapshots of initialized sources are written.
+	recordDir string
+	// replayDir is the directory, from which sources are loaded instead of initialized.
+	replayDir string
+	// auditLog records writes to netbox, if configured.
+	auditLog *audit.Log
+}
+
+// newApp parses the configuration at configPath and initializes the app
+// with it, see newAppWithConfig.
+func newApp(configPath string, dryRun bool, lockRun bool) (*app, error) {
+	config, err := parser.ParseConfig(configPath)
+	if err != nil {
+		return nil, fmt.Errorf("parser: %s", err)
+	}
+	return newAppWithConfig(config, dryRun, lockRun)
+}
+
+// newAppWithConfig initializes the netbox inventory. If dryRun is set,
+// nothing is written to netbox. If lockRun is set, the run lock is
+// acquired before initialization. Resources of the app must be released with close.
+func newAppWithConfig(config *parser.Config, dryRun bool, lockRun bool) (*app, error) {
+	// Create our main context
+	mainCtx := context.Background()
+	mainCtx = context.WithValue(mainCtx, constants.CtxSourceKey, "main")
+
+	// Initialize Logger, it is shared by the inventory and all sources
+	ssotLogger, err := newLogger(config.Logger, config.Logger.Append)
+	if err != nil {
+		return nil, fmt.Errorf("logger: %s", err)
+	}
+	ssotLogger.Debug(mainCtx, "Parsed Logger config: ", config.Logger)
+	ssotLogger.Debug(mainCtx, "Parsed Netbox config: ", config.Netbox)
+	ssotLogger.Debug(mainCtx, "Parsed Source config: ", config.Sources)
+
+	shutdownTracing, err := tracing.Setup(mainCtx, config.Tracing)
+	if err != nil {
+		ssotLogger.Close()
+		return nil, fmt.Errorf("tracing: %s", err)
+	}
+
+	inventoryCtx := context.WithValue(context.Background(), constants.CtxSourceKey, "inventory")
+	netboxInventory := inventory.NewNetboxInventory(inventoryCtx, ssotLogger, config.Netbox)
+	netboxInventory.DryRun = dryRun
+	for _, sourceConfig := range config.Sources {
+		netboxInventory.SourceTypes = append(netboxInventory.SourceTypes, sourceConfig.Type)
+	}
+	ssotLogger.Debug(mainCtx, "Netbox inventory: ", netboxInventory)
+
+	a := &app{
+		config:          config,
+		logger:          ssotLogger,
+		ctx:             mainCtx,
+		netboxInventory: netboxInventory,
+		shutdownTracing: shutdownTracing,
+	}
+	if lockRun {
+		a.runLock, err = runlock.New(ssotLogger, config.RunLock, config.Netbox)
+		if err != nil {
+			a.close()
+			return nil, fmt.Errorf("run lock: %s", err)
+		}
+		if a.runLock != nil {
+			err = a.runLock.Acquire(mainCtx)
+			if err != nil {
+				a.runLock = nil
+				a.close()
+				return nil, err
+			}
+		}
+	}
+
+	if config.Audit != nil {
+		runID := audit.NewRunID()
+		a.auditLog, err = audit.Open(config.Audit.Dest, config.Audit.HashChain, runID)
+		if err != nil {
+			a.close()
+			return nil, fmt.Errorf("audit log: %s", err)
+		}
+		netboxInventory.AuditLog = a.auditLog
+		ssotLogger.Infof(mainCtx, "Writes to netbox are audited in %s with run id %s", config.Audit.Dest, runID)
+	}
+
+	ssotLogger.Info(mainCtx, "Starting initializing netbox inventory")
+	err = netboxInventory.Init()
+	if err != nil {
+		a.close()
+		return nil, err
+	}
+	ssotLogger.Debug(mainCtx, "Netbox inventory initialized: ", netboxInventory)
+
+	return a, nil
+}
+
+// newLogger creates the logger configured by loggerConfig. If appendLog
+// is set, the log file is appended to, regardless of the config.
+func newLogger(loggerConfig *parser.LoggerConfig, appendLog bool) (*logger.Logger, error) {
+	return logger.NewWithOptions(logger.Options{
+		Dest:       loggerConfig.Dest,
+		Level:      loggerConfig.Level,
+		Format:     loggerConfig.Format,
+		Append:     appendLog,
+		MaxSize:    loggerConfig.MaxSize,
+		MaxAge:     loggerConfig.MaxAge,
+		MaxBackups: loggerConfig.MaxBackups,
+	})
+}
+
+// close releases the run lock, if it is held, flushes remaining spans
+// and closes the audit log and the log file.
+func (a *app) close() {
+	if a.runLock != nil {
+		err := a.runLock.Release(a.ctx)
+		if err != nil {
+			a.logger.Error(a.ctx, err)
+		}
+	}
+	err := a.shutdownTracing(a.ctx)
+	if err != nil {
+		a.logger.Error(a.ctx, fmt.Errorf("tracing: %s", err))
+	}
+	if a.auditLog != nil {
+		err = a.auditLog.Close()
+		if err != nil {
+			a.logger.Error(a.ctx, fmt.Errorf("audit log: %s", err))
+		}
+	}
+	err = a.logger.Close()
+	if err != nil {
+		fmt.Printf("close logger: %s\n", err)
+	}
+}
+
+// selectSources returns configs of sources with the given names.
+// If no names are given, all sources are returned.
+func (a *app) selectSources(sourceNames []string) ([]*parser.SourceConfig, error) {
+	selected := make([]*parser.SourceConfig, 0, len(a.config.Sources))
+	if len(sourceNames) == 0 {
+		for i := range a.config.Sources {
+			selected = append(selected, &a.config.Sources[i])
+		}
+		return selected, nil
+	}
+	for _, sourceName := range sourceNames {
+		found := false
+		for i := range a.config.Sources {
+			if a.config.Sources[i].Name == sourceName {
+				selected = append(selected, &a.config.Sources[i])
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("source %s is not defined in the config", sourceName)
+		}
+	}
+	return selected, nil
+}
+
+// syncSources initializes and syncs the given sources in parallel.
+// If initOnly is set, sources are only initialized, which is enough
+// to check connectivity and credentials. It returns errors of the
+// failed sources, indexed by the source name.
+func (a *app) syncSources(sourceConfigs []*parser.SourceConfig, initOnly bool) map[string]error {
+	encounteredErrors := map[string]error{}
+	var errorsLock sync.Mutex
+	addError := func(sourceName string, err error) {
+		errorsLock.Lock()
+		defer errorsLock.Unlock()
+		encounteredErrors[sourceName] = err
+	}
+
+	// Go through all sources and sync data
+	var wg sync.WaitGroup
+	for _, sourceConfig := range sourceConfigs {
+		a.logger.Info(a.ctx, "Processing source ", sourceConfig.Name, "...")
+		sourceCtx := context.WithValue(a.ctx, constants.CtxSourceKey, sourceConfig.Name)
+		// Spans of the source steps are children of the source span
+		sourceCtx, span := tracing.Start(sourceCtx, "source "+sourceConfig.Name)
+		nbSource, err := source.NewSource(sourceCtx, sourceConfig, a.logger, a.netboxInventory)
+		if err != nil {
+			a.logger.Error(sourceCtx, err)
+			addError(sourceConfig.Name, err)
+			tracing.End(span, err)
+			continue
+		}
+		a.logger.Infof(sourceCtx, "Successfully created source %s", constants.CheckMark)
+		a.logger.Debugf(sourceCtx, "Source content: %s", nbSource)
+		wg.Add(1)
+		// Run each source in parallel
+		go func(sourceCtx context.Context, sourceName string, span trace.Span) {
+			defer wg.Done()
+			var err error
+			defer func() { tracing.End(span, err) }()
+			// Source initialization
+			err = a.initSource(sourceCtx, nbSource, sourceConfig)
+			if err != nil {
+				a.logger.Error(sourceCtx, err)
+				addError(sourceName, err)
+				return
+			}
+			a.logger.Infof(sourceCtx, "Successfully initialized source %s", constants.CheckMark)
+			if initOnly {
+				return
+			}
+
+			// Source synchronization
+			a.logger.Info(sourceCtx, "Syncing source...")
+			err = nbSource.Sync(a.netboxInventory)
+			if err != nil {
+				a.logger.Error(sourceCtx, err)
+				addError(sourceName, err)
+				return
+			}
+			a.logger.Infof(sourceCtx, "Source synced successfully %s", constants.CheckMark)
+		}(sourceCtx, sourceConfig.Name, span)
+	}
+	wg.Wait()
+	return encounteredErrors
+}
+
+// initSource initializes the source, or loads it from its snapshot in replay
+// mode. In record mode, the snapshot of the initialized source is written.
+func (a *app) initSource(ctx context.Context, nbSource common.Source, sourceConfig *parser.SourceConfig) error {
+	if a.replayDir != "" {
+		snapshotPath := source.SnapshotPath(a.replayDir, sourceConfig.Name)
+		a.logger.Infof(ctx, "Replaying source from snapshot %s", snapshotPath)
+		return source.ReplaySnapshot(nbSource, sourceConfig, snapshotPath)
+	}
+	a.logger.Info(ctx, "Initializing source")
+	err := nbSource.Init()
+	if err != nil {
+		return err
+	}
+	if a.recordDir != "" {
+		snapshotPath := source.SnapshotPath(a.recordDir, sourceConfig.Name)
+		err = source.RecordSnapshot(nbSource, sourceConfig, snapshotPath)
+		if err != nil {
+			return fmt.Errorf("record snapshot: %s", err)
+		}
+		a.logger.Infof(ctx, "Recorded snapshot of source to %s", snapshotPath)
+	}
+	return nil
+}
+
+// cleanupOrphans removes orphaned objects and returns the number of objects, that
+// were tagged or removed as orphans in this run. Orphans are only removed if all
+// sources were synced successfully, otherwise we could remove objects that are
+// still present on the failed sources.
+func (a *app) cleanupOrphans(partialRun bool, encounteredErrors map[string]error) (int, error) {
+	switch {
+	case len(encounteredErrors) > 0:
+		a.logger.Info(a.ctx, "Skipping removing orphaned objects because run failed...")
+	case partialRun:
+		a.logger.Info(a.ctx, "Skipping removing orphaned objects because only a subset of sources was synced...")
+	default:
+		a.logger.Info(a.ctx, "Cleaning up orphaned objects...")
+		orphaned, err := a.netboxInventory.DeleteOrphans(a.config.Netbox.RemoveOrphans)
+		if err != nil {
+			return 0, err
+		}
+		a.logger.Infof(a.ctx, "%s Successfully removed orphans", constants.CheckMark)
+		return orphaned, nil
+	}
+	return 0, nil
+}
+
+// finishBranch merges the branch, that changes of the run were written to,
+// if all sources were synced and the number of changes doesn't exceed
+// netbox.branchMergeThreshold. Otherwise the branch is left for review.
+func (a *app) finishBranch(encounteredErrors map[string]error) error {
+	api := a.netboxInventory.NetboxAPI
+	branch := api.Branch()
+	if branch == nil || a.netboxInventory.DryRun {
+		return nil
+	}
+	writeStats := api.WriteStats()
+	changes := writeStats.Created + writeStats.Updated + writeStats.Deleted
+	threshold := a.config.Netbox.BranchMergeThreshold
+	switch {
+	case changes == 0:
+		a.logger.Infof(a.ctx, "No changes were written to branch %s", branch.Name)
+	case len(encounteredErrors) > 0 || threshold == 0 || changes > threshold:
+		a.logger.Infof(a.ctx, "Branch %s with %d changes is left for review", branch.Name, changes)
+	default:
+		err := api.MergeBranch(a.ctx)
+		if err != nil {
+			return fmt.Errorf("merge branch %s: %s", branch.Name, err)
+		}
+		a.logger.Infof(a.ctx, "%s Merging branch %s with %d changes", constants.CheckMark, branch.Name, changes)
+	}
+	return nil
+}
+
+// notify sends the summary of the sync run to the configured notification
+// targets. Failed notifications are only logged, so they don't fail the run.
+func (a *app) notify(
+	startTime time.Time,
+	encounteredErrors map[string]error,
+	runErr error,
+	writeStats service.WriteStats,
+	orphaned int,
+) {
+	if len(a.config.Notifications) == 0 {
+		return
+	}
+	summary := notify.NewSummary(
+		startTime,
+		encounteredErrors,
+		runErr,
+		writeStats.Created,
+		writeStats.Updated,
+		orphaned,
+	)
+	err := notify.New(a.logger, a.config.Notifications).Notify(a.ctx, summary)
+	if err != nil {
+		a.logger.Error(a.ctx, err)
+	}
+}
+
+// notifyFailure sends the summary of a run, that failed before the app was
+// initialized, to the configured notification targets. The log file is already
+// closed by then, so it is reopened for appending.
+func notifyFailure(config *parser.Config, startTime time.Time, runErr error) {
+	if len(config.Notifications) == 0 {
+		return
+	}
+	ctx := context.WithValue(context.Background(), constants.CtxSourceKey, "main")
+	ssotLogger, err := newLogger(config.Logger, true)
+	if err != nil {
+		fmt.Printf("logger: %s\n", err)
+		ssotLogger, _ = logger.New("", config.Logger.Level)
+	}
+	defer ssotLogger.Close()
+	summary := notify.NewSummary(startTime, nil, runErr, 0, 0, 0)
+	err = notify.New(ssotLogger, config.Notifications).Notify(ctx, summary)
+	if err != nil {
+		ssotLogger.Error(ctx, err)
+	}
+}
+
+// reportAdoption logs objects adopted in adoption mode and unmanaged objects,
+// that weren't matched by any source. Unmatched objects are only reported
+// on full runs, because on partial runs they could belong to skipped sources.
+func (a *app) reportAdoption(partialRun bool, encounteredErrors map[string]error) {
+	if !a.config.Netbox.AdoptionMode {
+		return
+	}
+	adoptedItems := a.netboxInventory.OrphanManager.AdoptedItems()
+	a.logger.Infof(a.ctx, "Adopted %d unmanaged objects", len(adoptedItems))
+	for _, adoptedItem := range adoptedItems {
+		a.logger.Infof(
+			a.ctx,
+			"Adopted %s (id %d) by source %s, matched by %s",
+			adoptedItem.Item,
+			adoptedItem.Item.GetID(),
+			adoptedItem.Source,
+			adoptedItem.MatchedBy,
+		)
+	}
+	if partialRun || len(encounteredErrors) > 0 {
+		a.logger.Info(a.ctx, "Skipping report of unmatched unmanaged objects because not all sources were synced...")
+		return
+	}
+	for apiPath, items := range a.netboxInventory.OrphanManager.UnmanagedItems() {
+		a.logger.Infof(a.ctx, "%d unmanaged objects of %s weren't matched by any source", len(items), apiPath)
+		for _, item := range items {
+			a.logger.Infof(a.ctx, "Unmatched unmanaged %s (id %d)", item, item.GetID())
+		}
+	}
+}
+
+// reportConflicts prints conflicts with existing netbox objects encountered
+// during the run. If reportPath is set, they are also written there as json.
+func (a *app) reportConflicts(reportPath string) error {
+	conflicts := a.netboxInventory.NetboxAPI.Conflicts()
+	if len(conflicts) > 0 {
+		err := printConflicts(os.Stdout, conflicts, false)
+		if err != nil {
+			return err
+		}
+	}
+	if reportPath == "" {
+		return nil
+	}
+	file, err := os.Create(reportPath)
+	if err != nil {
+		return fmt.Errorf("create conflicts report: %s", err)
+	}
+	defer file.Close()
+	err = printConflicts(file, conflicts, true)
+	if err != nil {
+		return fmt.Errorf("write conflicts report: %s", err)
+	}
+	return nil
+}
+
+// sourceNamesFlag is a flag.Value that collects source names. It
+// can be repeated (-source a -source b) or comma separated (-source a,b).
+type sourceNamesFlag []string
+
+func (s *sourceNamesFlag) String() string {
+	return strings.Join(*s, ",")
+}
+
+func (s *sourceNamesFlag) Set(value string) error {
+	for _, sourceName := range strings.Split(value, ",") {
+		sourceName = strings.TrimSpace(sourceName)
+		if sourceName == "" {
+			return errors.New("empty source name")
+		}
+		*s = append(*s, sourceName)
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/src-doo/netbox-ssot/internal/notify"
+)
+
+func TestSourceNamesFlag_Set(t *testing.T) {
+	tests := []struct {
+		name    string
+		values  []string
+		want    sourceNamesFlag
+		wantErr bool
+	}{
+		{
+			name:   "Repeated flag",
+			values: []string{"vmware", "dnac"},
+			want:   sourceNamesFlag{"vmware", "dnac"},
+		},
+		{
+			name:   "Comma separated flag",
+			values: []string{"vmware, dnac", "ovirt"},
+			want:   sourceNamesFlag{"vmware", "dnac", "ovirt"},
+		},
+		{
+			name:    "Empty source name",
+			values:  []string{"vmware,"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got sourceNamesFlag
+			var err error
+			for _, value := range tt.values {
+				if err = got.Set(value); err != nil {
+					break
+				}
+			}
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Set() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Set() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRunSync_NotifiesInitFailure(t *testing.T) {
+	netbox := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer netbox.Close()
+	var summariesLock sync.Mutex
+	var summaries []notify.Summary
+	webhook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var summary notify.Summary
+		err := json.NewDecoder(r.Body).Decode(&summary)
+		if err != nil {
+			t.Errorf("decode notification: %v", err)
+		}
+		summariesLock.Lock()
+		summaries = append(summaries, summary)
+		summariesLock.Unlock()
+	}))
+	defer webhook.Close()
+
+	netboxURL, err := url.Parse(netbox.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	config := fmt.Sprintf(`netbox:
+  apiToken: "netbox-token"
+  hostname: %s
+  port: %s
+  httpScheme: http
+notifications:
+  - name: ops
+    type: webhook
+    url: %s
+    policy: failure
+`, netboxURL.Hostname(), netboxURL.Port(), webhook.URL)
+	err = os.WriteFile(configPath, []byte(config), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = runSync([]string{"-config", configPath})
+	if err == nil {
+		t.Fatal("runSync() error = nil, want init error")
+	}
+	summariesLock.Lock()
+	defer summariesLock.Unlock()
+	if len(summaries) != 1 {
+		t.Fatalf("runSync() sent %d notifications, want 1", len(summaries))
+	}
+	if !summaries[0].Failed() || !strings.Contains(summaries[0].Error, err.Error()) {
+		t.Errorf("runSync() notified %+v, want failure with error %q", summaries[0], err)
+	}
+}
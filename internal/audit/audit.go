@@ -0,0 +1,211 @@
+// Package audit records every write netbox-ssot performs against netbox
+// in an append-only log of json lines. Optionally, entries are chained
+// with hashes, so changed or removed entries can be detected with Verify.
+package audit
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// DestStdout is the destination of the audit log, that writes entries to stdout.
+const DestStdout = "stdout"
+
+// Actions of entries.
+const (
+	ActionCreate = "create"
+	ActionUpdate = "update"
+	ActionDelete = "delete"
+)
+
+// Entry is a single write to netbox.
+type Entry struct {
+	Timestamp time.Time `json:"timestamp"`
+	RunID     string    `json:"run_id"`
+	// Source is the name of the source, that wrote the object.
+	Source string `json:"source"`
+	// ObjectType is the content type of the object, e.g. dcim.device.
+	ObjectType string `json:"object_type"`
+	ObjectID   int    `json:"object_id"`
+	// Action is one of ActionCreate, ActionUpdate or ActionDelete.
+	Action string `json:"action"`
+	// Data contains the fields sent to netbox.
+	Data map[string]interface{} `json:"data,omitempty"`
+	// PrevHash is the hash of the previous entry and Hash is the hash
+	// of this entry (including PrevHash). Set only if hash chain is enabled.
+	PrevHash string `json:"prev_hash,omitempty"`
+	Hash     string `json:"hash,omitempty"`
+}
+
+// Log is an append-only audit log. It is safe for concurrent use.
+type Log struct {
+	runID     string
+	hashChain bool
+
+	lock     sync.Mutex
+	writer   io.Writer
+	file     *os.File
+	lastHash string
+}
+
+// NewRunID returns a unique id of the run, e.g. 20261019-153042-9f86d081.
+func NewRunID() string {
+	randomBytes := make([]byte, 4) //nolint:mnd
+	_, _ = rand.Read(randomBytes)
+	return fmt.Sprintf("%s-%s", time.Now().Format("20060102-150405"), hex.EncodeToString(randomBytes))
+}
+
+// Open opens the audit log at dest, which is either a path of a file,
+// that entries are appended to, or DestStdout. If hashChain is set
+// and the file already contains entries, the chain is continued
+// from the last of them. The log must be closed with Close.
+func Open(dest string, hashChain bool, runID string) (*Log, error) {
+	l := &Log{runID: runID, hashChain: hashChain, writer: os.Stdout}
+	if dest == DestStdout {
+		return l, nil
+	}
+	if hashChain {
+		lastHash, err := readLastHash(dest)
+		if err != nil {
+			return nil, fmt.Errorf("read audit log %s: %s", dest, err)
+		}
+		l.lastHash = lastHash
+	}
+	file, err := os.OpenFile(dest, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o600) //nolint:mnd
+	if err != nil {
+		return nil, err
+	}
+	l.file = file
+	l.writer = file
+	return l, nil
+}
+
+// Write appends the entry to the log. Timestamp and run id of the entry are set by the log.
+func (l *Log) Write(entry Entry) error {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	entry.Timestamp = time.Now().UTC()
+	entry.RunID = l.runID
+	entry.PrevHash = ""
+	entry.Hash = ""
+	if l.hashChain {
+		entry.PrevHash = l.lastHash
+		// The hash is computed from the entry decoded from json, like in Verify,
+		// because e.g. structs in data are decoded as maps with sorted keys.
+		line, err := json.Marshal(entry)
+		if err != nil {
+			return err
+		}
+		entry, err = decodeEntry(line)
+		if err != nil {
+			return err
+		}
+		entry.Hash, err = entryHash(entry)
+		if err != nil {
+			return err
+		}
+	}
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	_, err = l.writer.Write(append(line, '\n'))
+	if err != nil {
+		return fmt.Errorf("write audit log: %s", err)
+	}
+	l.lastHash = entry.Hash
+	return nil
+}
+
+// Close closes the file of the log.
+func (l *Log) Close() error {
+	if l.file == nil {
+		return nil
+	}
+	return l.file.Close()
+}
+
+// Verify checks the hash chain of the audit log read from r
+// and returns the number of verified entries.
+// All entries must have been written with hash chain enabled.
+func Verify(r io.Reader) (int, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, bufio.MaxScanTokenSize*64) //nolint:mnd
+	count := 0
+	prevHash := ""
+	for scanner.Scan() {
+		entry, err := decodeEntry(scanner.Bytes())
+		if err != nil {
+			return count, fmt.Errorf("entry %d: %s", count+1, err)
+		}
+		if entry.Hash == "" {
+			return count, fmt.Errorf("entry %d: missing hash", count+1)
+		}
+		if entry.PrevHash != prevHash {
+			return count, fmt.Errorf("entry %d: previous entry was changed or removed", count+1)
+		}
+		hash := entry.Hash
+		entry.Hash = ""
+		wantHash, err := entryHash(entry)
+		if err != nil {
+			return count, fmt.Errorf("entry %d: %s", count+1, err)
+		}
+		if hash != wantHash {
+			return count, fmt.Errorf("entry %d: entry was changed", count+1)
+		}
+		prevHash = hash
+		count++
+	}
+	return count, scanner.Err()
+}
+
+// entryHash returns sha256 of the json encoding of the entry without its hash.
+func entryHash(entry Entry) (string, error) {
+	content, err := json.Marshal(entry)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// decodeEntry decodes the line of the log. Numbers are kept as they were
+// written, so the entry encodes to the same json again.
+func decodeEntry(line []byte) (Entry, error) {
+	var entry Entry
+	decoder := json.NewDecoder(bytes.NewReader(line))
+	decoder.UseNumber()
+	err := decoder.Decode(&entry)
+	return entry, err
+}
+
+// readLastHash returns the hash of the last entry of the log at path.
+func readLastHash(path string) (string, error) {
+	content, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	lines := bytes.Split(bytes.TrimSpace(content), []byte("\n"))
+	lastLine := lines[len(lines)-1]
+	if len(lastLine) == 0 {
+		return "", nil
+	}
+	entry, err := decodeEntry(lastLine)
+	if err != nil {
+		return "", fmt.Errorf("last entry: %s", err)
+	}
+	return entry.Hash, nil
+}
@@ -0,0 +1,113 @@
+package audit
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+type testObject struct {
+	Name string `json:"name"`
+	ID   int    `json:"id"`
+}
+
+func writeEntries(t *testing.T, path string, runID string, count int) {
+	t.Helper()
+	auditLog, err := Open(path, true, runID)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	defer auditLog.Close()
+	for i := 0; i < count; i++ {
+		err = auditLog.Write(Entry{
+			Source:     "vmware",
+			ObjectType: "dcim.device",
+			ObjectID:   i + 1,
+			Action:     ActionCreate,
+			Data: map[string]interface{}{
+				"name":   "device",
+				"weight": 1.5,
+				"tags":   []*testObject{{Name: "<ssot>", ID: 1}},
+			},
+		})
+		if err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+	}
+}
+
+func TestLog_HashChain(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	writeEntries(t, path, "run1", 2)
+	// Chain is continued by the next run
+	writeEntries(t, path, "run2", 1)
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	count, err := Verify(bytes.NewReader(content))
+	if err != nil || count != 3 {
+		t.Fatalf("Verify() = %d, %v, want 3 entries", count, err)
+	}
+
+	lines := strings.SplitAfter(string(content), "\n")
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{
+			name:    "Changed entry",
+			content: lines[0] + strings.Replace(lines[1], `"object_id":2`, `"object_id":5`, 1) + lines[2],
+			want:    "entry 2: entry was changed",
+		},
+		{
+			name:    "Removed entry",
+			content: lines[0] + lines[2],
+			want:    "entry 2: previous entry was changed or removed",
+		},
+		{
+			name:    "Entry without hash",
+			content: `{"run_id":"run0","object_id":1}` + "\n" + lines[0],
+			want:    "entry 1: missing hash",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Verify(strings.NewReader(tt.content))
+			if err == nil || err.Error() != tt.want {
+				t.Errorf("Verify() error = %v, want %s", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestLog_WithoutHashChain(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	auditLog, err := Open(path, false, "run1")
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	err = auditLog.Write(Entry{Source: "dnac", ObjectType: "dcim.interface", ObjectID: 3, Action: ActionDelete})
+	if err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if err = auditLog.Close(); err != nil {
+		t.Fatal(err)
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	entry, err := decodeEntry(content)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if entry.RunID != "run1" || entry.Source != "dnac" || entry.ObjectID != 3 || entry.Action != ActionDelete ||
+		entry.Hash != "" || entry.Timestamp.IsZero() {
+		t.Errorf("entry = %+v", entry)
+	}
+}
@@ -27,6 +27,10 @@ const IgnoreDeviceTypeTagName = "netbox-ssot-ignore-device-type"
 const IgnoreDeviceTypeTagColor = ColorGrey
 const IgnoreDeviceTypeTagDescription = "Tag used by netbox-ssot to preserve manually set device types"
 
+// SourceTagDescriptionPrefix is the prefix of descriptions of tags,
+// that are automatically created for each source and source type.
+const SourceTagDescriptionPrefix = "Automatically created tag by netbox-ssot"
+
 const DefaultVlanGroupName = "DefaultVlanGroup"
 
 const DefaultVlanGroupDescription = "Default netbox-ssot VlanGroup for all vlans that are not part of " +
@@ -105,6 +109,10 @@ var SourceTypeTagColorMap = map[SourceType]string{
 const (
 	// API timeout in seconds.
 	DefaultAPITimeout = 15
+	// Age in hours, after which the netbox cache is fully refreshed.
+	DefaultCacheMaxAge = 24
+	// Number of objects a source syncs concurrently.
+	DefaultSourceMaxWorkers = 50
 )
 
 // Magic numbers for dealing with bytes.
@@ -132,6 +140,47 @@ const (
 	HTTPSDefaultPort = 443
 )
 
+// Default service name of netbox-ssot traces.
+const TracingServiceName = "netbox-ssot"
+
+// Run lock backends and defaults.
+const (
+	RunLockTypeNetbox = "netbox"
+	RunLockTypeFile   = "file"
+	// Name of the tag used as a run lock marker by netbox backend.
+	RunLockTagName = "netbox-ssot-lock"
+	// Default name of the lock file used by file backend.
+	RunLockFileName = "netbox-ssot.lock"
+	// Run lock is considered stale, if it wasn't refreshed for ttl seconds.
+	DefaultRunLockTTL = 600
+	// Interval in seconds between attempts to acquire the run lock.
+	RunLockRetryInterval = 5
+)
+
+// Mappings of the dnac site hierarchy to netbox objects.
+const (
+	// Every dnac site is synced as a netbox site.
+	SiteHierarchyFlat = "flat"
+	// Dnac areas are synced as nested regions and buildings as sites.
+	SiteHierarchyBuilding = "building"
+	// Same as SiteHierarchyBuilding, and dnac floors are synced as locations.
+	SiteHierarchyFloor = "floor"
+)
+
+// Notification target types and policies.
+const (
+	NotificationTypeWebhook = "webhook"
+	NotificationTypeSlack   = "slack"
+	NotificationTypeTeams   = "teams"
+
+	// Notify after each run.
+	NotificationPolicyAlways = "always"
+	// Notify only if a source or the run failed.
+	NotificationPolicyFailure = "failure"
+	// Notify only if objects were created, updated or orphaned, or the run failed.
+	NotificationPolicyChange = "change"
+)
+
 // Names used for netbox objects custom fields attribute.
 const (
 	// Custom Field for matching object with a source. This custom field is important
@@ -205,6 +254,11 @@ type CtxKey int
 
 const (
 	CtxSourceKey CtxKey = iota
+	// Keys describing the netbox object that is being changed,
+	// see logger.WithObject.
+	CtxObjectTypeKey
+	CtxObjectNameKey
+	CtxActionKey
 )
 
 const (
@@ -219,16 +273,22 @@ type ContentType string
 // Content types predefined in netbox.
 const (
 	// DCIM object types.
+	ContentTypeDcimCable                ContentType = "dcim.cable"
+	ContentTypeDcimConsolePortTemplate  ContentType = "dcim.consoleporttemplate"
 	ContentTypeDcimDevice               ContentType = "dcim.device"
 	ContentTypeDcimDeviceRole           ContentType = "dcim.devicerole"
 	ContentTypeDcimDeviceType           ContentType = "dcim.devicetype"
 	ContentTypeDcimInterface            ContentType = "dcim.interface"
+	ContentTypeDcimInterfaceTemplate    ContentType = "dcim.interfacetemplate"
+	ContentTypeDcimInventoryItem        ContentType = "dcim.inventoryitem"
 	ContentTypeDcimLocation             ContentType = "dcim.location"
 	ContentTypeDcimManufacturer         ContentType = "dcim.manufacturer"
 	ContentTypeDcimPlatform             ContentType = "dcim.platform"
+	ContentTypeDcimPowerPortTemplate    ContentType = "dcim.powerporttemplate"
 	ContentTypeDcimRegion               ContentType = "dcim.region"
 	ContentTypeDcimSite                 ContentType = "dcim.site"
 	ContentTypeDcimSiteGroup            ContentType = "dcim.sitegroup"
+	ContentTypeDcimVirtualChassis       ContentType = "dcim.virtualchassis"
 	ContentTypeDcimVirtualDeviceContext ContentType = "dcim.virtualdevicecontext"
 	ContentTypeDcimMACAddress           ContentType = "dcim.macaddress"
 
@@ -238,6 +298,7 @@ const (
 
 	// IPAM object types.
 	ContentTypeIpamIPAddress ContentType = "ipam.ipaddress"
+	ContentTypeIpamIPRange   ContentType = "ipam.iprange"
 	ContentTypeIpamVlanGroup ContentType = "ipam.vlangroup"
 	ContentTypeIpamVlan      ContentType = "ipam.vlan"
 	ContentTypeIpamPrefix    ContentType = "ipam.prefix"
@@ -283,6 +344,7 @@ const (
 	VlanGroupsAPIPath  APIPath = "/api/ipam/vlan-groups/"
 	VlansAPIPath       APIPath = "/api/ipam/vlans/"
 	IPAddressesAPIPath APIPath = "/api/ipam/ip-addresses/"
+	IPRangesAPIPath    APIPath = "/api/ipam/ip-ranges/"
 	VRFsAPIPath        APIPath = "/api/ipam/vrfs/"
 
 	// Virtualization paths.
@@ -299,12 +361,18 @@ const (
 	DeviceRolesAPIPath           APIPath = "/api/dcim/device-roles/"
 	DeviceTypesAPIPath           APIPath = "/api/dcim/device-types/"
 	InterfacesAPIPath            APIPath = "/api/dcim/interfaces/"
+	InterfaceTemplatesAPIPath    APIPath = "/api/dcim/interface-templates/"
+	CablesAPIPath                APIPath = "/api/dcim/cables/"
+	InventoryItemsAPIPath        APIPath = "/api/dcim/inventory-items/"
+	ConsolePortTemplatesAPIPath  APIPath = "/api/dcim/console-port-templates/"
+	PowerPortTemplatesAPIPath    APIPath = "/api/dcim/power-port-templates/"
 	SitesAPIPath                 APIPath = "/api/dcim/sites/"
 	SiteGroupsAPIPath            APIPath = "/api/dcim/site-groups/"
 	RegionsAPIPath               APIPath = "/api/dcim/regions/"
 	LocationsAPIPath             APIPath = "/api/dcim/locations/"
 	ManufacturersAPIPath         APIPath = "/api/dcim/manufacturers/"
 	PlatformsAPIPath             APIPath = "/api/dcim/platforms/"
+	VirtualChassisAPIPath        APIPath = "/api/dcim/virtual-chassis/"
 	VirtualDeviceContextsAPIPath APIPath = "/api/dcim/virtual-device-contexts/"
 
 	// Wireless paths.
@@ -314,6 +382,9 @@ const (
 	// Extras paths.
 	CustomFieldsAPIPath APIPath = "/api/extras/custom-fields/"
 	TagsAPIPath         APIPath = "/api/extras/tags/"
+
+	// Plugin paths.
+	BranchesAPIPath APIPath = "/api/plugins/branching/branches/"
 )
 
 var Arch2Bit = map[string]string{
@@ -340,6 +411,8 @@ const (
 	MaxVMInterfaceNameLength = 64
 	MaxVirtualDiskNameLength = 64
 
+	MaxInventoryItemNameLength = 64
+
 	//nolint:lll
 	// Limitations for devices https://github.com/netbox-community/netbox/blob/d03d302eef3819db64cad8ae74dc5255647045f6/netbox/dcim/models/device_components.py.
 	MaxDeviceNameLength   = 64
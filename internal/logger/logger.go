@@ -2,12 +2,14 @@ package logger
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"log"
 	"os"
 	"path/filepath"
 	"runtime"
+	"time"
 
 	"github.com/src-doo/netbox-ssot/internal/constants"
 )
@@ -20,28 +22,136 @@ const (
 	ERROR
 )
 
-const logCallDepth = 2
+// Supported log formats.
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+// Actions on netbox objects, see WithObject.
+const (
+	ActionCreate = "create"
+	ActionPatch  = "patch"
+	ActionDelete = "delete"
+)
+
+// Call depth of the caller of the logging functions, relative to Output.
+const logCallDepth = 3
 
 type Logger struct {
 	*log.Logger
 	// Level of the logger (DEBUG, INFO, WARNING, ERROR).
 	level int
+	// Format of the log lines (FormatText or FormatJSON).
+	format string
+	// file is the log file, nil when logging to stdout.
+	file io.Closer
+}
+
+// Options configure the destination and the format of the Logger.
+type Options struct {
+	// Dest is the path of the log file. If empty, logs are written to stdout.
+	Dest string
+	// Level of the logger (DEBUG, INFO, WARNING, ERROR).
+	Level int
+	// Format is either FormatText (default) or FormatJSON.
+	Format string
+	// Append to the existing log file instead of truncating it.
+	Append bool
+	// MaxSize in megabytes, after which the log file is rotated.
+	// 0 disables rotation.
+	MaxSize int
+	// MaxAge is the number of days to keep rotated log files. 0 keeps them forever.
+	MaxAge int
+	// MaxBackups is the number of rotated log files to keep. 0 keeps all of them.
+	MaxBackups int
 }
 
 // New creates a new Logger instance, which writes to the specified destination (file) or stdout if dest is empty.
 // It also sets the log level.
 func New(dest string, logLevel int) (*Logger, error) {
-	var output io.Writer
-	if dest == "" {
-		output = os.Stdout
-	} else {
-		file, err := os.Create(dest)
+	return NewWithOptions(Options{Dest: dest, Level: logLevel})
+}
+
+// NewWithOptions creates a new Logger instance configured with options.
+func NewWithOptions(options Options) (*Logger, error) {
+	logger := &Logger{level: options.Level, format: options.Format}
+	var output io.Writer = os.Stdout
+	if options.Dest != "" {
+		file, err := newRotatingFile(options)
 		if err != nil {
 			return nil, err
 		}
 		output = file
+		logger.file = file
 	}
-	return &Logger{log.New(output, "", log.LstdFlags), logLevel}, nil
+	flags := log.LstdFlags
+	if options.Format == FormatJSON {
+		// Timestamp is part of the json object
+		flags = 0
+	}
+	logger.Logger = log.New(output, "", flags)
+	return logger, nil
+}
+
+// Close closes the log file, if the logger writes to one.
+func (l *Logger) Close() error {
+	if l.file == nil {
+		return nil
+	}
+	return l.file.Close()
+}
+
+// WithObject returns a copy of ctx, that carries information about the
+// netbox object being changed. It is included in json log lines as
+// object_type, object_name and action fields.
+func WithObject(ctx context.Context, objectType, objectName, action string) context.Context {
+	ctx = context.WithValue(ctx, constants.CtxObjectTypeKey, objectType)
+	ctx = context.WithValue(ctx, constants.CtxObjectNameKey, objectName)
+	return context.WithValue(ctx, constants.CtxActionKey, action)
+}
+
+// jsonLine is a single log line in json format.
+type jsonLine struct {
+	Time       string `json:"time"`
+	Level      string `json:"level"`
+	Source     string `json:"source,omitempty"`
+	ObjectType string `json:"object_type,omitempty"`
+	ObjectName string `json:"object_name,omitempty"`
+	Action     string `json:"action,omitempty"`
+	Caller     string `json:"caller,omitempty"`
+	Message    string `json:"msg"`
+}
+
+// log writes message on the given level, in the format of the logger.
+func (l *Logger) log(ctx context.Context, level string, message string) error {
+	if l.format != FormatJSON {
+		return l.Output(
+			logCallDepth,
+			fmt.Sprintf("%-7s (%s): %s", level, ctx.Value(constants.CtxSourceKey), message),
+		)
+	}
+
+	line := jsonLine{
+		Time:    time.Now().Format(time.RFC3339),
+		Level:   level,
+		Message: message,
+	}
+	line.Source, _ = ctx.Value(constants.CtxSourceKey).(string)
+	line.ObjectType, _ = ctx.Value(constants.CtxObjectTypeKey).(string)
+	line.ObjectName, _ = ctx.Value(constants.CtxObjectNameKey).(string)
+	line.Action, _ = ctx.Value(constants.CtxActionKey).(string)
+	if l.level <= DEBUG {
+		if _, file, lineNumber, ok := runtime.Caller(logCallDepth - 1); ok {
+			line.Caller = fmt.Sprintf("%s:%d", filepath.Base(file), lineNumber)
+		}
+	}
+	lineJSON, err := json.Marshal(line)
+	if err != nil {
+		return err
+	}
+	l.Println(string(lineJSON))
+	return nil
 }
 
 // Custom log output function. It is used to add additional runtime information to the log message.
@@ -73,15 +183,7 @@ func (l *Logger) Output(calldepth int, s string) error {
 
 func (l *Logger) Debug(ctx context.Context, v ...interface{}) error {
 	if l.level <= DEBUG {
-		return l.Output(
-			logCallDepth,
-			fmt.Sprintf(
-				"%-7s (%s): %s",
-				"DEBUG",
-				ctx.Value(constants.CtxSourceKey),
-				fmt.Sprint(v...),
-			),
-		)
+		return l.log(ctx, "DEBUG", fmt.Sprint(v...))
 	}
 	return nil
 }
@@ -89,30 +191,14 @@ func (l *Logger) Debug(ctx context.Context, v ...interface{}) error {
 // Debugf logs a formatted debug message.
 func (l *Logger) Debugf(ctx context.Context, format string, v ...interface{}) error {
 	if l.level <= DEBUG {
-		return l.Output(
-			logCallDepth,
-			fmt.Sprintf(
-				"%-7s (%s): %s",
-				"DEBUG",
-				ctx.Value(constants.CtxSourceKey),
-				fmt.Sprintf(format, v...),
-			),
-		)
+		return l.log(ctx, "DEBUG", fmt.Sprintf(format, v...))
 	}
 	return nil
 }
 
 func (l *Logger) Info(ctx context.Context, v ...interface{}) error {
 	if l.level <= INFO {
-		return l.Output(
-			logCallDepth,
-			fmt.Sprintf(
-				"%-7s (%s): %s",
-				"INFO",
-				ctx.Value(constants.CtxSourceKey),
-				fmt.Sprint(v...),
-			),
-		)
+		return l.log(ctx, "INFO", fmt.Sprint(v...))
 	}
 	return nil
 }
@@ -120,30 +206,14 @@ func (l *Logger) Info(ctx context.Context, v ...interface{}) error {
 // Infof logs a formatted info message.
 func (l *Logger) Infof(ctx context.Context, format string, v ...interface{}) error {
 	if l.level <= INFO {
-		return l.Output(
-			logCallDepth,
-			fmt.Sprintf(
-				"%-7s (%s): %s",
-				"INFO",
-				ctx.Value(constants.CtxSourceKey),
-				fmt.Sprintf(format, v...),
-			),
-		)
+		return l.log(ctx, "INFO", fmt.Sprintf(format, v...))
 	}
 	return nil
 }
 
 func (l *Logger) Warning(ctx context.Context, v ...interface{}) error {
 	if l.level <= WARNING {
-		return l.Output(
-			logCallDepth,
-			fmt.Sprintf(
-				"%-7s (%s): %s",
-				"WARNING",
-				ctx.Value(constants.CtxSourceKey),
-				fmt.Sprint(v...),
-			),
-		)
+		return l.log(ctx, "WARNING", fmt.Sprint(v...))
 	}
 	return nil
 }
@@ -151,30 +221,14 @@ func (l *Logger) Warning(ctx context.Context, v ...interface{}) error {
 // Warningf logs a formatted warning message.
 func (l *Logger) Warningf(ctx context.Context, format string, v ...interface{}) error {
 	if l.level <= WARNING {
-		return l.Output(
-			logCallDepth,
-			fmt.Sprintf(
-				"%-7s (%s): %s",
-				"WARNING",
-				ctx.Value(constants.CtxSourceKey),
-				fmt.Sprintf(format, v...),
-			),
-		)
+		return l.log(ctx, "WARNING", fmt.Sprintf(format, v...))
 	}
 	return nil
 }
 
 func (l *Logger) Error(ctx context.Context, v ...interface{}) error {
 	if l.level <= ERROR {
-		return l.Output(
-			logCallDepth,
-			fmt.Sprintf(
-				"%-7s (%s): %s",
-				"ERROR",
-				ctx.Value(constants.CtxSourceKey),
-				fmt.Sprint(v...),
-			),
-		)
+		return l.log(ctx, "ERROR", fmt.Sprint(v...))
 	}
 	return nil
 }
@@ -182,15 +236,7 @@ func (l *Logger) Error(ctx context.Context, v ...interface{}) error {
 // Errorf logs a formatted error message.
 func (l *Logger) Errorf(ctx context.Context, format string, v ...interface{}) error {
 	if l.level <= ERROR {
-		return l.Output(
-			logCallDepth,
-			fmt.Sprintf(
-				"%-7s (%s): %s",
-				"ERROR",
-				ctx.Value(constants.CtxSourceKey),
-				fmt.Sprintf(format, v...),
-			),
-		)
+		return l.log(ctx, "ERROR", fmt.Sprintf(format, v...))
 	}
 	return nil
 }
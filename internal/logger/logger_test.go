@@ -3,6 +3,9 @@ package logger
 import (
 	"bytes"
 	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -197,3 +200,60 @@ func TestOutputUnknownCaller(t *testing.T) {
 		t.Errorf("Expected \"???\" in output, but didn't get it")
 	}
 }
+
+func TestJSONFormat(t *testing.T) {
+	l, err := NewWithOptions(Options{Level: INFO, Format: FormatJSON})
+	if err != nil {
+		t.Fatalf("Error creating logger: %v", err)
+	}
+	buf := new(bytes.Buffer)
+	l.SetOutput(buf)
+
+	ctx := context.WithValue(context.Background(), constants.CtxSourceKey, "vcenter")
+	ctx = WithObject(ctx, "objects.Device", "server1", ActionCreate)
+	err = l.Infof(ctx, "Creating %s", "server1")
+	if err != nil {
+		t.Fatalf("Error writing to logger: %v", err)
+	}
+
+	var got jsonLine
+	err = json.Unmarshal(buf.Bytes(), &got)
+	if err != nil {
+		t.Fatalf("Output %q is not valid json: %v", buf.String(), err)
+	}
+	if got.Time == "" {
+		t.Errorf("Expected time in json output, got %q", buf.String())
+	}
+	got.Time = ""
+	want := jsonLine{
+		Level:      "INFO",
+		Source:     "vcenter",
+		ObjectType: "objects.Device",
+		ObjectName: "server1",
+		Action:     ActionCreate,
+		Message:    "Creating server1",
+	}
+	if got != want {
+		t.Errorf("got = %+v, want %+v", got, want)
+	}
+}
+
+func TestAppendMode(t *testing.T) {
+	dest := filepath.Join(t.TempDir(), "netbox-ssot.log")
+	testCtx := context.WithValue(context.Background(), constants.CtxSourceKey, "test")
+	for _, message := range []string{"first run", "second run"} {
+		l, err := NewWithOptions(Options{Dest: dest, Level: INFO, Append: true})
+		if err != nil {
+			t.Fatalf("Error creating logger: %v", err)
+		}
+		l.Info(testCtx, message)
+		l.Close()
+	}
+	content, err := os.ReadFile(dest)
+	if err != nil {
+		t.Fatalf("Error reading log file: %v", err)
+	}
+	if !strings.Contains(string(content), "first run") || !strings.Contains(string(content), "second run") {
+		t.Errorf("Expected logs of both runs, got %q", content)
+	}
+}
@@ -0,0 +1,131 @@
+package logger
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/src-doo/netbox-ssot/internal/constants"
+)
+
+// Layout of the timestamp appended to the names of rotated log files.
+const rotatedFileTimeLayout = "2006-01-02T15-04-05.000"
+
+// rotatingFile is a log file, that is rotated once it exceeds maxSize.
+// Rotated files are renamed to <path>.<timestamp> and removed once
+// they are older than maxAge or there are more than maxBackups of them.
+type rotatingFile struct {
+	path       string
+	maxSize    int64
+	maxAge     time.Duration
+	maxBackups int
+
+	lock sync.Mutex
+	file *os.File
+	size int64
+}
+
+func newRotatingFile(options Options) (*rotatingFile, error) {
+	r := &rotatingFile{
+		path:       options.Dest,
+		maxSize:    int64(options.MaxSize) * constants.MiB,
+		maxAge:     time.Duration(options.MaxAge) * 24 * time.Hour, //nolint:mnd
+		maxBackups: options.MaxBackups,
+	}
+	flags := os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	if options.Append {
+		flags = os.O_WRONLY | os.O_CREATE | os.O_APPEND
+	}
+	err := r.open(flags)
+	if err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *rotatingFile) open(flags int) error {
+	file, err := os.OpenFile(r.path, flags, 0o644) //nolint:mnd,gosec
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+	r.file = file
+	r.size = info.Size()
+	return nil
+}
+
+func (r *rotatingFile) Write(p []byte) (int, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if r.maxSize > 0 && r.size > 0 && r.size+int64(len(p)) > r.maxSize {
+		err := r.rotate()
+		if err != nil {
+			return 0, fmt.Errorf("rotate log file: %s", err)
+		}
+	}
+	n, err := r.file.Write(p)
+	r.size += int64(n)
+	return n, err
+}
+
+func (r *rotatingFile) Close() error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return r.file.Close()
+}
+
+// rotate renames the current log file, opens a new one and
+// removes rotated files exceeding maxAge and maxBackups.
+func (r *rotatingFile) rotate() error {
+	err := r.file.Close()
+	if err != nil {
+		return err
+	}
+	rotatedPath := fmt.Sprintf("%s.%s", r.path, time.Now().Format(rotatedFileTimeLayout))
+	err = os.Rename(r.path, rotatedPath)
+	if err != nil {
+		return err
+	}
+	err = r.open(os.O_WRONLY | os.O_CREATE | os.O_TRUNC)
+	if err != nil {
+		return err
+	}
+	return r.removeOldBackups()
+}
+
+func (r *rotatingFile) removeOldBackups() error {
+	backups, err := filepath.Glob(r.path + ".*")
+	if err != nil {
+		return err
+	}
+	// Timestamp layout sorts lexicographically, newest first
+	sort.Sort(sort.Reverse(sort.StringSlice(backups)))
+	kept := 0
+	for _, backup := range backups {
+		timestamp := strings.TrimPrefix(backup, r.path+".")
+		rotatedAt, err := time.ParseInLocation(rotatedFileTimeLayout, timestamp, time.Local)
+		if err != nil {
+			// Not a file rotated by us
+			continue
+		}
+		tooMany := r.maxBackups > 0 && kept >= r.maxBackups
+		tooOld := r.maxAge > 0 && time.Since(rotatedAt) > r.maxAge
+		if !tooMany && !tooOld {
+			kept++
+			continue
+		}
+		err = os.Remove(backup)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package logger
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/src-doo/netbox-ssot/internal/constants"
+)
+
+func TestRotatingFile(t *testing.T) {
+	dir := t.TempDir()
+	dest := filepath.Join(dir, "netbox-ssot.log")
+	// Rotated file older than max age
+	oldBackup := fmt.Sprintf("%s.%s", dest, time.Now().Add(-48*time.Hour).Format(rotatedFileTimeLayout))
+	err := os.WriteFile(oldBackup, []byte("old"), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := newRotatingFile(Options{Dest: dest, MaxSize: 1, MaxAge: 1})
+	if err != nil {
+		t.Fatalf("newRotatingFile() error = %v", err)
+	}
+	defer r.Close()
+	line := []byte(strings.Repeat("a", constants.MiB/2-1) + "\n")
+	for i := 0; i < 3; i++ {
+		_, err = r.Write(line)
+		if err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+	}
+
+	backups, err := filepath.Glob(dest + ".*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(backups) != 1 || backups[0] == oldBackup {
+		t.Errorf("Expected one new rotated file, got %v", backups)
+	}
+	info, err := os.Stat(dest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Size() != int64(len(line)) {
+		t.Errorf("Expected log file with a single line after rotation, got size %d", info.Size())
+	}
+}
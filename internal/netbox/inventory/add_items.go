@@ -3,6 +3,7 @@ package inventory
 import (
 	"context"
 	"fmt"
+	"slices"
 	"strings"
 
 	"github.com/src-doo/netbox-ssot/internal/constants"
@@ -13,6 +14,9 @@ import (
 
 // AddTag adds the newTag from source sourceName to the local inventory.
 func (nbi *NetboxInventory) AddTag(ctx context.Context, newTag *objects.Tag) (*objects.Tag, error) {
+	ctx, span := startSpan(ctx, "AddTag", "Tag")
+	defer span.End()
+	defer nbi.inFlight.acquire(constants.TagsAPIPath, newTag.Name)()
 	nbi.tagsLock.Lock()
 	defer nbi.tagsLock.Unlock()
 	if _, ok := nbi.tagsIndexByName[newTag.Name]; ok {
@@ -27,7 +31,7 @@ func (nbi *NetboxInventory) AddTag(ctx context.Context, newTag *objects.Tag) (*o
 				"Tag %s already exists in Netbox but is out of date. Patching it... ",
 				newTag.Name,
 			)
-			patchedTag, err := service.Patch[objects.Tag](ctx, nbi.NetboxAPI, oldTag.ID, diffMap)
+			patchedTag, err := patchUnlocked[objects.Tag](ctx, nbi, &nbi.tagsLock, oldTag.ID, diffMap)
 			if err != nil {
 				return nil, err
 			}
@@ -37,7 +41,7 @@ func (nbi *NetboxInventory) AddTag(ctx context.Context, newTag *objects.Tag) (*o
 		}
 	} else {
 		nbi.Logger.Debugf(ctx, "Tag %s does not exist in Netbox. Creating it...", newTag.Name)
-		createdTag, err := service.Create(ctx, nbi.NetboxAPI, newTag)
+		createdTag, err := createUnlocked(ctx, nbi, &nbi.tagsLock, newTag)
 		if err != nil {
 			return nil, err
 		}
@@ -51,7 +55,10 @@ func (nbi *NetboxInventory) AddTenant(
 	ctx context.Context,
 	newTenant *objects.Tenant,
 ) (*objects.Tenant, error) {
+	ctx, span := startSpan(ctx, "AddTenant", "Tenant")
+	defer span.End()
 	newTenant.NetboxObject.AddTag(nbi.SsotTag)
+	defer nbi.inFlight.acquire(constants.TenantsAPIPath, newTenant.Name)()
 	nbi.tenantsLock.Lock()
 	defer nbi.tenantsLock.Unlock()
 	if _, ok := nbi.tenantsIndexByName[newTenant.Name]; ok {
@@ -66,9 +73,10 @@ func (nbi *NetboxInventory) AddTenant(
 				"Tenant %s already exists in Netbox but is out of date. Patching it...",
 				newTenant.Name,
 			)
-			patchedTenant, err := service.Patch[objects.Tenant](
+			patchedTenant, err := patchUnlocked[objects.Tenant](
 				ctx,
-				nbi.NetboxAPI,
+				nbi,
+				&nbi.tenantsLock,
 				oldTenant.ID,
 				diffMap,
 			)
@@ -81,7 +89,7 @@ func (nbi *NetboxInventory) AddTenant(
 		}
 	} else {
 		nbi.Logger.Debugf(ctx, "Tenant %s does not exist in Netbox. Creating it...", newTenant.Name)
-		createdTag, err := service.Create(ctx, nbi.NetboxAPI, newTenant)
+		createdTag, err := createUnlocked(ctx, nbi, &nbi.tenantsLock, newTenant)
 		if err != nil {
 			return nil, err
 		}
@@ -90,12 +98,63 @@ func (nbi *NetboxInventory) AddTenant(
 	return nbi.tenantsIndexByName[newTenant.Name], nil
 }
 
+// AddTenantGroup adds a new tenant group to the local netbox inventory.
+func (nbi *NetboxInventory) AddTenantGroup(
+	ctx context.Context,
+	newTenantGroup *objects.TenantGroup,
+) (*objects.TenantGroup, error) {
+	ctx, span := startSpan(ctx, "AddTenantGroup", "TenantGroup")
+	defer span.End()
+	newTenantGroup.NetboxObject.AddTag(nbi.SsotTag)
+	defer nbi.inFlight.acquire(constants.TenantGroupsAPIPath, newTenantGroup.Name)()
+	nbi.tenantGroupsLock.Lock()
+	defer nbi.tenantGroupsLock.Unlock()
+	if _, ok := nbi.tenantGroupsIndexByName[newTenantGroup.Name]; ok {
+		oldTenantGroup := nbi.tenantGroupsIndexByName[newTenantGroup.Name]
+		diffMap, err := utils.JSONDiffMapExceptID(newTenantGroup, oldTenantGroup, false, nbi.SourcePriority)
+		if err != nil {
+			return nil, err
+		}
+		if len(diffMap) > 0 {
+			nbi.Logger.Debugf(
+				ctx,
+				"TenantGroup %s already exists in Netbox but is out of date. Patching it...",
+				newTenantGroup.Name,
+			)
+			patchedTenantGroup, err := patchUnlocked[objects.TenantGroup](
+				ctx,
+				nbi,
+				&nbi.tenantGroupsLock,
+				oldTenantGroup.ID,
+				diffMap,
+			)
+			if err != nil {
+				return nil, err
+			}
+			nbi.tenantGroupsIndexByName[newTenantGroup.Name] = patchedTenantGroup
+		} else {
+			nbi.Logger.Debugf(ctx, "TenantGroup %s already exists in Netbox and is up to date...", newTenantGroup.Name)
+		}
+	} else {
+		nbi.Logger.Debugf(ctx, "TenantGroup %s does not exist in Netbox. Creating it...", newTenantGroup.Name)
+		createdTenantGroup, err := createUnlocked(ctx, nbi, &nbi.tenantGroupsLock, newTenantGroup)
+		if err != nil {
+			return nil, err
+		}
+		nbi.tenantGroupsIndexByName[newTenantGroup.Name] = createdTenantGroup
+	}
+	return nbi.tenantGroupsIndexByName[newTenantGroup.Name], nil
+}
+
 // AddSite adds a site to the local netbox inventory.
 func (nbi *NetboxInventory) AddSite(
 	ctx context.Context,
 	newSite *objects.Site,
 ) (*objects.Site, error) {
+	ctx, span := startSpan(ctx, "AddSite", "Site")
+	defer span.End()
 	newSite.NetboxObject.AddTag(nbi.SsotTag)
+	defer nbi.inFlight.acquire(constants.SitesAPIPath, newSite.Name)()
 	nbi.sitesLock.Lock()
 	defer nbi.sitesLock.Unlock()
 	if _, ok := nbi.sitesIndexByName[newSite.Name]; ok {
@@ -110,7 +169,7 @@ func (nbi *NetboxInventory) AddSite(
 				"Site %s already exists in Netbox but is out of date. Patching it... ",
 				newSite.Name,
 			)
-			patchedSite, err := service.Patch[objects.Site](ctx, nbi.NetboxAPI, oldSite.ID, diffMap)
+			patchedSite, err := patchUnlocked[objects.Site](ctx, nbi, &nbi.sitesLock, oldSite.ID, diffMap)
 			if err != nil {
 				return nil, err
 			}
@@ -120,7 +179,7 @@ func (nbi *NetboxInventory) AddSite(
 		}
 	} else {
 		nbi.Logger.Debugf(ctx, "Site %s does not exist in Netbox. Creating it...", newSite.Name)
-		createdContact, err := service.Create(ctx, nbi.NetboxAPI, newSite)
+		createdContact, err := createUnlocked(ctx, nbi, &nbi.sitesLock, newSite)
 		if err != nil {
 			return nil, err
 		}
@@ -134,9 +193,12 @@ func (nbi *NetboxInventory) AddSiteGroup(
 	ctx context.Context,
 	newSiteGroup *objects.SiteGroup,
 ) (*objects.SiteGroup, error) {
+	ctx, span := startSpan(ctx, "AddSiteGroup", "SiteGroup")
+	defer span.End()
 	newSiteGroup.NetboxObject.AddTag(nbi.SsotTag)
+	defer nbi.inFlight.acquire(constants.SiteGroupsAPIPath, newSiteGroup.Name)()
 	nbi.siteGroupsLock.Lock()
-	defer nbi.sitesLock.Unlock()
+	defer nbi.siteGroupsLock.Unlock()
 	if _, ok := nbi.siteGroupsIndexByName[newSiteGroup.Name]; ok {
 		oldSiteGroup := nbi.siteGroupsIndexByName[newSiteGroup.Name]
 		diffMap, err := utils.JSONDiffMapExceptID(
@@ -154,9 +216,10 @@ func (nbi *NetboxInventory) AddSiteGroup(
 				"SiteGroup %s already exists in Netbox but is out of date. Patching it...",
 				newSiteGroup.Name,
 			)
-			patchedSiteGroup, err := service.Patch[objects.SiteGroup](
+			patchedSiteGroup, err := patchUnlocked[objects.SiteGroup](
 				ctx,
-				nbi.NetboxAPI,
+				nbi,
+				&nbi.siteGroupsLock,
 				oldSiteGroup.ID,
 				diffMap,
 			)
@@ -169,7 +232,7 @@ func (nbi *NetboxInventory) AddSiteGroup(
 		}
 	} else {
 		nbi.Logger.Debugf(ctx, "SiteGroup %s does not exist in Netbox. Creating it...", newSiteGroup.Name)
-		createdSiteGroup, err := service.Create(ctx, nbi.NetboxAPI, newSiteGroup)
+		createdSiteGroup, err := createUnlocked(ctx, nbi, &nbi.siteGroupsLock, newSiteGroup)
 		if err != nil {
 			return nil, err
 		}
@@ -178,13 +241,132 @@ func (nbi *NetboxInventory) AddSiteGroup(
 	return nbi.siteGroupsIndexByName[newSiteGroup.Name], nil
 }
 
+// AddRegion adds a region to the local netbox inventory.
+// Regions are matched by their parent region and name, so regions
+// with the same name in different parent regions are separate regions.
+func (nbi *NetboxInventory) AddRegion(
+	ctx context.Context,
+	newRegion *objects.Region,
+) (*objects.Region, error) {
+	ctx, span := startSpan(ctx, "AddRegion", "Region")
+	defer span.End()
+	newRegion.NetboxObject.AddTag(nbi.SsotTag)
+	parentID := regionParentID(newRegion)
+	defer nbi.inFlight.acquire(constants.RegionsAPIPath, parentID, newRegion.Name)()
+	nbi.regionsLock.Lock()
+	defer nbi.regionsLock.Unlock()
+	if nbi.regionsIndexByParentIDAndName[parentID] == nil {
+		nbi.regionsIndexByParentIDAndName[parentID] = make(map[string]*objects.Region)
+	}
+	if oldRegion, ok := nbi.regionsIndexByParentIDAndName[parentID][newRegion.Name]; ok {
+		diffMap, err := utils.JSONDiffMapExceptID(newRegion, oldRegion, false, nbi.SourcePriority)
+		if err != nil {
+			return nil, err
+		}
+		if len(diffMap) > 0 {
+			nbi.Logger.Debugf(
+				ctx,
+				"Region %s already exists in Netbox but is out of date. Patching it...",
+				newRegion.Name,
+			)
+			patchedRegion, err := patchUnlocked[objects.Region](
+				ctx,
+				nbi,
+				&nbi.regionsLock,
+				oldRegion.ID,
+				diffMap,
+			)
+			if err != nil {
+				return nil, err
+			}
+			nbi.regionsIndexByParentIDAndName[parentID][newRegion.Name] = patchedRegion
+		} else {
+			nbi.Logger.Debugf(ctx, "Region %s already exists in Netbox and is up to date...", newRegion.Name)
+		}
+	} else {
+		nbi.Logger.Debugf(ctx, "Region %s does not exist in Netbox. Creating it...", newRegion.Name)
+		createdRegion, err := createUnlocked(ctx, nbi, &nbi.regionsLock, newRegion)
+		if err != nil {
+			return nil, err
+		}
+		nbi.regionsIndexByParentIDAndName[parentID][newRegion.Name] = createdRegion
+	}
+	return nbi.regionsIndexByParentIDAndName[parentID][newRegion.Name], nil
+}
+
+// regionParentID returns the id of the parent of the region,
+// or 0 for top level regions.
+func regionParentID(region *objects.Region) int {
+	if region.Parent == nil {
+		return 0
+	}
+	return region.Parent.ID
+}
+
+// AddLocation adds a location to the local netbox inventory.
+// Locations are unique by their site and name.
+func (nbi *NetboxInventory) AddLocation(
+	ctx context.Context,
+	newLocation *objects.Location,
+) (*objects.Location, error) {
+	ctx, span := startSpan(ctx, "AddLocation", "Location")
+	defer span.End()
+	if newLocation.Site == nil {
+		return nil, fmt.Errorf("location %s has no site", newLocation.Name)
+	}
+	newLocation.NetboxObject.AddTag(nbi.SsotTag)
+	defer nbi.inFlight.acquire(constants.LocationsAPIPath, newLocation.Site.ID, newLocation.Name)()
+	nbi.locationsLock.Lock()
+	defer nbi.locationsLock.Unlock()
+	if _, ok := nbi.locationsIndexBySiteIDAndName[newLocation.Site.ID]; !ok {
+		nbi.locationsIndexBySiteIDAndName[newLocation.Site.ID] = make(map[string]*objects.Location)
+	}
+	if oldLocation, ok := nbi.locationsIndexBySiteIDAndName[newLocation.Site.ID][newLocation.Name]; ok {
+		diffMap, err := utils.JSONDiffMapExceptID(newLocation, oldLocation, false, nbi.SourcePriority)
+		if err != nil {
+			return nil, err
+		}
+		if len(diffMap) > 0 {
+			nbi.Logger.Debugf(
+				ctx,
+				"Location %s already exists in Netbox but is out of date. Patching it...",
+				newLocation.Name,
+			)
+			patchedLocation, err := patchUnlocked[objects.Location](
+				ctx,
+				nbi,
+				&nbi.locationsLock,
+				oldLocation.ID,
+				diffMap,
+			)
+			if err != nil {
+				return nil, err
+			}
+			nbi.locationsIndexBySiteIDAndName[newLocation.Site.ID][newLocation.Name] = patchedLocation
+		} else {
+			nbi.Logger.Debugf(ctx, "Location %s already exists in Netbox and is up to date...", newLocation.Name)
+		}
+	} else {
+		nbi.Logger.Debugf(ctx, "Location %s does not exist in Netbox. Creating it...", newLocation.Name)
+		createdLocation, err := createUnlocked(ctx, nbi, &nbi.locationsLock, newLocation)
+		if err != nil {
+			return nil, err
+		}
+		nbi.locationsIndexBySiteIDAndName[newLocation.Site.ID][newLocation.Name] = createdLocation
+	}
+	return nbi.locationsIndexBySiteIDAndName[newLocation.Site.ID][newLocation.Name], nil
+}
+
 // AddContactRole adds the newContactRole to the local netbox inventory.
 func (nbi *NetboxInventory) AddContactRole(
 	ctx context.Context,
 	newContactRole *objects.ContactRole,
 ) (*objects.ContactRole, error) {
+	ctx, span := startSpan(ctx, "AddContactRole", "ContactRole")
+	defer span.End()
 	newContactRole.NetboxObject.AddTag(nbi.SsotTag)
 	addSourceNameCustomField(ctx, &newContactRole.NetboxObject)
+	defer nbi.inFlight.acquire(constants.ContactRolesAPIPath, newContactRole.Name)()
 	nbi.contactRolesLock.Lock()
 	defer nbi.contactRolesLock.Unlock()
 	if _, ok := nbi.contactRolesIndexByName[newContactRole.Name]; ok {
@@ -204,9 +386,10 @@ func (nbi *NetboxInventory) AddContactRole(
 				"Contact role %s already exists in Netbox but is out of date. Patching it...",
 				newContactRole.Name,
 			)
-			patchedContactRole, err := service.Patch[objects.ContactRole](
+			patchedContactRole, err := patchUnlocked[objects.ContactRole](
 				ctx,
-				nbi.NetboxAPI,
+				nbi,
+				&nbi.contactRolesLock,
 				oldContactRole.ID,
 				diffMap,
 			)
@@ -219,7 +402,7 @@ func (nbi *NetboxInventory) AddContactRole(
 		}
 	} else {
 		nbi.Logger.Debugf(ctx, "Contact role %s does not exist in Netbox. Creating it...", newContactRole.Name)
-		newContactRole, err := service.Create(ctx, nbi.NetboxAPI, newContactRole)
+		newContactRole, err := createUnlocked(ctx, nbi, &nbi.contactRolesLock, newContactRole)
 		if err != nil {
 			return nil, err
 		}
@@ -233,7 +416,10 @@ func (nbi *NetboxInventory) AddContactGroup(
 	ctx context.Context,
 	newContactGroup *objects.ContactGroup,
 ) (*objects.ContactGroup, error) {
+	ctx, span := startSpan(ctx, "AddContactGroup", "ContactGroup")
+	defer span.End()
 	newContactGroup.NetboxObject.AddTag(nbi.SsotTag)
+	defer nbi.inFlight.acquire(constants.ContactGroupsAPIPath, newContactGroup.Name)()
 	nbi.contactGroupsLock.Lock()
 	defer nbi.contactGroupsLock.Unlock()
 	if _, ok := nbi.contactGroupsIndexByName[newContactGroup.Name]; ok {
@@ -253,9 +439,10 @@ func (nbi *NetboxInventory) AddContactGroup(
 				"Contact group %s already exists in Netbox but is out of date. Patching it...",
 				newContactGroup.Name,
 			)
-			patchedContactGroup, err := service.Patch[objects.ContactGroup](
+			patchedContactGroup, err := patchUnlocked[objects.ContactGroup](
 				ctx,
-				nbi.NetboxAPI,
+				nbi,
+				&nbi.contactGroupsLock,
 				oldContactGroup.ID,
 				diffMap,
 			)
@@ -268,7 +455,7 @@ func (nbi *NetboxInventory) AddContactGroup(
 		}
 	} else {
 		nbi.Logger.Debugf(ctx, "Contact group %s does not exist in Netbox. Creating it...", newContactGroup.Name)
-		newContactGroup, err := service.Create(ctx, nbi.NetboxAPI, newContactGroup)
+		newContactGroup, err := createUnlocked(ctx, nbi, &nbi.contactGroupsLock, newContactGroup)
 		if err != nil {
 			return nil, err
 		}
@@ -282,13 +469,16 @@ func (nbi *NetboxInventory) AddContact(
 	ctx context.Context,
 	newContact *objects.Contact,
 ) (*objects.Contact, error) {
+	ctx, span := startSpan(ctx, "AddContact", "Contact")
+	defer span.End()
 	newContact.NetboxObject.AddTag(nbi.SsotTag)
 	newContact.SetCustomField(constants.CustomFieldOrphanLastSeenName, nil)
+	defer nbi.inFlight.acquire(constants.ContactsAPIPath, newContact.Name)()
 	nbi.contactsLock.Lock()
 	defer nbi.contactsLock.Unlock()
 	if _, ok := nbi.contactsIndexByName[newContact.Name]; ok {
 		oldContact := nbi.contactsIndexByName[newContact.Name]
-		nbi.OrphanManager.RemoveItem(oldContact)
+		nbi.OrphanManager.RemoveItem(ctx, oldContact)
 		diffMap, err := utils.JSONDiffMapExceptID(newContact, oldContact, false, nbi.SourcePriority)
 		if err != nil {
 			return nil, err
@@ -299,9 +489,10 @@ func (nbi *NetboxInventory) AddContact(
 				"Contact %s already exists in Netbox but is out of date. Patching it...",
 				newContact.Name,
 			)
-			patchedContact, err := service.Patch[objects.Contact](
+			patchedContact, err := patchUnlocked[objects.Contact](
 				ctx,
-				nbi.NetboxAPI,
+				nbi,
+				&nbi.contactsLock,
 				oldContact.ID,
 				diffMap,
 			)
@@ -314,7 +505,7 @@ func (nbi *NetboxInventory) AddContact(
 		}
 	} else {
 		nbi.Logger.Debugf(ctx, "Contact %s does not exist in Netbox. Creating it...", newContact.Name)
-		createdContact, err := service.Create(ctx, nbi.NetboxAPI, newContact)
+		createdContact, err := createUnlocked(ctx, nbi, &nbi.contactsLock, newContact)
 		if err != nil {
 			return nil, err
 		}
@@ -329,8 +520,17 @@ func (nbi *NetboxInventory) AddContactAssignment(
 	ctx context.Context,
 	newCA *objects.ContactAssignment,
 ) (*objects.ContactAssignment, error) {
+	ctx, span := startSpan(ctx, "AddContactAssignment", "ContactAssignment")
+	defer span.End()
 	newCA.NetboxObject.AddTag(nbi.SsotTag)
 	newCA.SetCustomField(constants.CustomFieldOrphanLastSeenName, nil)
+	defer nbi.inFlight.acquire(
+		constants.ContactAssignmentsAPIPath,
+		newCA.ModelType,
+		newCA.ObjectID,
+		newCA.Contact.ID,
+		newCA.Role.ID,
+	)()
 	nbi.contactAssignmentsLock.Lock()
 	defer nbi.contactAssignmentsLock.Unlock()
 	if nbi.contactAssignmentsIndex[newCA.ModelType] == nil {
@@ -351,7 +551,7 @@ func (nbi *NetboxInventory) AddContactAssignment(
 	newCA.Tags = append(newCA.Tags, nbi.SsotTag)
 	if _, ok := nbi.contactAssignmentsIndex[newCA.ModelType][newCA.ObjectID][newCA.Contact.ID][newCA.Role.ID]; ok {
 		oldCA := nbi.contactAssignmentsIndex[newCA.ModelType][newCA.ObjectID][newCA.Contact.ID][newCA.Role.ID]
-		nbi.OrphanManager.RemoveItem(oldCA)
+		nbi.OrphanManager.RemoveItem(ctx, oldCA)
 		diffMap, err := utils.JSONDiffMapExceptID(newCA, oldCA, false, nbi.SourcePriority)
 		if err != nil {
 			return nil, err
@@ -362,9 +562,10 @@ func (nbi *NetboxInventory) AddContactAssignment(
 				"ContactAssignment %d already exists in Netbox but is out of date. Patching it...",
 				newCA.ID,
 			)
-			patchedCA, err := service.Patch[objects.ContactAssignment](
+			patchedCA, err := patchUnlocked[objects.ContactAssignment](
 				ctx,
-				nbi.NetboxAPI,
+				nbi,
+				&nbi.contactAssignmentsLock,
 				oldCA.ID,
 				diffMap,
 			)
@@ -377,7 +578,7 @@ func (nbi *NetboxInventory) AddContactAssignment(
 		}
 	} else {
 		nbi.Logger.Debugf(ctx, "ContactAssignment %s does not exist in Netbox. Creating it...", newCA)
-		newCA, err := service.Create(ctx, nbi.NetboxAPI, newCA)
+		newCA, err := createUnlocked(ctx, nbi, &nbi.contactAssignmentsLock, newCA)
 		if err != nil {
 			return nil, err
 		}
@@ -395,6 +596,17 @@ func (nbi *NetboxInventory) AddCustomField(
 	ctx context.Context,
 	newCf *objects.CustomField,
 ) (*objects.CustomField, error) {
+	ctx, span := startSpan(ctx, "AddCustomField", "CustomField")
+	defer span.End()
+	if !nbi.Supports(service.FeatureMACAddressObjects) {
+		newCf.ObjectTypes = slices.DeleteFunc(
+			slices.Clone(newCf.ObjectTypes),
+			func(objectType constants.ContentType) bool {
+				return objectType == constants.ContentTypeDcimMACAddress
+			},
+		)
+	}
+	defer nbi.inFlight.acquire(constants.CustomFieldsAPIPath, newCf.Name)()
 	nbi.customFieldsLock.Lock()
 	defer nbi.customFieldsLock.Unlock()
 	if _, ok := nbi.customFieldsIndexByName[newCf.Name]; ok {
@@ -409,9 +621,10 @@ func (nbi *NetboxInventory) AddCustomField(
 				"Custom field %s already exists in Netbox but is out of date. Patching it...",
 				newCf.Name,
 			)
-			patchedCf, err := service.Patch[objects.CustomField](
+			patchedCf, err := patchUnlocked[objects.CustomField](
 				ctx,
-				nbi.NetboxAPI,
+				nbi,
+				&nbi.customFieldsLock,
 				oldCustomField.ID,
 				diffMap,
 			)
@@ -424,7 +637,7 @@ func (nbi *NetboxInventory) AddCustomField(
 		}
 	} else {
 		nbi.Logger.Debugf(ctx, "Custom field %s does not exist in Netbox. Creating it...", newCf.Name)
-		createdCf, err := service.Create(ctx, nbi.NetboxAPI, newCf)
+		createdCf, err := createUnlocked(ctx, nbi, &nbi.customFieldsLock, newCf)
 		if err != nil {
 			return nil, err
 		}
@@ -442,14 +655,17 @@ func (nbi *NetboxInventory) AddClusterGroup(
 	ctx context.Context,
 	newCg *objects.ClusterGroup,
 ) (*objects.ClusterGroup, error) {
+	ctx, span := startSpan(ctx, "AddClusterGroup", "ClusterGroup")
+	defer span.End()
 	newCg.NetboxObject.AddTag(nbi.SsotTag)
 	addSourceNameCustomField(ctx, &newCg.NetboxObject)
 	newCg.SetCustomField(constants.CustomFieldOrphanLastSeenName, nil)
+	defer nbi.inFlight.acquire(constants.ClusterGroupsAPIPath, newCg.Name)()
 	nbi.clusterGroupsLock.Lock()
 	defer nbi.clusterGroupsLock.Unlock()
 	if _, ok := nbi.clusterGroupsIndexByName[newCg.Name]; ok {
 		oldCg := nbi.clusterGroupsIndexByName[newCg.Name]
-		nbi.OrphanManager.RemoveItem(oldCg)
+		nbi.OrphanManager.RemoveItem(ctx, oldCg)
 		diffMap, err := utils.JSONDiffMapExceptID(newCg, oldCg, false, nbi.SourcePriority)
 		if err != nil {
 			return nil, err
@@ -460,9 +676,10 @@ func (nbi *NetboxInventory) AddClusterGroup(
 				"Cluster group %s already exists in Netbox but is out of date. Patching it...",
 				newCg.Name,
 			)
-			patchedCg, err := service.Patch[objects.ClusterGroup](
+			patchedCg, err := patchUnlocked[objects.ClusterGroup](
 				ctx,
-				nbi.NetboxAPI,
+				nbi,
+				&nbi.clusterGroupsLock,
 				oldCg.ID,
 				diffMap,
 			)
@@ -475,7 +692,7 @@ func (nbi *NetboxInventory) AddClusterGroup(
 		}
 	} else {
 		nbi.Logger.Debugf(ctx, "Cluster group %s does not exist in Netbox. Creating it...", newCg.Name)
-		newCg, err := service.Create(ctx, nbi.NetboxAPI, newCg)
+		newCg, err := createUnlocked(ctx, nbi, &nbi.clusterGroupsLock, newCg)
 		if err != nil {
 			return nil, err
 		}
@@ -495,13 +712,16 @@ func (nbi *NetboxInventory) AddClusterType(
 	ctx context.Context,
 	newClusterType *objects.ClusterType,
 ) (*objects.ClusterType, error) {
+	ctx, span := startSpan(ctx, "AddClusterType", "ClusterType")
+	defer span.End()
 	newClusterType.NetboxObject.AddTag(nbi.SsotTag)
 	newClusterType.SetCustomField(constants.CustomFieldOrphanLastSeenName, nil)
+	defer nbi.inFlight.acquire(constants.ClusterTypesAPIPath, newClusterType.Name)()
 	nbi.clusterTypesLock.Lock()
 	defer nbi.clusterTypesLock.Unlock()
 	if _, ok := nbi.clusterTypesIndexByName[newClusterType.Name]; ok {
 		oldClusterType := nbi.clusterTypesIndexByName[newClusterType.Name]
-		nbi.OrphanManager.RemoveItem(oldClusterType)
+		nbi.OrphanManager.RemoveItem(ctx, oldClusterType)
 		diffMap, err := utils.JSONDiffMapExceptID(
 			newClusterType,
 			oldClusterType,
@@ -517,9 +737,10 @@ func (nbi *NetboxInventory) AddClusterType(
 				"Cluster type %s already exists in Netbox but is out of date. Patching it...",
 				newClusterType.Name,
 			)
-			patchedClusterType, err := service.Patch[objects.ClusterType](
+			patchedClusterType, err := patchUnlocked[objects.ClusterType](
 				ctx,
-				nbi.NetboxAPI,
+				nbi,
+				&nbi.clusterTypesLock,
 				oldClusterType.ID,
 				diffMap,
 			)
@@ -542,7 +763,7 @@ func (nbi *NetboxInventory) AddClusterType(
 		"Cluster type %s does not exist in Netbox. Creating it...",
 		newClusterType.Name,
 	)
-	newClusterType, err := service.Create(ctx, nbi.NetboxAPI, newClusterType)
+	newClusterType, err := createUnlocked(ctx, nbi, &nbi.clusterTypesLock, newClusterType)
 	if err != nil {
 		return nil, err
 	}
@@ -560,15 +781,18 @@ func (nbi *NetboxInventory) AddCluster(
 	ctx context.Context,
 	newCluster *objects.Cluster,
 ) (*objects.Cluster, error) {
+	ctx, span := startSpan(ctx, "AddCluster", "Cluster")
+	defer span.End()
 	newCluster.NetboxObject.AddTag(nbi.SsotTag)
 	addSourceNameCustomField(ctx, &newCluster.NetboxObject)
 	newCluster.SetCustomField(constants.CustomFieldOrphanLastSeenName, nil)
+	defer nbi.inFlight.acquire(constants.ClustersAPIPath, newCluster.Name)()
 	nbi.clustersLock.Lock()
 	defer nbi.clustersLock.Unlock()
 	if _, ok := nbi.clustersIndexByName[newCluster.Name]; ok {
 		// Remove id from orphan manager, because it still exists in the sources
 		oldCluster := nbi.clustersIndexByName[newCluster.Name]
-		nbi.OrphanManager.RemoveItem(oldCluster)
+		nbi.OrphanManager.RemoveItem(ctx, oldCluster)
 		diffMap, err := utils.JSONDiffMapExceptID(newCluster, oldCluster, false, nbi.SourcePriority)
 		if err != nil {
 			return nil, err
@@ -579,9 +803,10 @@ func (nbi *NetboxInventory) AddCluster(
 				"Cluster %s already exists in Netbox but is out of date. Patching it...",
 				newCluster.Name,
 			)
-			patchedCluster, err := service.Patch[objects.Cluster](
+			patchedCluster, err := patchUnlocked[objects.Cluster](
 				ctx,
-				nbi.NetboxAPI,
+				nbi,
+				&nbi.clustersLock,
 				oldCluster.ID,
 				diffMap,
 			)
@@ -594,7 +819,7 @@ func (nbi *NetboxInventory) AddCluster(
 		}
 	} else {
 		nbi.Logger.Debugf(ctx, "Cluster %s does not exist in Netbox. Creating it...", newCluster.Name)
-		createdCluster, err := service.Create(ctx, nbi.NetboxAPI, newCluster)
+		createdCluster, err := createUnlocked(ctx, nbi, &nbi.clustersLock, newCluster)
 		if err != nil {
 			return nil, err
 		}
@@ -612,14 +837,17 @@ func (nbi *NetboxInventory) AddDeviceRole(
 	ctx context.Context,
 	newDeviceRole *objects.DeviceRole,
 ) (*objects.DeviceRole, error) {
+	ctx, span := startSpan(ctx, "AddDeviceRole", "DeviceRole")
+	defer span.End()
 	newDeviceRole.NetboxObject.AddTag(nbi.SsotTag)
 	newDeviceRole.SetCustomField(constants.CustomFieldOrphanLastSeenName, nil)
+	defer nbi.inFlight.acquire(constants.DeviceRolesAPIPath, newDeviceRole.Name)()
 	nbi.deviceRolesLock.Lock()
 	defer nbi.deviceRolesLock.Unlock()
 	if _, ok := nbi.deviceRolesIndexByName[newDeviceRole.Name]; ok {
 		// Remove id from orphan manager, because it still exists in the sources
 		oldDeviceRole := nbi.deviceRolesIndexByName[newDeviceRole.Name]
-		nbi.OrphanManager.RemoveItem(oldDeviceRole)
+		nbi.OrphanManager.RemoveItem(ctx, oldDeviceRole)
 		diffMap, err := utils.JSONDiffMapExceptID(
 			newDeviceRole,
 			oldDeviceRole,
@@ -635,9 +863,10 @@ func (nbi *NetboxInventory) AddDeviceRole(
 				"Device role %s already exists in Netbox but is out of date. Patching it...",
 				newDeviceRole.Name,
 			)
-			patchedDeviceRole, err := service.Patch[objects.DeviceRole](
+			patchedDeviceRole, err := patchUnlocked[objects.DeviceRole](
 				ctx,
-				nbi.NetboxAPI,
+				nbi,
+				&nbi.deviceRolesLock,
 				oldDeviceRole.ID,
 				diffMap,
 			)
@@ -650,7 +879,7 @@ func (nbi *NetboxInventory) AddDeviceRole(
 		}
 	} else {
 		nbi.Logger.Debugf(ctx, "Device role %s does not exist in Netbox. Creating it...", newDeviceRole.Name)
-		newDeviceRole, err := service.Create(ctx, nbi.NetboxAPI, newDeviceRole)
+		newDeviceRole, err := createUnlocked(ctx, nbi, &nbi.deviceRolesLock, newDeviceRole)
 		if err != nil {
 			return nil, err
 		}
@@ -669,14 +898,17 @@ func (nbi *NetboxInventory) AddManufacturer(
 	ctx context.Context,
 	newManufacturer *objects.Manufacturer,
 ) (*objects.Manufacturer, error) {
+	ctx, span := startSpan(ctx, "AddManufacturer", "Manufacturer")
+	defer span.End()
 	newManufacturer.NetboxObject.AddTag(nbi.SsotTag)
 	newManufacturer.SetCustomField(constants.CustomFieldOrphanLastSeenName, nil)
+	defer nbi.inFlight.acquire(constants.ManufacturersAPIPath, newManufacturer.Name)()
 	nbi.manufacturersLock.Lock()
 	defer nbi.manufacturersLock.Unlock()
 	if _, ok := nbi.manufacturersIndexByName[newManufacturer.Name]; ok {
 		// Remove id from orphan manager, because it still exists in the sources
 		oldManufacturer := nbi.manufacturersIndexByName[newManufacturer.Name]
-		nbi.OrphanManager.RemoveItem(oldManufacturer)
+		nbi.OrphanManager.RemoveItem(ctx, oldManufacturer)
 		diffMap, err := utils.JSONDiffMapExceptID(
 			newManufacturer,
 			oldManufacturer,
@@ -692,9 +924,10 @@ func (nbi *NetboxInventory) AddManufacturer(
 				"Manufacturer %s already exists in Netbox but is out of date. Patching it...",
 				newManufacturer.Name,
 			)
-			patchedManufacturer, err := service.Patch[objects.Manufacturer](
+			patchedManufacturer, err := patchUnlocked[objects.Manufacturer](
 				ctx,
-				nbi.NetboxAPI,
+				nbi,
+				&nbi.manufacturersLock,
 				oldManufacturer.ID,
 				diffMap,
 			)
@@ -707,7 +940,7 @@ func (nbi *NetboxInventory) AddManufacturer(
 		}
 	} else {
 		nbi.Logger.Debugf(ctx, "Manufacturer %s does not exist in Netbox. Creating it...", newManufacturer.Name)
-		newManufacturer, err := service.Create(ctx, nbi.NetboxAPI, newManufacturer)
+		newManufacturer, err := createUnlocked(ctx, nbi, &nbi.manufacturersLock, newManufacturer)
 		if err != nil {
 			return nil, err
 		}
@@ -725,13 +958,16 @@ func (nbi *NetboxInventory) AddDeviceType(
 	ctx context.Context,
 	newDeviceType *objects.DeviceType,
 ) (*objects.DeviceType, error) {
+	ctx, span := startSpan(ctx, "AddDeviceType", "DeviceType")
+	defer span.End()
 	newDeviceType.NetboxObject.AddTag(nbi.SsotTag)
 	newDeviceType.SetCustomField(constants.CustomFieldOrphanLastSeenName, nil)
+	defer nbi.inFlight.acquire(constants.DeviceTypesAPIPath, newDeviceType.Model)()
 	nbi.deviceTypesLock.Lock()
 	defer nbi.deviceTypesLock.Unlock()
 	if _, ok := nbi.deviceTypesIndexByModel[newDeviceType.Model]; ok {
 		oldDeviceType := nbi.deviceTypesIndexByModel[newDeviceType.Model]
-		nbi.OrphanManager.RemoveItem(oldDeviceType)
+		nbi.OrphanManager.RemoveItem(ctx, oldDeviceType)
 		diffMap, err := utils.JSONDiffMapExceptID(
 			newDeviceType,
 			oldDeviceType,
@@ -747,9 +983,10 @@ func (nbi *NetboxInventory) AddDeviceType(
 				"Device type %s already exists in Netbox but is out of date. Patching it...",
 				newDeviceType.Model,
 			)
-			patchedDeviceType, err := service.Patch[objects.DeviceType](
+			patchedDeviceType, err := patchUnlocked[objects.DeviceType](
 				ctx,
-				nbi.NetboxAPI,
+				nbi,
+				&nbi.deviceTypesLock,
 				oldDeviceType.ID,
 				diffMap,
 			)
@@ -762,7 +999,7 @@ func (nbi *NetboxInventory) AddDeviceType(
 		}
 	} else {
 		nbi.Logger.Debugf(ctx, "Device type %s does not exist in Netbox. Creating it...", newDeviceType.Model)
-		newDeviceType, err := service.Create(ctx, nbi.NetboxAPI, newDeviceType)
+		newDeviceType, err := createUnlocked(ctx, nbi, &nbi.deviceTypesLock, newDeviceType)
 		if err != nil {
 			return nil, err
 		}
@@ -771,6 +1008,252 @@ func (nbi *NetboxInventory) AddDeviceType(
 	return nbi.deviceTypesIndexByModel[newDeviceType.Model], nil
 }
 
+// AddInterfaceTemplate adds the interface template of a device type to the local netbox inventory.
+// Templates are unique by their device type and name. They don't support tags,
+// so they are not managed as orphans, but are deleted with their device type.
+func (nbi *NetboxInventory) AddInterfaceTemplate(
+	ctx context.Context,
+	newInterfaceTemplate *objects.InterfaceTemplate,
+) (*objects.InterfaceTemplate, error) {
+	ctx, span := startSpan(ctx, "AddInterfaceTemplate", "InterfaceTemplate")
+	defer span.End()
+	if newInterfaceTemplate.DeviceType == nil {
+		return nil, fmt.Errorf("interface template %s has no device type", newInterfaceTemplate.Name)
+	}
+	deviceTypeID := newInterfaceTemplate.DeviceType.ID
+	defer nbi.inFlight.acquire(
+		constants.InterfaceTemplatesAPIPath,
+		deviceTypeID,
+		newInterfaceTemplate.Name,
+	)()
+	nbi.interfaceTemplatesLock.Lock()
+	defer nbi.interfaceTemplatesLock.Unlock()
+	templates := nbi.interfaceTemplatesIndexByDeviceTypeIDAndName[deviceTypeID]
+	if templates == nil {
+		templates = make(map[string]*objects.InterfaceTemplate)
+		nbi.interfaceTemplatesIndexByDeviceTypeIDAndName[deviceTypeID] = templates
+	}
+	if oldInterfaceTemplate, ok := templates[newInterfaceTemplate.Name]; ok {
+		diffMap, err := utils.JSONDiffMapExceptID(
+			newInterfaceTemplate,
+			oldInterfaceTemplate,
+			false,
+			nbi.SourcePriority,
+		)
+		if err != nil {
+			return nil, err
+		}
+		if len(diffMap) > 0 {
+			nbi.Logger.Debugf(
+				ctx,
+				"Interface template %s of %s already exists in Netbox but is out of date. Patching it...",
+				newInterfaceTemplate.Name,
+				newInterfaceTemplate.DeviceType.Model,
+			)
+			patchedInterfaceTemplate, err := patchUnlocked[objects.InterfaceTemplate](
+				ctx,
+				nbi,
+				&nbi.interfaceTemplatesLock,
+				oldInterfaceTemplate.ID,
+				diffMap,
+			)
+			if err != nil {
+				return nil, err
+			}
+			templates[newInterfaceTemplate.Name] = patchedInterfaceTemplate
+		} else {
+			nbi.Logger.Debugf(
+				ctx,
+				"Interface template %s of %s already exists in Netbox and is up to date...",
+				newInterfaceTemplate.Name,
+				newInterfaceTemplate.DeviceType.Model,
+			)
+		}
+	} else {
+		nbi.Logger.Debugf(
+			ctx,
+			"Interface template %s of %s does not exist in Netbox. Creating it...",
+			newInterfaceTemplate.Name,
+			newInterfaceTemplate.DeviceType.Model,
+		)
+		createdInterfaceTemplate, err := createUnlocked(
+			ctx,
+			nbi,
+			&nbi.interfaceTemplatesLock,
+			newInterfaceTemplate,
+		)
+		if err != nil {
+			return nil, err
+		}
+		templates[newInterfaceTemplate.Name] = createdInterfaceTemplate
+	}
+	return templates[newInterfaceTemplate.Name], nil
+}
+
+// AddConsolePortTemplate adds the console port template of a device type to the local netbox inventory.
+// Templates are unique by their device type and name. They don't support tags,
+// so they are not managed as orphans, but are deleted with their device type.
+func (nbi *NetboxInventory) AddConsolePortTemplate(
+	ctx context.Context,
+	newConsolePortTemplate *objects.ConsolePortTemplate,
+) (*objects.ConsolePortTemplate, error) {
+	ctx, span := startSpan(ctx, "AddConsolePortTemplate", "ConsolePortTemplate")
+	defer span.End()
+	if newConsolePortTemplate.DeviceType == nil {
+		return nil, fmt.Errorf("console port template %s has no device type", newConsolePortTemplate.Name)
+	}
+	deviceTypeID := newConsolePortTemplate.DeviceType.ID
+	defer nbi.inFlight.acquire(
+		constants.ConsolePortTemplatesAPIPath,
+		deviceTypeID,
+		newConsolePortTemplate.Name,
+	)()
+	nbi.consolePortTemplatesLock.Lock()
+	defer nbi.consolePortTemplatesLock.Unlock()
+	templates := nbi.consolePortTemplatesIndexByDeviceTypeIDAndName[deviceTypeID]
+	if templates == nil {
+		templates = make(map[string]*objects.ConsolePortTemplate)
+		nbi.consolePortTemplatesIndexByDeviceTypeIDAndName[deviceTypeID] = templates
+	}
+	if oldConsolePortTemplate, ok := templates[newConsolePortTemplate.Name]; ok {
+		diffMap, err := utils.JSONDiffMapExceptID(
+			newConsolePortTemplate,
+			oldConsolePortTemplate,
+			false,
+			nbi.SourcePriority,
+		)
+		if err != nil {
+			return nil, err
+		}
+		if len(diffMap) > 0 {
+			nbi.Logger.Debugf(
+				ctx,
+				"Console port template %s of %s already exists in Netbox but is out of date. Patching it...",
+				newConsolePortTemplate.Name,
+				newConsolePortTemplate.DeviceType.Model,
+			)
+			patchedConsolePortTemplate, err := patchUnlocked[objects.ConsolePortTemplate](
+				ctx,
+				nbi,
+				&nbi.consolePortTemplatesLock,
+				oldConsolePortTemplate.ID,
+				diffMap,
+			)
+			if err != nil {
+				return nil, err
+			}
+			templates[newConsolePortTemplate.Name] = patchedConsolePortTemplate
+		} else {
+			nbi.Logger.Debugf(
+				ctx,
+				"Console port template %s of %s already exists in Netbox and is up to date...",
+				newConsolePortTemplate.Name,
+				newConsolePortTemplate.DeviceType.Model,
+			)
+		}
+	} else {
+		nbi.Logger.Debugf(
+			ctx,
+			"Console port template %s of %s does not exist in Netbox. Creating it...",
+			newConsolePortTemplate.Name,
+			newConsolePortTemplate.DeviceType.Model,
+		)
+		createdConsolePortTemplate, err := createUnlocked(
+			ctx,
+			nbi,
+			&nbi.consolePortTemplatesLock,
+			newConsolePortTemplate,
+		)
+		if err != nil {
+			return nil, err
+		}
+		templates[newConsolePortTemplate.Name] = createdConsolePortTemplate
+	}
+	return templates[newConsolePortTemplate.Name], nil
+}
+
+// AddPowerPortTemplate adds the power port template of a device type to the local netbox inventory.
+// Templates are unique by their device type and name. They don't support tags,
+// so they are not managed as orphans, but are deleted with their device type.
+func (nbi *NetboxInventory) AddPowerPortTemplate(
+	ctx context.Context,
+	newPowerPortTemplate *objects.PowerPortTemplate,
+) (*objects.PowerPortTemplate, error) {
+	ctx, span := startSpan(ctx, "AddPowerPortTemplate", "PowerPortTemplate")
+	defer span.End()
+	if newPowerPortTemplate.DeviceType == nil {
+		return nil, fmt.Errorf("power port template %s has no device type", newPowerPortTemplate.Name)
+	}
+	deviceTypeID := newPowerPortTemplate.DeviceType.ID
+	defer nbi.inFlight.acquire(
+		constants.PowerPortTemplatesAPIPath,
+		deviceTypeID,
+		newPowerPortTemplate.Name,
+	)()
+	nbi.powerPortTemplatesLock.Lock()
+	defer nbi.powerPortTemplatesLock.Unlock()
+	templates := nbi.powerPortTemplatesIndexByDeviceTypeIDAndName[deviceTypeID]
+	if templates == nil {
+		templates = make(map[string]*objects.PowerPortTemplate)
+		nbi.powerPortTemplatesIndexByDeviceTypeIDAndName[deviceTypeID] = templates
+	}
+	if oldPowerPortTemplate, ok := templates[newPowerPortTemplate.Name]; ok {
+		diffMap, err := utils.JSONDiffMapExceptID(
+			newPowerPortTemplate,
+			oldPowerPortTemplate,
+			false,
+			nbi.SourcePriority,
+		)
+		if err != nil {
+			return nil, err
+		}
+		if len(diffMap) > 0 {
+			nbi.Logger.Debugf(
+				ctx,
+				"Power port template %s of %s already exists in Netbox but is out of date. Patching it...",
+				newPowerPortTemplate.Name,
+				newPowerPortTemplate.DeviceType.Model,
+			)
+			patchedPowerPortTemplate, err := patchUnlocked[objects.PowerPortTemplate](
+				ctx,
+				nbi,
+				&nbi.powerPortTemplatesLock,
+				oldPowerPortTemplate.ID,
+				diffMap,
+			)
+			if err != nil {
+				return nil, err
+			}
+			templates[newPowerPortTemplate.Name] = patchedPowerPortTemplate
+		} else {
+			nbi.Logger.Debugf(
+				ctx,
+				"Power port template %s of %s already exists in Netbox and is up to date...",
+				newPowerPortTemplate.Name,
+				newPowerPortTemplate.DeviceType.Model,
+			)
+		}
+	} else {
+		nbi.Logger.Debugf(
+			ctx,
+			"Power port template %s of %s does not exist in Netbox. Creating it...",
+			newPowerPortTemplate.Name,
+			newPowerPortTemplate.DeviceType.Model,
+		)
+		createdPowerPortTemplate, err := createUnlocked(
+			ctx,
+			nbi,
+			&nbi.powerPortTemplatesLock,
+			newPowerPortTemplate,
+		)
+		if err != nil {
+			return nil, err
+		}
+		templates[newPowerPortTemplate.Name] = createdPowerPortTemplate
+	}
+	return templates[newPowerPortTemplate.Name], nil
+}
+
 // AddPlatform adds a new platform to the Netbox inventory.
 // It takes a context and a newPlatform object as input and
 // returns the created or updated platform object and an error, if any.
@@ -780,14 +1263,17 @@ func (nbi *NetboxInventory) AddPlatform(
 	ctx context.Context,
 	newPlatform *objects.Platform,
 ) (*objects.Platform, error) {
+	ctx, span := startSpan(ctx, "AddPlatform", "Platform")
+	defer span.End()
 	newPlatform.NetboxObject.AddTag(nbi.SsotTag)
 	newPlatform.SetCustomField(constants.CustomFieldOrphanLastSeenName, nil)
+	defer nbi.inFlight.acquire(constants.PlatformsAPIPath, newPlatform.Name)()
 	nbi.platformsLock.Lock()
 	defer nbi.platformsLock.Unlock()
 	if _, ok := nbi.platformsIndexByName[newPlatform.Name]; ok {
 		// Remove id from orphan manager, because it still exists in the sources
 		oldPlatform := nbi.platformsIndexByName[newPlatform.Name]
-		nbi.OrphanManager.RemoveItem(oldPlatform)
+		nbi.OrphanManager.RemoveItem(ctx, oldPlatform)
 		diffMap, err := utils.JSONDiffMapExceptID(
 			newPlatform,
 			oldPlatform,
@@ -803,9 +1289,10 @@ func (nbi *NetboxInventory) AddPlatform(
 				"Platform %s already exists in Netbox but is out of date. Patching it...",
 				newPlatform.Name,
 			)
-			patchedPlatform, err := service.Patch[objects.Platform](
+			patchedPlatform, err := patchUnlocked[objects.Platform](
 				ctx,
-				nbi.NetboxAPI,
+				nbi,
+				&nbi.platformsLock,
 				oldPlatform.ID,
 				diffMap,
 			)
@@ -818,7 +1305,7 @@ func (nbi *NetboxInventory) AddPlatform(
 		}
 	} else {
 		nbi.Logger.Debugf(ctx, "Platform %s does not exist in Netbox. Creating it...", newPlatform.Name)
-		newPlatform, err := service.Create(ctx, nbi.NetboxAPI, newPlatform)
+		newPlatform, err := createUnlocked(ctx, nbi, &nbi.platformsLock, newPlatform)
 		if err != nil {
 			return nil, err
 		}
@@ -836,18 +1323,33 @@ func (nbi *NetboxInventory) AddDevice(
 	ctx context.Context,
 	newDevice *objects.Device,
 ) (*objects.Device, error) {
+	ctx, span := startSpan(ctx, "AddDevice", "Device")
+	defer span.End()
 	newDevice.NetboxObject.AddTag(nbi.SsotTag)
 	addSourceNameCustomField(ctx, &newDevice.NetboxObject)
 	nbi.applyDeviceFieldLengthLimitations(newDevice)
 	newDevice.SetCustomField(constants.CustomFieldOrphanLastSeenName, nil)
-	nbi.devicesLock.Lock()
-	defer nbi.devicesLock.Unlock()
 	if newDevice.Site == nil {
 		return nil, fmt.Errorf("device %s is not assigned to a site, but it should be", newDevice)
 	}
-	if _, ok := nbi.devicesIndexByNameAndSiteID[newDevice.Name][newDevice.Site.ID]; ok {
-		oldDevice := nbi.devicesIndexByNameAndSiteID[newDevice.Name][newDevice.Site.ID]
-		nbi.OrphanManager.RemoveItem(oldDevice)
+	defer nbi.inFlight.acquire(constants.DevicesAPIPath, newDevice.Name, newDevice.Site.ID)()
+	nbi.devicesLock.Lock()
+	defer nbi.devicesLock.Unlock()
+	oldDevice, ok := nbi.devicesIndexByNameAndSiteID[newDevice.Name][newDevice.Site.ID]
+	matchedBy := AdoptionMatchDefault
+	if !ok && nbi.OrphanManager.AdoptionMode {
+		oldDevice, matchedBy, ok = nbi.matchUnmanagedDevice(newDevice)
+	}
+	if ok {
+		nbi.OrphanManager.removeItem(ctx, oldDevice, matchedBy)
+		if matchedBy != AdoptionMatchDefault {
+			// Adopted device is reindexed under its new name and site
+			delete(nbi.devicesIndexByNameAndSiteID[oldDevice.Name], oldDevice.Site.ID)
+			if nbi.devicesIndexByNameAndSiteID[newDevice.Name] == nil {
+				nbi.devicesIndexByNameAndSiteID[newDevice.Name] = make(map[int]*objects.Device)
+			}
+			nbi.devicesIndexByNameAndSiteID[newDevice.Name][newDevice.Site.ID] = oldDevice
+		}
 
 		// Allow manual override device type
 		if newDevice.DeviceType != nil && oldDevice.DeviceType != nil &&
@@ -868,9 +1370,10 @@ func (nbi *NetboxInventory) AddDevice(
 				"Device %s already exists in Netbox but is out of date. Patching it...",
 				newDevice.Name,
 			)
-			patchedDevice, err := service.Patch[objects.Device](
+			patchedDevice, err := patchUnlocked[objects.Device](
 				ctx,
-				nbi.NetboxAPI,
+				nbi,
+				&nbi.devicesLock,
 				oldDevice.ID,
 				diffMap,
 			)
@@ -884,7 +1387,7 @@ func (nbi *NetboxInventory) AddDevice(
 		}
 	} else {
 		nbi.Logger.Debugf(ctx, "Device %s does not exist in Netbox. Creating it...", newDevice.Name)
-		newDevice, err := service.Create(ctx, nbi.NetboxAPI, newDevice)
+		newDevice, err := createUnlocked(ctx, nbi, &nbi.devicesLock, newDevice)
 		if err != nil {
 			return nil, err
 		}
@@ -893,10 +1396,142 @@ func (nbi *NetboxInventory) AddDevice(
 		}
 		nbi.devicesIndexByNameAndSiteID[newDevice.Name][newDevice.Site.ID] = newDevice
 		nbi.devicesIndexByID[newDevice.ID] = newDevice
+		nbi.devicesLock.Unlock()
+		err = nbi.indexTemplateInterfaces(ctx, newDevice)
+		nbi.devicesLock.Lock()
+		if err != nil {
+			return nil, err
+		}
 	}
 	return nbi.devicesIndexByNameAndSiteID[newDevice.Name][newDevice.Site.ID], nil
 }
 
+// indexTemplateInterfaces adds interfaces, which netbox created on the new device
+// from interface templates of its device type, to the local inventory,
+// so sources patch them instead of creating interfaces with the same names.
+func (nbi *NetboxInventory) indexTemplateInterfaces(ctx context.Context, device *objects.Device) error {
+	if nbi.DryRun || device.DeviceType == nil {
+		return nil
+	}
+	nbi.interfaceTemplatesLock.Lock()
+	hasTemplates := len(nbi.interfaceTemplatesIndexByDeviceTypeIDAndName[device.DeviceType.ID]) > 0
+	nbi.interfaceTemplatesLock.Unlock()
+	if !hasTemplates {
+		return nil
+	}
+	extraArgs := fmt.Sprintf(
+		"&device_id=%d&fields=%s",
+		device.ID,
+		utils.ExtractJSONTagsFromStructIntoString(objects.Interface{}),
+	)
+	nbInterfaces, err := service.GetAll[objects.Interface](ctx, nbi.NetboxAPI, extraArgs)
+	if err != nil {
+		return fmt.Errorf("get interfaces of device %s: %s", device.Name, err)
+	}
+	nbi.interfacesLock.Lock()
+	defer nbi.interfacesLock.Unlock()
+	if nbi.interfacesIndexByDeviceIDAndName[device.ID] == nil {
+		nbi.interfacesIndexByDeviceIDAndName[device.ID] = make(map[string]*objects.Interface)
+	}
+	for i := range nbInterfaces {
+		iface := &nbInterfaces[i]
+		if _, ok := nbi.interfacesIndexByDeviceIDAndName[device.ID][iface.Name]; ok {
+			continue
+		}
+		nbi.interfacesIndexByDeviceIDAndName[device.ID][iface.Name] = iface
+		nbi.interfacesIndexByID[iface.ID] = iface
+	}
+	return nil
+}
+
+// matchUnmanagedDevice returns unmanaged device with the same serial number
+// or uuid custom field as newDevice. It is used in adoption mode for devices,
+// that couldn't be matched by name and site.
+func (nbi *NetboxInventory) matchUnmanagedDevice(
+	newDevice *objects.Device,
+) (*objects.Device, string, bool) {
+	newUUID, _ := newDevice.GetCustomField(constants.CustomFieldDeviceUUIDName).(string)
+	for _, item := range nbi.OrphanManager.unmanagedItemsOf(constants.DevicesAPIPath) {
+		device, ok := item.(*objects.Device)
+		if !ok || device.Site == nil {
+			continue
+		}
+		if newDevice.SerialNumber != "" && device.SerialNumber == newDevice.SerialNumber {
+			return device, AdoptionMatchSerial, true
+		}
+		if uuid, _ := device.GetCustomField(constants.CustomFieldDeviceUUIDName).(string); newUUID != "" &&
+			uuid == newUUID {
+			return device, AdoptionMatchUUID, true
+		}
+	}
+	return nil, "", false
+}
+
+// AddVirtualChassis adds a new virtual chassis to the Netbox inventory.
+// It takes a context and a newVirtualChassis object as input and
+// returns the created or updated virtual chassis object and an error, if any.
+// If the virtual chassis already exists in Netbox, it checks if it is up to date and patches it if necessary.
+// If the virtual chassis does not exist, it creates a new one.
+// Master of the virtual chassis must already be its member, so it can only be set
+// after the member device is added.
+func (nbi *NetboxInventory) AddVirtualChassis(
+	ctx context.Context,
+	newVirtualChassis *objects.VirtualChassis,
+) (*objects.VirtualChassis, error) {
+	ctx, span := startSpan(ctx, "AddVirtualChassis", "VirtualChassis")
+	defer span.End()
+	newVirtualChassis.NetboxObject.AddTag(nbi.SsotTag)
+	addSourceNameCustomField(ctx, &newVirtualChassis.NetboxObject)
+	newVirtualChassis.SetCustomField(constants.CustomFieldOrphanLastSeenName, nil)
+	defer nbi.inFlight.acquire(constants.VirtualChassisAPIPath, newVirtualChassis.Name)()
+	nbi.virtualChassisLock.Lock()
+	defer nbi.virtualChassisLock.Unlock()
+	if oldVirtualChassis, ok := nbi.virtualChassisIndexByName[newVirtualChassis.Name]; ok {
+		nbi.OrphanManager.RemoveItem(ctx, oldVirtualChassis)
+		diffMap, err := utils.JSONDiffMapExceptID(
+			newVirtualChassis,
+			oldVirtualChassis,
+			false,
+			nbi.SourcePriority,
+		)
+		if err != nil {
+			return nil, err
+		}
+		if len(diffMap) > 0 {
+			nbi.Logger.Debugf(
+				ctx,
+				"VirtualChassis %s already exists in Netbox but is out of date. Patching it...",
+				newVirtualChassis.Name,
+			)
+			patchedVirtualChassis, err := patchUnlocked[objects.VirtualChassis](
+				ctx,
+				nbi,
+				&nbi.virtualChassisLock,
+				oldVirtualChassis.ID,
+				diffMap,
+			)
+			if err != nil {
+				return nil, err
+			}
+			nbi.virtualChassisIndexByName[newVirtualChassis.Name] = patchedVirtualChassis
+		} else {
+			nbi.Logger.Debugf(
+				ctx,
+				"VirtualChassis %s already exists in Netbox and is up to date...",
+				newVirtualChassis.Name,
+			)
+		}
+	} else {
+		nbi.Logger.Debugf(ctx, "VirtualChassis %s does not exist in Netbox. Creating it...", newVirtualChassis.Name)
+		createdVirtualChassis, err := createUnlocked(ctx, nbi, &nbi.virtualChassisLock, newVirtualChassis)
+		if err != nil {
+			return nil, err
+		}
+		nbi.virtualChassisIndexByName[createdVirtualChassis.Name] = createdVirtualChassis
+	}
+	return nbi.virtualChassisIndexByName[newVirtualChassis.Name], nil
+}
+
 // AddVirtualDeviceContext adds new virtual device context to the local inventory.
 // It takes a context and a newVDC object as input and
 // returns the created or updated virtual device context object and an error, if any.
@@ -906,20 +1541,23 @@ func (nbi *NetboxInventory) AddVirtualDeviceContext(
 	ctx context.Context,
 	newVDC *objects.VirtualDeviceContext,
 ) (*objects.VirtualDeviceContext, error) {
+	ctx, span := startSpan(ctx, "AddVirtualDeviceContext", "VirtualDeviceContext")
+	defer span.End()
 	newVDC.NetboxObject.AddTag(nbi.SsotTag)
 	addSourceNameCustomField(ctx, &newVDC.NetboxObject)
 	newVDC.SetCustomField(constants.CustomFieldOrphanLastSeenName, nil)
-	nbi.virtualDeviceContextsLock.Lock()
-	defer nbi.virtualDeviceContextsLock.Unlock()
 	if newVDC.Device == nil {
 		return nil, fmt.Errorf(
 			"VirtualDeviceContext %s is not assigned to a device, but it should be",
 			newVDC,
 		)
 	}
+	defer nbi.inFlight.acquire(constants.VirtualDeviceContextsAPIPath, newVDC.Name, newVDC.Device.ID)()
+	nbi.virtualDeviceContextsLock.Lock()
+	defer nbi.virtualDeviceContextsLock.Unlock()
 	if _, ok := nbi.virtualDeviceContextsIndex[newVDC.Name][newVDC.Device.ID]; ok {
 		oldVDC := nbi.virtualDeviceContextsIndex[newVDC.Name][newVDC.Device.ID]
-		nbi.OrphanManager.RemoveItem(oldVDC)
+		nbi.OrphanManager.RemoveItem(ctx, oldVDC)
 		diffMap, err := utils.JSONDiffMapExceptID(newVDC, oldVDC, false, nbi.SourcePriority)
 		if err != nil {
 			return nil, err
@@ -930,9 +1568,10 @@ func (nbi *NetboxInventory) AddVirtualDeviceContext(
 				"VirtualDeviceContext %s already exists in Netbox but is out of date. Patching it...",
 				newVDC.Name,
 			)
-			patchedVDC, err := service.Patch[objects.VirtualDeviceContext](
+			patchedVDC, err := patchUnlocked[objects.VirtualDeviceContext](
 				ctx,
-				nbi.NetboxAPI,
+				nbi,
+				&nbi.virtualDeviceContextsLock,
 				oldVDC.ID,
 				diffMap,
 			)
@@ -945,7 +1584,7 @@ func (nbi *NetboxInventory) AddVirtualDeviceContext(
 		}
 	} else {
 		nbi.Logger.Debugf(ctx, "VirtualDeviceContext %s does not exist in Netbox. Creating it...", newVDC.Name)
-		newDevice, err := service.Create(ctx, nbi.NetboxAPI, newVDC)
+		newDevice, err := createUnlocked(ctx, nbi, &nbi.virtualDeviceContextsLock, newVDC)
 		if err != nil {
 			return nil, err
 		}
@@ -966,14 +1605,17 @@ func (nbi *NetboxInventory) AddVlanGroup(
 	ctx context.Context,
 	newVlanGroup *objects.VlanGroup,
 ) (*objects.VlanGroup, error) {
+	ctx, span := startSpan(ctx, "AddVlanGroup", "VlanGroup")
+	defer span.End()
 	newVlanGroup.NetboxObject.AddTag(nbi.SsotTag)
 	newVlanGroup.SetCustomField(constants.CustomFieldOrphanLastSeenName, nil)
+	defer nbi.inFlight.acquire(constants.VlanGroupsAPIPath, newVlanGroup.Name)()
 	nbi.vlanGroupsLock.Lock()
 	defer nbi.vlanGroupsLock.Unlock()
 	if _, ok := nbi.vlanGroupsIndexByName[newVlanGroup.Name]; ok {
 		// Remove id from orphan manager, because it still exists in the sources
 		oldVlanGroup := nbi.vlanGroupsIndexByName[newVlanGroup.Name]
-		nbi.OrphanManager.RemoveItem(oldVlanGroup)
+		nbi.OrphanManager.RemoveItem(ctx, oldVlanGroup)
 		diffMap, err := utils.JSONDiffMapExceptID(
 			newVlanGroup,
 			oldVlanGroup,
@@ -989,9 +1631,10 @@ func (nbi *NetboxInventory) AddVlanGroup(
 				"VlanGroup %s already exists in Netbox but is out of date. Patching it...",
 				newVlanGroup.Name,
 			)
-			patchedVlanGroup, err := service.Patch[objects.VlanGroup](
+			patchedVlanGroup, err := patchUnlocked[objects.VlanGroup](
 				ctx,
-				nbi.NetboxAPI,
+				nbi,
+				&nbi.vlanGroupsLock,
 				oldVlanGroup.ID,
 				diffMap,
 			)
@@ -1004,7 +1647,7 @@ func (nbi *NetboxInventory) AddVlanGroup(
 		}
 	} else {
 		nbi.Logger.Debugf(ctx, "VlanGroup %s does not exist in Netbox. Creating it...", newVlanGroup.Name)
-		newVlan, err := service.Create(ctx, nbi.NetboxAPI, newVlanGroup)
+		newVlan, err := createUnlocked(ctx, nbi, &nbi.vlanGroupsLock, newVlanGroup)
 		if err != nil {
 			return nil, err
 		}
@@ -1021,15 +1664,18 @@ func (nbi *NetboxInventory) AddVlan(
 	ctx context.Context,
 	newVlan *objects.Vlan,
 ) (*objects.Vlan, error) {
+	ctx, span := startSpan(ctx, "AddVlan", "Vlan")
+	defer span.End()
 	newVlan.NetboxObject.AddTag(nbi.SsotTag)
 	addSourceNameCustomField(ctx, &newVlan.NetboxObject)
 	newVlan.SetCustomField(constants.CustomFieldOrphanLastSeenName, nil)
+	defer nbi.inFlight.acquire(constants.VlansAPIPath, newVlan.Group.ID, newVlan.Vid)()
 	nbi.vlansLock.Lock()
 	defer nbi.vlansLock.Unlock()
 	if _, ok := nbi.vlansIndexByVlanGroupIDAndVID[newVlan.Group.ID][newVlan.Vid]; ok {
 		// Remove id from orphan manager, because it still exists in the sources
 		oldVlan := nbi.vlansIndexByVlanGroupIDAndVID[newVlan.Group.ID][newVlan.Vid]
-		nbi.OrphanManager.RemoveItem(oldVlan)
+		nbi.OrphanManager.RemoveItem(ctx, oldVlan)
 		diffMap, err := utils.JSONDiffMapExceptID(newVlan, oldVlan, false, nbi.SourcePriority)
 		if err != nil {
 			return nil, err
@@ -1040,7 +1686,7 @@ func (nbi *NetboxInventory) AddVlan(
 				"Vlan %s already exists in Netbox but is out of date. Patching it...",
 				newVlan.Name,
 			)
-			patchedVlan, err := service.Patch[objects.Vlan](ctx, nbi.NetboxAPI, oldVlan.ID, diffMap)
+			patchedVlan, err := patchUnlocked[objects.Vlan](ctx, nbi, &nbi.vlansLock, oldVlan.ID, diffMap)
 			if err != nil {
 				return nil, err
 			}
@@ -1050,7 +1696,7 @@ func (nbi *NetboxInventory) AddVlan(
 		}
 	} else {
 		nbi.Logger.Debugf(ctx, "Vlan %s does not exist in Netbox. Creating it...", newVlan.Name)
-		newVlan, err := service.Create(ctx, nbi.NetboxAPI, newVlan)
+		newVlan, err := createUnlocked(ctx, nbi, &nbi.vlansLock, newVlan)
 		if err != nil {
 			return nil, err
 		}
@@ -1071,17 +1717,20 @@ func (nbi *NetboxInventory) AddInterface(
 	ctx context.Context,
 	newInterface *objects.Interface,
 ) (*objects.Interface, error) {
+	ctx, span := startSpan(ctx, "AddInterface", "Interface")
+	defer span.End()
 	newInterface.NetboxObject.AddTag(nbi.SsotTag)
 	addSourceNameCustomField(ctx, &newInterface.NetboxObject)
 	newInterface.SetCustomField(constants.CustomFieldOrphanLastSeenName, nil)
 	if len(newInterface.Name) > constants.MaxInterfaceNameLength {
 		newInterface.Name = newInterface.Name[:constants.MaxInterfaceNameLength]
 	}
+	defer nbi.inFlight.acquire(constants.InterfacesAPIPath, newInterface.Device.ID, newInterface.Name)()
 	nbi.interfacesLock.Lock()
 	defer nbi.interfacesLock.Unlock()
 	if _, ok := nbi.interfacesIndexByDeviceIDAndName[newInterface.Device.ID][newInterface.Name]; ok {
 		oldInterface := nbi.interfacesIndexByDeviceIDAndName[newInterface.Device.ID][newInterface.Name]
-		nbi.OrphanManager.RemoveItem(oldInterface)
+		nbi.OrphanManager.RemoveItem(ctx, oldInterface)
 		diffMap, err := utils.JSONDiffMapExceptID(
 			newInterface,
 			oldInterface,
@@ -1098,9 +1747,10 @@ func (nbi *NetboxInventory) AddInterface(
 				newInterface.Device.Name,
 				newInterface.Name,
 			)
-			patchedInterface, err := service.Patch[objects.Interface](
+			patchedInterface, err := patchUnlocked[objects.Interface](
 				ctx,
-				nbi.NetboxAPI,
+				nbi,
+				&nbi.interfacesLock,
 				oldInterface.ID,
 				diffMap,
 			)
@@ -1122,7 +1772,7 @@ func (nbi *NetboxInventory) AddInterface(
 			"Interface %s/%s does not exist in Netbox. Creating it...",
 			newInterface.Device.Name, newInterface.Name,
 		)
-		newInterface, err := service.Create(ctx, nbi.NetboxAPI, newInterface)
+		newInterface, err := createUnlocked(ctx, nbi, &nbi.interfacesLock, newInterface)
 		if err != nil {
 			return nil, err
 		}
@@ -1136,17 +1786,234 @@ func (nbi *NetboxInventory) AddInterface(
 	return nbi.interfacesIndexByDeviceIDAndName[newInterface.Device.ID][newInterface.Name], nil
 }
 
+// AddInventoryItem adds a new inventory item to the Netbox inventory.
+// It takes a context and a newInventoryItem object as input and
+// returns the created or updated inventory item object and an error, if any.
+// If the inventory item already exists in Netbox, it checks if it is up to date and patches it if necessary.
+// If the inventory item does not exist, it creates a new one.
+func (nbi *NetboxInventory) AddInventoryItem(
+	ctx context.Context,
+	newInventoryItem *objects.InventoryItem,
+) (*objects.InventoryItem, error) {
+	ctx, span := startSpan(ctx, "AddInventoryItem", "InventoryItem")
+	defer span.End()
+	newInventoryItem.NetboxObject.AddTag(nbi.SsotTag)
+	addSourceNameCustomField(ctx, &newInventoryItem.NetboxObject)
+	newInventoryItem.SetCustomField(constants.CustomFieldOrphanLastSeenName, nil)
+	if newInventoryItem.Device == nil {
+		return nil, fmt.Errorf(
+			"InventoryItem %s is not assigned to a device, but it should be",
+			newInventoryItem,
+		)
+	}
+	if len(newInventoryItem.Name) > constants.MaxInventoryItemNameLength {
+		newInventoryItem.Name = newInventoryItem.Name[:constants.MaxInventoryItemNameLength]
+	}
+	if len(newInventoryItem.SerialNumber) > constants.MaxSerialNumberLength {
+		newInventoryItem.SerialNumber = newInventoryItem.SerialNumber[:constants.MaxSerialNumberLength]
+	}
+	deviceID := newInventoryItem.Device.ID
+	defer nbi.inFlight.acquire(constants.InventoryItemsAPIPath, deviceID, newInventoryItem.Name)()
+	nbi.inventoryItemsLock.Lock()
+	defer nbi.inventoryItemsLock.Unlock()
+	if oldInventoryItem, ok := nbi.inventoryItemsIndexByDeviceIDAndName[deviceID][newInventoryItem.Name]; ok {
+		nbi.OrphanManager.RemoveItem(ctx, oldInventoryItem)
+		diffMap, err := utils.JSONDiffMapExceptID(
+			newInventoryItem,
+			oldInventoryItem,
+			false,
+			nbi.SourcePriority,
+		)
+		if err != nil {
+			return nil, err
+		}
+		if len(diffMap) > 0 {
+			nbi.Logger.Debugf(
+				ctx,
+				"InventoryItem %s/%s already exists in Netbox but is out of date. Patching it...",
+				newInventoryItem.Device.Name,
+				newInventoryItem.Name,
+			)
+			patchedInventoryItem, err := patchUnlocked[objects.InventoryItem](
+				ctx,
+				nbi,
+				&nbi.inventoryItemsLock,
+				oldInventoryItem.ID,
+				diffMap,
+			)
+			if err != nil {
+				return nil, err
+			}
+			nbi.inventoryItemsIndexByDeviceIDAndName[deviceID][newInventoryItem.Name] = patchedInventoryItem
+		} else {
+			nbi.Logger.Debugf(
+				ctx,
+				"InventoryItem %s/%s already exists in Netbox and is up to date...",
+				newInventoryItem.Device.Name,
+				newInventoryItem.Name,
+			)
+		}
+	} else {
+		nbi.Logger.Debugf(
+			ctx,
+			"InventoryItem %s/%s does not exist in Netbox. Creating it...",
+			newInventoryItem.Device.Name,
+			newInventoryItem.Name,
+		)
+		createdInventoryItem, err := createUnlocked(ctx, nbi, &nbi.inventoryItemsLock, newInventoryItem)
+		if err != nil {
+			return nil, err
+		}
+		if nbi.inventoryItemsIndexByDeviceIDAndName[deviceID] == nil {
+			nbi.inventoryItemsIndexByDeviceIDAndName[deviceID] = make(map[string]*objects.InventoryItem)
+		}
+		nbi.inventoryItemsIndexByDeviceIDAndName[deviceID][createdInventoryItem.Name] = createdInventoryItem
+	}
+	return nbi.inventoryItemsIndexByDeviceIDAndName[deviceID][newInventoryItem.Name], nil
+}
+
+// AddCable adds a new cable between interfaces to the Netbox inventory.
+// Cables are matched by their terminated interfaces, regardless of which end
+// is A and which is B, so the same link reported from both of its ends
+// results in a single cable. Netbox allows only one cable per interface,
+// so cables managed by netbox-ssot, that connect any of the interfaces
+// differently, are deleted first. Cables not managed by netbox-ssot are never
+// replaced: in that case the new cable is skipped and nil is returned.
+func (nbi *NetboxInventory) AddCable(ctx context.Context, newCable *objects.Cable) (*objects.Cable, error) {
+	ctx, span := startSpan(ctx, "AddCable", "Cable")
+	defer span.End()
+	newCable.NetboxObject.AddTag(nbi.SsotTag)
+	addSourceNameCustomField(ctx, &newCable.NetboxObject)
+	newCable.SetCustomField(constants.CustomFieldOrphanLastSeenName, nil)
+	aInterfaceIDs, bInterfaceIDs := newCable.InterfaceIDs()
+	if len(aInterfaceIDs) == 0 || len(bInterfaceIDs) == 0 {
+		return nil, fmt.Errorf("cable %s has no interface on one of its ends", newCable)
+	}
+	interfaceIDs := append(slices.Clone(aInterfaceIDs), bInterfaceIDs...)
+	slices.Sort(interfaceIDs)
+	interfaceIDs = slices.Compact(interfaceIDs)
+	// Each interface is locked separately, so cables sharing an interface (e.g. A-B and A-C)
+	// aren't added concurrently. Locks are acquired in order of interface ids to avoid deadlocks.
+	releases := make([]func(), 0, len(interfaceIDs))
+	for _, interfaceID := range interfaceIDs {
+		releases = append(releases, nbi.inFlight.acquire(constants.CablesAPIPath, interfaceID))
+	}
+	defer func() {
+		for _, release := range slices.Backward(releases) {
+			release()
+		}
+	}()
+	nbi.cablesLock.Lock()
+	defer nbi.cablesLock.Unlock()
+
+	oldCables := []*objects.Cable{}
+	for _, interfaceID := range interfaceIDs {
+		if oldCable, ok := nbi.cablesIndexByInterfaceID[interfaceID]; ok && !slices.Contains(oldCables, oldCable) {
+			oldCables = append(oldCables, oldCable)
+		}
+	}
+	if len(oldCables) == 1 {
+		oldCable := oldCables[0]
+		oldAInterfaceIDs, oldBInterfaceIDs := oldCable.InterfaceIDs()
+		if sameInterfaceIDs(oldAInterfaceIDs, bInterfaceIDs) && sameInterfaceIDs(oldBInterfaceIDs, aInterfaceIDs) {
+			// Cable is reported from its other end
+			newCable.ATerminations, newCable.BTerminations = newCable.BTerminations, newCable.ATerminations
+			aInterfaceIDs, bInterfaceIDs = bInterfaceIDs, aInterfaceIDs
+		}
+		if sameInterfaceIDs(oldAInterfaceIDs, aInterfaceIDs) && sameInterfaceIDs(oldBInterfaceIDs, bInterfaceIDs) {
+			return nbi.patchCable(ctx, newCable, oldCable)
+		}
+	}
+
+	for _, oldCable := range oldCables {
+		if !oldCable.HasTagByName(constants.SsotTagName) {
+			nbi.Logger.Warningf(
+				ctx,
+				"Skipping %s, because its interfaces are already connected with %s, which is not managed by netbox-ssot",
+				newCable,
+				oldCable,
+			)
+			return nil, nil
+		}
+	}
+	for _, oldCable := range oldCables {
+		nbi.Logger.Debugf(ctx, "%s no longer matches the topology. Deleting it...", oldCable)
+		err := deleteUnlocked(ctx, nbi, &nbi.cablesLock, oldCable)
+		if err != nil {
+			return nil, fmt.Errorf("delete %s: %s", oldCable, err)
+		}
+		nbi.OrphanManager.RemoveItem(ctx, oldCable)
+		oldAInterfaceIDs, oldBInterfaceIDs := oldCable.InterfaceIDs()
+		for _, interfaceID := range append(oldAInterfaceIDs, oldBInterfaceIDs...) {
+			// Other end of the old cable may already be connected with a new cable
+			if nbi.cablesIndexByInterfaceID[interfaceID] == oldCable {
+				delete(nbi.cablesIndexByInterfaceID, interfaceID)
+			}
+		}
+	}
+
+	nbi.Logger.Debugf(ctx, "%s does not exist in Netbox. Creating it...", newCable)
+	newCable, err := createUnlocked(ctx, nbi, &nbi.cablesLock, newCable)
+	if err != nil {
+		return nil, err
+	}
+	for _, interfaceID := range interfaceIDs {
+		nbi.cablesIndexByInterfaceID[interfaceID] = newCable
+	}
+	return newCable, nil
+}
+
+// patchCable patches oldCable, connecting the same interfaces as newCable,
+// if it is out of date. The caller must hold cablesLock.
+func (nbi *NetboxInventory) patchCable(
+	ctx context.Context,
+	newCable *objects.Cable,
+	oldCable *objects.Cable,
+) (*objects.Cable, error) {
+	nbi.OrphanManager.RemoveItem(ctx, oldCable)
+	// Terminations are the same, and can't be diffed as they have no id
+	newCableCopy, oldCableCopy := *newCable, *oldCable
+	newCableCopy.ATerminations, newCableCopy.BTerminations = nil, nil
+	oldCableCopy.ATerminations, oldCableCopy.BTerminations = nil, nil
+	diffMap, err := utils.JSONDiffMapExceptID(&newCableCopy, &oldCableCopy, false, nbi.SourcePriority)
+	if err != nil {
+		return nil, err
+	}
+	if len(diffMap) == 0 {
+		nbi.Logger.Debugf(ctx, "%s already exists in Netbox and is up to date...", oldCable)
+		return oldCable, nil
+	}
+	nbi.Logger.Debugf(ctx, "%s already exists in Netbox but is out of date. Patching it...", oldCable)
+	patchedCable, err := patchUnlocked[objects.Cable](ctx, nbi, &nbi.cablesLock, oldCable.ID, diffMap)
+	if err != nil {
+		return nil, err
+	}
+	aInterfaceIDs, bInterfaceIDs := patchedCable.InterfaceIDs()
+	for _, interfaceID := range append(aInterfaceIDs, bInterfaceIDs...) {
+		nbi.cablesIndexByInterfaceID[interfaceID] = patchedCable
+	}
+	return patchedCable, nil
+}
+
+// sameInterfaceIDs returns true if both slices contain the same interface ids.
+func sameInterfaceIDs(ids1 []int, ids2 []int) bool {
+	sorted1, sorted2 := slices.Clone(ids1), slices.Clone(ids2)
+	slices.Sort(sorted1)
+	slices.Sort(sorted2)
+	return slices.Equal(sorted1, sorted2)
+}
+
 // AddVM adds a new virtual machine to the Netbox inventory.
 // It takes a context and a newVM object as input and
 // returns the created or updated virtual machine object and an error, if any.
 // If the virtual machine already exists in Netbox, it checks if it is up to date and patches it if necessary.
 // If the virtual machine does not exist, it creates a new one.
 func (nbi *NetboxInventory) AddVM(ctx context.Context, newVM *objects.VM) (*objects.VM, error) {
+	ctx, span := startSpan(ctx, "AddVM", "VM")
+	defer span.End()
 	newVM.NetboxObject.AddTag(nbi.SsotTag)
 	addSourceNameCustomField(ctx, &newVM.NetboxObject)
 	newVM.SetCustomField(constants.CustomFieldOrphanLastSeenName, nil)
-	nbi.vmsLock.Lock()
-	defer nbi.vmsLock.Unlock()
 	newVMClusterID := -1
 	if newVM.Cluster != nil {
 		newVMClusterID = newVM.Cluster.ID
@@ -1154,8 +2021,11 @@ func (nbi *NetboxInventory) AddVM(ctx context.Context, newVM *objects.VM) (*obje
 	if len(newVM.Name) > constants.MaxVMNameLength {
 		newVM.Name = newVM.Name[:constants.MaxVMNameLength]
 	}
+	defer nbi.inFlight.acquire(constants.VirtualMachinesAPIPath, newVM.Name, newVMClusterID)()
+	nbi.vmsLock.Lock()
+	defer nbi.vmsLock.Unlock()
 	if oldVM, ok := nbi.vmsIndexByNameAndClusterID[newVM.Name][newVMClusterID]; ok {
-		nbi.OrphanManager.RemoveItem(oldVM)
+		nbi.OrphanManager.RemoveItem(ctx, oldVM)
 		diffMap, err := utils.JSONDiffMapExceptID(newVM, oldVM, false, nbi.SourcePriority)
 		if err != nil {
 			return nil, err
@@ -1166,7 +2036,7 @@ func (nbi *NetboxInventory) AddVM(ctx context.Context, newVM *objects.VM) (*obje
 				"VM %s already exists in Netbox but is out of date. Patching it...",
 				newVM,
 			)
-			patchedVM, err := service.Patch[objects.VM](ctx, nbi.NetboxAPI, oldVM.ID, diffMap)
+			patchedVM, err := patchUnlocked[objects.VM](ctx, nbi, &nbi.vmsLock, oldVM.ID, diffMap)
 			if err != nil {
 				nbi.Logger.Errorf(ctx, "Error while patching %s : %s", newVM.Name, err)
 				return nil, err
@@ -1178,7 +2048,7 @@ func (nbi *NetboxInventory) AddVM(ctx context.Context, newVM *objects.VM) (*obje
 		}
 	} else {
 		nbi.Logger.Debugf(ctx, "VM %s does not exist in Netbox. Creating it...", newVM)
-		newVM, err := service.Create(ctx, nbi.NetboxAPI, newVM)
+		newVM, err := createUnlocked(ctx, nbi, &nbi.vmsLock, newVM)
 		if err != nil {
 			return nil, err
 		}
@@ -1201,17 +2071,20 @@ func (nbi *NetboxInventory) AddVMInterface(
 	ctx context.Context,
 	newVMInterface *objects.VMInterface,
 ) (*objects.VMInterface, error) {
+	ctx, span := startSpan(ctx, "AddVMInterface", "VMInterface")
+	defer span.End()
 	newVMInterface.NetboxObject.AddTag(nbi.SsotTag)
 	addSourceNameCustomField(ctx, &newVMInterface.NetboxObject)
 	newVMInterface.SetCustomField(constants.CustomFieldOrphanLastSeenName, nil)
-	nbi.vmInterfacesLock.Lock()
-	defer nbi.vmInterfacesLock.Unlock()
 	if len(newVMInterface.Name) > constants.MaxVMInterfaceNameLength {
 		newVMInterface.Name = newVMInterface.Name[:constants.MaxVMInterfaceNameLength]
 	}
+	defer nbi.inFlight.acquire(constants.VMInterfacesAPIPath, newVMInterface.VM.ID, newVMInterface.Name)()
+	nbi.vmInterfacesLock.Lock()
+	defer nbi.vmInterfacesLock.Unlock()
 	if _, ok := nbi.vmInterfacesIndexByVMIdAndName[newVMInterface.VM.ID][newVMInterface.Name]; ok {
 		oldVMIface := nbi.vmInterfacesIndexByVMIdAndName[newVMInterface.VM.ID][newVMInterface.Name]
-		nbi.OrphanManager.RemoveItem(oldVMIface)
+		nbi.OrphanManager.RemoveItem(ctx, oldVMIface)
 		diffMap, err := utils.JSONDiffMapExceptID(
 			newVMInterface,
 			oldVMIface,
@@ -1227,9 +2100,10 @@ func (nbi *NetboxInventory) AddVMInterface(
 				"VM interface %s already exists in Netbox but is out of date. Patching it...",
 				newVMInterface.Name,
 			)
-			patchedVMInterface, err := service.Patch[objects.VMInterface](
+			patchedVMInterface, err := patchUnlocked[objects.VMInterface](
 				ctx,
-				nbi.NetboxAPI,
+				nbi,
+				&nbi.vmInterfacesLock,
 				oldVMIface.ID,
 				diffMap,
 			)
@@ -1243,7 +2117,7 @@ func (nbi *NetboxInventory) AddVMInterface(
 		}
 	} else {
 		nbi.Logger.Debugf(ctx, "VM interface %s does not exist in Netbox. Creating it...", newVMInterface.Name)
-		newVMInterface, err := service.Create(ctx, nbi.NetboxAPI, newVMInterface)
+		newVMInterface, err := createUnlocked(ctx, nbi, &nbi.vmInterfacesLock, newVMInterface)
 		if err != nil {
 			return nil, err
 		}
@@ -1265,6 +2139,8 @@ func (nbi *NetboxInventory) AddIPAddress(
 	ctx context.Context,
 	newIPAddress *objects.IPAddress,
 ) (*objects.IPAddress, error) {
+	ctx, span := startSpan(ctx, "AddIPAddress", "IPAddress")
+	defer span.End()
 	newIPAddress.NetboxObject.AddTag(nbi.SsotTag)
 	addSourceNameCustomField(ctx, &newIPAddress.NetboxObject)
 	newIPAddress.SetCustomField(constants.CustomFieldOrphanLastSeenName, nil)
@@ -1277,6 +2153,7 @@ func (nbi *NetboxInventory) AddIPAddress(
 
 	indexKey := ipAddressIndexKey(newIPAddress)
 
+	defer nbi.inFlight.acquire(constants.IPAddressesAPIPath, objType, objName, ifaceName, newIPAddress.Address)()
 	nbi.ipAddressesLock.Lock()
 	defer nbi.ipAddressesLock.Unlock()
 
@@ -1293,7 +2170,7 @@ func (nbi *NetboxInventory) AddIPAddress(
 
 	if _, ok := nbi.ipAddressesIndex[objType][objName][ifaceName][indexKey]; ok {
 		oldIPAddress := nbi.ipAddressesIndex[objType][objName][ifaceName][indexKey]
-		nbi.OrphanManager.RemoveItem(oldIPAddress)
+		nbi.OrphanManager.RemoveItem(ctx, oldIPAddress)
 		diffMap, err := utils.JSONDiffMapExceptID(
 			newIPAddress,
 			oldIPAddress,
@@ -1309,9 +2186,10 @@ func (nbi *NetboxInventory) AddIPAddress(
 				"IP address %s already exists in Netbox but is out of date. Patching it...",
 				newIPAddress.Address,
 			)
-			patchedIPAddress, err := service.Patch[objects.IPAddress](
+			patchedIPAddress, err := patchUnlocked[objects.IPAddress](
 				ctx,
-				nbi.NetboxAPI,
+				nbi,
+				&nbi.ipAddressesLock,
 				oldIPAddress.ID,
 				diffMap,
 			)
@@ -1328,7 +2206,7 @@ func (nbi *NetboxInventory) AddIPAddress(
 		)
 	} else {
 		nbi.Logger.Debugf(ctx, "IP address %s does not exist in Netbox. Creating it...", newIPAddress.Address)
-		newIPAddress, err := service.Create(ctx, nbi.NetboxAPI, newIPAddress)
+		newIPAddress, err := createUnlocked(ctx, nbi, &nbi.ipAddressesLock, newIPAddress)
 		if err != nil {
 			return nil, err
 		}
@@ -1347,6 +2225,13 @@ func (nbi *NetboxInventory) AddMACAddress(
 	ctx context.Context,
 	newMACAddress *objects.MACAddress,
 ) (*objects.MACAddress, error) {
+	ctx, span := startSpan(ctx, "AddMACAddress", "MACAddress")
+	defer span.End()
+	if !nbi.Supports(service.FeatureMACAddressObjects) {
+		// The MAC address is set on the interface by its owner instead
+		newMACAddress.MAC = strings.ToUpper(newMACAddress.MAC)
+		return newMACAddress, nil
+	}
 	newMACAddress.NetboxObject.AddTag(nbi.SsotTag)
 	newMACAddress.SetCustomField(constants.CustomFieldOrphanLastSeenName, nil)
 
@@ -1362,11 +2247,12 @@ func (nbi *NetboxInventory) AddMACAddress(
 	// ensure MAC address is uppercase
 	newMACAddress.MAC = strings.ToUpper(newMACAddress.MAC)
 
+	defer nbi.inFlight.acquire(constants.MACAddressesAPIPath, objType, objName, ifaceName, newMACAddress.MAC)()
 	nbi.macAddressesLock.Lock()
 	defer nbi.macAddressesLock.Unlock()
 	if _, ok := nbi.macAddressesIndex[objType][objName][ifaceName][newMACAddress.MAC]; ok {
 		oldMACAddress := nbi.macAddressesIndex[objType][objName][ifaceName][newMACAddress.MAC]
-		nbi.OrphanManager.RemoveItem(oldMACAddress)
+		nbi.OrphanManager.RemoveItem(ctx, oldMACAddress)
 
 		diffMap, err := utils.JSONDiffMapExceptID(
 			newMACAddress,
@@ -1384,9 +2270,10 @@ func (nbi *NetboxInventory) AddMACAddress(
 				"MAC address %s already exists in Netbox but is out of date. Patching it...",
 				newMACAddress.MAC,
 			)
-			patchedMACAddress, err := service.Patch[objects.MACAddress](
+			patchedMACAddress, err := patchUnlocked[objects.MACAddress](
 				ctx,
-				nbi.NetboxAPI,
+				nbi,
+				&nbi.macAddressesLock,
 				oldMACAddress.ID,
 				diffMap,
 			)
@@ -1403,7 +2290,7 @@ func (nbi *NetboxInventory) AddMACAddress(
 		)
 	} else {
 		nbi.Logger.Debugf(ctx, "MAC address %s does not exist in Netbox. Creating it...", newMACAddress.MAC)
-		newMACAddress, err := service.Create(ctx, nbi.NetboxAPI, newMACAddress)
+		newMACAddress, err := createUnlocked(ctx, nbi, &nbi.macAddressesLock, newMACAddress)
 		if err != nil {
 			return nil, err
 		}
@@ -1422,6 +2309,8 @@ func (nbi *NetboxInventory) AddPrefix(
 	ctx context.Context,
 	newPrefix *objects.Prefix,
 ) (*objects.Prefix, error) {
+	ctx, span := startSpan(ctx, "AddPrefix", "Prefix")
+	defer span.End()
 	newPrefix.NetboxObject.AddTag(nbi.SsotTag)
 	newPrefix.SetCustomField(constants.CustomFieldOrphanLastSeenName, nil)
 	if newPrefix.NetboxObject.CustomFields == nil {
@@ -1436,6 +2325,7 @@ func (nbi *NetboxInventory) AddPrefix(
 		vrfID = newPrefix.VRF.ID
 	}
 
+	defer nbi.inFlight.acquire(constants.PrefixesAPIPath, newPrefix.Prefix)()
 	nbi.prefixesLock.Lock()
 	defer nbi.prefixesLock.Unlock()
 
@@ -1456,7 +2346,7 @@ func (nbi *NetboxInventory) AddPrefix(
 
 	if _, ok := nbi.prefixesIndexByPrefix[newPrefix.Prefix][vrfID]; ok {
 		oldPrefix := nbi.prefixesIndexByPrefix[newPrefix.Prefix][vrfID]
-		nbi.OrphanManager.RemoveItem(oldPrefix)
+		nbi.OrphanManager.RemoveItem(ctx, oldPrefix)
 		diffMap, err := utils.JSONDiffMapExceptID(newPrefix, oldPrefix, false, nbi.SourcePriority)
 		if err != nil {
 			return nil, err
@@ -1467,9 +2357,10 @@ func (nbi *NetboxInventory) AddPrefix(
 				"Prefix %s already exists in Netbox but is out of date. Patching it...",
 				newPrefix.Prefix,
 			)
-			patchedPrefix, err := service.Patch[objects.Prefix](
+			patchedPrefix, err := patchUnlocked[objects.Prefix](
 				ctx,
-				nbi.NetboxAPI,
+				nbi,
+				&nbi.prefixesLock,
 				oldPrefix.ID,
 				diffMap,
 			)
@@ -1482,7 +2373,7 @@ func (nbi *NetboxInventory) AddPrefix(
 		}
 	} else {
 		nbi.Logger.Debugf(ctx, "Prefix %s does not exist in Netbox. Creating it...", newPrefix.Prefix)
-		newPrefix, err := service.Create(ctx, nbi.NetboxAPI, newPrefix)
+		newPrefix, err := createUnlocked(ctx, nbi, &nbi.prefixesLock, newPrefix)
 		if err != nil {
 			return nil, err
 		}
@@ -1492,6 +2383,146 @@ func (nbi *NetboxInventory) AddPrefix(
 	return nbi.prefixesIndexByPrefix[newPrefix.Prefix][vrfID], nil
 }
 
+// AddIPRange adds a new IP range to the Netbox inventory.
+// It takes a context and a newIPRange object as input and
+// returns the created or updated IP range object and an error, if any.
+// IP ranges are matched by their start address and VRF, so a changed
+// end address of the range patches the existing IP range. Netbox rejects
+// overlapping IP ranges in the same VRF, so an overlapping IP range
+// synced by the same source (e.g. of a DHCP pool with a moved start address)
+// is patched as well. IP ranges synced by other sources are never patched:
+// in that case a conflict is recorded, and nil is returned.
+// If the IP range does not exist, it creates a new one.
+func (nbi *NetboxInventory) AddIPRange(
+	ctx context.Context,
+	newIPRange *objects.IPRange,
+) (*objects.IPRange, error) {
+	ctx, span := startSpan(ctx, "AddIPRange", "IPRange")
+	defer span.End()
+	newIPRange.NetboxObject.AddTag(nbi.SsotTag)
+	addSourceNameCustomField(ctx, &newIPRange.NetboxObject)
+	newIPRange.SetCustomField(constants.CustomFieldOrphanLastSeenName, nil)
+
+	// Determine VRF ID for index key (0 = global table)
+	vrfID := 0
+	if newIPRange.VRF != nil {
+		vrfID = newIPRange.VRF.ID
+	}
+
+	// IP ranges of the same VRF can overlap, so they are added one at a time
+	defer nbi.inFlight.acquire(constants.IPRangesAPIPath, vrfID)()
+	nbi.ipRangesLock.Lock()
+	defer nbi.ipRangesLock.Unlock()
+
+	oldIPRange, conflictingIPRange, err := nbi.findIPRange(newIPRange, vrfID)
+	if err != nil {
+		return nil, err
+	}
+	if oldIPRange == nil && conflictingIPRange != nil {
+		conflictingSource, _ := conflictingIPRange.GetCustomField(constants.CustomFieldSourceName).(string)
+		nbi.Logger.Warningf(
+			ctx,
+			"Skipping %s, because it overlaps with %s of source %s",
+			newIPRange,
+			conflictingIPRange,
+			conflictingSource,
+		)
+		nbi.NetboxAPI.RecordConflict(ctx, &service.ValidationError{
+			ObjectPath: constants.IPRangesAPIPath,
+			Action:     service.PlannedActionCreate,
+			NonFieldErrors: []string{
+				fmt.Sprintf("overlaps with %s of source %s", conflictingIPRange, conflictingSource),
+			},
+		}, newIPRange)
+		return nil, nil
+	}
+	ipRange := oldIPRange
+	if oldIPRange != nil {
+		nbi.OrphanManager.RemoveItem(ctx, oldIPRange)
+		diffMap, err := utils.JSONDiffMapExceptID(newIPRange, oldIPRange, false, nbi.SourcePriority)
+		if err != nil {
+			return nil, err
+		}
+		if len(diffMap) > 0 {
+			nbi.Logger.Debugf(
+				ctx,
+				"IP range %s already exists in Netbox but is out of date. Patching it...",
+				newIPRange,
+			)
+			ipRange, err = patchUnlocked[objects.IPRange](
+				ctx,
+				nbi,
+				&nbi.ipRangesLock,
+				oldIPRange.ID,
+				diffMap,
+			)
+			if err != nil {
+				return nil, err
+			}
+			delete(nbi.ipRangesIndexByStartAddress[oldIPRange.StartAddress], vrfID)
+		} else {
+			nbi.Logger.Debugf(ctx, "IP range %s already exists in Netbox and is up to date...", newIPRange)
+		}
+	} else {
+		nbi.Logger.Debugf(ctx, "IP range %s does not exist in Netbox. Creating it...", newIPRange)
+		ipRange, err = createUnlocked(ctx, nbi, &nbi.ipRangesLock, newIPRange)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if nbi.ipRangesIndexByStartAddress[ipRange.StartAddress] == nil {
+		nbi.ipRangesIndexByStartAddress[ipRange.StartAddress] = make(map[int]*objects.IPRange)
+	}
+	nbi.ipRangesIndexByStartAddress[ipRange.StartAddress][vrfID] = ipRange
+	return ipRange, nil
+}
+
+// findIPRange returns the IP range of the VRF, that is replaced by newIPRange:
+// the IP range with the start address of newIPRange, or an IP range synced
+// by the same source, that overlaps with newIPRange. IP ranges synced by other
+// sources are never replaced, so if the matching IP range is one of them,
+// it is returned as conflicting IP range instead. The caller must hold ipRangesLock.
+func (nbi *NetboxInventory) findIPRange(
+	newIPRange *objects.IPRange,
+	vrfID int,
+) (*objects.IPRange, *objects.IPRange, error) {
+	sourceName := newIPRange.GetCustomField(constants.CustomFieldSourceName)
+	isOtherSource := func(ipRange *objects.IPRange) bool {
+		return ipRange.HasTag(nbi.SsotTag) &&
+			ipRange.GetCustomField(constants.CustomFieldSourceName) != sourceName
+	}
+	if ipRange, ok := nbi.ipRangesIndexByStartAddress[newIPRange.StartAddress][vrfID]; ok {
+		if isOtherSource(ipRange) {
+			return nil, ipRange, nil
+		}
+		return ipRange, nil, nil
+	}
+	var conflictingIPRange *objects.IPRange
+	for _, vrfIndex := range nbi.ipRangesIndexByStartAddress {
+		ipRange, ok := vrfIndex[vrfID]
+		if !ok || !ipRange.HasTag(nbi.SsotTag) {
+			continue
+		}
+		overlap, err := utils.IPRangesOverlap(
+			newIPRange.StartAddress,
+			newIPRange.EndAddress,
+			ipRange.StartAddress,
+			ipRange.EndAddress,
+		)
+		if err != nil {
+			return nil, nil, fmt.Errorf("compare IP ranges %s and %s: %s", newIPRange, ipRange, err)
+		}
+		if !overlap {
+			continue
+		}
+		if !isOtherSource(ipRange) {
+			return ipRange, nil, nil
+		}
+		conflictingIPRange = ipRange
+	}
+	return nil, conflictingIPRange, nil
+}
+
 // AddWirelessLAN adds a new wireless LAN to the Netbox inventory.
 // It takes a context and a newWirelessLan object as input and
 // returns the created or updated wireless LAN object and an error, if any.
@@ -1501,14 +2532,17 @@ func (nbi *NetboxInventory) AddWirelessLAN(
 	ctx context.Context,
 	newWirelessLan *objects.WirelessLAN,
 ) (*objects.WirelessLAN, error) {
+	ctx, span := startSpan(ctx, "AddWirelessLAN", "WirelessLAN")
+	defer span.End()
 	newWirelessLan.NetboxObject.AddTag(nbi.SsotTag)
 	newWirelessLan.SetCustomField(constants.CustomFieldOrphanLastSeenName, nil)
+	defer nbi.inFlight.acquire(constants.WirelessLANsAPIPath, newWirelessLan.SSID)()
 	nbi.wirelessLANsLock.Lock()
 	defer nbi.wirelessLANsLock.Unlock()
 	if _, ok := nbi.wirelessLANsIndexBySSID[newWirelessLan.SSID]; ok {
 		// Remove id from orphan manager, because it still exists in the sources
 		oldWirelessLan := nbi.wirelessLANsIndexBySSID[newWirelessLan.SSID]
-		nbi.OrphanManager.RemoveItem(oldWirelessLan)
+		nbi.OrphanManager.RemoveItem(ctx, oldWirelessLan)
 		diffMap, err := utils.JSONDiffMapExceptID(
 			newWirelessLan,
 			oldWirelessLan,
@@ -1524,9 +2558,10 @@ func (nbi *NetboxInventory) AddWirelessLAN(
 				"WirelessLAN %s already exists in Netbox but is out of date. Patching it...",
 				newWirelessLan.SSID,
 			)
-			patchedWirelessLan, err := service.Patch[objects.WirelessLAN](
+			patchedWirelessLan, err := patchUnlocked[objects.WirelessLAN](
 				ctx,
-				nbi.NetboxAPI,
+				nbi,
+				&nbi.wirelessLANsLock,
 				oldWirelessLan.ID,
 				diffMap,
 			)
@@ -1539,7 +2574,7 @@ func (nbi *NetboxInventory) AddWirelessLAN(
 		}
 	} else {
 		nbi.Logger.Debugf(ctx, "WirelessLAN %s does not exist in Netbox. Creating it...", newWirelessLan.SSID)
-		newWirelessLan, err := service.Create(ctx, nbi.NetboxAPI, newWirelessLan)
+		newWirelessLan, err := createUnlocked(ctx, nbi, &nbi.wirelessLANsLock, newWirelessLan)
 		if err != nil {
 			return nil, err
 		}
@@ -1557,14 +2592,17 @@ func (nbi *NetboxInventory) AddWirelessLANGroup(
 	ctx context.Context,
 	newWirelessLANGroup *objects.WirelessLANGroup,
 ) (*objects.WirelessLANGroup, error) {
+	ctx, span := startSpan(ctx, "AddWirelessLANGroup", "WirelessLANGroup")
+	defer span.End()
 	newWirelessLANGroup.NetboxObject.AddTag(nbi.SsotTag)
 	newWirelessLANGroup.SetCustomField(constants.CustomFieldOrphanLastSeenName, nil)
+	defer nbi.inFlight.acquire(constants.WirelessLANGroupsAPIPath, newWirelessLANGroup.Name)()
 	nbi.wirelessLANGroupsLock.Lock()
 	defer nbi.wirelessLANGroupsLock.Unlock()
 	if _, ok := nbi.wirelessLANGroupsIndexByName[newWirelessLANGroup.Name]; ok {
 		// Remove id from orphan manager, because it still exists in the sources
 		oldWirelessLANGroup := nbi.wirelessLANGroupsIndexByName[newWirelessLANGroup.Name]
-		nbi.OrphanManager.RemoveItem(oldWirelessLANGroup)
+		nbi.OrphanManager.RemoveItem(ctx, oldWirelessLANGroup)
 		diffMap, err := utils.JSONDiffMapExceptID(
 			newWirelessLANGroup,
 			oldWirelessLANGroup,
@@ -1580,9 +2618,10 @@ func (nbi *NetboxInventory) AddWirelessLANGroup(
 				"WirelessLANGroup %s already exists in Netbox but is out of date. Patching it...",
 				newWirelessLANGroup.Name,
 			)
-			patchedWirelessLANGroup, err := service.Patch[objects.WirelessLANGroup](
+			patchedWirelessLANGroup, err := patchUnlocked[objects.WirelessLANGroup](
 				ctx,
-				nbi.NetboxAPI,
+				nbi,
+				&nbi.wirelessLANGroupsLock,
 				oldWirelessLANGroup.ID,
 				diffMap,
 			)
@@ -1595,7 +2634,7 @@ func (nbi *NetboxInventory) AddWirelessLANGroup(
 		}
 	} else {
 		nbi.Logger.Debugf(ctx, "WirelessLANGroup %s does not exist in Netbox. Creating it...", newWirelessLANGroup.Name)
-		newWirelessLANGroup, err := service.Create(ctx, nbi.NetboxAPI, newWirelessLANGroup)
+		newWirelessLANGroup, err := createUnlocked(ctx, nbi, &nbi.wirelessLANGroupsLock, newWirelessLANGroup)
 		if err != nil {
 			return nil, err
 		}
@@ -1613,6 +2652,8 @@ func (nbi *NetboxInventory) AddVirtualDisk(
 	ctx context.Context,
 	newVirtualDisk *objects.VirtualDisk,
 ) (*objects.VirtualDisk, error) {
+	ctx, span := startSpan(ctx, "AddVirtualDisk", "VirtualDisk")
+	defer span.End()
 	newVirtualDisk.NetboxObject.AddTag(nbi.SsotTag)
 	addSourceNameCustomField(ctx, &newVirtualDisk.NetboxObject)
 	newVirtualDisk.SetCustomField(constants.CustomFieldOrphanLastSeenName, nil)
@@ -1625,11 +2666,12 @@ func (nbi *NetboxInventory) AddVirtualDisk(
 		)
 		newVirtualDisk.Name = newVirtualDisk.Name[:constants.MaxVirtualDiskNameLength]
 	}
+	defer nbi.inFlight.acquire(constants.VirtualDisksAPIPath, newVirtualDisk.VM.ID, newVirtualDisk.Name)()
 	nbi.virtualDisksLock.Lock()
 	defer nbi.virtualDisksLock.Unlock()
 	if _, ok := nbi.virtualDisksIndexByVMIDAndName[newVirtualDisk.VM.ID][newVirtualDisk.Name]; ok {
 		oldVirtualDisk := nbi.virtualDisksIndexByVMIDAndName[newVirtualDisk.VM.ID][newVirtualDisk.Name]
-		nbi.OrphanManager.RemoveItem(oldVirtualDisk)
+		nbi.OrphanManager.RemoveItem(ctx, oldVirtualDisk)
 		diffMap, err := utils.JSONDiffMapExceptID(
 			newVirtualDisk,
 			oldVirtualDisk,
@@ -1645,9 +2687,10 @@ func (nbi *NetboxInventory) AddVirtualDisk(
 				"VirtualDisk %s already exists in Netbox but is out of date. Patching it...",
 				newVirtualDisk.Name,
 			)
-			patchedVirtualDisk, err := service.Patch[objects.VirtualDisk](
+			patchedVirtualDisk, err := patchUnlocked[objects.VirtualDisk](
 				ctx,
-				nbi.NetboxAPI,
+				nbi,
+				&nbi.virtualDisksLock,
 				oldVirtualDisk.ID,
 				diffMap,
 			)
@@ -1660,7 +2703,7 @@ func (nbi *NetboxInventory) AddVirtualDisk(
 		}
 	} else {
 		nbi.Logger.Debugf(ctx, "VirtualDisk %s does not exist in Netbox. Creating it...", newVirtualDisk.Name)
-		newVirtualDisk, err := service.Create(ctx, nbi.NetboxAPI, newVirtualDisk)
+		newVirtualDisk, err := createUnlocked(ctx, nbi, &nbi.virtualDisksLock, newVirtualDisk)
 		if err != nil {
 			return nil, err
 		}
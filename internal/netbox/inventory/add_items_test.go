@@ -2,10 +2,15 @@ package inventory
 
 import (
 	"context"
+	"log"
+	"os"
 	"reflect"
+	"sync"
 	"testing"
 
 	"github.com/src-doo/netbox-ssot/internal/constants"
+	"github.com/src-doo/netbox-ssot/internal/logger"
+	"github.com/src-doo/netbox-ssot/internal/netbox/netboxtest"
 	"github.com/src-doo/netbox-ssot/internal/netbox/objects"
 	"github.com/src-doo/netbox-ssot/internal/netbox/service"
 )
@@ -662,6 +667,205 @@ func TestNetboxInventory_AddInterface(t *testing.T) {
 	}
 }
 
+// testCable returns a cable between interfaces with ids aInterfaceID and bInterfaceID.
+func testCable(aInterfaceID int, bInterfaceID int) *objects.Cable {
+	return &objects.Cable{
+		ATerminations: []*objects.CableTermination{
+			{ObjectType: constants.ContentTypeDcimInterface, ObjectID: aInterfaceID},
+		},
+		BTerminations: []*objects.CableTermination{
+			{ObjectType: constants.ContentTypeDcimInterface, ObjectID: bInterfaceID},
+		},
+		Status: &objects.CableStatusConnected,
+	}
+}
+
+func TestNetboxInventory_AddCable(t *testing.T) {
+	s := netboxtest.NewServer()
+	defer s.Close()
+	testLogger := &logger.Logger{Logger: log.New(os.Stdout, "", log.LstdFlags)}
+	nbi := &NetboxInventory{
+		Logger: testLogger,
+		NetboxAPI: &service.NetboxClient{
+			HTTPClient: s.Client(),
+			Logger:     testLogger,
+			BaseURL:    s.URL,
+			Timeout:    constants.DefaultAPITimeout,
+		},
+		OrphanManager:            NewOrphanManager(testLogger),
+		tagsIndexByName:          map[string]*objects.Tag{},
+		cablesIndexByInterfaceID: map[int]*objects.Cable{},
+	}
+	ctx := context.WithValue(context.Background(), constants.CtxSourceKey, "test")
+	ssotTag, err := nbi.AddTag(ctx, &objects.Tag{Name: constants.SsotTagName, Slug: constants.SsotTagName})
+	if err != nil {
+		t.Fatal(err)
+	}
+	nbi.SsotTag = ssotTag
+	// Cable between interfaces 5 and 6 was created manually
+	manualCable, err := service.Create(ctx, nbi.NetboxAPI, testCable(5, 6))
+	if err != nil {
+		t.Fatal(err)
+	}
+	nbi.cablesIndexByInterfaceID[5] = manualCable
+	nbi.cablesIndexByInterfaceID[6] = manualCable
+
+	cable, err := nbi.AddCable(ctx, testCable(1, 2))
+	if err != nil {
+		t.Fatalf("AddCable() error = %v", err)
+	}
+	reversedCable, err := nbi.AddCable(ctx, testCable(2, 1))
+	if err != nil {
+		t.Fatalf("AddCable() error = %v", err)
+	}
+	if reversedCable.ID != cable.ID {
+		t.Errorf("AddCable() with swapped ends = %s, want existing %s", reversedCable, cable)
+	}
+
+	// Interface 1 is now connected to interface 3, so the old cable is replaced
+	movedCable, err := nbi.AddCable(ctx, testCable(1, 3))
+	if err != nil {
+		t.Fatalf("AddCable() error = %v", err)
+	}
+	if _, ok := nbi.cablesIndexByInterfaceID[2]; ok {
+		t.Errorf("replaced cable is still indexed by its interface")
+	}
+
+	// Manually created cable is never replaced
+	skippedCable, err := nbi.AddCable(ctx, testCable(5, 4))
+	if err != nil || skippedCable != nil {
+		t.Errorf("AddCable() = %v, %v, want cable to be skipped", skippedCable, err)
+	}
+
+	cables, err := netboxtest.Objects[objects.Cable](s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantIDs := map[int]bool{manualCable.ID: true, movedCable.ID: true}
+	if len(cables) != len(wantIDs) {
+		t.Errorf("netbox has %d cables, want %d", len(cables), len(wantIDs))
+	}
+	for _, cable := range cables {
+		if !wantIDs[cable.ID] {
+			t.Errorf("netbox has unexpected %s", cable)
+		}
+	}
+}
+
+func TestNetboxInventory_AddCableConcurrently(t *testing.T) {
+	s := netboxtest.NewServer()
+	defer s.Close()
+	testLogger := &logger.Logger{Logger: log.New(os.Stdout, "", log.LstdFlags)}
+	nbi := &NetboxInventory{
+		Logger: testLogger,
+		NetboxAPI: &service.NetboxClient{
+			HTTPClient: s.Client(),
+			Logger:     testLogger,
+			BaseURL:    s.URL,
+			Timeout:    constants.DefaultAPITimeout,
+		},
+		OrphanManager:            NewOrphanManager(testLogger),
+		tagsIndexByName:          map[string]*objects.Tag{},
+		cablesIndexByInterfaceID: map[int]*objects.Cable{},
+	}
+	ctx := context.WithValue(context.Background(), constants.CtxSourceKey, "test")
+	ssotTag, err := nbi.AddTag(ctx, &objects.Tag{Name: constants.SsotTagName, Slug: constants.SsotTagName})
+	if err != nil {
+		t.Fatal(err)
+	}
+	nbi.SsotTag = ssotTag
+
+	// Neighbors disagree about the interface connected to interface 1,
+	// so only the last added cable may remain
+	var wg sync.WaitGroup
+	for neighborInterfaceID := 2; neighborInterfaceID <= 10; neighborInterfaceID++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := nbi.AddCable(ctx, testCable(1, neighborInterfaceID))
+			if err != nil {
+				t.Errorf("AddCable() error = %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	cables, err := netboxtest.Objects[objects.Cable](s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cables) != 1 {
+		t.Fatalf("netbox has cables %v, want 1", cables)
+	}
+	if indexedCable := nbi.cablesIndexByInterfaceID[1]; indexedCable == nil || indexedCable.ID != cables[0].ID {
+		t.Errorf("interface 1 is indexed with cable %v, want %s", indexedCable, cables[0])
+	}
+}
+
+func TestNetboxInventory_AddInventoryItem(t *testing.T) {
+	s := netboxtest.NewServer()
+	defer s.Close()
+	testLogger := &logger.Logger{Logger: log.New(os.Stdout, "", log.LstdFlags)}
+	nbi := &NetboxInventory{
+		Logger: testLogger,
+		NetboxAPI: &service.NetboxClient{
+			HTTPClient: s.Client(),
+			Logger:     testLogger,
+			BaseURL:    s.URL,
+			Timeout:    constants.DefaultAPITimeout,
+		},
+		OrphanManager:                        NewOrphanManager(testLogger),
+		tagsIndexByName:                      map[string]*objects.Tag{},
+		inventoryItemsIndexByDeviceIDAndName: map[int]map[string]*objects.InventoryItem{},
+	}
+	ctx := context.WithValue(context.Background(), constants.CtxSourceKey, "test")
+	ssotTag, err := nbi.AddTag(ctx, &objects.Tag{Name: constants.SsotTagName, Slug: constants.SsotTagName})
+	if err != nil {
+		t.Fatal(err)
+	}
+	nbi.SsotTag = ssotTag
+	device, err := netboxtest.Seed(s, &objects.Device{Name: "router1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	psu, err := nbi.AddInventoryItem(ctx, &objects.InventoryItem{
+		Device:       device,
+		Name:         "Power Supply Module 0",
+		PartID:       "PWR-4430-AC",
+		SerialNumber: "ABC1234",
+	})
+	if err != nil {
+		t.Fatalf("AddInventoryItem() error = %v", err)
+	}
+	// Power supply was swapped, so the existing inventory item is patched
+	swappedPsu, err := nbi.AddInventoryItem(ctx, &objects.InventoryItem{
+		Device:       device,
+		Name:         "Power Supply Module 0",
+		PartID:       "PWR-4430-AC",
+		SerialNumber: "DEF5678",
+	})
+	if err != nil {
+		t.Fatalf("AddInventoryItem() error = %v", err)
+	}
+	if swappedPsu.ID != psu.ID || swappedPsu.SerialNumber != "DEF5678" {
+		t.Errorf("AddInventoryItem() = %s, want patched %s", swappedPsu, psu)
+	}
+
+	_, err = nbi.AddInventoryItem(ctx, &objects.InventoryItem{Name: "Fan Tray"})
+	if err == nil {
+		t.Errorf("AddInventoryItem() without device, want error")
+	}
+
+	inventoryItems, err := netboxtest.Objects[objects.InventoryItem](s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(inventoryItems) != 1 || inventoryItems[0].SerialNumber != "DEF5678" {
+		t.Errorf("netbox has inventory items %v, want one with serial DEF5678", inventoryItems)
+	}
+}
+
 func TestNetboxInventory_AddVM(t *testing.T) {
 	type args struct {
 		ctx   context.Context
@@ -939,3 +1143,224 @@ func TestNetboxInventory_applyDeviceFieldLengthLimitations(t *testing.T) {
 		})
 	}
 }
+
+func TestNetboxInventory_AddIPRange(t *testing.T) {
+	s := netboxtest.NewServer()
+	defer s.Close()
+	testLogger := &logger.Logger{Logger: log.New(os.Stdout, "", log.LstdFlags)}
+	nbi := &NetboxInventory{
+		Logger: testLogger,
+		NetboxAPI: &service.NetboxClient{
+			HTTPClient: s.Client(),
+			Logger:     testLogger,
+			BaseURL:    s.URL,
+			Timeout:    constants.DefaultAPITimeout,
+		},
+		OrphanManager:               NewOrphanManager(testLogger),
+		tagsIndexByName:             map[string]*objects.Tag{},
+		ipRangesIndexByStartAddress: map[string]map[int]*objects.IPRange{},
+	}
+	ctx := context.WithValue(context.Background(), constants.CtxSourceKey, "test")
+	ssotTag, err := nbi.AddTag(ctx, &objects.Tag{Name: constants.SsotTagName, Slug: constants.SsotTagName})
+	if err != nil {
+		t.Fatal(err)
+	}
+	nbi.SsotTag = ssotTag
+	vrf, err := netboxtest.Seed(s, &objects.VRF{Name: "guests"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pool, err := nbi.AddIPRange(ctx, &objects.IPRange{
+		StartAddress: "10.0.0.100/24",
+		EndAddress:   "10.0.0.150/24",
+		Status:       &objects.IPRangeStatusActive,
+	})
+	if err != nil {
+		t.Fatalf("AddIPRange() error = %v", err)
+	}
+	// Pool was extended, so the existing IP range is patched
+	extendedPool, err := nbi.AddIPRange(ctx, &objects.IPRange{
+		StartAddress: "10.0.0.100/24",
+		EndAddress:   "10.0.0.200/24",
+		Status:       &objects.IPRangeStatusActive,
+	})
+	if err != nil {
+		t.Fatalf("AddIPRange() error = %v", err)
+	}
+	if extendedPool.ID != pool.ID || extendedPool.EndAddress != "10.0.0.200/24" {
+		t.Errorf("AddIPRange() = %s, want patched %s", extendedPool, pool)
+	}
+	// Same pool in another VRF is a separate IP range
+	vrfPool, err := nbi.AddIPRange(ctx, &objects.IPRange{
+		StartAddress: "10.0.0.100/24",
+		EndAddress:   "10.0.0.150/24",
+		Status:       &objects.IPRangeStatusActive,
+		VRF:          vrf,
+	})
+	if err != nil {
+		t.Fatalf("AddIPRange() error = %v", err)
+	}
+	if vrfPool.ID == pool.ID {
+		t.Errorf("AddIPRange() = %s, want new IP range in VRF %s", vrfPool, vrf.Name)
+	}
+	// Start address of the pool was moved, so the overlapping IP range is patched
+	movedPool, err := nbi.AddIPRange(ctx, &objects.IPRange{
+		StartAddress: "10.0.0.120/24",
+		EndAddress:   "10.0.0.200/24",
+		Status:       &objects.IPRangeStatusActive,
+	})
+	if err != nil {
+		t.Fatalf("AddIPRange() error = %v", err)
+	}
+	if movedPool.ID != pool.ID || movedPool.StartAddress != "10.0.0.120/24" {
+		t.Errorf("AddIPRange() = %s, want patched %s", movedPool, pool)
+	}
+	if _, ok := nbi.ipRangesIndexByStartAddress["10.0.0.100/24"][0]; ok {
+		t.Errorf("IP range index still contains the old start address of %s", movedPool)
+	}
+	if nbi.ipRangesIndexByStartAddress["10.0.0.100/24"][vrf.ID] != vrfPool {
+		t.Errorf("IP range index lost %s in VRF %s", vrfPool, vrf.Name)
+	}
+
+	ipRanges, err := netboxtest.Objects[objects.IPRange](s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ipRanges) != 2 {
+		t.Errorf("netbox has IP ranges %v, want 2", ipRanges)
+	}
+}
+
+func TestNetboxInventory_AddIPRangeOfOtherSource(t *testing.T) {
+	s := netboxtest.NewServer()
+	defer s.Close()
+	testLogger := &logger.Logger{Logger: log.New(os.Stdout, "", log.LstdFlags)}
+	nbi := &NetboxInventory{
+		Logger: testLogger,
+		NetboxAPI: &service.NetboxClient{
+			HTTPClient: s.Client(),
+			Logger:     testLogger,
+			BaseURL:    s.URL,
+			Timeout:    constants.DefaultAPITimeout,
+		},
+		OrphanManager:               NewOrphanManager(testLogger),
+		tagsIndexByName:             map[string]*objects.Tag{},
+		ipRangesIndexByStartAddress: map[string]map[int]*objects.IPRange{},
+	}
+	branchACtx := context.WithValue(context.Background(), constants.CtxSourceKey, "branch-a")
+	branchBCtx := context.WithValue(context.Background(), constants.CtxSourceKey, "branch-b")
+	ssotTag, err := nbi.AddTag(branchACtx, &objects.Tag{Name: constants.SsotTagName, Slug: constants.SsotTagName})
+	if err != nil {
+		t.Fatal(err)
+	}
+	nbi.SsotTag = ssotTag
+
+	pool, err := nbi.AddIPRange(branchACtx, &objects.IPRange{
+		StartAddress: "192.168.1.100/24",
+		EndAddress:   "192.168.1.200/24",
+		Status:       &objects.IPRangeStatusActive,
+	})
+	if err != nil {
+		t.Fatalf("AddIPRange() error = %v", err)
+	}
+	// Both the same and an overlapping pool of another source are skipped
+	for _, startAddress := range []string{"192.168.1.100/24", "192.168.1.150/24"} {
+		otherPool, err := nbi.AddIPRange(branchBCtx, &objects.IPRange{
+			StartAddress: startAddress,
+			EndAddress:   "192.168.1.250/24",
+			Status:       &objects.IPRangeStatusActive,
+		})
+		if err != nil || otherPool != nil {
+			t.Errorf("AddIPRange() of other source = %v, %v, want IP range to be skipped", otherPool, err)
+		}
+	}
+	// Pool of the first source is left as it is
+	samePool, err := nbi.AddIPRange(branchACtx, &objects.IPRange{
+		StartAddress: "192.168.1.100/24",
+		EndAddress:   "192.168.1.200/24",
+		Status:       &objects.IPRangeStatusActive,
+	})
+	if err != nil {
+		t.Fatalf("AddIPRange() error = %v", err)
+	}
+	if samePool.ID != pool.ID || samePool.EndAddress != "192.168.1.200/24" {
+		t.Errorf("AddIPRange() = %s, want unchanged %s", samePool, pool)
+	}
+
+	ipRanges, err := netboxtest.Objects[objects.IPRange](s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ipRanges) != 1 || ipRanges[0].EndAddress != "192.168.1.200/24" {
+		t.Errorf("netbox has IP ranges %v, want only %s", ipRanges, pool)
+	}
+	conflicts := nbi.NetboxAPI.Conflicts()
+	if len(conflicts) != 2 {
+		t.Fatalf("Conflicts() = %v, want 2 conflicts", conflicts)
+	}
+	for _, conflict := range conflicts {
+		if conflict.Source != "branch-b" || conflict.ObjectPath != constants.IPRangesAPIPath {
+			t.Errorf("Conflicts() has %+v, want conflict of branch-b IP range", conflict)
+		}
+	}
+}
+
+func TestNetboxInventory_AddRegion(t *testing.T) {
+	s := netboxtest.NewServer()
+	defer s.Close()
+	testLogger := &logger.Logger{Logger: log.New(os.Stdout, "", log.LstdFlags)}
+	nbi := &NetboxInventory{
+		Logger: testLogger,
+		NetboxAPI: &service.NetboxClient{
+			HTTPClient: s.Client(),
+			Logger:     testLogger,
+			BaseURL:    s.URL,
+			Timeout:    constants.DefaultAPITimeout,
+		},
+		tagsIndexByName:               map[string]*objects.Tag{},
+		regionsIndexByParentIDAndName: map[int]map[string]*objects.Region{},
+	}
+	ctx := context.WithValue(context.Background(), constants.CtxSourceKey, "test")
+	ssotTag, err := nbi.AddTag(ctx, &objects.Tag{Name: constants.SsotTagName, Slug: constants.SsotTagName})
+	if err != nil {
+		t.Fatal(err)
+	}
+	nbi.SsotTag = ssotTag
+
+	parents := []*objects.Region{}
+	for _, name := range []string{"europe", "america"} {
+		parent, err := nbi.AddRegion(ctx, &objects.Region{Name: name, Slug: name})
+		if err != nil {
+			t.Fatalf("AddRegion() error = %v", err)
+		}
+		parents = append(parents, parent)
+	}
+	// Same named regions in different parent regions are separate regions
+	europeRegion, err := nbi.AddRegion(ctx, &objects.Region{Name: "West", Slug: "west", Parent: parents[0]})
+	if err != nil {
+		t.Fatalf("AddRegion() error = %v", err)
+	}
+	americaRegion, err := nbi.AddRegion(ctx, &objects.Region{Name: "West", Slug: "west", Parent: parents[1]})
+	if err != nil {
+		t.Fatalf("AddRegion() error = %v", err)
+	}
+	if europeRegion.ID == americaRegion.ID {
+		t.Errorf("AddRegion() = %s, want new region in parent %s", americaRegion, parents[1].Name)
+	}
+	existingRegion, err := nbi.AddRegion(ctx, &objects.Region{Name: "West", Slug: "west", Parent: parents[0]})
+	if err != nil {
+		t.Fatalf("AddRegion() error = %v", err)
+	}
+	if existingRegion.ID != europeRegion.ID {
+		t.Errorf("AddRegion() = %s, want existing region %s", existingRegion, europeRegion)
+	}
+
+	regions, err := netboxtest.Objects[objects.Region](s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(regions) != 4 {
+		t.Errorf("netbox has regions %v, want 4", regions)
+	}
+}
@@ -10,7 +10,11 @@ import (
 	"github.com/src-doo/netbox-ssot/internal/utils"
 )
 
-func (nbi *NetboxInventory) DeleteOrphans(hard bool) error {
+// DeleteOrphans removes orphaned objects from netbox. If hard is false, orphans are
+// only tagged, and removed once they are orphaned for longer than RemoveOrphansAfterDays.
+// It returns the number of objects, that were tagged or removed in this run.
+func (nbi *NetboxInventory) DeleteOrphans(hard bool) (int, error) {
+	changed := 0
 	for i := 0; i < len(nbi.OrphanManager.OrphanObjectPriority); i++ {
 		deleteTypeStr := "soft"
 		if hard {
@@ -43,16 +47,21 @@ func (nbi *NetboxInventory) DeleteOrphans(hard bool) error {
 					nbi.OrphanManager.Logger.Errorf(nbi.Ctx, "hard delete object: %s", err)
 					continue
 				}
+				changed++
 			} else {
-				err := nbi.softDelete(orphanItem)
+				softDeleted, err := nbi.softDelete(orphanItem)
 				if err != nil {
 					nbi.OrphanManager.Logger.Errorf(nbi.Ctx, "soft delete object: %s", err)
+					continue
+				}
+				if softDeleted {
+					changed++
 				}
 			}
 		}
 	}
 
-	return nil
+	return changed, nil
 }
 
 func (nbi *NetboxInventory) hardDelete(orphanItem objects.OrphanItem) error {
@@ -64,7 +73,10 @@ func (nbi *NetboxInventory) hardDelete(orphanItem objects.OrphanItem) error {
 	return nil
 }
 
-func (nbi *NetboxInventory) softDelete(orphanItem objects.OrphanItem) error {
+// softDelete tags the orphanItem as orphaned, or removes it if it is orphaned for
+// longer than RemoveOrphansAfterDays. It returns false, if the orphanItem was
+// already tagged and wasn't removed.
+func (nbi *NetboxInventory) softDelete(orphanItem objects.OrphanItem) (bool, error) {
 	// Perform soft deletion
 	// Add tag to the object to mark it as orphaned
 	todayDate := time.Now().Format(constants.CustomFieldOrphanLastSeenFormat)
@@ -81,82 +93,102 @@ func (nbi *NetboxInventory) softDelete(orphanItem objects.OrphanItem) error {
 			[]string{"tags", "custom_fields"},
 		)
 		// Update object on the API
-		var err error
-		switch orphanItem.(type) {
-		case *objects.VlanGroup:
-			_, err = service.Patch[objects.VlanGroup](nbi.OrphanManager.Ctx, nbi.NetboxAPI, orphanItem.GetID(), diffMap)
-		case *objects.Prefix:
-			_, err = service.Patch[objects.Prefix](nbi.OrphanManager.Ctx, nbi.NetboxAPI, orphanItem.GetID(), diffMap)
-		case *objects.Vlan:
-			_, err = service.Patch[objects.Vlan](nbi.OrphanManager.Ctx, nbi.NetboxAPI, orphanItem.GetID(), diffMap)
-		case *objects.IPAddress:
-			_, err = service.Patch[objects.IPAddress](nbi.OrphanManager.Ctx, nbi.NetboxAPI, orphanItem.GetID(), diffMap)
-		case *objects.VirtualDeviceContext:
-			_, err = service.Patch[objects.VirtualDeviceContext](
-				nbi.OrphanManager.Ctx,
-				nbi.NetboxAPI,
-				orphanItem.GetID(),
-				diffMap,
-			)
-		case *objects.Interface:
-			_, err = service.Patch[objects.Interface](nbi.OrphanManager.Ctx, nbi.NetboxAPI, orphanItem.GetID(), diffMap)
-		case *objects.VMInterface:
-			_, err = service.Patch[objects.VMInterface](nbi.OrphanManager.Ctx, nbi.NetboxAPI, orphanItem.GetID(), diffMap)
-		case *objects.VM:
-			_, err = service.Patch[objects.VM](nbi.OrphanManager.Ctx, nbi.NetboxAPI, orphanItem.GetID(), diffMap)
-		case *objects.Device:
-			_, err = service.Patch[objects.Device](nbi.OrphanManager.Ctx, nbi.NetboxAPI, orphanItem.GetID(), diffMap)
-		case *objects.Platform:
-			_, err = service.Patch[objects.Platform](nbi.OrphanManager.Ctx, nbi.NetboxAPI, orphanItem.GetID(), diffMap)
-		case *objects.DeviceType:
-			_, err = service.Patch[objects.DeviceType](nbi.OrphanManager.Ctx, nbi.NetboxAPI, orphanItem.GetID(), diffMap)
-		case *objects.Manufacturer:
-			_, err = service.Patch[objects.Manufacturer](nbi.OrphanManager.Ctx, nbi.NetboxAPI, orphanItem.GetID(), diffMap)
-		case *objects.DeviceRole:
-			_, err = service.Patch[objects.DeviceRole](nbi.OrphanManager.Ctx, nbi.NetboxAPI, orphanItem.GetID(), diffMap)
-		case *objects.ClusterType:
-			_, err = service.Patch[objects.ClusterType](nbi.OrphanManager.Ctx, nbi.NetboxAPI, orphanItem.GetID(), diffMap)
-		case *objects.Cluster:
-			_, err = service.Patch[objects.Cluster](nbi.OrphanManager.Ctx, nbi.NetboxAPI, orphanItem.GetID(), diffMap)
-		case *objects.ClusterGroup:
-			_, err = service.Patch[objects.ClusterGroup](nbi.OrphanManager.Ctx, nbi.NetboxAPI, orphanItem.GetID(), diffMap)
-		case *objects.ContactAssignment:
-			_, err = service.Patch[objects.ContactAssignment](nbi.OrphanManager.Ctx, nbi.NetboxAPI, orphanItem.GetID(), diffMap)
-		case *objects.Contact:
-			_, err = service.Patch[objects.Contact](nbi.OrphanManager.Ctx, nbi.NetboxAPI, orphanItem.GetID(), diffMap)
-		case *objects.WirelessLAN:
-			_, err = service.Patch[objects.WirelessLAN](nbi.OrphanManager.Ctx, nbi.NetboxAPI, orphanItem.GetID(), diffMap)
-		case *objects.WirelessLANGroup:
-			_, err = service.Patch[objects.WirelessLANGroup](nbi.OrphanManager.Ctx, nbi.NetboxAPI, orphanItem.GetID(), diffMap)
-		case *objects.MACAddress:
-			_, err = service.Patch[objects.MACAddress](nbi.OrphanManager.Ctx, nbi.NetboxAPI, orphanItem.GetID(), diffMap)
-		case *objects.VirtualDisk:
-			_, err = service.Patch[objects.VirtualDisk](nbi.OrphanManager.Ctx, nbi.NetboxAPI, orphanItem.GetID(), diffMap)
-		default:
-			return fmt.Errorf("unsupported type for orphan item%T", orphanItem)
-		}
+		err := nbi.patchOrphanItem(orphanItem, diffMap)
 		if err != nil {
-			return fmt.Errorf("failed updating %s object with orphan tag: %s", orphanItem, err)
-		}
-	} else {
-		nbi.Logger.Debugf(nbi.Ctx, "%s is already marked as orphan", orphanItem)
-		lastSeenRaw, ok := orphanItem.GetNetboxObject().GetCustomField(constants.CustomFieldOrphanLastSeenName).(string)
-		if !ok {
-			return fmt.Errorf("failed to get last seen date as string for %s", orphanItem)
+			return false, fmt.Errorf("failed updating %s object with orphan tag: %s", orphanItem, err)
 		}
-		lastSeen, err := time.Parse(
-			constants.CustomFieldOrphanLastSeenFormat,
-			lastSeenRaw,
-		)
+		return true, nil
+	}
+	nbi.Logger.Debugf(nbi.Ctx, "%s is already marked as orphan", orphanItem)
+	lastSeenRaw, ok := orphanItem.GetNetboxObject().GetCustomField(constants.CustomFieldOrphanLastSeenName).(string)
+	if !ok {
+		return false, fmt.Errorf("failed to get last seen date as string for %s", orphanItem)
+	}
+	lastSeen, err := time.Parse(
+		constants.CustomFieldOrphanLastSeenFormat,
+		lastSeenRaw,
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed parsing last seen date: %s", err)
+	}
+	if int((time.Since(lastSeen).Hours())/24) > nbi.NetboxConfig.RemoveOrphansAfterDays { //nolint:mnd
+		err := nbi.hardDelete(orphanItem)
 		if err != nil {
-			return fmt.Errorf("failed parsing last seen date: %s", err)
-		}
-		if int((time.Since(lastSeen).Hours())/24) > nbi.NetboxConfig.RemoveOrphansAfterDays { //nolint:mnd
-			err := nbi.hardDelete(orphanItem)
-			if err != nil {
-				return fmt.Errorf("failed deleting %s object: %s", orphanItem, err)
-			}
+			return false, fmt.Errorf("failed deleting %s object: %s", orphanItem, err)
 		}
+		return true, nil
 	}
-	return nil
+	return false, nil
+}
+
+// patchOrphanItem patches the orphanItem on the API with the given diffMap.
+func (nbi *NetboxInventory) patchOrphanItem(
+	orphanItem objects.OrphanItem,
+	diffMap map[string]interface{},
+) error {
+	var err error
+	switch orphanItem.(type) {
+	case *objects.VlanGroup:
+		_, err = service.Patch[objects.VlanGroup](nbi.OrphanManager.Ctx, nbi.NetboxAPI, orphanItem.GetID(), diffMap)
+	case *objects.Prefix:
+		_, err = service.Patch[objects.Prefix](nbi.OrphanManager.Ctx, nbi.NetboxAPI, orphanItem.GetID(), diffMap)
+	case *objects.IPRange:
+		_, err = service.Patch[objects.IPRange](nbi.OrphanManager.Ctx, nbi.NetboxAPI, orphanItem.GetID(), diffMap)
+	case *objects.Vlan:
+		_, err = service.Patch[objects.Vlan](nbi.OrphanManager.Ctx, nbi.NetboxAPI, orphanItem.GetID(), diffMap)
+	case *objects.IPAddress:
+		_, err = service.Patch[objects.IPAddress](nbi.OrphanManager.Ctx, nbi.NetboxAPI, orphanItem.GetID(), diffMap)
+	case *objects.VirtualDeviceContext:
+		_, err = service.Patch[objects.VirtualDeviceContext](
+			nbi.OrphanManager.Ctx,
+			nbi.NetboxAPI,
+			orphanItem.GetID(),
+			diffMap,
+		)
+	case *objects.Cable:
+		_, err = service.Patch[objects.Cable](nbi.OrphanManager.Ctx, nbi.NetboxAPI, orphanItem.GetID(), diffMap)
+	case *objects.InventoryItem:
+		_, err = service.Patch[objects.InventoryItem](nbi.OrphanManager.Ctx, nbi.NetboxAPI, orphanItem.GetID(), diffMap)
+	case *objects.Interface:
+		_, err = service.Patch[objects.Interface](nbi.OrphanManager.Ctx, nbi.NetboxAPI, orphanItem.GetID(), diffMap)
+	case *objects.VMInterface:
+		_, err = service.Patch[objects.VMInterface](nbi.OrphanManager.Ctx, nbi.NetboxAPI, orphanItem.GetID(), diffMap)
+	case *objects.VM:
+		_, err = service.Patch[objects.VM](nbi.OrphanManager.Ctx, nbi.NetboxAPI, orphanItem.GetID(), diffMap)
+	case *objects.VirtualChassis:
+		_, err = service.Patch[objects.VirtualChassis](nbi.OrphanManager.Ctx, nbi.NetboxAPI, orphanItem.GetID(), diffMap)
+	case *objects.Device:
+		_, err = service.Patch[objects.Device](nbi.OrphanManager.Ctx, nbi.NetboxAPI, orphanItem.GetID(), diffMap)
+	case *objects.Platform:
+		_, err = service.Patch[objects.Platform](nbi.OrphanManager.Ctx, nbi.NetboxAPI, orphanItem.GetID(), diffMap)
+	case *objects.DeviceType:
+		_, err = service.Patch[objects.DeviceType](nbi.OrphanManager.Ctx, nbi.NetboxAPI, orphanItem.GetID(), diffMap)
+	case *objects.Manufacturer:
+		_, err = service.Patch[objects.Manufacturer](nbi.OrphanManager.Ctx, nbi.NetboxAPI, orphanItem.GetID(), diffMap)
+	case *objects.DeviceRole:
+		_, err = service.Patch[objects.DeviceRole](nbi.OrphanManager.Ctx, nbi.NetboxAPI, orphanItem.GetID(), diffMap)
+	case *objects.ClusterType:
+		_, err = service.Patch[objects.ClusterType](nbi.OrphanManager.Ctx, nbi.NetboxAPI, orphanItem.GetID(), diffMap)
+	case *objects.Cluster:
+		_, err = service.Patch[objects.Cluster](nbi.OrphanManager.Ctx, nbi.NetboxAPI, orphanItem.GetID(), diffMap)
+	case *objects.ClusterGroup:
+		_, err = service.Patch[objects.ClusterGroup](nbi.OrphanManager.Ctx, nbi.NetboxAPI, orphanItem.GetID(), diffMap)
+	case *objects.ContactAssignment:
+		_, err = service.Patch[objects.ContactAssignment](nbi.OrphanManager.Ctx, nbi.NetboxAPI, orphanItem.GetID(), diffMap)
+	case *objects.Contact:
+		_, err = service.Patch[objects.Contact](nbi.OrphanManager.Ctx, nbi.NetboxAPI, orphanItem.GetID(), diffMap)
+	case *objects.WirelessLAN:
+		_, err = service.Patch[objects.WirelessLAN](nbi.OrphanManager.Ctx, nbi.NetboxAPI, orphanItem.GetID(), diffMap)
+	case *objects.WirelessLANGroup:
+		_, err = service.Patch[objects.WirelessLANGroup](nbi.OrphanManager.Ctx, nbi.NetboxAPI, orphanItem.GetID(), diffMap)
+	case *objects.MACAddress:
+		_, err = service.Patch[objects.MACAddress](nbi.OrphanManager.Ctx, nbi.NetboxAPI, orphanItem.GetID(), diffMap)
+	case *objects.VirtualDisk:
+		_, err = service.Patch[objects.VirtualDisk](nbi.OrphanManager.Ctx, nbi.NetboxAPI, orphanItem.GetID(), diffMap)
+	case *objects.VRF:
+		_, err = service.Patch[objects.VRF](nbi.OrphanManager.Ctx, nbi.NetboxAPI, orphanItem.GetID(), diffMap)
+	default:
+		return fmt.Errorf("unsupported type for orphan item %T", orphanItem)
+	}
+	return err
 }
@@ -1,9 +1,17 @@
 package inventory
 
 import (
+	"context"
+	"log"
+	"os"
 	"testing"
 
+	"github.com/src-doo/netbox-ssot/internal/constants"
+	"github.com/src-doo/netbox-ssot/internal/logger"
+	"github.com/src-doo/netbox-ssot/internal/netbox/netboxtest"
 	"github.com/src-doo/netbox-ssot/internal/netbox/objects"
+	"github.com/src-doo/netbox-ssot/internal/netbox/service"
+	"github.com/src-doo/netbox-ssot/internal/parser"
 )
 
 func TestNetboxInventory_DeleteOrphans(t *testing.T) {
@@ -20,13 +28,61 @@ func TestNetboxInventory_DeleteOrphans(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if err := tt.nbi.DeleteOrphans(tt.args.hard); (err != nil) != tt.wantErr {
+			if _, err := tt.nbi.DeleteOrphans(tt.args.hard); (err != nil) != tt.wantErr {
 				t.Errorf("NetboxInventory.DeleteOrphans() error = %v, wantErr %v", err, tt.wantErr)
 			}
 		})
 	}
 }
 
+func TestNetboxInventory_DeleteOrphansCount(t *testing.T) {
+	s := netboxtest.NewServer()
+	defer s.Close()
+	testLogger := &logger.Logger{Logger: log.New(os.Stdout, "", log.LstdFlags)}
+	ctx := context.WithValue(context.Background(), constants.CtxSourceKey, "test")
+	nbi := &NetboxInventory{
+		Ctx:    ctx,
+		Logger: testLogger,
+		NetboxAPI: &service.NetboxClient{
+			HTTPClient: s.Client(),
+			Logger:     testLogger,
+			BaseURL:    s.URL,
+			Timeout:    constants.DefaultAPITimeout,
+		},
+		NetboxConfig:  &parser.NetboxConfig{RemoveOrphansAfterDays: 5},
+		OrphanManager: NewOrphanManager(testLogger),
+	}
+	nbi.OrphanManager.Ctx = ctx
+	ssotTag, err := netboxtest.Seed(s, &objects.Tag{Name: constants.SsotTagName, Slug: constants.SsotTagName})
+	if err != nil {
+		t.Fatal(err)
+	}
+	orphanTag, err := netboxtest.Seed(s, &objects.Tag{Name: constants.OrphanTagName, Slug: constants.OrphanTagName})
+	if err != nil {
+		t.Fatal(err)
+	}
+	nbi.OrphanManager.Tag = orphanTag
+	platform, err := netboxtest.Seed(s, &objects.Platform{
+		NetboxObject: objects.NetboxObject{Tags: []*objects.Tag{ssotTag}},
+		Name:         "old platform",
+		Slug:         "old-platform",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	nbi.OrphanManager.AddItem(platform)
+
+	changed, err := nbi.DeleteOrphans(false)
+	if err != nil || changed != 1 {
+		t.Errorf("DeleteOrphans() = %d, %v, want 1 tagged orphan", changed, err)
+	}
+	// Orphan is already tagged, so nothing changes in the next run
+	changed, err = nbi.DeleteOrphans(false)
+	if err != nil || changed != 0 {
+		t.Errorf("DeleteOrphans() of tagged orphans = %d, %v, want 0", changed, err)
+	}
+}
+
 func TestNetboxInventory_hardDelete(t *testing.T) {
 	type args struct {
 		orphanItem objects.OrphanItem
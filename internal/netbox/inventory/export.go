@@ -0,0 +1,342 @@
+package inventory
+
+import (
+	"sort"
+
+	"github.com/src-doo/netbox-ssot/internal/constants"
+	"github.com/src-doo/netbox-ssot/internal/netbox/objects"
+)
+
+// Export returns all objects currently stored in the inventory,
+// grouped by their api path and sorted by their id.
+// This function is thread-safe.
+//
+//nolint:gocyclo
+func (nbi *NetboxInventory) Export() map[constants.APIPath][]objects.IDItem {
+	exported := map[constants.APIPath][]objects.IDItem{}
+	add := func(item objects.IDItem) {
+		exported[item.GetAPIPath()] = append(exported[item.GetAPIPath()], item)
+	}
+
+	nbi.tagsLock.Lock()
+	for _, tag := range nbi.tagsIndexByName {
+		add(tag)
+	}
+	nbi.tagsLock.Unlock()
+
+	nbi.customFieldsLock.Lock()
+	for _, customField := range nbi.customFieldsIndexByName {
+		add(customField)
+	}
+	nbi.customFieldsLock.Unlock()
+
+	nbi.contactGroupsLock.Lock()
+	for _, contactGroup := range nbi.contactGroupsIndexByName {
+		add(contactGroup)
+	}
+	nbi.contactGroupsLock.Unlock()
+
+	nbi.contactRolesLock.Lock()
+	for _, contactRole := range nbi.contactRolesIndexByName {
+		add(contactRole)
+	}
+	nbi.contactRolesLock.Unlock()
+
+	nbi.contactsLock.Lock()
+	for _, contact := range nbi.contactsIndexByName {
+		add(contact)
+	}
+	nbi.contactsLock.Unlock()
+
+	nbi.contactAssignmentsLock.Lock()
+	for _, objectIndex := range nbi.contactAssignmentsIndex {
+		for _, contactIndex := range objectIndex {
+			for _, roleIndex := range contactIndex {
+				for _, contactAssignment := range roleIndex {
+					add(contactAssignment)
+				}
+			}
+		}
+	}
+	nbi.contactAssignmentsLock.Unlock()
+
+	nbi.tenantGroupsLock.Lock()
+	for _, tenantGroup := range nbi.tenantGroupsIndexByName {
+		add(tenantGroup)
+	}
+	nbi.tenantGroupsLock.Unlock()
+
+	nbi.tenantsLock.Lock()
+	for _, tenant := range nbi.tenantsIndexByName {
+		add(tenant)
+	}
+	nbi.tenantsLock.Unlock()
+
+	nbi.siteGroupsLock.Lock()
+	for _, siteGroup := range nbi.siteGroupsIndexByName {
+		add(siteGroup)
+	}
+	nbi.siteGroupsLock.Unlock()
+
+	nbi.regionsLock.Lock()
+	for _, parentRegions := range nbi.regionsIndexByParentIDAndName {
+		for _, region := range parentRegions {
+			add(region)
+		}
+	}
+	nbi.regionsLock.Unlock()
+
+	nbi.sitesLock.Lock()
+	for _, site := range nbi.sitesIndexByName {
+		add(site)
+	}
+	nbi.sitesLock.Unlock()
+
+	nbi.locationsLock.Lock()
+	for _, siteLocations := range nbi.locationsIndexBySiteIDAndName {
+		for _, location := range siteLocations {
+			add(location)
+		}
+	}
+	nbi.locationsLock.Unlock()
+
+	nbi.manufacturersLock.Lock()
+	for _, manufacturer := range nbi.manufacturersIndexByName {
+		add(manufacturer)
+	}
+	nbi.manufacturersLock.Unlock()
+
+	nbi.platformsLock.Lock()
+	for _, platform := range nbi.platformsIndexByName {
+		add(platform)
+	}
+	nbi.platformsLock.Unlock()
+
+	nbi.deviceRolesLock.Lock()
+	for _, deviceRole := range nbi.deviceRolesIndexByName {
+		add(deviceRole)
+	}
+	nbi.deviceRolesLock.Unlock()
+
+	nbi.deviceTypesLock.Lock()
+	for _, deviceType := range nbi.deviceTypesIndexByModel {
+		add(deviceType)
+	}
+	nbi.deviceTypesLock.Unlock()
+
+	nbi.interfaceTemplatesLock.Lock()
+	for _, deviceTypeTemplates := range nbi.interfaceTemplatesIndexByDeviceTypeIDAndName {
+		for _, template := range deviceTypeTemplates {
+			add(template)
+		}
+	}
+	nbi.interfaceTemplatesLock.Unlock()
+
+	nbi.consolePortTemplatesLock.Lock()
+	for _, deviceTypeTemplates := range nbi.consolePortTemplatesIndexByDeviceTypeIDAndName {
+		for _, template := range deviceTypeTemplates {
+			add(template)
+		}
+	}
+	nbi.consolePortTemplatesLock.Unlock()
+
+	nbi.powerPortTemplatesLock.Lock()
+	for _, deviceTypeTemplates := range nbi.powerPortTemplatesIndexByDeviceTypeIDAndName {
+		for _, template := range deviceTypeTemplates {
+			add(template)
+		}
+	}
+	nbi.powerPortTemplatesLock.Unlock()
+
+	nbi.devicesLock.Lock()
+	for _, siteIndex := range nbi.devicesIndexByNameAndSiteID {
+		for _, device := range siteIndex {
+			add(device)
+		}
+	}
+	nbi.devicesLock.Unlock()
+
+	nbi.virtualChassisLock.Lock()
+	for _, virtualChassis := range nbi.virtualChassisIndexByName {
+		add(virtualChassis)
+	}
+	nbi.virtualChassisLock.Unlock()
+
+	nbi.virtualDeviceContextsLock.Lock()
+	for _, deviceIndex := range nbi.virtualDeviceContextsIndex {
+		for _, vdc := range deviceIndex {
+			add(vdc)
+		}
+	}
+	nbi.virtualDeviceContextsLock.Unlock()
+
+	nbi.interfacesLock.Lock()
+	for _, nameIndex := range nbi.interfacesIndexByDeviceIDAndName {
+		for _, iface := range nameIndex {
+			add(iface)
+		}
+	}
+	nbi.interfacesLock.Unlock()
+
+	nbi.cablesLock.Lock()
+	// Cables are indexed by each of their interfaces
+	exportedCables := map[int]bool{}
+	for _, cable := range nbi.cablesIndexByInterfaceID {
+		if !exportedCables[cable.ID] {
+			exportedCables[cable.ID] = true
+			add(cable)
+		}
+	}
+	nbi.cablesLock.Unlock()
+
+	nbi.inventoryItemsLock.Lock()
+	for _, nameIndex := range nbi.inventoryItemsIndexByDeviceIDAndName {
+		for _, inventoryItem := range nameIndex {
+			add(inventoryItem)
+		}
+	}
+	nbi.inventoryItemsLock.Unlock()
+
+	nbi.clusterGroupsLock.Lock()
+	for _, clusterGroup := range nbi.clusterGroupsIndexByName {
+		add(clusterGroup)
+	}
+	nbi.clusterGroupsLock.Unlock()
+
+	nbi.clusterTypesLock.Lock()
+	for _, clusterType := range nbi.clusterTypesIndexByName {
+		add(clusterType)
+	}
+	nbi.clusterTypesLock.Unlock()
+
+	nbi.clustersLock.Lock()
+	for _, cluster := range nbi.clustersIndexByName {
+		add(cluster)
+	}
+	nbi.clustersLock.Unlock()
+
+	nbi.vmsLock.Lock()
+	for _, clusterIndex := range nbi.vmsIndexByNameAndClusterID {
+		for _, vm := range clusterIndex {
+			add(vm)
+		}
+	}
+	nbi.vmsLock.Unlock()
+
+	nbi.vmInterfacesLock.Lock()
+	for _, nameIndex := range nbi.vmInterfacesIndexByVMIdAndName {
+		for _, vmIface := range nameIndex {
+			add(vmIface)
+		}
+	}
+	nbi.vmInterfacesLock.Unlock()
+
+	nbi.virtualDisksLock.Lock()
+	for _, nameIndex := range nbi.virtualDisksIndexByVMIDAndName {
+		for _, virtualDisk := range nameIndex {
+			add(virtualDisk)
+		}
+	}
+	nbi.virtualDisksLock.Unlock()
+
+	nbi.vrfsLock.Lock()
+	for _, vrf := range nbi.vrfsIndexByName {
+		add(vrf)
+	}
+	nbi.vrfsLock.Unlock()
+
+	nbi.prefixesLock.Lock()
+	for _, vrfIndex := range nbi.prefixesIndexByPrefix {
+		for _, prefix := range vrfIndex {
+			add(prefix)
+		}
+	}
+	nbi.prefixesLock.Unlock()
+
+	nbi.ipRangesLock.Lock()
+	for _, vrfIndex := range nbi.ipRangesIndexByStartAddress {
+		for _, ipRange := range vrfIndex {
+			add(ipRange)
+		}
+	}
+	nbi.ipRangesLock.Unlock()
+
+	nbi.vlanGroupsLock.Lock()
+	for _, vlanGroup := range nbi.vlanGroupsIndexByName {
+		add(vlanGroup)
+	}
+	nbi.vlanGroupsLock.Unlock()
+
+	nbi.vlansLock.Lock()
+	for _, vidIndex := range nbi.vlansIndexByVlanGroupIDAndVID {
+		for _, vlan := range vidIndex {
+			add(vlan)
+		}
+	}
+	nbi.vlansLock.Unlock()
+
+	nbi.ipAddressesLock.Lock()
+	for _, ifaceIndex := range nbi.ipAddressesIndex {
+		for _, parentIndex := range ifaceIndex {
+			for _, addressIndex := range parentIndex {
+				for _, ipAddress := range addressIndex {
+					add(ipAddress)
+				}
+			}
+		}
+	}
+	nbi.ipAddressesLock.Unlock()
+
+	nbi.macAddressesLock.Lock()
+	for _, ifaceIndex := range nbi.macAddressesIndex {
+		for _, parentIndex := range ifaceIndex {
+			for _, addressIndex := range parentIndex {
+				for _, macAddress := range addressIndex {
+					add(macAddress)
+				}
+			}
+		}
+	}
+	nbi.macAddressesLock.Unlock()
+
+	nbi.wirelessLANGroupsLock.Lock()
+	for _, wirelessLANGroup := range nbi.wirelessLANGroupsIndexByName {
+		add(wirelessLANGroup)
+	}
+	nbi.wirelessLANGroupsLock.Unlock()
+
+	nbi.wirelessLANsLock.Lock()
+	for _, wirelessLAN := range nbi.wirelessLANsIndexBySSID {
+		add(wirelessLAN)
+	}
+	nbi.wirelessLANsLock.Unlock()
+
+	for _, items := range exported {
+		sort.Slice(items, func(i, j int) bool {
+			return items[i].GetID() < items[j].GetID()
+		})
+	}
+	return exported
+}
+
+// ListOrphans returns all objects that are currently tracked by the
+// OrphanManager, grouped by their api path and sorted by their id.
+// After all sources are synced, these are the objects that will be
+// removed by DeleteOrphans.
+func (nbi *NetboxInventory) ListOrphans() map[constants.APIPath][]objects.OrphanItem {
+	orphans := map[constants.APIPath][]objects.OrphanItem{}
+	for apiPath, id2orphanItem := range nbi.OrphanManager.Items {
+		if len(id2orphanItem) == 0 {
+			continue
+		}
+		items := make([]objects.OrphanItem, 0, len(id2orphanItem))
+		for _, orphanItem := range id2orphanItem {
+			items = append(items, orphanItem)
+		}
+		sort.Slice(items, func(i, j int) bool {
+			return items[i].GetID() < items[j].GetID()
+		})
+		orphans[apiPath] = items
+	}
+	return orphans
+}
@@ -0,0 +1,83 @@
+package inventory
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/src-doo/netbox-ssot/internal/constants"
+	"github.com/src-doo/netbox-ssot/internal/netbox/objects"
+)
+
+func TestNetboxInventory_Export(t *testing.T) {
+	nbi := &NetboxInventory{
+		tenantsIndexByName: map[string]*objects.Tenant{
+			"tenant2": {NetboxObject: objects.NetboxObject{ID: 2}, Name: "tenant2"},
+			"tenant1": {NetboxObject: objects.NetboxObject{ID: 1}, Name: "tenant1"},
+		},
+		sitesIndexByName: map[string]*objects.Site{
+			"site3": {NetboxObject: objects.NetboxObject{ID: 3}, Name: "site3"},
+			"site1": {NetboxObject: objects.NetboxObject{ID: 1}, Name: "site1"},
+		},
+	}
+	exported := nbi.Export()
+	tests := []struct {
+		name    string
+		apiPath constants.APIPath
+		wantIDs []int
+	}{
+		{
+			name:    "Tenants are sorted by id",
+			apiPath: constants.TenantsAPIPath,
+			wantIDs: []int{1, 2},
+		},
+		{
+			name:    "Sites are sorted by id",
+			apiPath: constants.SitesAPIPath,
+			wantIDs: []int{1, 3},
+		},
+		{
+			name:    "Empty index is not exported",
+			apiPath: constants.DevicesAPIPath,
+			wantIDs: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotIDs []int
+			for _, item := range exported[tt.apiPath] {
+				gotIDs = append(gotIDs, item.GetID())
+			}
+			if !reflect.DeepEqual(gotIDs, tt.wantIDs) {
+				t.Errorf("Export()[%s] ids = %v, want %v", tt.apiPath, gotIDs, tt.wantIDs)
+			}
+		})
+	}
+}
+
+func TestNetboxInventory_ListOrphans(t *testing.T) {
+	ssotTag := &objects.Tag{Name: constants.SsotTagName}
+	orphanManager := NewOrphanManager(MockInventory.Logger)
+	orphanManager.AddItem(&objects.Device{
+		NetboxObject: objects.NetboxObject{ID: 5, Tags: []*objects.Tag{ssotTag}},
+	})
+	orphanManager.AddItem(&objects.Device{
+		NetboxObject: objects.NetboxObject{ID: 3, Tags: []*objects.Tag{ssotTag}},
+	})
+	// Objects without ssot tag are not managed by orphan manager
+	orphanManager.AddItem(&objects.Device{
+		NetboxObject: objects.NetboxObject{ID: 4},
+	})
+	nbi := &NetboxInventory{OrphanManager: orphanManager}
+
+	orphans := nbi.ListOrphans()
+	if len(orphans) != 1 {
+		t.Fatalf("ListOrphans() = %v, want only devices", orphans)
+	}
+	var gotIDs []int
+	for _, orphan := range orphans[constants.DevicesAPIPath] {
+		gotIDs = append(gotIDs, orphan.GetID())
+	}
+	if want := []int{3, 5}; !reflect.DeepEqual(gotIDs, want) {
+		t.Errorf("ListOrphans() device ids = %v, want %v", gotIDs, want)
+	}
+}
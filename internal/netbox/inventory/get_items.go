@@ -154,6 +154,21 @@ func (nbi *NetboxInventory) GetDevice(deviceName string, siteID int) (*objects.D
 	return device, true
 }
 
+// GetDeviceByName returns the device with the given name from any site.
+// It returns false if there is no such device, or if devices with
+// this name exist in more than one site.
+func (nbi *NetboxInventory) GetDeviceByName(deviceName string) (*objects.Device, bool) {
+	nbi.devicesLock.Lock()
+	defer nbi.devicesLock.Unlock()
+	if len(nbi.devicesIndexByNameAndSiteID[deviceName]) != 1 {
+		return nil, false
+	}
+	for _, device := range nbi.devicesIndexByNameAndSiteID[deviceName] {
+		return device, true
+	}
+	return nil, false
+}
+
 func (nbi *NetboxInventory) GetDeviceRole(deviceRoleName string) (*objects.DeviceRole, bool) {
 	nbi.deviceRolesLock.Lock()
 	defer nbi.deviceRolesLock.Unlock()
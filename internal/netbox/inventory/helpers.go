@@ -6,9 +6,16 @@ import (
 
 	"github.com/src-doo/netbox-ssot/internal/constants"
 	"github.com/src-doo/netbox-ssot/internal/netbox/objects"
+	"github.com/src-doo/netbox-ssot/internal/tracing"
 	"github.com/src-doo/netbox-ssot/internal/utils"
+	"go.opentelemetry.io/otel/trace"
 )
 
+// startSpan starts a tracing span for the inventory operation on objects of objectType.
+func startSpan(ctx context.Context, operation string, objectType string) (context.Context, trace.Span) {
+	return tracing.Start(ctx, "inventory."+operation, tracing.ObjectTypeKey.String(objectType))
+}
+
 // Inits default VlanGroup, which is required to group all Vlans that are not part of other
 // vlangroups into it. Each vlan is indexed by their (vlanGroup, vid).
 func (nbi *NetboxInventory) CreateDefaultVlanGroupForVlan(
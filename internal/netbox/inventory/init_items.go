@@ -77,6 +77,26 @@ func (nbi *NetboxInventory) initTags(ctx context.Context) error {
 	return nil
 }
 
+// Collects all tenant groups from Netbox API and store them in the NetBoxInventory.
+func (nbi *NetboxInventory) initTenantGroups(ctx context.Context) error {
+	extraArgs := fmt.Sprintf(
+		"&fields=%s",
+		utils.ExtractJSONTagsFromStructIntoString(objects.TenantGroup{}),
+	)
+	nbTenantGroups, err := service.GetAll[objects.TenantGroup](ctx, nbi.NetboxAPI, extraArgs)
+	if err != nil {
+		return err
+	}
+	// We also create an index of tenant groups by name for easier access
+	nbi.tenantGroupsIndexByName = make(map[string]*objects.TenantGroup)
+	for i := range nbTenantGroups {
+		tenantGroup := &nbTenantGroups[i]
+		nbi.tenantGroupsIndexByName[tenantGroup.Name] = tenantGroup
+	}
+	nbi.Logger.Debug(ctx, "Successfully collected tenant groups from Netbox: ", nbi.tenantGroupsIndexByName)
+	return nil
+}
+
 // Collects all tenants from Netbox API and store them in the NetBoxInventory.
 func (nbi *NetboxInventory) initTenants(ctx context.Context) error {
 	extraArgs := fmt.Sprintf(
@@ -267,6 +287,60 @@ func (nbi *NetboxInventory) initSiteGroups(ctx context.Context) error {
 	return nil
 }
 
+// Collects all regions from Netbox API and store them in the NetBoxInventory.
+func (nbi *NetboxInventory) initRegions(ctx context.Context) error {
+	extraArgs := fmt.Sprintf(
+		"&fields=%s",
+		utils.ExtractJSONTagsFromStructIntoString(objects.Region{}),
+	)
+	nbRegions, err := service.GetAll[objects.Region](ctx, nbi.NetboxAPI, extraArgs)
+	if err != nil {
+		return err
+	}
+	// Regions are unique by parent and name
+	nbi.regionsIndexByParentIDAndName = make(map[int]map[string]*objects.Region)
+	for i := range nbRegions {
+		region := &nbRegions[i]
+		parentID := regionParentID(region)
+		if nbi.regionsIndexByParentIDAndName[parentID] == nil {
+			nbi.regionsIndexByParentIDAndName[parentID] = make(map[string]*objects.Region)
+		}
+		nbi.regionsIndexByParentIDAndName[parentID][region.Name] = region
+	}
+	nbi.Logger.Debug(ctx, "Successfully collected regions from Netbox: ", nbi.regionsIndexByParentIDAndName)
+	return nil
+}
+
+// Collects all locations from Netbox API and store them in the NetBoxInventory.
+func (nbi *NetboxInventory) initLocations(ctx context.Context) error {
+	extraArgs := fmt.Sprintf(
+		"&fields=%s",
+		utils.ExtractJSONTagsFromStructIntoString(objects.Location{}),
+	)
+	nbLocations, err := service.GetAll[objects.Location](ctx, nbi.NetboxAPI, extraArgs)
+	if err != nil {
+		return err
+	}
+	// Locations are unique by site and name
+	nbi.locationsIndexBySiteIDAndName = make(map[int]map[string]*objects.Location)
+	for i := range nbLocations {
+		location := &nbLocations[i]
+		if location.Site == nil {
+			continue
+		}
+		if nbi.locationsIndexBySiteIDAndName[location.Site.ID] == nil {
+			nbi.locationsIndexBySiteIDAndName[location.Site.ID] = make(map[string]*objects.Location)
+		}
+		nbi.locationsIndexBySiteIDAndName[location.Site.ID][location.Name] = location
+	}
+	nbi.Logger.Debug(
+		ctx,
+		"Successfully collected locations from Netbox: ",
+		nbi.locationsIndexBySiteIDAndName,
+	)
+	return nil
+}
+
 // initDefaultSite inits default site, which is used for hosts that have no corresponding site.
 // This is because site is required for adding new hosts.
 func (nbi *NetboxInventory) initDefaultSite(ctx context.Context) error {
@@ -373,6 +447,29 @@ func (nbi *NetboxInventory) initDevices(ctx context.Context) error {
 	return nil
 }
 
+// initVirtualChassis collects all virtual chassis from Netbox API
+// and stores them in the NetBoxInventory.
+func (nbi *NetboxInventory) initVirtualChassis(ctx context.Context) error {
+	extraArgs := fmt.Sprintf(
+		"&fields=%s",
+		utils.ExtractJSONTagsFromStructIntoString(objects.VirtualChassis{}),
+	)
+	nbVirtualChassis, err := service.GetAll[objects.VirtualChassis](ctx, nbi.NetboxAPI, extraArgs)
+	if err != nil {
+		return err
+	}
+
+	nbi.virtualChassisIndexByName = make(map[string]*objects.VirtualChassis)
+	for i := range nbVirtualChassis {
+		virtualChassis := &nbVirtualChassis[i]
+		nbi.virtualChassisIndexByName[virtualChassis.Name] = virtualChassis
+		nbi.OrphanManager.AddItem(virtualChassis)
+	}
+
+	nbi.Logger.Debug(ctx, "Successfully collected virtual chassis from Netbox: ", nbi.virtualChassisIndexByName)
+	return nil
+}
+
 // Collect all devices from Netbox API and store them in the NetBoxInventory.
 func (nbi *NetboxInventory) initVirtualDeviceContexts(ctx context.Context) error {
 	extraArgs := fmt.Sprintf(
@@ -479,17 +576,21 @@ func (nbi *NetboxInventory) initSsotCustomFields(ctx context.Context) error {
 		Description:           constants.CustomFieldSourceDescription,
 		SearchWeight:          objects.SearchWeightDefault,
 		ObjectTypes: []constants.ContentType{
+			constants.ContentTypeDcimCable,
 			constants.ContentTypeDcimDevice,
 			constants.ContentTypeDcimDeviceRole,
 			constants.ContentTypeDcimDeviceType,
 			constants.ContentTypeDcimInterface,
+			constants.ContentTypeDcimInventoryItem,
 			constants.ContentTypeDcimLocation,
 			constants.ContentTypeDcimManufacturer,
 			constants.ContentTypeDcimPlatform,
 			constants.ContentTypeDcimRegion,
 			constants.ContentTypeDcimSite,
+			constants.ContentTypeDcimVirtualChassis,
 			constants.ContentTypeDcimVirtualDeviceContext,
 			constants.ContentTypeIpamIPAddress,
+			constants.ContentTypeIpamIPRange,
 			constants.ContentTypeIpamVlanGroup,
 			constants.ContentTypeIpamVlan,
 			constants.ContentTypeIpamPrefix,
@@ -527,17 +628,21 @@ func (nbi *NetboxInventory) initSsotCustomFields(ctx context.Context) error {
 		Description:           constants.CustomFieldOrphanLastSeenDescription,
 		SearchWeight:          objects.SearchWeightDefault,
 		ObjectTypes: []constants.ContentType{
+			constants.ContentTypeDcimCable,
 			constants.ContentTypeDcimDevice,
 			constants.ContentTypeDcimDeviceRole,
 			constants.ContentTypeDcimDeviceType,
 			constants.ContentTypeDcimInterface,
+			constants.ContentTypeDcimInventoryItem,
 			constants.ContentTypeDcimLocation,
 			constants.ContentTypeDcimManufacturer,
 			constants.ContentTypeDcimPlatform,
 			constants.ContentTypeDcimRegion,
 			constants.ContentTypeDcimSite,
+			constants.ContentTypeDcimVirtualChassis,
 			constants.ContentTypeDcimVirtualDeviceContext,
 			constants.ContentTypeIpamIPAddress,
+			constants.ContentTypeIpamIPRange,
 			constants.ContentTypeIpamVlanGroup,
 			constants.ContentTypeIpamVlan,
 			constants.ContentTypeIpamPrefix,
@@ -781,6 +886,108 @@ func (nbi *NetboxInventory) initDeviceTypes(ctx context.Context) error {
 	return nil
 }
 
+// Collects all interface templates from Netbox API and stores them to local inventory.
+func (nbi *NetboxInventory) initInterfaceTemplates(ctx context.Context) error {
+	extraArgs := fmt.Sprintf(
+		"&fields=%s",
+		utils.ExtractJSONTagsFromStructIntoString(objects.InterfaceTemplate{}),
+	)
+	nbTemplates, err := service.GetAll[objects.InterfaceTemplate](ctx, nbi.NetboxAPI, extraArgs)
+	if err != nil {
+		return err
+	}
+
+	// Templates are unique by device type and name
+	nbi.interfaceTemplatesIndexByDeviceTypeIDAndName = make(map[int]map[string]*objects.InterfaceTemplate)
+	for i := range nbTemplates {
+		template := &nbTemplates[i]
+		if template.DeviceType == nil {
+			continue
+		}
+		if nbi.interfaceTemplatesIndexByDeviceTypeIDAndName[template.DeviceType.ID] == nil {
+			nbi.interfaceTemplatesIndexByDeviceTypeIDAndName[template.DeviceType.ID] = make(
+				map[string]*objects.InterfaceTemplate,
+			)
+		}
+		nbi.interfaceTemplatesIndexByDeviceTypeIDAndName[template.DeviceType.ID][template.Name] = template
+	}
+
+	nbi.Logger.Debug(
+		ctx,
+		"Successfully collected interface templates from Netbox: ",
+		nbi.interfaceTemplatesIndexByDeviceTypeIDAndName,
+	)
+	return nil
+}
+
+// Collects all console port templates from Netbox API and stores them to local inventory.
+func (nbi *NetboxInventory) initConsolePortTemplates(ctx context.Context) error {
+	extraArgs := fmt.Sprintf(
+		"&fields=%s",
+		utils.ExtractJSONTagsFromStructIntoString(objects.ConsolePortTemplate{}),
+	)
+	nbTemplates, err := service.GetAll[objects.ConsolePortTemplate](ctx, nbi.NetboxAPI, extraArgs)
+	if err != nil {
+		return err
+	}
+
+	// Templates are unique by device type and name
+	nbi.consolePortTemplatesIndexByDeviceTypeIDAndName = make(map[int]map[string]*objects.ConsolePortTemplate)
+	for i := range nbTemplates {
+		template := &nbTemplates[i]
+		if template.DeviceType == nil {
+			continue
+		}
+		if nbi.consolePortTemplatesIndexByDeviceTypeIDAndName[template.DeviceType.ID] == nil {
+			nbi.consolePortTemplatesIndexByDeviceTypeIDAndName[template.DeviceType.ID] = make(
+				map[string]*objects.ConsolePortTemplate,
+			)
+		}
+		nbi.consolePortTemplatesIndexByDeviceTypeIDAndName[template.DeviceType.ID][template.Name] = template
+	}
+
+	nbi.Logger.Debug(
+		ctx,
+		"Successfully collected console port templates from Netbox: ",
+		nbi.consolePortTemplatesIndexByDeviceTypeIDAndName,
+	)
+	return nil
+}
+
+// Collects all power port templates from Netbox API and stores them to local inventory.
+func (nbi *NetboxInventory) initPowerPortTemplates(ctx context.Context) error {
+	extraArgs := fmt.Sprintf(
+		"&fields=%s",
+		utils.ExtractJSONTagsFromStructIntoString(objects.PowerPortTemplate{}),
+	)
+	nbTemplates, err := service.GetAll[objects.PowerPortTemplate](ctx, nbi.NetboxAPI, extraArgs)
+	if err != nil {
+		return err
+	}
+
+	// Templates are unique by device type and name
+	nbi.powerPortTemplatesIndexByDeviceTypeIDAndName = make(map[int]map[string]*objects.PowerPortTemplate)
+	for i := range nbTemplates {
+		template := &nbTemplates[i]
+		if template.DeviceType == nil {
+			continue
+		}
+		if nbi.powerPortTemplatesIndexByDeviceTypeIDAndName[template.DeviceType.ID] == nil {
+			nbi.powerPortTemplatesIndexByDeviceTypeIDAndName[template.DeviceType.ID] = make(
+				map[string]*objects.PowerPortTemplate,
+			)
+		}
+		nbi.powerPortTemplatesIndexByDeviceTypeIDAndName[template.DeviceType.ID][template.Name] = template
+	}
+
+	nbi.Logger.Debug(
+		ctx,
+		"Successfully collected power port templates from Netbox: ",
+		nbi.powerPortTemplatesIndexByDeviceTypeIDAndName,
+	)
+	return nil
+}
+
 // Collects all interfaces from Netbox API and stores them to local inventory.
 func (nbi *NetboxInventory) initInterfaces(ctx context.Context) error {
 	extraArgs := fmt.Sprintf(
@@ -817,6 +1024,68 @@ func (nbi *NetboxInventory) initInterfaces(ctx context.Context) error {
 	return nil
 }
 
+// initCables collects all cables from Netbox API and indexes
+// the ones connecting interfaces by their terminated interfaces.
+func (nbi *NetboxInventory) initCables(ctx context.Context) error {
+	extraArgs := fmt.Sprintf(
+		"&fields=%s",
+		utils.ExtractJSONTagsFromStructIntoString(objects.Cable{}),
+	)
+	nbCables, err := service.GetAll[objects.Cable](ctx, nbi.NetboxAPI, extraArgs)
+	if err != nil {
+		return err
+	}
+
+	nbi.cablesIndexByInterfaceID = make(map[int]*objects.Cable)
+	for i := range nbCables {
+		cable := &nbCables[i]
+		aInterfaceIDs, bInterfaceIDs := cable.InterfaceIDs()
+		for _, interfaceID := range append(aInterfaceIDs, bInterfaceIDs...) {
+			nbi.cablesIndexByInterfaceID[interfaceID] = cable
+		}
+		nbi.OrphanManager.AddItem(cable)
+	}
+
+	nbi.Logger.Debug(
+		ctx,
+		"Successfully collected cables from Netbox: ",
+		nbi.cablesIndexByInterfaceID,
+	)
+	return nil
+}
+
+// initInventoryItems collects all inventory items from Netbox API
+// and stores them to local inventory.
+func (nbi *NetboxInventory) initInventoryItems(ctx context.Context) error {
+	extraArgs := fmt.Sprintf(
+		"&fields=%s",
+		utils.ExtractJSONTagsFromStructIntoString(objects.InventoryItem{}),
+	)
+	nbInventoryItems, err := service.GetAll[objects.InventoryItem](ctx, nbi.NetboxAPI, extraArgs)
+	if err != nil {
+		return err
+	}
+
+	nbi.inventoryItemsIndexByDeviceIDAndName = make(map[int]map[string]*objects.InventoryItem)
+	for i := range nbInventoryItems {
+		inventoryItem := &nbInventoryItems[i]
+		if nbi.inventoryItemsIndexByDeviceIDAndName[inventoryItem.Device.ID] == nil {
+			nbi.inventoryItemsIndexByDeviceIDAndName[inventoryItem.Device.ID] = make(
+				map[string]*objects.InventoryItem,
+			)
+		}
+		nbi.inventoryItemsIndexByDeviceIDAndName[inventoryItem.Device.ID][inventoryItem.Name] = inventoryItem
+		nbi.OrphanManager.AddItem(inventoryItem)
+	}
+
+	nbi.Logger.Debug(
+		ctx,
+		"Successfully collected inventory items from Netbox: ",
+		nbi.inventoryItemsIndexByDeviceIDAndName,
+	)
+	return nil
+}
+
 // Collects all vlans from Netbox API and stores them to local inventory.
 func (nbi *NetboxInventory) initVlanGroups(ctx context.Context) error {
 	extraArgs := fmt.Sprintf(
@@ -990,6 +1259,13 @@ func (nbi *NetboxInventory) initIPAddresses(ctx context.Context) error {
 }
 
 func (nbi *NetboxInventory) initMACAddresses(ctx context.Context) error {
+	nbi.macAddressesIndex = make(
+		map[constants.ContentType]map[string]map[string]map[string]*objects.MACAddress,
+	)
+	if !nbi.Supports(service.FeatureMACAddressObjects) {
+		// MAC addresses are stored on interfaces
+		return nil
+	}
 	extraArgs := fmt.Sprintf(
 		"&fields=%s",
 		utils.ExtractJSONTagsFromStructIntoString(objects.MACAddress{}),
@@ -998,10 +1274,6 @@ func (nbi *NetboxInventory) initMACAddresses(ctx context.Context) error {
 	if err != nil {
 		return err
 	}
-	// Initializes internal indexx
-	nbi.macAddressesIndex = make(
-		map[constants.ContentType]map[string]map[string]map[string]*objects.MACAddress,
-	)
 	for i := range nbMACAddresses {
 		macAddress := &nbMACAddresses[i]
 		ifaceType, ifaceName, ifaceParentName, err := nbi.getIndexValuesForMACAddress(
@@ -1061,6 +1333,40 @@ func (nbi *NetboxInventory) initPrefixes(ctx context.Context) error {
 	return nil
 }
 
+// Collects all IP ranges from Netbox API and stores them to local inventory.
+func (nbi *NetboxInventory) initIPRanges(ctx context.Context) error {
+	extraArgs := fmt.Sprintf(
+		"&fields=%s",
+		utils.ExtractJSONTagsFromStructIntoString(objects.IPRange{}),
+	)
+	ipRanges, err := service.GetAll[objects.IPRange](ctx, nbi.NetboxAPI, extraArgs)
+	if err != nil {
+		return err
+	}
+
+	nbi.ipRangesIndexByStartAddress = make(map[string]map[int]*objects.IPRange)
+
+	for i := range ipRanges {
+		ipRange := &ipRanges[i]
+		vrfID := 0
+		if ipRange.VRF != nil {
+			vrfID = ipRange.VRF.ID
+		}
+		if nbi.ipRangesIndexByStartAddress[ipRange.StartAddress] == nil {
+			nbi.ipRangesIndexByStartAddress[ipRange.StartAddress] = make(map[int]*objects.IPRange)
+		}
+		nbi.ipRangesIndexByStartAddress[ipRange.StartAddress][vrfID] = ipRange
+		nbi.OrphanManager.AddItem(ipRange)
+	}
+
+	nbi.Logger.Debug(
+		ctx,
+		"Successfully collected IP ranges from Netbox: ",
+		nbi.ipRangesIndexByStartAddress,
+	)
+	return nil
+}
+
 // Collects all WirelessLANs from Netbox API and stores them to local inventory.
 func (nbi *NetboxInventory) initWirelessLANs(ctx context.Context) error {
 	extraArgs := fmt.Sprintf(
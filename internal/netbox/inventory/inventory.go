@@ -3,11 +3,11 @@ package inventory
 import (
 	"context"
 	"fmt"
-	"strconv"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/src-doo/netbox-ssot/internal/audit"
 	"github.com/src-doo/netbox-ssot/internal/constants"
 	"github.com/src-doo/netbox-ssot/internal/logger"
 	"github.com/src-doo/netbox-ssot/internal/netbox/objects"
@@ -35,10 +35,25 @@ type NetboxInventory struct {
 	SsotTag *objects.Tag
 	// Tag used by netbox-ssot to preserve manually set device type
 	IgnoreDeviceTypeTag *objects.Tag
+	// DryRun if set, no changes are written to Netbox. Instead they are
+	// recorded by the NetboxAPI, see service.NetboxClient.PlannedChanges.
+	DryRun bool
+	// AuditLog, if set, records every write to netbox, see service.NetboxClient.AuditLog.
+	AuditLog *audit.Log
+	// SourceTypes are types of the configured sources. They determine which
+	// objects are written to netbox, see RequiredPermissions.
+	SourceTypes []constants.SourceType
 	// Default context for the inventory, we use it to pass sourcename
 	// to functions for logging.
 	Ctx context.Context //nolint:containedctx
 
+	// Each index below is guarded by its lock, which is held only while
+	// the index is read or updated, but not during requests to netbox.
+	// inFlight guards objects, which are being added, by their index key,
+	// so the same object isn't created twice by concurrent sources,
+	// while different objects of the same type are written concurrently.
+	inFlight keyLocks
+
 	// tagsIndexByName is a map of all tags in the Netbox's inventory,
 	// indexed by their name
 	tagsIndexByName map[string]*objects.Tag
@@ -75,6 +90,16 @@ type NetboxInventory struct {
 	siteGroupsIndexByName map[string]*objects.SiteGroup
 	siteGroupsLock        sync.Mutex
 
+	// regionsIndexByParentIDAndName is a map of all regions in the Netbox's inventory,
+	// indexed by the id of their parent region (0 for top level regions) and name
+	regionsIndexByParentIDAndName map[int]map[string]*objects.Region
+	regionsLock                   sync.Mutex
+
+	// locationsIndexBySiteIDAndName is a map of all locations in the Netbox's inventory,
+	// indexed by their site id and name
+	locationsIndexBySiteIDAndName map[int]map[string]*objects.Location
+	locationsLock                 sync.Mutex
+
 	// manufacturersIndexByName is a map of all manufacturers in the Netbox's inventory,
 	// indexed by their name
 	manufacturersIndexByName map[string]*objects.Manufacturer
@@ -89,11 +114,31 @@ type NetboxInventory struct {
 	tenantsIndexByName map[string]*objects.Tenant
 	tenantsLock        sync.Mutex
 
+	// tenantGroupsIndexByName is a map of all tenant groups in the Netbox's inventory,
+	// indexed by their name
+	tenantGroupsIndexByName map[string]*objects.TenantGroup
+	tenantGroupsLock        sync.Mutex
+
 	// deviceTypesIndexByModel is a map of all device types in the Netbox's inventory,
 	// indexed by their model
 	deviceTypesIndexByModel map[string]*objects.DeviceType
 	deviceTypesLock         sync.Mutex
 
+	// interfaceTemplatesIndexByDeviceTypeIDAndName is a map of all interface templates
+	// in the Netbox's inventory, indexed by their device type id and name
+	interfaceTemplatesIndexByDeviceTypeIDAndName map[int]map[string]*objects.InterfaceTemplate
+	interfaceTemplatesLock                       sync.Mutex
+
+	// consolePortTemplatesIndexByDeviceTypeIDAndName is a map of all console port templates
+	// in the Netbox's inventory, indexed by their device type id and name
+	consolePortTemplatesIndexByDeviceTypeIDAndName map[int]map[string]*objects.ConsolePortTemplate
+	consolePortTemplatesLock                       sync.Mutex
+
+	// powerPortTemplatesIndexByDeviceTypeIDAndName is a map of all power port templates
+	// in the Netbox's inventory, indexed by their device type id and name
+	powerPortTemplatesIndexByDeviceTypeIDAndName map[int]map[string]*objects.PowerPortTemplate
+	powerPortTemplatesLock                       sync.Mutex
+
 	// devicesIndexByNameAndSiteID is a map of all devices in the Netbox's inventory,
 	// indexed by their name and SiteID
 	devicesIndexByNameAndSiteID map[string]map[int]*objects.Device
@@ -102,6 +147,11 @@ type NetboxInventory struct {
 	devicesIndexByID map[int]*objects.Device
 	devicesLock      sync.Mutex
 
+	// virtualChassisIndexByName is a map of all virtual chassis
+	// in the Netbox's inventory indexed by their name.
+	virtualChassisIndexByName map[string]*objects.VirtualChassis
+	virtualChassisLock        sync.Mutex
+
 	// virtualDeviceContextsIndex is a map of all virtual device contexts
 	// in the Netbox's inventory indexed by their name and device ID.
 	virtualDeviceContextsIndex map[string]map[int]*objects.VirtualDeviceContext
@@ -112,6 +162,11 @@ type NetboxInventory struct {
 	prefixesIndexByPrefix map[string]map[int]*objects.Prefix
 	prefixesLock          sync.Mutex
 
+	// ipRangesIndexByStartAddress is a map of all IP ranges in the Netbox's inventory,
+	// indexed by their start address and VRF.
+	ipRangesIndexByStartAddress map[string]map[int]*objects.IPRange
+	ipRangesLock                sync.Mutex
+
 	// vrfsIndexByName is a map of all VRFs in the Netbox's inventory,
 	// indexed by their name.
 	vrfsIndexByName map[string]*objects.VRF
@@ -160,6 +215,16 @@ type NetboxInventory struct {
 	interfacesIndexByID map[int]*objects.Interface
 	interfacesLock      sync.Mutex
 
+	// cablesIndexByInterfaceID is a map of all cables connecting interfaces
+	// in the inventory, indexed by ids of their terminated interfaces.
+	cablesIndexByInterfaceID map[int]*objects.Cable
+	cablesLock               sync.Mutex
+
+	// inventoryItemsIndexByDeviceIDAndName is a map of all inventory items
+	// in the inventory, indexed by their device id and their name.
+	inventoryItemsIndexByDeviceIDAndName map[int]map[string]*objects.InventoryItem
+	inventoryItemsLock                   sync.Mutex
+
 	// vmsIndexByNameAndClusterID is a map of all virtual machines in the inventory,
 	// indexed by their name and their cluster id
 	vmsIndexByNameAndClusterID map[string]map[int]*objects.VM
@@ -232,6 +297,7 @@ func NewNetboxInventory(
 		sourcePriority[sourceName] = i
 	}
 	orphanManager := NewOrphanManager(logger)
+	orphanManager.AdoptionMode = nbConfig.AdoptionMode
 
 	nbi := &NetboxInventory{
 		Ctx:            ctx,
@@ -258,18 +324,46 @@ func (nbi *NetboxInventory) Init() error {
 		nbi.Logger,
 		baseURL,
 		nbi.NetboxConfig.APIToken,
-		nbi.NetboxConfig.ValidateCert,
 		nbi.NetboxConfig.Timeout,
-		nbi.NetboxConfig.CAFile,
+		nbi.NetboxConfig.HTTPClientConfig(),
 	)
 	if err != nil {
 		return fmt.Errorf("create new netbox client: %s", err)
 	}
+	nbi.NetboxAPI.DryRun = nbi.DryRun
+	nbi.NetboxAPI.AuditLog = nbi.AuditLog
 
-	err = nbi.checkVersion()
+	version, err := nbi.checkVersion()
 	if err != nil {
 		return err
 	}
+	if err := nbi.checkPermissions(); err != nil {
+		return err
+	}
+	var branchName string
+	if nbi.NetboxConfig.Branch != "" {
+		// Branch is selected before objects are loaded, so the inventory
+		// contains objects created in the branch by previous runs
+		branch, err := nbi.NetboxAPI.UseBranch(nbi.Ctx, BranchName(nbi.NetboxConfig.Branch, time.Now()))
+		if err != nil {
+			return fmt.Errorf("use branch: %s", err)
+		}
+		if branch != nil {
+			branchName = branch.Name
+		}
+	}
+	if nbi.NetboxConfig.CacheFile != "" {
+		nbi.NetboxAPI.Cache, err = service.LoadCache(
+			nbi.NetboxConfig.CacheFile,
+			baseURL,
+			version,
+			branchName,
+			time.Duration(nbi.NetboxConfig.CacheMaxAge)*time.Hour,
+		)
+		if err != nil {
+			return fmt.Errorf("load netbox cache: %s", err)
+		}
+	}
 
 	// WARNING: Order matters
 	initFunctions := []func(context.Context) error{
@@ -281,25 +375,35 @@ func (nbi *NetboxInventory) Init() error {
 		nbi.initAdminContactRole,
 		nbi.initContacts,
 		nbi.initContactAssignments,
+		nbi.initTenantGroups,
 		nbi.initTenants,
 		nbi.initSiteGroups,
+		nbi.initRegions,
 		nbi.initSites,
 		nbi.initDefaultSite,
+		nbi.initLocations,
 		nbi.initManufacturers,
 		nbi.initPlatforms,
 		nbi.initVMs,
 		nbi.initVirtualDisks,
 		nbi.initVMInterfaces,
 		nbi.initDevices,
+		nbi.initVirtualChassis,
 		nbi.initInterfaces,
+		nbi.initCables,
+		nbi.initInventoryItems,
 		nbi.initIPAddresses,
 		nbi.initMACAddresses,
 		nbi.initVlanGroups,
 		nbi.initPrefixes,
+		nbi.initIPRanges,
 		nbi.initVRFs,
 		nbi.initVlans,
 		nbi.initDeviceRoles,
 		nbi.initDeviceTypes,
+		nbi.initInterfaceTemplates,
+		nbi.initConsolePortTemplates,
+		nbi.initPowerPortTemplates,
 		nbi.initClusterGroups,
 		nbi.initClusterTypes,
 		nbi.initClusters,
@@ -321,30 +425,42 @@ func (nbi *NetboxInventory) Init() error {
 		)
 	}
 
+	if nbi.NetboxAPI.Cache != nil {
+		// Failing to save the cache only makes the next init slower
+		err = nbi.NetboxAPI.Cache.Save()
+		if err != nil {
+			nbi.Logger.Warningf(nbi.Ctx, "Saving netbox cache failed: %s", err)
+		}
+	}
+
 	return nil
 }
 
-func (nbi *NetboxInventory) checkVersion() error {
+// BranchName returns name of the branch configured by name for the run
+// started at startTime, with {timestamp} replaced by the start time.
+func BranchName(name string, startTime time.Time) string {
+	return strings.ReplaceAll(name, "{timestamp}", startTime.UTC().Format("20060102-150405"))
+}
+
+// checkVersion returns the netbox version, if it is supported,
+// and detects capabilities of the netbox api based on it.
+func (nbi *NetboxInventory) checkVersion() (string, error) {
 	version, err := service.GetVersion(nbi.Ctx, nbi.NetboxAPI)
 	if err != nil {
-		return fmt.Errorf("get version: %s", err)
-	}
-	supportedVersion := 4
-	supportedMinorVersion := 2
-	versionComponents := strings.Split(version, ".")
-	majorVersion, err := strconv.Atoi(versionComponents[0])
-	if err != nil {
-		return fmt.Errorf("parse major version: %s", err)
+		return "", fmt.Errorf("get version: %s", err)
 	}
-	minorVersion, err := strconv.Atoi(versionComponents[1])
+	capabilities, err := service.NewCapabilities(version)
 	if err != nil {
-		return fmt.Errorf("parse minor version: %s", err)
+		return "", err
 	}
-	if majorVersion != supportedVersion || minorVersion < supportedMinorVersion {
-		return fmt.Errorf(
-			"this version of netbox-ssot works only with netbox version >= 4.2.0, but received version: %s",
-			version,
-		)
+	for _, warning := range capabilities.Warnings() {
+		nbi.Logger.Warning(nbi.Ctx, warning)
 	}
-	return nil
+	nbi.NetboxAPI.Capabilities = capabilities
+	return version, nil
+}
+
+// Supports returns true, if the feature is supported by the netbox version.
+func (nbi *NetboxInventory) Supports(feature service.Feature) bool {
+	return nbi.NetboxAPI == nil || nbi.NetboxAPI.Supports(feature)
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/src-doo/netbox-ssot/internal/logger"
 	"github.com/src-doo/netbox-ssot/internal/parser"
@@ -78,9 +79,28 @@ func TestNetboxInventory_checkVersion(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if err := tt.nbi.checkVersion(); (err != nil) != tt.wantErr {
+			if _, err := tt.nbi.checkVersion(); (err != nil) != tt.wantErr {
 				t.Errorf("NetboxInventory.checkVersion() error = %v, wantErr %v", err, tt.wantErr)
 			}
 		})
 	}
 }
+
+func TestBranchName(t *testing.T) {
+	startTime := time.Date(2025, 3, 14, 15, 9, 26, 0, time.UTC)
+	tests := []struct {
+		name       string
+		branchName string
+		want       string
+	}{
+		{name: "Fixed name", branchName: "netbox-ssot", want: "netbox-ssot"},
+		{name: "Name with timestamp", branchName: "ssot-{timestamp}", want: "ssot-20250314-150926"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := BranchName(tt.branchName, startTime); got != tt.want {
+				t.Errorf("BranchName() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,109 @@
+package inventory
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/src-doo/netbox-ssot/internal/constants"
+	"github.com/src-doo/netbox-ssot/internal/netbox/objects"
+	"github.com/src-doo/netbox-ssot/internal/netbox/service"
+)
+
+// keyLocks guards objects, which are being added to the inventory, by their
+// api path and index key. Only one goroutine can add an object with the same
+// key at a time, so the same object is never created twice, while objects
+// with different keys can be written to netbox concurrently.
+// The zero value is ready to use.
+type keyLocks struct {
+	lock  sync.Mutex
+	locks map[string]*keyLock
+}
+
+type keyLock struct {
+	sync.Mutex
+	// waiters is the number of goroutines holding or waiting for the lock.
+	waiters int
+}
+
+// acquire blocks until no other goroutine holds the lock of the object
+// identified by path and keyParts. It returns function releasing the lock.
+func (k *keyLocks) acquire(path constants.APIPath, keyParts ...interface{}) func() {
+	key := objectKey(path, keyParts...)
+	k.lock.Lock()
+	if k.locks == nil {
+		k.locks = make(map[string]*keyLock)
+	}
+	lock, ok := k.locks[key]
+	if !ok {
+		lock = &keyLock{}
+		k.locks[key] = lock
+	}
+	lock.waiters++
+	k.lock.Unlock()
+
+	lock.Lock()
+	return func() {
+		lock.Unlock()
+		k.lock.Lock()
+		defer k.lock.Unlock()
+		lock.waiters--
+		if lock.waiters == 0 {
+			delete(k.locks, key)
+		}
+	}
+}
+
+func objectKey(path constants.APIPath, keyParts ...interface{}) string {
+	parts := make([]string, 0, len(keyParts)+1)
+	parts = append(parts, string(path))
+	for _, part := range keyParts {
+		parts = append(parts, fmt.Sprint(part))
+	}
+	return strings.Join(parts, "/")
+}
+
+// createUnlocked creates the object in netbox like service.Create, but releases
+// the index lock of the object's type during the request, so other objects
+// of the same type can be added meanwhile. The caller must hold both the index
+// lock and the key lock of the object.
+func createUnlocked[T any](
+	ctx context.Context,
+	nbi *NetboxInventory,
+	indexLock *sync.Mutex,
+	object *T,
+) (*T, error) {
+	indexLock.Unlock()
+	defer indexLock.Lock()
+	return service.Create(ctx, nbi.NetboxAPI, object)
+}
+
+// patchUnlocked patches the object in netbox like service.Patch, but releases
+// the index lock of the object's type during the request.
+// The caller must hold both the index lock and the key lock of the object.
+func patchUnlocked[T any](
+	ctx context.Context,
+	nbi *NetboxInventory,
+	indexLock *sync.Mutex,
+	objectID int,
+	diffMap map[string]interface{},
+) (*T, error) {
+	indexLock.Unlock()
+	defer indexLock.Lock()
+	return service.Patch[T](ctx, nbi.NetboxAPI, objectID, diffMap)
+}
+
+// deleteUnlocked deletes the object in netbox like NetboxClient.DeleteObject,
+// but releases the index lock of the object's type during the request.
+// The caller must hold both the index lock and the key lock of the object.
+func deleteUnlocked(
+	ctx context.Context,
+	nbi *NetboxInventory,
+	indexLock *sync.Mutex,
+	object objects.IDItem,
+) error {
+	indexLock.Unlock()
+	defer indexLock.Lock()
+	return nbi.NetboxAPI.DeleteObject(ctx, object)
+}
@@ -0,0 +1,140 @@
+package inventory
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/src-doo/netbox-ssot/internal/constants"
+	"github.com/src-doo/netbox-ssot/internal/logger"
+	"github.com/src-doo/netbox-ssot/internal/netbox/netboxtest"
+	"github.com/src-doo/netbox-ssot/internal/netbox/objects"
+	"github.com/src-doo/netbox-ssot/internal/netbox/service"
+)
+
+func TestKeyLocks_SameKey(t *testing.T) {
+	var locks keyLocks
+	var holdersLock sync.Mutex
+	holders, maxHolders := 0, 0
+	var wg sync.WaitGroup
+	for range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			release := locks.acquire(constants.TagsAPIPath, "tag")
+			holdersLock.Lock()
+			holders++
+			maxHolders = max(maxHolders, holders)
+			holdersLock.Unlock()
+			time.Sleep(time.Millisecond)
+			holdersLock.Lock()
+			holders--
+			holdersLock.Unlock()
+			release()
+		}()
+	}
+	wg.Wait()
+	if maxHolders != 1 {
+		t.Errorf("key lock was held by %d goroutines at once, want 1", maxHolders)
+	}
+	if len(locks.locks) != 0 {
+		t.Errorf("released key locks were not removed: %v", locks.locks)
+	}
+}
+
+func TestKeyLocks_DifferentKeys(t *testing.T) {
+	var locks keyLocks
+	release := locks.acquire(constants.TagsAPIPath, "tag1")
+	defer release()
+	acquired := make(chan struct{})
+	go func() {
+		defer locks.acquire(constants.TagsAPIPath, "tag2")()
+		close(acquired)
+	}()
+	select {
+	case <-acquired:
+	case <-time.After(time.Second):
+		t.Fatal("lock of another key was blocked by held key lock")
+	}
+}
+
+func TestObjectKey(t *testing.T) {
+	got := objectKey(constants.VlansAPIPath, 1, 100)
+	if want := "/api/ipam/vlans//1/100"; got != want {
+		t.Errorf("objectKey() = %s, want %s", got, want)
+	}
+}
+
+// slowTransport delays requests, so concurrent requests overlap.
+type slowTransport struct {
+	lock        sync.Mutex
+	inFlight    int
+	maxInFlight int
+}
+
+func (s *slowTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	s.lock.Lock()
+	s.inFlight++
+	s.maxInFlight = max(s.maxInFlight, s.inFlight)
+	s.lock.Unlock()
+	defer func() {
+		s.lock.Lock()
+		s.inFlight--
+		s.lock.Unlock()
+	}()
+	time.Sleep(10 * time.Millisecond) //nolint:mnd
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestNetboxInventory_AddTagConcurrently(t *testing.T) {
+	s := netboxtest.NewServer()
+	defer s.Close()
+	transport := &slowTransport{}
+	nbi := &NetboxInventory{
+		Logger: &logger.Logger{Logger: log.New(os.Stdout, "", log.LstdFlags)},
+		NetboxAPI: &service.NetboxClient{
+			HTTPClient: &http.Client{Transport: transport},
+			Logger:     &logger.Logger{Logger: log.New(os.Stdout, "", log.LstdFlags)},
+			BaseURL:    s.URL,
+			Timeout:    constants.DefaultAPITimeout,
+		},
+		tagsIndexByName: map[string]*objects.Tag{},
+	}
+	ctx := context.WithValue(context.Background(), constants.CtxSourceKey, "test")
+
+	const tagCount = 5
+	var wg sync.WaitGroup
+	errs := make(chan error, 4*tagCount)
+	for i := range 4 * tagCount {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			name := fmt.Sprintf("tag%d", i%tagCount)
+			_, err := nbi.AddTag(ctx, &objects.Tag{Name: name, Slug: name})
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Errorf("AddTag() error = %v", err)
+		}
+	}
+
+	tags, err := netboxtest.Objects[objects.Tag](s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tags) != tagCount {
+		t.Errorf("netbox has %d tags, want %d", len(tags), tagCount)
+	}
+	if transport.maxInFlight < 2 {
+		t.Errorf("tags were created one at a time, want concurrent requests")
+	}
+}
@@ -2,6 +2,8 @@ package inventory
 
 import (
 	"context"
+	"sort"
+	"sync"
 
 	"github.com/src-doo/netbox-ssot/internal/constants"
 	"github.com/src-doo/netbox-ssot/internal/logger"
@@ -37,6 +39,36 @@ type OrphanManager struct {
 	Logger *logger.Logger
 	// Context for orphan manager
 	Ctx context.Context
+	// AdoptionMode if set, unmanaged objects (objects without netbox-ssot tag)
+	// are tracked, so we can report which of them were adopted by sources
+	// and which were not matched by any source.
+	AdoptionMode bool
+
+	// unmanagedItems is a map of objectAPIPath to objects, that are not
+	// managed by netbox-ssot and haven't been matched by any source yet.
+	unmanagedItems map[constants.APIPath]map[int]objects.OrphanItem
+	// adoptedItems is a list of unmanaged objects that were matched by sources.
+	adoptedItems []AdoptedItem
+	adoptionLock sync.Mutex
+}
+
+// Values of AdoptedItem.MatchedBy.
+const (
+	// AdoptionMatchDefault means that the object was matched the same way
+	// as managed objects are (e.g. by name for most objects).
+	AdoptionMatchDefault = "default"
+	AdoptionMatchSerial  = "serial"
+	AdoptionMatchUUID    = "uuid"
+)
+
+// AdoptedItem is an unmanaged object, that was matched by a source
+// and taken over by netbox-ssot.
+type AdoptedItem struct {
+	Item objects.OrphanItem `json:"item"`
+	// Source is the name of the source that matched the object.
+	Source string `json:"source"`
+	// MatchedBy is one of AdoptionMatchDefault, AdoptionMatchSerial or AdoptionMatchUUID.
+	MatchedBy string `json:"matched_by"`
 }
 
 func NewOrphanManager(logger *logger.Logger) *OrphanManager {
@@ -44,32 +76,37 @@ func NewOrphanManager(logger *logger.Logger) *OrphanManager {
 	orphanObjectPriority := map[int]constants.APIPath{
 		0:  constants.VlanGroupsAPIPath,
 		1:  constants.PrefixesAPIPath,
-		2:  constants.VlansAPIPath,
-		3:  constants.IPAddressesAPIPath,
-		4:  constants.VirtualDeviceContextsAPIPath,
-		5:  constants.InterfacesAPIPath,
-		6:  constants.VMInterfacesAPIPath,
-		7:  constants.VirtualDisksAPIPath,
-		8:  constants.VirtualMachinesAPIPath,
-		9:  constants.DevicesAPIPath,
-		10: constants.PlatformsAPIPath,
-		11: constants.DeviceTypesAPIPath,
-		12: constants.ManufacturersAPIPath,
-		13: constants.DeviceRolesAPIPath,
-		14: constants.ClustersAPIPath,
-		15: constants.ClusterTypesAPIPath,
-		16: constants.ClusterGroupsAPIPath,
-		17: constants.ContactAssignmentsAPIPath,
-		18: constants.ContactsAPIPath,
-		19: constants.WirelessLANsAPIPath,
-		20: constants.WirelessLANGroupsAPIPath,
-		21: constants.MACAddressesAPIPath,
-		22: constants.VRFsAPIPath,
+		2:  constants.IPRangesAPIPath,
+		3:  constants.VlansAPIPath,
+		4:  constants.IPAddressesAPIPath,
+		5:  constants.VirtualDeviceContextsAPIPath,
+		6:  constants.CablesAPIPath,
+		7:  constants.InventoryItemsAPIPath,
+		8:  constants.InterfacesAPIPath,
+		9:  constants.VMInterfacesAPIPath,
+		10: constants.VirtualDisksAPIPath,
+		11: constants.VirtualMachinesAPIPath,
+		12: constants.VirtualChassisAPIPath,
+		13: constants.DevicesAPIPath,
+		14: constants.PlatformsAPIPath,
+		15: constants.DeviceTypesAPIPath,
+		16: constants.ManufacturersAPIPath,
+		17: constants.DeviceRolesAPIPath,
+		18: constants.ClustersAPIPath,
+		19: constants.ClusterTypesAPIPath,
+		20: constants.ClusterGroupsAPIPath,
+		21: constants.ContactAssignmentsAPIPath,
+		22: constants.ContactsAPIPath,
+		23: constants.WirelessLANsAPIPath,
+		24: constants.WirelessLANGroupsAPIPath,
+		25: constants.MACAddressesAPIPath,
+		26: constants.VRFsAPIPath,
 	}
 	orphanCtx := context.WithValue(context.Background(), constants.CtxSourceKey, "orphanManager")
 
 	return &OrphanManager{
 		Items:                map[constants.APIPath]map[int]objects.OrphanItem{},
+		unmanagedItems:       map[constants.APIPath]map[int]objects.OrphanItem{},
 		OrphanObjectPriority: orphanObjectPriority,
 		Logger:               logger,
 		Ctx:                  orphanCtx,
@@ -84,9 +121,94 @@ func (orphanManager *OrphanManager) AddItem(orphanItem objects.OrphanItem) {
 			orphanManager.Items[orphanItem.GetAPIPath()] = map[int]objects.OrphanItem{}
 		}
 		orphanManager.Items[orphanItem.GetAPIPath()][netboxObject.ID] = orphanItem
+	} else if orphanManager.AdoptionMode {
+		orphanManager.adoptionLock.Lock()
+		defer orphanManager.adoptionLock.Unlock()
+		if orphanManager.unmanagedItems[orphanItem.GetAPIPath()] == nil {
+			orphanManager.unmanagedItems[orphanItem.GetAPIPath()] = map[int]objects.OrphanItem{}
+		}
+		orphanManager.unmanagedItems[orphanItem.GetAPIPath()][netboxObject.ID] = orphanItem
 	}
 }
 
-func (orphanManager *OrphanManager) RemoveItem(obj objects.OrphanItem) {
+// RemoveItem removes obj from orphans, because it was found on a source.
+// In adoption mode, unmanaged obj is marked as adopted.
+func (orphanManager *OrphanManager) RemoveItem(ctx context.Context, obj objects.OrphanItem) {
+	orphanManager.removeItem(ctx, obj, AdoptionMatchDefault)
+}
+
+func (orphanManager *OrphanManager) removeItem(
+	ctx context.Context,
+	obj objects.OrphanItem,
+	matchedBy string,
+) {
 	delete(orphanManager.Items[obj.GetAPIPath()], obj.GetID())
+	if !orphanManager.AdoptionMode {
+		return
+	}
+	orphanManager.adoptionLock.Lock()
+	defer orphanManager.adoptionLock.Unlock()
+	if _, ok := orphanManager.unmanagedItems[obj.GetAPIPath()][obj.GetID()]; !ok {
+		return
+	}
+	delete(orphanManager.unmanagedItems[obj.GetAPIPath()], obj.GetID())
+	sourceName, _ := ctx.Value(constants.CtxSourceKey).(string)
+	orphanManager.Logger.Infof(ctx, "Adopting unmanaged %s matched by %s", obj, matchedBy)
+	orphanManager.adoptedItems = append(orphanManager.adoptedItems, AdoptedItem{
+		Item:      obj,
+		Source:    sourceName,
+		MatchedBy: matchedBy,
+	})
+}
+
+// unmanagedItemsOf returns unmanaged objects with the given api path,
+// that weren't matched by any source yet.
+func (orphanManager *OrphanManager) unmanagedItemsOf(apiPath constants.APIPath) []objects.OrphanItem {
+	orphanManager.adoptionLock.Lock()
+	defer orphanManager.adoptionLock.Unlock()
+	items := make([]objects.OrphanItem, 0, len(orphanManager.unmanagedItems[apiPath]))
+	for _, item := range orphanManager.unmanagedItems[apiPath] {
+		items = append(items, item)
+	}
+	return items
+}
+
+// AdoptedItems returns all objects adopted in adoption mode,
+// sorted by their api path and id.
+func (orphanManager *OrphanManager) AdoptedItems() []AdoptedItem {
+	orphanManager.adoptionLock.Lock()
+	defer orphanManager.adoptionLock.Unlock()
+	adoptedItems := make([]AdoptedItem, len(orphanManager.adoptedItems))
+	copy(adoptedItems, orphanManager.adoptedItems)
+	sort.Slice(adoptedItems, func(i, j int) bool {
+		if adoptedItems[i].Item.GetAPIPath() != adoptedItems[j].Item.GetAPIPath() {
+			return adoptedItems[i].Item.GetAPIPath() < adoptedItems[j].Item.GetAPIPath()
+		}
+		return adoptedItems[i].Item.GetID() < adoptedItems[j].Item.GetID()
+	})
+	return adoptedItems
+}
+
+// UnmanagedItems returns all unmanaged objects that weren't matched by
+// any source, grouped by their api path and sorted by their id.
+// It is only populated in adoption mode.
+func (orphanManager *OrphanManager) UnmanagedItems() map[constants.APIPath][]objects.OrphanItem {
+	unmanagedItems := map[constants.APIPath][]objects.OrphanItem{}
+	orphanManager.adoptionLock.Lock()
+	apiPaths := make([]constants.APIPath, 0, len(orphanManager.unmanagedItems))
+	for apiPath := range orphanManager.unmanagedItems {
+		apiPaths = append(apiPaths, apiPath)
+	}
+	orphanManager.adoptionLock.Unlock()
+	for _, apiPath := range apiPaths {
+		items := orphanManager.unmanagedItemsOf(apiPath)
+		if len(items) == 0 {
+			continue
+		}
+		sort.Slice(items, func(i, j int) bool {
+			return items[i].GetID() < items[j].GetID()
+		})
+		unmanagedItems[apiPath] = items
+	}
+	return unmanagedItems
 }
@@ -1,10 +1,13 @@
 package inventory
 
 import (
+	"context"
 	"reflect"
 	"testing"
 
+	"github.com/src-doo/netbox-ssot/internal/constants"
 	"github.com/src-doo/netbox-ssot/internal/logger"
+	"github.com/src-doo/netbox-ssot/internal/netbox/objects"
 )
 
 func TestNewOrphanManager(t *testing.T) {
@@ -26,3 +29,106 @@ func TestNewOrphanManager(t *testing.T) {
 		})
 	}
 }
+
+func TestOrphanManager_Adoption(t *testing.T) {
+	ctx := context.WithValue(context.Background(), constants.CtxSourceKey, "vcenter")
+	ssotTag := &objects.Tag{ID: 1, Name: constants.SsotTagName}
+	managedDevice := &objects.Device{
+		NetboxObject: objects.NetboxObject{ID: 1, Tags: []*objects.Tag{ssotTag}},
+		Name:         "managed",
+	}
+	adoptedDevice := &objects.Device{
+		NetboxObject: objects.NetboxObject{ID: 2},
+		Name:         "manual",
+	}
+	unmatchedDevice := &objects.Device{
+		NetboxObject: objects.NetboxObject{ID: 3},
+		Name:         "forgotten",
+	}
+	orphanManager := NewOrphanManager(MockInventory.Logger)
+	orphanManager.AdoptionMode = true
+	for _, device := range []*objects.Device{managedDevice, adoptedDevice, unmatchedDevice} {
+		orphanManager.AddItem(device)
+	}
+
+	orphanManager.RemoveItem(ctx, managedDevice)
+	orphanManager.removeItem(ctx, adoptedDevice, AdoptionMatchSerial)
+
+	wantAdopted := []AdoptedItem{
+		{Item: adoptedDevice, Source: "vcenter", MatchedBy: AdoptionMatchSerial},
+	}
+	if got := orphanManager.AdoptedItems(); !reflect.DeepEqual(got, wantAdopted) {
+		t.Errorf("AdoptedItems() = %v, want %v", got, wantAdopted)
+	}
+	wantUnmanaged := map[constants.APIPath][]objects.OrphanItem{
+		constants.DevicesAPIPath: {unmatchedDevice},
+	}
+	if got := orphanManager.UnmanagedItems(); !reflect.DeepEqual(got, wantUnmanaged) {
+		t.Errorf("UnmanagedItems() = %v, want %v", got, wantUnmanaged)
+	}
+}
+
+func TestNetboxInventory_matchUnmanagedDevice(t *testing.T) {
+	site := &objects.Site{NetboxObject: objects.NetboxObject{ID: 1}}
+	bySerial := &objects.Device{
+		NetboxObject: objects.NetboxObject{ID: 1},
+		Name:         "manual-1",
+		Site:         site,
+		SerialNumber: "ABC123",
+	}
+	byUUID := &objects.Device{
+		NetboxObject: objects.NetboxObject{
+			ID:           2,
+			CustomFields: map[string]interface{}{constants.CustomFieldDeviceUUIDName: "uuid-2"},
+		},
+		Name: "manual-2",
+		Site: site,
+	}
+	nbi := &NetboxInventory{OrphanManager: NewOrphanManager(MockInventory.Logger)}
+	nbi.OrphanManager.AdoptionMode = true
+	nbi.OrphanManager.AddItem(bySerial)
+	nbi.OrphanManager.AddItem(byUUID)
+
+	tests := []struct {
+		name          string
+		newDevice     *objects.Device
+		wantDevice    *objects.Device
+		wantMatchedBy string
+		wantOk        bool
+	}{
+		{
+			name:          "Match by serial number",
+			newDevice:     &objects.Device{Name: "device-1", SerialNumber: "ABC123"},
+			wantDevice:    bySerial,
+			wantMatchedBy: AdoptionMatchSerial,
+			wantOk:        true,
+		},
+		{
+			name: "Match by uuid",
+			newDevice: &objects.Device{
+				NetboxObject: objects.NetboxObject{
+					CustomFields: map[string]interface{}{constants.CustomFieldDeviceUUIDName: "uuid-2"},
+				},
+				Name: "device-2",
+			},
+			wantDevice:    byUUID,
+			wantMatchedBy: AdoptionMatchUUID,
+			wantOk:        true,
+		},
+		{
+			name:      "No match",
+			newDevice: &objects.Device{Name: "device-3", SerialNumber: "XYZ"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotDevice, gotMatchedBy, gotOk := nbi.matchUnmanagedDevice(tt.newDevice)
+			if gotDevice != tt.wantDevice || gotMatchedBy != tt.wantMatchedBy || gotOk != tt.wantOk {
+				t.Errorf(
+					"matchUnmanagedDevice() = %v, %q, %v, want %v, %q, %v",
+					gotDevice, gotMatchedBy, gotOk, tt.wantDevice, tt.wantMatchedBy, tt.wantOk,
+				)
+			}
+		})
+	}
+}
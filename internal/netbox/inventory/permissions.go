@@ -0,0 +1,199 @@
+package inventory
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/src-doo/netbox-ssot/internal/constants"
+	"github.com/src-doo/netbox-ssot/internal/netbox/service"
+)
+
+// initObjectPaths are api paths of objects, which are written during Init.
+var initObjectPaths = []constants.APIPath{
+	constants.CustomFieldsAPIPath,
+	constants.TagsAPIPath,
+	constants.ContactRolesAPIPath,
+	constants.SitesAPIPath,
+}
+
+// sourceObjectPaths are api paths of objects, which are written by all sources.
+var sourceObjectPaths = []constants.APIPath{
+	constants.TagsAPIPath,
+	constants.SitesAPIPath,
+	constants.TenantsAPIPath,
+	constants.TenantGroupsAPIPath,
+	constants.DeviceRolesAPIPath,
+	constants.ManufacturersAPIPath,
+	constants.PlatformsAPIPath,
+	constants.DeviceTypesAPIPath,
+	constants.DevicesAPIPath,
+	constants.InterfacesAPIPath,
+	constants.IPAddressesAPIPath,
+}
+
+// sourceTypeObjectPaths are api paths of objects, which are written only
+// by sources of the given type.
+var sourceTypeObjectPaths = map[constants.SourceType][]constants.APIPath{
+	constants.Vmware: {
+		constants.CablesAPIPath,
+		constants.ClusterGroupsAPIPath,
+		constants.ClusterTypesAPIPath,
+		constants.ClustersAPIPath,
+		constants.ConsolePortTemplatesAPIPath,
+		constants.ContactsAPIPath,
+		constants.ContactAssignmentsAPIPath,
+		constants.CustomFieldsAPIPath,
+		constants.InterfaceTemplatesAPIPath,
+		constants.MACAddressesAPIPath,
+		constants.PowerPortTemplatesAPIPath,
+		constants.PrefixesAPIPath,
+		constants.VirtualMachinesAPIPath,
+		constants.VMInterfacesAPIPath,
+		constants.VirtualDisksAPIPath,
+		constants.VlanGroupsAPIPath,
+		constants.VlansAPIPath,
+	},
+	constants.Ovirt: {
+		constants.ClusterGroupsAPIPath,
+		constants.ClusterTypesAPIPath,
+		constants.ClustersAPIPath,
+		constants.ConsolePortTemplatesAPIPath,
+		constants.InterfaceTemplatesAPIPath,
+		constants.MACAddressesAPIPath,
+		constants.PowerPortTemplatesAPIPath,
+		constants.PrefixesAPIPath,
+		constants.VirtualMachinesAPIPath,
+		constants.VMInterfacesAPIPath,
+		constants.VirtualDisksAPIPath,
+		constants.VlanGroupsAPIPath,
+		constants.VlansAPIPath,
+	},
+	constants.Proxmox: {
+		constants.ClusterTypesAPIPath,
+		constants.ClustersAPIPath,
+		constants.MACAddressesAPIPath,
+		constants.PrefixesAPIPath,
+		constants.VirtualMachinesAPIPath,
+		constants.VMInterfacesAPIPath,
+		constants.VirtualDisksAPIPath,
+	},
+	constants.Dnac: {
+		constants.CablesAPIPath,
+		constants.InventoryItemsAPIPath,
+		constants.LocationsAPIPath,
+		constants.MACAddressesAPIPath,
+		constants.PrefixesAPIPath,
+		constants.RegionsAPIPath,
+		constants.VirtualChassisAPIPath,
+		constants.VlanGroupsAPIPath,
+		constants.VlansAPIPath,
+		constants.WirelessLANsAPIPath,
+		constants.WirelessLANGroupsAPIPath,
+	},
+	constants.PaloAlto: {
+		constants.IPRangesAPIPath,
+		constants.PrefixesAPIPath,
+		constants.VirtualChassisAPIPath,
+		constants.VirtualDeviceContextsAPIPath,
+		constants.VlanGroupsAPIPath,
+		constants.VlansAPIPath,
+	},
+	constants.Fortigate: {
+		constants.IPRangesAPIPath,
+		constants.MACAddressesAPIPath,
+		constants.PrefixesAPIPath,
+		constants.VirtualDeviceContextsAPIPath,
+		constants.VlanGroupsAPIPath,
+		constants.VlansAPIPath,
+	},
+	constants.FMC: {
+		constants.PrefixesAPIPath,
+		constants.VirtualChassisAPIPath,
+		constants.VlanGroupsAPIPath,
+		constants.VlansAPIPath,
+	},
+	constants.IOSXE: {
+		constants.CablesAPIPath,
+		constants.ConsolePortTemplatesAPIPath,
+		constants.InterfaceTemplatesAPIPath,
+		constants.InventoryItemsAPIPath,
+		constants.IPRangesAPIPath,
+		constants.MACAddressesAPIPath,
+		constants.PowerPortTemplatesAPIPath,
+	},
+}
+
+// RequiredPermissions returns permissions, which the api token needs for
+// syncing the configured sources, by api path of objects.
+// Objects are loaded during Init, so view permission is required for all of them.
+// Objects written by netbox-ssot also require add and change permissions,
+// and delete permission if they can be deleted as orphans.
+// In dry run mode, nothing is written, so only view permissions are required.
+func (nbi *NetboxInventory) RequiredPermissions() map[constants.APIPath][]service.PermissionAction {
+	required := map[constants.APIPath][]service.PermissionAction{}
+	for _, path := range nbi.OrphanManager.OrphanObjectPriority {
+		required[path] = []service.PermissionAction{service.PermissionView}
+	}
+	for _, path := range []constants.APIPath{
+		constants.ContactGroupsAPIPath,
+		constants.SiteGroupsAPIPath,
+		constants.RegionsAPIPath,
+		constants.LocationsAPIPath,
+		constants.InterfaceTemplatesAPIPath,
+		constants.ConsolePortTemplatesAPIPath,
+		constants.PowerPortTemplatesAPIPath,
+	} {
+		required[path] = []service.PermissionAction{service.PermissionView}
+	}
+	for _, path := range sourceObjectPaths {
+		required[path] = []service.PermissionAction{service.PermissionView}
+	}
+	for _, path := range initObjectPaths {
+		required[path] = []service.PermissionAction{service.PermissionView}
+	}
+	if !nbi.Supports(service.FeatureMACAddressObjects) {
+		delete(required, constants.MACAddressesAPIPath)
+	}
+	if nbi.DryRun {
+		return required
+	}
+
+	orphanPaths := make(map[constants.APIPath]bool, len(nbi.OrphanManager.OrphanObjectPriority))
+	for _, path := range nbi.OrphanManager.OrphanObjectPriority {
+		orphanPaths[path] = true
+	}
+	written := slices.Clone(initObjectPaths)
+	if len(nbi.SourceTypes) > 0 {
+		written = append(written, sourceObjectPaths...)
+	}
+	for _, sourceType := range nbi.SourceTypes {
+		written = append(written, sourceTypeObjectPaths[sourceType]...)
+	}
+	for _, path := range written {
+		actions, ok := required[path]
+		if !ok || slices.Contains(actions, service.PermissionAdd) {
+			continue
+		}
+		actions = append(actions, service.PermissionAdd, service.PermissionChange)
+		if orphanPaths[path] {
+			actions = append(actions, service.PermissionDelete)
+		}
+		required[path] = actions
+	}
+	return required
+}
+
+// checkPermissions verifies that the api token has all RequiredPermissions,
+// so syncing doesn't fail midway because of a missing permission.
+func (nbi *NetboxInventory) checkPermissions() error {
+	missing, err := nbi.NetboxAPI.MissingPermissions(nbi.Ctx, nbi.RequiredPermissions())
+	if err != nil {
+		return fmt.Errorf("check permissions: %s", err)
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("netbox api token is missing permissions: %s", strings.Join(missing, ", "))
+	}
+	nbi.Logger.Debug(nbi.Ctx, "Netbox api token has all required permissions")
+	return nil
+}
@@ -0,0 +1,114 @@
+package inventory
+
+import (
+	"context"
+	"log"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/src-doo/netbox-ssot/internal/constants"
+	"github.com/src-doo/netbox-ssot/internal/logger"
+	"github.com/src-doo/netbox-ssot/internal/netbox/netboxtest"
+	"github.com/src-doo/netbox-ssot/internal/netbox/service"
+)
+
+func TestNetboxInventory_RequiredPermissions(t *testing.T) {
+	tests := []struct {
+		name        string
+		dryRun      bool
+		sourceTypes []constants.SourceType
+		path        constants.APIPath
+		want        []service.PermissionAction
+	}{
+		{
+			name:        "Dry run requires only view",
+			dryRun:      true,
+			sourceTypes: []constants.SourceType{constants.Vmware},
+			path:        constants.DevicesAPIPath,
+			want:        []service.PermissionAction{service.PermissionView},
+		},
+		{
+			name:        "Objects written by source",
+			sourceTypes: []constants.SourceType{constants.Proxmox},
+			path:        constants.VirtualMachinesAPIPath,
+			want: []service.PermissionAction{
+				service.PermissionView,
+				service.PermissionAdd,
+				service.PermissionChange,
+				service.PermissionDelete,
+			},
+		},
+		{
+			name:        "Objects not written by source",
+			sourceTypes: []constants.SourceType{constants.PaloAlto},
+			path:        constants.VirtualMachinesAPIPath,
+			want:        []service.PermissionAction{service.PermissionView},
+		},
+		{
+			name:        "Objects written during init, that are not orphans",
+			sourceTypes: []constants.SourceType{constants.PaloAlto},
+			path:        constants.CustomFieldsAPIPath,
+			want: []service.PermissionAction{
+				service.PermissionView,
+				service.PermissionAdd,
+				service.PermissionChange,
+			},
+		},
+		{
+			name: "Without sources",
+			path: constants.DevicesAPIPath,
+			want: []service.PermissionAction{service.PermissionView},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nbi := &NetboxInventory{
+				OrphanManager: NewOrphanManager(nil),
+				DryRun:        tt.dryRun,
+				SourceTypes:   tt.sourceTypes,
+			}
+			required := nbi.RequiredPermissions()
+			if got := required[tt.path]; !slices.Equal(got, tt.want) {
+				t.Errorf("RequiredPermissions()[%s] = %v, want %v", tt.path, got, tt.want)
+			}
+			for path := range required {
+				if name := service.PermissionName(path, service.PermissionView); strings.HasPrefix(name, "view ") {
+					t.Errorf("RequiredPermissions() contains path %s without permission name", path)
+				}
+			}
+		})
+	}
+}
+
+func TestNetboxInventory_checkPermissions(t *testing.T) {
+	s := netboxtest.NewServer()
+	defer s.Close()
+	nbi := &NetboxInventory{
+		Logger: &logger.Logger{Logger: log.New(os.Stdout, "", log.LstdFlags)},
+		NetboxAPI: &service.NetboxClient{
+			HTTPClient: s.Client(),
+			Logger:     &logger.Logger{Logger: log.New(os.Stdout, "", log.LstdFlags)},
+			BaseURL:    s.URL,
+			Timeout:    constants.DefaultAPITimeout,
+		},
+		OrphanManager: NewOrphanManager(nil),
+		SourceTypes:   []constants.SourceType{constants.Fortigate},
+		Ctx:           context.Background(),
+	}
+	if err := nbi.checkPermissions(); err != nil {
+		t.Fatalf("checkPermissions() error = %v", err)
+	}
+
+	s.DeniedPermissions = map[string]bool{
+		"dcim.add_interface":                true,
+		"virtualization.add_virtualmachine": true,
+		"ipam.view_vrf":                     true,
+	}
+	err := nbi.checkPermissions()
+	want := "netbox api token is missing permissions: dcim.add_interface, ipam.view_vrf"
+	if err == nil || err.Error() != want {
+		t.Errorf("checkPermissions() error = %v, want %s", err, want)
+	}
+}
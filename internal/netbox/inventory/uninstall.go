@@ -0,0 +1,171 @@
+package inventory
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/src-doo/netbox-ssot/internal/constants"
+	"github.com/src-doo/netbox-ssot/internal/netbox/objects"
+)
+
+// ssotCustomFieldNames are names of custom fields created in initSsotCustomFields.
+var ssotCustomFieldNames = []string{
+	constants.CustomFieldSourceName,
+	constants.CustomFieldOrphanLastSeenName,
+	constants.CustomFieldSourceIDName,
+	constants.CustomFieldHostCPUCoresName,
+	constants.CustomFieldHostMemoryName,
+	constants.CustomFieldDeviceUUIDName,
+	constants.CustomFieldArpEntryName,
+}
+
+// UninstallOptions configures which objects are removed by Uninstall.
+type UninstallOptions struct {
+	// SourceName restricts uninstall to objects synced by the source with this name.
+	// If empty, all objects managed by netbox-ssot are affected.
+	SourceName string
+	// SourceTagName is the name of the tag of the source with SourceName.
+	SourceTagName string
+	// Untag only removes netbox-ssot tags and custom field values from the objects,
+	// instead of deleting them.
+	Untag bool
+	// RemoveCustomFieldsAndTags also removes custom fields and tags created by
+	// netbox-ssot. When SourceName is set, only the tag of the source is removed.
+	RemoveCustomFieldsAndTags bool
+}
+
+// Uninstall deletes (or untags) all objects managed by netbox-ssot,
+// or only objects of a single source. Objects are processed in order
+// defined by OrphanManager.OrphanObjectPriority, so dependent objects
+// are removed first.
+func (nbi *NetboxInventory) Uninstall(ctx context.Context, options UninstallOptions) error {
+	failed := 0
+	for i := 0; i < len(nbi.OrphanManager.OrphanObjectPriority); i++ {
+		objectAPIPath := nbi.OrphanManager.OrphanObjectPriority[i]
+		id2orphanItem := nbi.OrphanManager.Items[objectAPIPath]
+		for id, orphanItem := range id2orphanItem {
+			if !nbi.isOwnedBy(orphanItem, options) {
+				continue
+			}
+			var err error
+			if options.Untag {
+				nbi.Logger.Debugf(ctx, "Untagging %s", orphanItem)
+				err = nbi.untag(orphanItem)
+			} else {
+				nbi.Logger.Debugf(ctx, "Deleting %s", orphanItem)
+				err = nbi.hardDelete(orphanItem)
+			}
+			if err != nil {
+				nbi.Logger.Errorf(ctx, "uninstall object: %s", err)
+				failed++
+				continue
+			}
+			delete(id2orphanItem, id)
+		}
+	}
+	if failed > 0 {
+		// Custom fields and tags are still used by objects that failed
+		return fmt.Errorf("failed to uninstall %d objects", failed)
+	}
+
+	if options.RemoveCustomFieldsAndTags {
+		return nbi.removeSsotCustomFieldsAndTags(ctx, options)
+	}
+	return nil
+}
+
+// isOwnedBy returns true if the orphanItem is affected by the uninstall options.
+func (nbi *NetboxInventory) isOwnedBy(orphanItem objects.OrphanItem, options UninstallOptions) bool {
+	netboxObject := orphanItem.GetNetboxObject()
+	if !netboxObject.HasTagByName(constants.SsotTagName) {
+		return false
+	}
+	if options.SourceName == "" {
+		return true
+	}
+	if sourceName, ok := netboxObject.GetCustomField(constants.CustomFieldSourceName).(string); ok {
+		return sourceName == options.SourceName
+	}
+	return options.SourceTagName != "" && netboxObject.HasTagByName(options.SourceTagName)
+}
+
+// isSsotTag returns true if the tag was created by netbox-ssot.
+func isSsotTag(tag *objects.Tag) bool {
+	switch tag.Name {
+	case constants.SsotTagName, constants.OrphanTagName, constants.IgnoreDeviceTypeTagName:
+		return true
+	}
+	return strings.HasPrefix(tag.Description, constants.SourceTagDescriptionPrefix)
+}
+
+// untag removes all netbox-ssot tags and custom field values from the orphanItem.
+func (nbi *NetboxInventory) untag(orphanItem objects.OrphanItem) error {
+	netboxObject := orphanItem.GetNetboxObject()
+	tagIDs := make([]int, 0, len(netboxObject.Tags))
+	for _, tag := range netboxObject.Tags {
+		// Nested tags don't include description, so we use the indexed ones
+		if indexedTag, ok := nbi.GetTag(tag.Name); ok {
+			tag = indexedTag
+		}
+		if !isSsotTag(tag) {
+			tagIDs = append(tagIDs, tag.ID)
+		}
+	}
+	customFields := map[string]interface{}{}
+	for _, customFieldName := range ssotCustomFieldNames {
+		if _, ok := netboxObject.CustomFields[customFieldName]; ok {
+			customFields[customFieldName] = nil
+		}
+	}
+	diffMap := map[string]interface{}{
+		"tags":          tagIDs,
+		"custom_fields": customFields,
+	}
+	err := nbi.patchOrphanItem(orphanItem, diffMap)
+	if err != nil {
+		return fmt.Errorf("failed untagging %s object: %s", orphanItem, err)
+	}
+	return nil
+}
+
+// removeSsotCustomFieldsAndTags removes custom fields created in
+// initSsotCustomFields and tags created in initTags and by sources.
+func (nbi *NetboxInventory) removeSsotCustomFieldsAndTags(
+	ctx context.Context,
+	options UninstallOptions,
+) error {
+	toRemove := []objects.IDItem{}
+	if options.SourceName != "" {
+		if sourceTag, ok := nbi.GetTag(options.SourceTagName); ok {
+			toRemove = append(toRemove, sourceTag)
+		}
+	} else {
+		for _, customFieldName := range ssotCustomFieldNames {
+			if customField, ok := nbi.GetCustomField(customFieldName); ok {
+				toRemove = append(toRemove, customField)
+			}
+		}
+		nbi.tagsLock.Lock()
+		for _, tag := range nbi.tagsIndexByName {
+			if isSsotTag(tag) {
+				toRemove = append(toRemove, tag)
+			}
+		}
+		nbi.tagsLock.Unlock()
+	}
+
+	failed := 0
+	for _, item := range toRemove {
+		nbi.Logger.Debugf(ctx, "Deleting %s", item)
+		err := nbi.NetboxAPI.DeleteObject(ctx, item)
+		if err != nil {
+			nbi.Logger.Errorf(ctx, "failed deleting %s: %s", item, err)
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("failed to remove %d custom fields and tags", failed)
+	}
+	return nil
+}
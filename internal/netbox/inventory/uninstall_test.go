@@ -0,0 +1,182 @@
+package inventory
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/src-doo/netbox-ssot/internal/constants"
+	"github.com/src-doo/netbox-ssot/internal/logger"
+	"github.com/src-doo/netbox-ssot/internal/netbox/objects"
+	"github.com/src-doo/netbox-ssot/internal/netbox/service"
+)
+
+func TestIsSsotTag(t *testing.T) {
+	tests := []struct {
+		name string
+		tag  *objects.Tag
+		want bool
+	}{
+		{
+			name: "Ssot tag",
+			tag:  &objects.Tag{Name: constants.SsotTagName},
+			want: true,
+		},
+		{
+			name: "Orphan tag",
+			tag:  &objects.Tag{Name: constants.OrphanTagName},
+			want: true,
+		},
+		{
+			name: "Source tag",
+			tag: &objects.Tag{
+				Name:        "Source: vcenter",
+				Description: constants.SourceTagDescriptionPrefix + " for source vcenter",
+			},
+			want: true,
+		},
+		{
+			name: "User defined tag",
+			tag:  &objects.Tag{Name: "production", Description: "Production objects"},
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isSsotTag(tt.tag); got != tt.want {
+				t.Errorf("isSsotTag() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNetboxInventory_Uninstall(t *testing.T) {
+	ssotTag := &objects.Tag{ID: 1, Name: constants.SsotTagName}
+	sourceTag := &objects.Tag{ID: 2, Name: "Source: vcenter"}
+	userTag := &objects.Tag{ID: 3, Name: "production"}
+	newOrphanManager := func() *OrphanManager {
+		orphanManager := NewOrphanManager(MockInventory.Logger)
+		orphanManager.AddItem(&objects.Device{
+			NetboxObject: objects.NetboxObject{
+				ID:           10,
+				Tags:         []*objects.Tag{ssotTag, sourceTag, userTag},
+				CustomFields: map[string]interface{}{constants.CustomFieldSourceName: "vcenter"},
+			},
+			Name: "vcenter-device",
+		})
+		orphanManager.AddItem(&objects.Device{
+			NetboxObject: objects.NetboxObject{
+				ID:           11,
+				Tags:         []*objects.Tag{ssotTag},
+				CustomFields: map[string]interface{}{constants.CustomFieldSourceName: "ovirt"},
+			},
+			Name: "ovirt-device",
+		})
+		orphanManager.AddItem(&objects.Interface{
+			NetboxObject: objects.NetboxObject{
+				ID:           20,
+				Tags:         []*objects.Tag{ssotTag, sourceTag},
+				CustomFields: map[string]interface{}{constants.CustomFieldSourceName: "vcenter"},
+			},
+			Name: "eth0",
+		})
+		orphanManager.AddItem(&objects.VRF{
+			NetboxObject: objects.NetboxObject{
+				ID:           30,
+				Tags:         []*objects.Tag{ssotTag},
+				CustomFields: map[string]interface{}{constants.CustomFieldSourceName: "ovirt"},
+			},
+			Name: "ovirt-vrf",
+		})
+		return orphanManager
+	}
+
+	tests := []struct {
+		name        string
+		options     UninstallOptions
+		wantChanges []service.PlannedChange
+	}{
+		{
+			name:    "Delete objects of a single source in priority order",
+			options: UninstallOptions{SourceName: "vcenter", SourceTagName: sourceTag.Name},
+			wantChanges: []service.PlannedChange{
+				{
+					Action:     service.PlannedActionDelete,
+					ObjectPath: constants.InterfacesAPIPath,
+					ObjectID:   20,
+				},
+				{
+					Action:     service.PlannedActionDelete,
+					ObjectPath: constants.DevicesAPIPath,
+					ObjectID:   10,
+				},
+			},
+		},
+		{
+			name:    "Untag objects of a single source",
+			options: UninstallOptions{SourceName: "ovirt", Untag: true},
+			wantChanges: []service.PlannedChange{
+				{
+					Action:     service.PlannedActionUpdate,
+					ObjectPath: constants.DevicesAPIPath,
+					ObjectID:   11,
+					Data: map[string]interface{}{
+						"tags":          []int{},
+						"custom_fields": map[string]interface{}{constants.CustomFieldSourceName: nil},
+					},
+				},
+				{
+					Action:     service.PlannedActionUpdate,
+					ObjectPath: constants.VRFsAPIPath,
+					ObjectID:   30,
+					Data: map[string]interface{}{
+						"tags":          []int{},
+						"custom_fields": map[string]interface{}{constants.CustomFieldSourceName: nil},
+					},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Dry run client records changes instead of sending them.
+			// It only fetches objects that are being patched.
+			mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodGet {
+					t.Errorf("unexpected %s request on dry run client", r.Method)
+				}
+				_, _ = w.Write([]byte(`{"id": 11, "name": "ovirt-device"}`))
+			}))
+			defer mockServer.Close()
+			netboxAPI := &service.NetboxClient{
+				HTTPClient: mockServer.Client(),
+				Logger:     &logger.Logger{Logger: log.Default()},
+				BaseURL:    mockServer.URL,
+				Timeout:    constants.DefaultAPITimeout,
+				DryRun:     true,
+			}
+			nbi := &NetboxInventory{
+				Logger:          MockInventory.Logger,
+				Ctx:             context.Background(),
+				NetboxAPI:       netboxAPI,
+				OrphanManager:   newOrphanManager(),
+				tagsIndexByName: map[string]*objects.Tag{},
+			}
+
+			err := nbi.Uninstall(context.Background(), tt.options)
+			if err != nil {
+				t.Fatalf("Uninstall() error = %v", err)
+			}
+			gotChanges := netboxAPI.PlannedChanges()
+			for i := range gotChanges {
+				gotChanges[i].Object = ""
+			}
+			if !reflect.DeepEqual(gotChanges, tt.wantChanges) {
+				t.Errorf("Uninstall() changes = %+v, want %+v", gotChanges, tt.wantChanges)
+			}
+		})
+	}
+}
@@ -26,15 +26,24 @@ var Type2Path = map[reflect.Type]constants.APIPath{
 	reflect.TypeOf((*objects.VMInterface)(nil)).Elem():          constants.VMInterfacesAPIPath,
 	reflect.TypeOf((*objects.Device)(nil)).Elem():               constants.DevicesAPIPath,
 	reflect.TypeOf((*objects.MACAddress)(nil)).Elem():           constants.MACAddressesAPIPath,
+	reflect.TypeOf((*objects.VirtualChassis)(nil)).Elem():       constants.VirtualChassisAPIPath,
 	reflect.TypeOf((*objects.VirtualDeviceContext)(nil)).Elem(): constants.VirtualDeviceContextsAPIPath,
 	reflect.TypeOf((*objects.DeviceRole)(nil)).Elem():           constants.DeviceRolesAPIPath,
 	reflect.TypeOf((*objects.DeviceType)(nil)).Elem():           constants.DeviceTypesAPIPath,
 	reflect.TypeOf((*objects.Interface)(nil)).Elem():            constants.InterfacesAPIPath,
+	reflect.TypeOf((*objects.InterfaceTemplate)(nil)).Elem():    constants.InterfaceTemplatesAPIPath,
+	reflect.TypeOf((*objects.ConsolePortTemplate)(nil)).Elem():  constants.ConsolePortTemplatesAPIPath,
+	reflect.TypeOf((*objects.PowerPortTemplate)(nil)).Elem():    constants.PowerPortTemplatesAPIPath,
+	reflect.TypeOf((*objects.Cable)(nil)).Elem():                constants.CablesAPIPath,
+	reflect.TypeOf((*objects.InventoryItem)(nil)).Elem():        constants.InventoryItemsAPIPath,
 	reflect.TypeOf((*objects.Site)(nil)).Elem():                 constants.SitesAPIPath,
 	reflect.TypeOf((*objects.SiteGroup)(nil)).Elem():            constants.SiteGroupsAPIPath,
+	reflect.TypeOf((*objects.Region)(nil)).Elem():               constants.RegionsAPIPath,
+	reflect.TypeOf((*objects.Location)(nil)).Elem():             constants.LocationsAPIPath,
 	reflect.TypeOf((*objects.Manufacturer)(nil)).Elem():         constants.ManufacturersAPIPath,
 	reflect.TypeOf((*objects.Platform)(nil)).Elem():             constants.PlatformsAPIPath,
 	reflect.TypeOf((*objects.Tenant)(nil)).Elem():               constants.TenantsAPIPath,
+	reflect.TypeOf((*objects.TenantGroup)(nil)).Elem():          constants.TenantGroupsAPIPath,
 	reflect.TypeOf((*objects.ContactGroup)(nil)).Elem():         constants.ContactGroupsAPIPath,
 	reflect.TypeOf((*objects.ContactRole)(nil)).Elem():          constants.ContactRolesAPIPath,
 	reflect.TypeOf((*objects.Contact)(nil)).Elem():              constants.ContactsAPIPath,
@@ -42,6 +51,7 @@ var Type2Path = map[reflect.Type]constants.APIPath{
 	reflect.TypeOf((*objects.Tag)(nil)).Elem():                  constants.TagsAPIPath,
 	reflect.TypeOf((*objects.ContactAssignment)(nil)).Elem():    constants.ContactAssignmentsAPIPath,
 	reflect.TypeOf((*objects.Prefix)(nil)).Elem():               constants.PrefixesAPIPath,
+	reflect.TypeOf((*objects.IPRange)(nil)).Elem():              constants.IPRangesAPIPath,
 	reflect.TypeOf((*objects.WirelessLAN)(nil)).Elem():          constants.WirelessLANsAPIPath,
 	reflect.TypeOf((*objects.WirelessLANGroup)(nil)).Elem():     constants.WirelessLANGroupsAPIPath,
 	reflect.TypeOf((*objects.VirtualDisk)(nil)).Elem():          constants.VirtualDisksAPIPath,
@@ -49,3 +59,21 @@ var Type2Path = map[reflect.Type]constants.APIPath{
 }
 
 var Path2Type = reverseMap(Type2Path)
+
+// objectTypeItem is implemented by all netbox objects.
+type objectTypeItem interface {
+	GetObjectType() constants.ContentType
+}
+
+// ContentType returns the content type of objects on the api path, e.g. dcim.device.
+func ContentType(path constants.APIPath) (constants.ContentType, bool) {
+	objectType, ok := Path2Type[path]
+	if !ok {
+		return "", false
+	}
+	item, ok := reflect.New(objectType).Interface().(objectTypeItem)
+	if !ok {
+		return "", false
+	}
+	return item.GetObjectType(), true
+}
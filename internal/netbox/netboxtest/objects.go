@@ -0,0 +1,101 @@
+package netboxtest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/src-doo/netbox-ssot/internal/netbox/mapper"
+	"github.com/src-doo/netbox-ssot/internal/utils"
+)
+
+// Seed stores the object in the emulator, as if it was created by the user,
+// and returns it as it would be returned by netbox. Related objects
+// must be seeded before and referenced by their id.
+func Seed[T any](s *Server, object *T) (*T, error) {
+	var dummy T
+	path, ok := mapper.Type2Path[reflect.TypeOf(dummy)]
+	if !ok {
+		return nil, fmt.Errorf("path not found for type %T", dummy)
+	}
+	objectMap, err := toJSONMap(utils.StructToNetboxJSONMap(object))
+	if err != nil {
+		return nil, err
+	}
+
+	s.lock.Lock()
+	id, validationErr := s.insert(path, objectMap)
+	if validationErr != nil {
+		s.lock.Unlock()
+		return nil, fmt.Errorf("seed %T: %v", dummy, validationErr)
+	}
+	expanded := s.expand(path, s.objects[path][id], nestedDepth)
+	s.lock.Unlock()
+
+	var seeded T
+	err = fromJSONMap(expanded, &seeded)
+	if err != nil {
+		return nil, err
+	}
+	return &seeded, nil
+}
+
+// Objects returns all stored objects of type T sorted by their id,
+// as they would be returned by netbox.
+func Objects[T any](s *Server) ([]*T, error) {
+	var dummy T
+	path, ok := mapper.Type2Path[reflect.TypeOf(dummy)]
+	if !ok {
+		return nil, fmt.Errorf("path not found for type %T", dummy)
+	}
+
+	s.lock.Lock()
+	ids := make([]int, 0, len(s.objects[path]))
+	for id := range s.objects[path] {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	expanded := make([]map[string]interface{}, 0, len(ids))
+	for _, id := range ids {
+		expanded = append(expanded, s.expand(path, s.objects[path][id], nestedDepth))
+	}
+	s.lock.Unlock()
+
+	objects := make([]*T, 0, len(expanded))
+	for _, objectMap := range expanded {
+		var object T
+		err := fromJSONMap(objectMap, &object)
+		if err != nil {
+			return nil, err
+		}
+		objects = append(objects, &object)
+	}
+	return objects, nil
+}
+
+// toJSONMap converts values of the map to the form they have after
+// being decoded from a request body.
+func toJSONMap(objectMap map[string]interface{}) (map[string]interface{}, error) {
+	objectJSON, err := json.Marshal(objectMap)
+	if err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(objectJSON))
+	decoder.UseNumber()
+	var decoded map[string]interface{}
+	err = decoder.Decode(&decoded)
+	if err != nil {
+		return nil, err
+	}
+	return decoded, nil
+}
+
+func fromJSONMap(objectMap map[string]interface{}, object interface{}) error {
+	objectJSON, err := json.Marshal(objectMap)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(objectJSON, object)
+}
@@ -0,0 +1,161 @@
+package netboxtest
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/src-doo/netbox-ssot/internal/constants"
+	"github.com/src-doo/netbox-ssot/internal/netbox/mapper"
+	"github.com/src-doo/netbox-ssot/internal/netbox/objects"
+)
+
+type fieldKind int
+
+const (
+	// Scalar or opaque value, stored and returned as is.
+	fieldPlain fieldKind = iota
+	// Id of a related object, returned as nested object.
+	fieldReference
+	// List of ids of related objects, returned as list of nested objects.
+	fieldReferenceList
+	// Value of a choice, returned as {"value": ..., "label": ...}.
+	fieldChoice
+)
+
+type field struct {
+	kind fieldKind
+	// Api path of the related object. Empty if it is unknown.
+	path constants.APIPath
+}
+
+// objectSchema maps json names of object attributes to their kind.
+type objectSchema map[string]field
+
+// uniqueTogether lists attributes, that must be unique (together) for each api path.
+var uniqueTogether = map[constants.APIPath][][]string{
+	constants.TagsAPIPath:                  {{"name"}, {"slug"}},
+	constants.CustomFieldsAPIPath:          {{"name"}},
+	constants.TenantsAPIPath:               {{"name"}, {"slug"}},
+	constants.TenantGroupsAPIPath:          {{"name"}, {"slug"}},
+	constants.ContactGroupsAPIPath:         {{"name"}, {"slug"}},
+	constants.ContactRolesAPIPath:          {{"name"}, {"slug"}},
+	constants.ContactAssignmentsAPIPath:    {{"object_type", "object_id", "contact", "role"}},
+	constants.SitesAPIPath:                 {{"name"}, {"slug"}},
+	constants.SiteGroupsAPIPath:            {{"name"}, {"slug"}},
+	constants.RegionsAPIPath:               {{"parent", "name"}, {"parent", "slug"}},
+	constants.LocationsAPIPath:             {{"site", "name"}, {"site", "slug"}},
+	constants.ManufacturersAPIPath:         {{"name"}, {"slug"}},
+	constants.PlatformsAPIPath:             {{"name"}, {"slug"}},
+	constants.DeviceRolesAPIPath:           {{"name"}, {"slug"}},
+	constants.DeviceTypesAPIPath:           {{"manufacturer", "model"}, {"manufacturer", "slug"}},
+	constants.DevicesAPIPath:               {{"site", "tenant", "name"}},
+	constants.InterfacesAPIPath:            {{"device", "name"}},
+	constants.InventoryItemsAPIPath:        {{"device", "name"}},
+	constants.InterfaceTemplatesAPIPath:    {{"device_type", "name"}},
+	constants.ConsolePortTemplatesAPIPath:  {{"device_type", "name"}},
+	constants.PowerPortTemplatesAPIPath:    {{"device_type", "name"}},
+	constants.VirtualChassisAPIPath:        {{"name"}},
+	constants.VirtualDeviceContextsAPIPath: {{"device", "name"}},
+	constants.ClusterTypesAPIPath:          {{"name"}, {"slug"}},
+	constants.ClusterGroupsAPIPath:         {{"name"}, {"slug"}},
+	constants.ClustersAPIPath:              {{"group", "name"}},
+	constants.VirtualMachinesAPIPath:       {{"cluster", "tenant", "name"}},
+	constants.VMInterfacesAPIPath:          {{"virtual_machine", "name"}},
+	constants.VirtualDisksAPIPath:          {{"virtual_machine", "name"}},
+	constants.VlanGroupsAPIPath:            {{"name"}, {"slug"}},
+	constants.VlansAPIPath:                 {{"group", "vid"}},
+	constants.WirelessLANGroupsAPIPath:     {{"name"}, {"slug"}},
+}
+
+// cascadeFields lists references, that delete the object when the related object
+// is deleted. All other references are set to null.
+var cascadeFields = map[constants.APIPath][]string{
+	constants.InterfacesAPIPath:            {"device"},
+	constants.InventoryItemsAPIPath:        {"device"},
+	constants.InterfaceTemplatesAPIPath:    {"device_type"},
+	constants.ConsolePortTemplatesAPIPath:  {"device_type"},
+	constants.PowerPortTemplatesAPIPath:    {"device_type"},
+	constants.VirtualDeviceContextsAPIPath: {"device"},
+	constants.VMInterfacesAPIPath:          {"virtual_machine"},
+	constants.VirtualDisksAPIPath:          {"virtual_machine"},
+	constants.ContactAssignmentsAPIPath:    {"contact"},
+}
+
+type apiPathItem interface {
+	GetAPIPath() constants.APIPath
+}
+
+// buildSchemas derives schemas of all objects known to the mapper,
+// and of all objects they reference, from their go structs.
+func buildSchemas() map[constants.APIPath]objectSchema {
+	schemas := map[constants.APIPath]objectSchema{}
+	for objectType, path := range mapper.Type2Path {
+		addSchema(schemas, objectType, path)
+	}
+	return schemas
+}
+
+func addSchema(schemas map[constants.APIPath]objectSchema, objectType reflect.Type, path constants.APIPath) {
+	if _, ok := schemas[path]; ok {
+		return
+	}
+	schema := objectSchema{}
+	schemas[path] = schema
+	addFields(schemas, schema, objectType)
+}
+
+func addFields(schemas map[constants.APIPath]objectSchema, schema objectSchema, objectType reflect.Type) {
+	for i := 0; i < objectType.NumField(); i++ {
+		structField := objectType.Field(i)
+		if structField.Anonymous {
+			addFields(schemas, schema, structField.Type)
+			continue
+		}
+		jsonName := strings.Split(structField.Tag.Get("json"), ",")[0]
+		if jsonName == "" || jsonName == "-" || jsonName == "id" {
+			continue
+		}
+		fieldType := structField.Type
+		kind := fieldReference
+		if fieldType.Kind() == reflect.Slice {
+			fieldType = fieldType.Elem()
+			kind = fieldReferenceList
+		}
+		if fieldType.Kind() == reflect.Ptr {
+			fieldType = fieldType.Elem()
+		}
+		switch {
+		case fieldType.Kind() != reflect.Struct:
+			schema[jsonName] = field{kind: fieldPlain}
+		case isChoice(fieldType):
+			schema[jsonName] = field{kind: fieldChoice}
+		case hasID(fieldType):
+			relatedPath := relatedAPIPath(fieldType)
+			if relatedPath != "" {
+				addSchema(schemas, fieldType, relatedPath)
+			}
+			schema[jsonName] = field{kind: kind, path: relatedPath}
+		default:
+			schema[jsonName] = field{kind: fieldPlain}
+		}
+	}
+}
+
+func isChoice(structType reflect.Type) bool {
+	return structType.NumField() > 0 && structType.Field(0).Type == reflect.TypeOf(objects.Choice{})
+}
+
+func hasID(structType reflect.Type) bool {
+	_, ok := structType.FieldByName("ID")
+	return ok
+}
+
+func relatedAPIPath(structType reflect.Type) constants.APIPath {
+	if path, ok := mapper.Type2Path[structType]; ok {
+		return path
+	}
+	if item, ok := reflect.New(structType).Interface().(apiPathItem); ok {
+		return item.GetAPIPath()
+	}
+	return ""
+}
@@ -0,0 +1,827 @@
+// Package netboxtest provides a stateful in-memory emulator of the Netbox API
+// for tests. Unlike service.CreateMockServer, which returns canned responses,
+// it stores written objects, so tests can run the whole inventory init,
+// source sync and orphan removal against it and assert the resulting state.
+//
+// Emulated are only the parts of the API used by netbox-ssot: listing with
+// pagination, filters and field selection (fields and brief), retrieving,
+// creating, patching and deleting objects, expansion of related objects into
+// nested objects, basic unique constraints and permissions denied to the api token.
+package netboxtest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/src-doo/netbox-ssot/internal/constants"
+	"github.com/src-doo/netbox-ssot/internal/netbox/mapper"
+)
+
+// DefaultVersion is the netbox version reported by the emulator.
+const DefaultVersion = "4.3.0"
+
+const (
+	defaultPageSize = 50
+	maxPageSize     = 1000
+	// Depth of related objects expanded into nested objects.
+	nestedDepth = 2
+)
+
+// Query parameters, that are not filters.
+var nonFilterParams = map[string]bool{
+	"limit":    true,
+	"offset":   true,
+	"fields":   true,
+	"brief":    true,
+	"ordering": true,
+	"q":        true,
+}
+
+// Attributes of objects returned in brief mode.
+var briefFields = []string{"id", "url", "display", "name", "slug", "description"}
+
+// Labels of choices used by the inventory itself, that can't be
+// derived from their values.
+var defaultChoiceLabels = map[string]string{
+	"boolean":  "Boolean (true/false)",
+	"longtext": "Text (long)",
+}
+
+// Server is a stateful in-memory emulator of the Netbox API.
+type Server struct {
+	*httptest.Server
+	// Version is reported by the status endpoint.
+	Version string
+	// ChoiceLabels maps values of choice fields to their labels.
+	// Labels of values, that are not in the map, are derived
+	// from the value (e.g. front-to-rear -> Front to rear).
+	ChoiceLabels map[string]string
+	// DeniedPermissions are names of permissions (e.g. dcim.add_interface),
+	// requests requiring them are denied, like for a token without them.
+	DeniedPermissions map[string]bool
+
+	lock    sync.Mutex
+	schemas map[constants.APIPath]objectSchema
+	objects map[constants.APIPath]map[int]map[string]interface{}
+	lastIDs map[constants.APIPath]int
+}
+
+// NewServer starts a new empty emulator. It must be closed with Close.
+func NewServer() *Server {
+	s := &Server{
+		Version:      DefaultVersion,
+		ChoiceLabels: maps.Clone(defaultChoiceLabels),
+		schemas:      buildSchemas(),
+		objects:      map[constants.APIPath]map[int]map[string]interface{}{},
+		lastIDs:      map[constants.APIPath]int{},
+	}
+	s.Server = httptest.NewServer(s)
+	return s
+}
+
+// apiError is returned as the body of the failed request.
+type apiError map[string]interface{}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if strings.TrimSuffix(r.URL.Path, "/") == "/api/status" {
+		writeJSON(w, http.StatusOK, map[string]string{"netbox-version": s.Version})
+		return
+	}
+	path, id, ok := s.route(r.URL.Path)
+	if !ok {
+		writeJSON(w, http.StatusNotFound, apiError{"detail": "Not found."})
+		return
+	}
+	if s.DeniedPermissions[permissionName(path, r.Method)] {
+		writeJSON(w, http.StatusForbidden, apiError{
+			"detail": "You do not have permission to perform this action.",
+		})
+		return
+	}
+
+	switch {
+	case r.Method == http.MethodGet && id == 0:
+		s.list(w, r, path)
+	case r.Method == http.MethodGet:
+		object, ok := s.objects[path][id]
+		if !ok {
+			writeJSON(w, http.StatusNotFound, apiError{"detail": "No object matches the given query."})
+			return
+		}
+		writeJSON(w, http.StatusOK, selectFields(s.expand(path, object, nestedDepth), r.URL.Query()))
+	case r.Method == http.MethodPost && id == 0:
+		s.create(w, r, path)
+	case r.Method == http.MethodPatch && id == 0:
+		s.bulkPatch(w, r, path)
+	case r.Method == http.MethodPatch:
+		s.patch(w, r, path, id)
+	case r.Method == http.MethodDelete && id == 0:
+		s.bulkDelete(w, r, path)
+	case r.Method == http.MethodDelete:
+		if _, ok := s.objects[path][id]; !ok {
+			writeJSON(w, http.StatusNotFound, apiError{"detail": "No object matches the given query."})
+			return
+		}
+		s.delete(path, id)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeJSON(w, http.StatusMethodNotAllowed, apiError{"detail": fmt.Sprintf("Method %q not allowed.", r.Method)})
+	}
+}
+
+// permissionMethodActions maps http methods to actions of netbox permissions.
+var permissionMethodActions = map[string]string{
+	http.MethodGet:    "view",
+	http.MethodPost:   "add",
+	http.MethodPatch:  "change",
+	http.MethodDelete: "delete",
+}
+
+// permissionName returns the name of the permission required
+// for the request with the method on objects of the api path.
+func permissionName(path constants.APIPath, method string) string {
+	contentType, ok := mapper.ContentType(path)
+	if !ok {
+		return ""
+	}
+	appLabel, model, _ := strings.Cut(string(contentType), ".")
+	return fmt.Sprintf("%s.%s_%s", appLabel, permissionMethodActions[method], model)
+}
+
+// route splits the url path into the api path of the objects and the object id.
+func (s *Server) route(urlPath string) (constants.APIPath, int, bool) {
+	if !strings.HasSuffix(urlPath, "/") {
+		urlPath += "/"
+	}
+	if _, ok := s.schemas[constants.APIPath(urlPath)]; ok {
+		return constants.APIPath(urlPath), 0, true
+	}
+	trimmed := strings.TrimSuffix(urlPath, "/")
+	slash := strings.LastIndex(trimmed, "/")
+	id, err := strconv.Atoi(trimmed[slash+1:])
+	if err != nil || id <= 0 {
+		return "", 0, false
+	}
+	path := constants.APIPath(trimmed[:slash+1])
+	if _, ok := s.schemas[path]; !ok {
+		return "", 0, false
+	}
+	return path, id, true
+}
+
+func (s *Server) list(w http.ResponseWriter, r *http.Request, path constants.APIPath) {
+	query := r.URL.Query()
+	limit, err := queryInt(query.Get("limit"), defaultPageSize)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, apiError{"limit": []string{err.Error()}})
+		return
+	}
+	if limit == 0 || limit > maxPageSize {
+		limit = maxPageSize
+	}
+	offset, err := queryInt(query.Get("offset"), 0)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, apiError{"offset": []string{err.Error()}})
+		return
+	}
+
+	ids := make([]int, 0, len(s.objects[path]))
+	for id, object := range s.objects[path] {
+		if s.matches(path, id, object, r.URL.Query()) {
+			ids = append(ids, id)
+		}
+	}
+	sort.Ints(ids)
+
+	results := []map[string]interface{}{}
+	for i := offset; i < len(ids) && i < offset+limit; i++ {
+		results = append(results, selectFields(s.expand(path, s.objects[path][ids[i]], nestedDepth), query))
+	}
+	response := map[string]interface{}{
+		"count":    len(ids),
+		"next":     nil,
+		"previous": nil,
+		"results":  results,
+	}
+	if offset+limit < len(ids) {
+		response["next"] = s.pageURL(r, limit, offset+limit)
+	}
+	if offset > 0 {
+		response["previous"] = s.pageURL(r, limit, max(offset-limit, 0))
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+// selectFields reduces the object to the attributes requested with the fields
+// parameter (e.g. fields=id,name), or to the brief attributes with brief=true.
+// Like in netbox, fields takes precedence over brief.
+func selectFields(object map[string]interface{}, query url.Values) map[string]interface{} {
+	var fields []string
+	switch {
+	case query.Get("fields") != "":
+		fields = strings.Split(query.Get("fields"), ",")
+	case query.Get("brief") == "true" || query.Get("brief") == "1":
+		fields = briefFields
+	default:
+		return object
+	}
+	selected := make(map[string]interface{}, len(fields))
+	for _, field := range fields {
+		if value, ok := object[field]; ok {
+			selected[field] = value
+		}
+	}
+	return selected
+}
+
+func (s *Server) pageURL(r *http.Request, limit int, offset int) string {
+	query := r.URL.Query()
+	query.Set("limit", strconv.Itoa(limit))
+	query.Set("offset", strconv.Itoa(offset))
+	return fmt.Sprintf("%s%s?%s", s.URL, r.URL.Path, query.Encode())
+}
+
+// matches reports whether the object matches all filters in query.
+// Supported are filters by id, tag (slug), related object id (e.g. site_id),
+// last_updated__gte and attribute value (e.g. name). Unknown filters are ignored.
+func (s *Server) matches(
+	path constants.APIPath,
+	id int,
+	object map[string]interface{},
+	query map[string][]string,
+) bool {
+	schema := s.schemas[path]
+	for param, values := range query {
+		if nonFilterParams[param] {
+			continue
+		}
+		var objectValues []string
+		switch {
+		case param == "last_updated__gte":
+			if !updatedSince(object, values) {
+				return false
+			}
+			continue
+		case param == "id":
+			objectValues = []string{strconv.Itoa(id)}
+		case param == "tag":
+			for _, tagID := range referenceIDs(object["tags"]) {
+				objectValues = append(objectValues, fmt.Sprint(s.objects[constants.TagsAPIPath][tagID]["slug"]))
+			}
+		case strings.HasSuffix(param, "_id") && schema[strings.TrimSuffix(param, "_id")].kind != fieldPlain:
+			for _, relatedID := range referenceIDs(object[strings.TrimSuffix(param, "_id")]) {
+				objectValues = append(objectValues, strconv.Itoa(relatedID))
+			}
+		default:
+			schemaField, ok := schema[param]
+			if !ok {
+				continue
+			}
+			switch schemaField.kind {
+			case fieldReference, fieldReferenceList:
+				for _, relatedID := range referenceIDs(object[param]) {
+					objectValues = append(objectValues, fmt.Sprint(s.objects[schemaField.path][relatedID]["slug"]))
+				}
+			default:
+				if value, ok := object[param]; ok && value != nil {
+					objectValues = []string{fmt.Sprint(value)}
+				}
+			}
+		}
+		if !containsAny(objectValues, values) {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *Server) create(w http.ResponseWriter, r *http.Request, path constants.APIPath) {
+	var body interface{}
+	err := decodeJSON(r, &body)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, apiError{"detail": err.Error()})
+		return
+	}
+
+	// Netbox supports bulk creation by posting a list of objects
+	if bodyList, isList := body.([]interface{}); isList {
+		created := make([]map[string]interface{}, 0, len(bodyList))
+		for _, item := range bodyList {
+			object, ok := item.(map[string]interface{})
+			if !ok {
+				writeJSON(w, http.StatusBadRequest, apiError{"detail": "Invalid data."})
+				return
+			}
+			id, validationErr := s.insert(path, object)
+			if validationErr != nil {
+				writeJSON(w, http.StatusBadRequest, validationErr)
+				return
+			}
+			created = append(created, s.expand(path, s.objects[path][id], nestedDepth))
+		}
+		writeJSON(w, http.StatusCreated, created)
+		return
+	}
+
+	object, ok := body.(map[string]interface{})
+	if !ok {
+		writeJSON(w, http.StatusBadRequest, apiError{"detail": "Invalid data."})
+		return
+	}
+	id, validationErr := s.insert(path, object)
+	if validationErr != nil {
+		writeJSON(w, http.StatusBadRequest, validationErr)
+		return
+	}
+	writeJSON(w, http.StatusCreated, s.expand(path, s.objects[path][id], nestedDepth))
+}
+
+// insert validates and stores a new object and returns its id.
+func (s *Server) insert(path constants.APIPath, object map[string]interface{}) (int, apiError) {
+	object, validationErr := s.normalize(path, object)
+	if validationErr != nil {
+		return 0, validationErr
+	}
+	validationErr = s.checkUnique(path, 0, object)
+	if validationErr != nil {
+		return 0, validationErr
+	}
+	s.lastIDs[path]++
+	id := s.lastIDs[path]
+	object["id"] = id
+	touch(object)
+	if s.objects[path] == nil {
+		s.objects[path] = map[int]map[string]interface{}{}
+	}
+	s.objects[path][id] = object
+	if path == constants.DevicesAPIPath {
+		s.instantiateInterfaces(id, object)
+	}
+	return id, nil
+}
+
+// instantiateInterfaces creates interfaces of the new device from
+// interface templates of its device type, like netbox does.
+func (s *Server) instantiateInterfaces(deviceID int, device map[string]interface{}) {
+	templateIDs := slices.Sorted(maps.Keys(s.objects[constants.InterfaceTemplatesAPIPath]))
+	for _, templateID := range templateIDs {
+		template := s.objects[constants.InterfaceTemplatesAPIPath][templateID]
+		if !slices.Equal(referenceIDs(template["device_type"]), referenceIDs(device["device_type"])) {
+			continue
+		}
+		iface := map[string]interface{}{"device": deviceID, "enabled": true}
+		for _, attribute := range []string{"name", "label", "type", "mgmt_only"} {
+			if value, ok := template[attribute]; ok {
+				iface[attribute] = value
+			}
+		}
+		_, _ = s.insert(constants.InterfacesAPIPath, iface)
+	}
+}
+
+func (s *Server) patch(w http.ResponseWriter, r *http.Request, path constants.APIPath, id int) {
+	var changes map[string]interface{}
+	err := decodeJSON(r, &changes)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, apiError{"detail": err.Error()})
+		return
+	}
+	patched, statusCode, validationErr := s.update(path, id, changes)
+	if validationErr != nil {
+		writeJSON(w, statusCode, validationErr)
+		return
+	}
+	writeJSON(w, http.StatusOK, s.expand(path, patched, nestedDepth))
+}
+
+// bulkPatch patches a list of objects, each identified by its id.
+func (s *Server) bulkPatch(w http.ResponseWriter, r *http.Request, path constants.APIPath) {
+	var body []map[string]interface{}
+	err := decodeJSON(r, &body)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, apiError{"detail": err.Error()})
+		return
+	}
+	patchedList := make([]map[string]interface{}, 0, len(body))
+	for _, changes := range body {
+		id, ok := toID(changes["id"])
+		if !ok {
+			writeJSON(w, http.StatusBadRequest, apiError{"id": []string{"This field is required."}})
+			return
+		}
+		delete(changes, "id")
+		patched, statusCode, validationErr := s.update(path, id, changes)
+		if validationErr != nil {
+			writeJSON(w, statusCode, validationErr)
+			return
+		}
+		patchedList = append(patchedList, s.expand(path, patched, nestedDepth))
+	}
+	writeJSON(w, http.StatusOK, patchedList)
+}
+
+// update validates and applies changes to the stored object and returns it.
+func (s *Server) update(
+	path constants.APIPath,
+	id int,
+	changes map[string]interface{},
+) (map[string]interface{}, int, apiError) {
+	existing, ok := s.objects[path][id]
+	if !ok {
+		return nil, http.StatusNotFound, apiError{"detail": "No object matches the given query."}
+	}
+	changes, validationErr := s.normalize(path, changes)
+	if validationErr != nil {
+		return nil, http.StatusBadRequest, validationErr
+	}
+
+	patched := make(map[string]interface{}, len(existing)+len(changes))
+	for key, value := range existing {
+		patched[key] = value
+	}
+	for key, value := range changes {
+		// Custom fields are merged, like in netbox
+		if key == "custom_fields" {
+			patched[key] = mergeMaps(existing[key], value)
+			continue
+		}
+		patched[key] = value
+	}
+	validationErr = s.checkUnique(path, id, patched)
+	if validationErr != nil {
+		return nil, http.StatusBadRequest, validationErr
+	}
+	touch(patched)
+	s.objects[path][id] = patched
+	return patched, http.StatusOK, nil
+}
+
+func (s *Server) bulkDelete(w http.ResponseWriter, r *http.Request, path constants.APIPath) {
+	var body []map[string]interface{}
+	err := decodeJSON(r, &body)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, apiError{"detail": err.Error()})
+		return
+	}
+	ids := make([]int, 0, len(body))
+	for _, item := range body {
+		id, ok := toID(item["id"])
+		if !ok {
+			writeJSON(w, http.StatusBadRequest, apiError{"id": []string{"This field is required."}})
+			return
+		}
+		if _, ok := s.objects[path][id]; !ok {
+			writeJSON(w, http.StatusNotFound, apiError{"detail": "No object matches the given query."})
+			return
+		}
+		ids = append(ids, id)
+	}
+	for _, id := range ids {
+		s.delete(path, id)
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// delete removes the object. Objects whose cascading reference points
+// to it are deleted too, other references to it are cleared.
+func (s *Server) delete(path constants.APIPath, id int) {
+	if _, ok := s.objects[path][id]; !ok {
+		return
+	}
+	delete(s.objects[path], id)
+	for relatedPath, schema := range s.schemas {
+		for relatedID, object := range s.objects[relatedPath] {
+			for attribute, schemaField := range schema {
+				if schemaField.path != path {
+					continue
+				}
+				switch schemaField.kind {
+				case fieldReference:
+					if referencedID, ok := toID(object[attribute]); !ok || referencedID != id {
+						continue
+					}
+					if slices.Contains(cascadeFields[relatedPath], attribute) {
+						s.delete(relatedPath, relatedID)
+					} else {
+						object[attribute] = nil
+						touch(object)
+					}
+				case fieldReferenceList:
+					referencedIDs := referenceIDs(object[attribute])
+					remaining := []interface{}{}
+					for _, referencedID := range referencedIDs {
+						if referencedID != id {
+							remaining = append(remaining, referencedID)
+						}
+					}
+					if len(remaining) != len(referencedIDs) {
+						object[attribute] = remaining
+						touch(object)
+					}
+				}
+			}
+		}
+	}
+}
+
+// normalize converts related objects to their ids and choices to their values.
+// It returns validation errors for references to objects that don't exist.
+func (s *Server) normalize(path constants.APIPath, object map[string]interface{}) (map[string]interface{}, apiError) {
+	schema := s.schemas[path]
+	normalized := make(map[string]interface{}, len(object))
+	fieldErrors := apiError{}
+	for attribute, value := range object {
+		if attribute == "id" {
+			continue
+		}
+		schemaField := schema[attribute]
+		if value == nil {
+			normalized[attribute] = nil
+			continue
+		}
+		switch schemaField.kind {
+		case fieldReference:
+			id, ok := toID(value)
+			if !ok {
+				fieldErrors[attribute] = []string{"Incorrect type. Expected pk value."}
+				continue
+			}
+			if !s.exists(schemaField.path, id) {
+				fieldErrors[attribute] = []string{fmt.Sprintf("Invalid pk \"%d\" - object does not exist.", id)}
+				continue
+			}
+			normalized[attribute] = id
+		case fieldReferenceList:
+			values, ok := value.([]interface{})
+			if !ok {
+				fieldErrors[attribute] = []string{"Expected a list of items."}
+				continue
+			}
+			ids := make([]interface{}, 0, len(values))
+			for _, item := range values {
+				id, ok := toID(item)
+				if !ok || !s.exists(schemaField.path, id) {
+					fieldErrors[attribute] = []string{fmt.Sprintf("Related object not found using the provided attributes: %v", item)}
+					break
+				}
+				ids = append(ids, id)
+			}
+			normalized[attribute] = ids
+		case fieldChoice:
+			if choice, ok := value.(map[string]interface{}); ok {
+				value = choice["value"]
+			}
+			normalized[attribute] = value
+		default:
+			normalized[attribute] = value
+		}
+	}
+	if len(fieldErrors) > 0 {
+		return nil, fieldErrors
+	}
+	return normalized, nil
+}
+
+// exists reports whether the related object exists. References
+// to objects of unknown api paths are always accepted.
+func (s *Server) exists(path constants.APIPath, id int) bool {
+	if path == "" {
+		return true
+	}
+	_, ok := s.objects[path][id]
+	return ok
+}
+
+// checkUnique returns a validation error, if the object violates
+// unique constraints of its api path. Object with id is excluded
+// from the check, so objects can be patched.
+func (s *Server) checkUnique(path constants.APIPath, id int, object map[string]interface{}) apiError {
+	for _, attributes := range uniqueTogether[path] {
+		values := make([]string, 0, len(attributes))
+		for _, attribute := range attributes {
+			value := object[attribute]
+			if value == nil || value == "" {
+				break
+			}
+			values = append(values, fmt.Sprint(value))
+		}
+		// Null values never violate unique constraints
+		if len(values) != len(attributes) {
+			continue
+		}
+		for existingID, existing := range s.objects[path] {
+			if existingID == id || !equalValues(existing, attributes, values) {
+				continue
+			}
+			if len(attributes) == 1 {
+				return apiError{
+					attributes[0]: []string{fmt.Sprintf("object with this %s already exists.", attributes[0])},
+				}
+			}
+			return apiError{
+				"non_field_errors": []string{
+					fmt.Sprintf("The fields %s must make a unique set.", strings.Join(attributes, ", ")),
+				},
+			}
+		}
+	}
+	return nil
+}
+
+// expand returns the object as it is returned by netbox: with its id,
+// related objects expanded into nested objects and choices with labels.
+// Nested objects are expanded up to depth, deeper references are omitted.
+func (s *Server) expand(path constants.APIPath, object map[string]interface{}, depth int) map[string]interface{} {
+	id, _ := toID(object["id"])
+	schema := s.schemas[path]
+	expanded := map[string]interface{}{
+		"id":  id,
+		"url": fmt.Sprintf("%s%s%d/", s.URL, path, id),
+	}
+	for attribute, value := range object {
+		schemaField := schema[attribute]
+		switch {
+		case value == nil:
+			expanded[attribute] = nil
+		case schemaField.kind == fieldReference:
+			if depth == 0 {
+				continue
+			}
+			expanded[attribute] = s.expandReference(schemaField.path, value, depth-1)
+		case schemaField.kind == fieldReferenceList:
+			if depth == 0 {
+				continue
+			}
+			nested := []interface{}{}
+			for _, relatedID := range referenceIDs(value) {
+				nested = append(nested, s.expandReference(schemaField.path, relatedID, depth-1))
+			}
+			expanded[attribute] = nested
+		case schemaField.kind == fieldChoice:
+			expanded[attribute] = map[string]interface{}{"value": value, "label": s.choiceLabel(fmt.Sprint(value))}
+		default:
+			expanded[attribute] = value
+		}
+	}
+	return expanded
+}
+
+func (s *Server) expandReference(path constants.APIPath, value interface{}, depth int) map[string]interface{} {
+	id, _ := toID(value)
+	related, ok := s.objects[path][id]
+	if !ok {
+		return map[string]interface{}{"id": id}
+	}
+	return s.expand(path, related, depth)
+}
+
+func (s *Server) choiceLabel(value string) string {
+	if label, ok := s.ChoiceLabels[value]; ok {
+		return label
+	}
+	label := []rune(strings.NewReplacer("-", " ", "_", " ").Replace(value))
+	if len(label) > 0 {
+		label[0] = unicode.ToUpper(label[0])
+	}
+	return string(label)
+}
+
+// touch sets last_updated of the changed object to the current time.
+func touch(object map[string]interface{}) {
+	object["last_updated"] = time.Now().UTC().Format(time.RFC3339Nano)
+}
+
+// updatedSince reports whether the object was last updated at or after any of the times.
+func updatedSince(object map[string]interface{}, times []string) bool {
+	lastUpdated, err := time.Parse(time.RFC3339Nano, fmt.Sprint(object["last_updated"]))
+	if err != nil {
+		return false
+	}
+	for _, since := range times {
+		sinceTime, err := time.Parse(time.RFC3339Nano, since)
+		if err == nil && !lastUpdated.Before(sinceTime) {
+			return true
+		}
+	}
+	return false
+}
+
+func decodeJSON(r *http.Request, v interface{}) error {
+	decoder := json.NewDecoder(r.Body)
+	decoder.UseNumber()
+	err := decoder.Decode(v)
+	if err != nil {
+		return fmt.Errorf("JSON parse error - %s", err)
+	}
+	return nil
+}
+
+func writeJSON(w http.ResponseWriter, statusCode int, v interface{}) {
+	var body bytes.Buffer
+	err := json.NewEncoder(&body).Encode(v)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	_, _ = w.Write(body.Bytes())
+}
+
+// toID converts a reference (id or nested object with id) to the id.
+func toID(value interface{}) (int, bool) {
+	switch v := value.(type) {
+	case int:
+		return v, true
+	case int64:
+		return int(v), true
+	case float64:
+		return int(v), v == float64(int(v))
+	case json.Number:
+		id, err := strconv.Atoi(v.String())
+		return id, err == nil
+	case map[string]interface{}:
+		return toID(v["id"])
+	default:
+		return 0, false
+	}
+}
+
+func referenceIDs(value interface{}) []int {
+	if values, ok := value.([]interface{}); ok {
+		ids := make([]int, 0, len(values))
+		for _, item := range values {
+			if id, ok := toID(item); ok {
+				ids = append(ids, id)
+			}
+		}
+		return ids
+	}
+	if id, ok := toID(value); ok {
+		return []int{id}
+	}
+	return nil
+}
+
+func queryInt(value string, defaultValue int) (int, error) {
+	if value == "" {
+		return defaultValue, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("a valid non-negative integer is required")
+	}
+	return n, nil
+}
+
+func mergeMaps(existing interface{}, changes interface{}) interface{} {
+	existingMap, ok := existing.(map[string]interface{})
+	if !ok {
+		return changes
+	}
+	changesMap, ok := changes.(map[string]interface{})
+	if !ok {
+		return changes
+	}
+	merged := make(map[string]interface{}, len(existingMap)+len(changesMap))
+	for key, value := range existingMap {
+		merged[key] = value
+	}
+	for key, value := range changesMap {
+		merged[key] = value
+	}
+	return merged
+}
+
+func equalValues(object map[string]interface{}, attributes []string, values []string) bool {
+	for i, attribute := range attributes {
+		if object[attribute] == nil || fmt.Sprint(object[attribute]) != values[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func containsAny(objectValues []string, values []string) bool {
+	for _, value := range values {
+		if slices.Contains(objectValues, value) {
+			return true
+		}
+	}
+	return false
+}
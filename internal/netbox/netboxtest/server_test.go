@@ -0,0 +1,286 @@
+package netboxtest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/src-doo/netbox-ssot/internal/constants"
+	"github.com/src-doo/netbox-ssot/internal/logger"
+	"github.com/src-doo/netbox-ssot/internal/netbox/objects"
+	"github.com/src-doo/netbox-ssot/internal/netbox/service"
+)
+
+func newTestClient(s *Server) *service.NetboxClient {
+	return &service.NetboxClient{
+		HTTPClient: s.Client(),
+		Logger:     &logger.Logger{Logger: log.Default()},
+		BaseURL:    s.URL,
+		Timeout:    constants.DefaultAPITimeout,
+	}
+}
+
+func TestServer_CreateAndList(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	ctx := context.Background()
+	api := newTestClient(s)
+
+	version, err := service.GetVersion(ctx, api)
+	if err != nil || version != DefaultVersion {
+		t.Fatalf("GetVersion() = %s, %v, want %s", version, err, DefaultVersion)
+	}
+
+	// More tags than fit on a single page of GetAll
+	const tagCount = 300
+	for i := 0; i < tagCount; i++ {
+		tag, err := service.Create(ctx, api, &objects.Tag{Name: fmt.Sprintf("tag%d", i), Slug: fmt.Sprintf("tag%d", i)})
+		if err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+		if tag.ID != i+1 {
+			t.Fatalf("Create() id = %d, want %d", tag.ID, i+1)
+		}
+	}
+	tags, err := service.GetAll[objects.Tag](ctx, api, "")
+	if err != nil {
+		t.Fatalf("GetAll() error = %v", err)
+	}
+	if len(tags) != tagCount {
+		t.Errorf("GetAll() returned %d tags, want %d", len(tags), tagCount)
+	}
+
+	filtered, err := service.GetAll[objects.Tag](ctx, api, "&name=tag7&id=8&id=9")
+	if err != nil {
+		t.Fatalf("GetAll() error = %v", err)
+	}
+	if len(filtered) != 1 || filtered[0].Name != "tag7" {
+		t.Errorf("GetAll() with filters = %v, want only tag7", filtered)
+	}
+
+	_, err = service.Create(ctx, api, &objects.Tag{Name: "tag1", Slug: "other"})
+	var validationErr *service.ValidationError
+	if !errors.As(err, &validationErr) || validationErr.FieldErrors["name"] == nil {
+		t.Errorf("Create() of duplicate tag error = %v, want name validation error", err)
+	}
+}
+
+func TestServer_Fields(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	tag, err := Seed(s, &objects.Tag{Name: "ssot", Slug: "ssot", Color: "00add8", Description: "ssot tag"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		query  string
+		want   string
+		single bool
+	}{
+		{name: "Fields", query: "?fields=id,name", want: "id,name"},
+		{name: "Brief", query: "?brief=true", want: "description,id,name,slug,url"},
+		{name: "Fields take precedence over brief", query: "?brief=true&fields=id", want: "id"},
+		{name: "Fields of single object", query: "?fields=slug", want: "slug", single: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			url := s.URL + string(constants.TagsAPIPath)
+			if tt.single {
+				url += fmt.Sprintf("%d/", tag.ID)
+			}
+			resp, err := s.Client().Get(url + tt.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+			if resp.StatusCode != http.StatusOK {
+				t.Fatalf("GET %s status = %d, want %d", tt.query, resp.StatusCode, http.StatusOK)
+			}
+			var object map[string]json.RawMessage
+			if tt.single {
+				err = json.NewDecoder(resp.Body).Decode(&object)
+			} else {
+				var list struct {
+					Results []map[string]json.RawMessage `json:"results"`
+				}
+				err = json.NewDecoder(resp.Body).Decode(&list)
+				if err == nil && len(list.Results) != 1 {
+					t.Fatalf("GET %s returned %d objects, want 1", tt.query, len(list.Results))
+				}
+				if err == nil {
+					object = list.Results[0]
+				}
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			fields := make([]string, 0, len(object))
+			for field := range object {
+				fields = append(fields, field)
+			}
+			sort.Strings(fields)
+			if got := strings.Join(fields, ","); got != tt.want {
+				t.Errorf("GET %s returned fields %s, want %s", tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestServer_NestedObjects(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	ctx := context.Background()
+	api := newTestClient(s)
+
+	tag, err := Seed(s, &objects.Tag{Name: "ssot", Slug: "ssot"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	manufacturer, err := Seed(s, &objects.Manufacturer{Name: "Cisco", Slug: "cisco"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	deviceType, err := Seed(s, &objects.DeviceType{Manufacturer: manufacturer, Model: "C9300", Slug: "c9300"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	site, err := Seed(s, &objects.Site{Name: "Site", Slug: "site"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	role, err := Seed(s, &objects.DeviceRole{Name: "Switch", Slug: "switch"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	device, err := service.Create(ctx, api, &objects.Device{
+		NetboxObject: objects.NetboxObject{Tags: []*objects.Tag{tag}},
+		Name:         "sw1",
+		DeviceType:   deviceType,
+		DeviceRole:   role,
+		Site:         site,
+		Status:       &objects.DeviceStatusActive,
+	})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if device.DeviceType.Manufacturer == nil || device.DeviceType.Manufacturer.Name != "Cisco" {
+		t.Errorf("device type of created device = %+v, want nested manufacturer", device.DeviceType)
+	}
+	if *device.Status != objects.DeviceStatusActive {
+		t.Errorf("status of created device = %v, want %v", device.Status, objects.DeviceStatusActive)
+	}
+	if len(device.Tags) != 1 || device.Tags[0].Slug != "ssot" {
+		t.Errorf("tags of created device = %v, want ssot", device.Tags)
+	}
+
+	filtered, err := service.GetAll[objects.Device](ctx, api, fmt.Sprintf("&tag=ssot&site_id=%d", site.ID))
+	if err != nil || len(filtered) != 1 {
+		t.Errorf("GetAll() with filters = %v, %v, want sw1", filtered, err)
+	}
+
+	_, err = service.Create(ctx, api, &objects.Device{
+		Name:       "sw2",
+		DeviceType: deviceType,
+		DeviceRole: role,
+		Site:       &objects.Site{NetboxObject: objects.NetboxObject{ID: 42}},
+	})
+	var validationErr *service.ValidationError
+	if !errors.As(err, &validationErr) || validationErr.FieldErrors["site"] == nil {
+		t.Errorf("Create() with missing site error = %v, want site validation error", err)
+	}
+}
+
+func TestServer_PatchAndDelete(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	ctx := context.Background()
+	api := newTestClient(s)
+
+	site, err := Seed(s, &objects.Site{Name: "Site", Slug: "site"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tenant, err := Seed(s, &objects.Tenant{Name: "Tenant", Slug: "tenant"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	deviceType, err := Seed(s, &objects.DeviceType{
+		Manufacturer: mustSeed(t, s, &objects.Manufacturer{Name: "Cisco", Slug: "cisco"}),
+		Model:        "C9300",
+		Slug:         "c9300",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	device, err := Seed(s, &objects.Device{
+		Name:       "sw1",
+		Site:       site,
+		Tenant:     tenant,
+		DeviceType: deviceType,
+		DeviceRole: mustSeed(t, s, &objects.DeviceRole{Name: "Switch", Slug: "switch"}),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = Seed(s, &objects.Interface{Name: "Gi1/0/1", Device: device, Type: &objects.VirtualInterfaceType})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	patched, err := service.Patch[objects.Device](ctx, api, device.ID, map[string]interface{}{
+		"serial":        "FOC123",
+		"custom_fields": map[string]interface{}{"source": "test"},
+	})
+	if err != nil {
+		t.Fatalf("Patch() error = %v", err)
+	}
+	if patched.SerialNumber != "FOC123" || patched.Name != "sw1" || patched.CustomFields["source"] != "test" {
+		t.Errorf("Patch() = %+v, want patched serial and unchanged name", patched)
+	}
+
+	err = api.DeleteObject(ctx, tenant)
+	if err != nil {
+		t.Fatalf("DeleteObject() error = %v", err)
+	}
+	devices, err := Objects[objects.Device](s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(devices) != 1 || devices[0].Tenant != nil {
+		t.Errorf("devices after deleting tenant = %v, want device without tenant", devices)
+	}
+
+	err = api.BulkDeleteObjects(ctx, constants.DevicesAPIPath, map[int]bool{device.ID: true})
+	if err != nil {
+		t.Fatalf("BulkDeleteObjects() error = %v", err)
+	}
+	interfaces, err := Objects[objects.Interface](s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(interfaces) != 0 {
+		t.Errorf("interfaces after deleting device = %v, want none", interfaces)
+	}
+	err = api.DeleteObject(ctx, device)
+	if err == nil {
+		t.Errorf("DeleteObject() of deleted device error = nil, want not found")
+	}
+}
+
+func mustSeed[T any](t *testing.T, s *Server, object *T) *T {
+	t.Helper()
+	seeded, err := Seed(s, object)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return seeded
+}
@@ -34,6 +34,8 @@ type Site struct {
 	Status *SiteStatus `json:"status,omitempty"`
 	// Tenant of the site
 	Tenant *Tenant `json:"tenant,omitempty"`
+	// Region of the site
+	Region *Region `json:"region,omitempty"`
 
 	// Physical location of the building
 	PhysicalAddress string `json:"physical_address,omitempty"`
@@ -133,6 +135,8 @@ type Region struct {
 	Name string `json:"name,omitempty"`
 	// Slug is a URL-friendly unique shorthand. This field is required.
 	Slug string `json:"slug,omitempty"`
+	// Parent region of the region
+	Parent *Region `json:"parent,omitempty"`
 }
 
 func (r Region) String() string {
@@ -159,13 +163,15 @@ func (r *Region) GetAPIPath() constants.APIPath {
 type Location struct {
 	NetboxObject
 	// Site is the site to which the location belongs. This field is required.
-	Site *Site
+	Site *Site `json:"site,omitempty"`
 	// Name is the name of the location. This field is required.
-	Name string
+	Name string `json:"name,omitempty"`
 	// URL-friendly unique shorthand. This field is required.
-	Slug string
+	Slug string `json:"slug,omitempty"`
 	// Status is the status of the location. This field is required.
-	Status *SiteStatus
+	Status *SiteStatus `json:"status,omitempty"`
+	// Parent location of the location
+	Parent *Location `json:"parent,omitempty"`
 }
 
 func (l Location) String() string {
@@ -217,6 +223,11 @@ func (m *Manufacturer) GetNetboxObject() *NetboxObject {
 	return &m.NetboxObject
 }
 
+// WeightUnit is the unit of weight of a device type, e.g. kg.
+type WeightUnit struct {
+	Choice
+}
+
 // DeviceType represents the physical and operational characteristics of a device.
 // For example, a device type may represent a Cisco C2960 switch running IOS 15.2.
 type DeviceType struct {
@@ -227,6 +238,18 @@ type DeviceType struct {
 	Model string `json:"model,omitempty"`
 	// Slug is a URL-friendly unique shorthand. This field is required.
 	Slug string `json:"slug,omitempty"`
+	// PartNumber is the discrete part number of the device type.
+	PartNumber string `json:"part_number,omitempty"`
+	// UHeight is the height of the device type in rack units.
+	UHeight float64 `json:"u_height,omitempty"`
+	// IsFullDepth is whether the device type consumes both front and rear rack faces.
+	IsFullDepth bool `json:"is_full_depth,omitempty"`
+	// Airflow is the direction of airflow through the device type.
+	Airflow *DeviceAirFlowType `json:"airflow,omitempty"`
+	// Weight of the device type in WeightUnit.
+	Weight float64 `json:"weight,omitempty"`
+	// WeightUnit is the unit of Weight.
+	WeightUnit *WeightUnit `json:"weight_unit,omitempty"`
 }
 
 func (dt DeviceType) String() string {
@@ -249,6 +272,109 @@ func (dt *DeviceType) GetNetboxObject() *NetboxObject {
 	return &dt.NetboxObject
 }
 
+// InterfaceTemplate is a template of the interface, which netbox
+// creates on every new device of the device type.
+type InterfaceTemplate struct {
+	NetboxObject
+	// DeviceType is the device type of the template. This field is required.
+	DeviceType *DeviceType `json:"device_type,omitempty"`
+	// Name is the name of the interface. This field is required.
+	Name string `json:"name,omitempty"`
+	// Label is the physical label of the interface.
+	Label string `json:"label,omitempty"`
+	// Type is the type of the interface. This field is required.
+	Type *InterfaceType `json:"type,omitempty"`
+	// MgmtOnly is whether the interface is used only for out-of-band management.
+	MgmtOnly bool `json:"mgmt_only,omitempty"`
+}
+
+func (it InterfaceTemplate) String() string {
+	return fmt.Sprintf("InterfaceTemplate{Name: %s, DeviceType: %s}", it.Name, it.DeviceType)
+}
+
+// InterfaceTemplate implements IDItem interface.
+func (it *InterfaceTemplate) GetID() int {
+	return it.ID
+}
+func (it *InterfaceTemplate) GetObjectType() constants.ContentType {
+	return constants.ContentTypeDcimInterfaceTemplate
+}
+func (it *InterfaceTemplate) GetAPIPath() constants.APIPath {
+	return constants.InterfaceTemplatesAPIPath
+}
+
+// ConsolePortType is the type of the console port, e.g. rj-45.
+type ConsolePortType struct {
+	Choice
+}
+
+// ConsolePortTemplate is a template of the console port, which netbox
+// creates on every new device of the device type.
+type ConsolePortTemplate struct {
+	NetboxObject
+	// DeviceType is the device type of the template. This field is required.
+	DeviceType *DeviceType `json:"device_type,omitempty"`
+	// Name is the name of the console port. This field is required.
+	Name string `json:"name,omitempty"`
+	// Label is the physical label of the console port.
+	Label string `json:"label,omitempty"`
+	// Type is the type of the console port.
+	Type *ConsolePortType `json:"type,omitempty"`
+}
+
+func (cpt ConsolePortTemplate) String() string {
+	return fmt.Sprintf("ConsolePortTemplate{Name: %s, DeviceType: %s}", cpt.Name, cpt.DeviceType)
+}
+
+// ConsolePortTemplate implements IDItem interface.
+func (cpt *ConsolePortTemplate) GetID() int {
+	return cpt.ID
+}
+func (cpt *ConsolePortTemplate) GetObjectType() constants.ContentType {
+	return constants.ContentTypeDcimConsolePortTemplate
+}
+func (cpt *ConsolePortTemplate) GetAPIPath() constants.APIPath {
+	return constants.ConsolePortTemplatesAPIPath
+}
+
+// PowerPortType is the type of the power port, e.g. iec-60320-c14.
+type PowerPortType struct {
+	Choice
+}
+
+// PowerPortTemplate is a template of the power port, which netbox
+// creates on every new device of the device type.
+type PowerPortTemplate struct {
+	NetboxObject
+	// DeviceType is the device type of the template. This field is required.
+	DeviceType *DeviceType `json:"device_type,omitempty"`
+	// Name is the name of the power port. This field is required.
+	Name string `json:"name,omitempty"`
+	// Label is the physical label of the power port.
+	Label string `json:"label,omitempty"`
+	// Type is the type of the power port.
+	Type *PowerPortType `json:"type,omitempty"`
+	// MaximumDraw is the maximum power draw in watts.
+	MaximumDraw int `json:"maximum_draw,omitempty"`
+	// AllocatedDraw is the allocated power draw in watts.
+	AllocatedDraw int `json:"allocated_draw,omitempty"`
+}
+
+func (ppt PowerPortTemplate) String() string {
+	return fmt.Sprintf("PowerPortTemplate{Name: %s, DeviceType: %s}", ppt.Name, ppt.DeviceType)
+}
+
+// PowerPortTemplate implements IDItem interface.
+func (ppt *PowerPortTemplate) GetID() int {
+	return ppt.ID
+}
+func (ppt *PowerPortTemplate) GetObjectType() constants.ContentType {
+	return constants.ContentTypeDcimPowerPortTemplate
+}
+func (ppt *PowerPortTemplate) GetAPIPath() constants.APIPath {
+	return constants.PowerPortTemplatesAPIPath
+}
+
 // DeviceRole represents the functional role of a device.
 // For example, a device may play the role of a router, a switch, a firewall, etc.
 type DeviceRole struct {
@@ -315,6 +441,38 @@ var (
 	}
 )
 
+// VirtualChassis represents a set of devices, which share a common control plane
+// (e.g. stacked switches or a firewall HA pair).
+type VirtualChassis struct {
+	NetboxObject
+	// Name of the VirtualChassis. This field is required.
+	Name string `json:"name,omitempty"`
+	// Domain of the VirtualChassis.
+	Domain string `json:"domain,omitempty"`
+	// Master is the member device, which is in control of the VirtualChassis.
+	Master *Device `json:"master,omitempty"`
+}
+
+func (vc VirtualChassis) String() string {
+	return fmt.Sprintf("VirtualChassis{Name: %s}", vc.Name)
+}
+
+// VirtualChassis implements IDItem interface.
+func (vc *VirtualChassis) GetID() int {
+	return vc.ID
+}
+func (vc *VirtualChassis) GetObjectType() constants.ContentType {
+	return constants.ContentTypeDcimVirtualChassis
+}
+func (vc *VirtualChassis) GetAPIPath() constants.APIPath {
+	return constants.VirtualChassisAPIPath
+}
+
+// VirtualChassis implements OrphanItem interface.
+func (vc *VirtualChassis) GetNetboxObject() *NetboxObject {
+	return &vc.NetboxObject
+}
+
 // Device can be any piece of physical hardware, such as a server, router, or switch.
 type Device struct {
 	NetboxObject
@@ -361,12 +519,13 @@ type Device struct {
 	Tenant *Tenant `json:"tenant,omitempty"`
 
 	// Virtual Chassis
+	// VirtualChassis is the virtual chassis (e.g. switch stack) the device is a member of.
+	VirtualChassis *VirtualChassis `json:"virtual_chassis,omitempty"`
+	// VCPosition is the position in the virtual chassis this device is identified by.
+	VCPosition int `json:"vc_position,omitempty"`
+	// VCPriority is the priority of the device in the virtual chassis for becoming its master.
+	VCPriority int `json:"vc_priority,omitempty"`
 
-	// The position in the virtual chassis this device is identified by
-	// Position
-
-	// The priority of the device in the virtual chassis
-	// Priority
 	// Additional comments.
 	Comments string `json:"comments,omitempty"`
 }
@@ -673,6 +832,9 @@ type Interface struct {
 	MTU int `json:"mtu,omitempty"`
 	// PrimaryMACAddress is the primary MAC address of the interface.
 	PrimaryMACAddress *MACAddress `json:"primary_mac_address,omitempty"`
+	// MAC is the MAC address of the interface. It is set only for netbox < 4.2,
+	// newer versions derive it from PrimaryMACAddress.
+	MAC string `json:"mac_address,omitempty"`
 
 	// Duplex is the duplex mode of the interface
 	Duplex *InterfaceDuplex `json:"duplex,omitempty"`
@@ -810,3 +972,122 @@ func (m *MACAddress) GetAPIPath() constants.APIPath {
 func (m *MACAddress) GetNetboxObject() *NetboxObject {
 	return &m.NetboxObject
 }
+
+// Cable status.
+type CableStatus struct {
+	Choice
+}
+
+var (
+	CableStatusConnected       = CableStatus{Choice{Value: "connected", Label: "Connected"}}
+	CableStatusPlanned         = CableStatus{Choice{Value: "planned", Label: "Planned"}}
+	CableStatusDecommissioning = CableStatus{Choice{Value: "decommissioning", Label: "Decommissioning"}}
+)
+
+// CableTermination is an object (e.g. interface) at one end of a cable.
+type CableTermination struct {
+	// ObjectType is the type of the terminated object. This field is required.
+	ObjectType constants.ContentType `json:"object_type,omitempty"`
+	// ObjectID is the ID of the terminated object. This field is required.
+	ObjectID int `json:"object_id,omitempty"`
+}
+
+// Cable represents a physical connection between two sets of terminations.
+type Cable struct {
+	NetboxObject
+	// ATerminations are objects at the A end of the cable. This field is required.
+	ATerminations []*CableTermination `json:"a_terminations,omitempty"`
+	// BTerminations are objects at the B end of the cable. This field is required.
+	BTerminations []*CableTermination `json:"b_terminations,omitempty"`
+	// Status of the cable.
+	Status *CableStatus `json:"status,omitempty"`
+	// Label of the cable.
+	Label string `json:"label,omitempty"`
+}
+
+func (c Cable) String() string {
+	aInterfaceIDs, bInterfaceIDs := c.InterfaceIDs()
+	return fmt.Sprintf(
+		"Cable{AInterfaceIDs: %v, BInterfaceIDs: %v, Label: %s}",
+		aInterfaceIDs,
+		bInterfaceIDs,
+		c.Label,
+	)
+}
+
+// InterfaceIDs returns ids of interfaces terminated at the A and B end of the cable.
+func (c Cable) InterfaceIDs() ([]int, []int) {
+	return terminatedInterfaceIDs(c.ATerminations), terminatedInterfaceIDs(c.BTerminations)
+}
+
+func terminatedInterfaceIDs(terminations []*CableTermination) []int {
+	ids := make([]int, 0, len(terminations))
+	for _, termination := range terminations {
+		if termination != nil && termination.ObjectType == constants.ContentTypeDcimInterface {
+			ids = append(ids, termination.ObjectID)
+		}
+	}
+	return ids
+}
+
+// Cable implements IDItem interface.
+func (c *Cable) GetID() int {
+	return c.ID
+}
+func (c *Cable) GetObjectType() constants.ContentType {
+	return constants.ContentTypeDcimCable
+}
+func (c *Cable) GetAPIPath() constants.APIPath {
+	return constants.CablesAPIPath
+}
+
+// Cable implements OrphanItem interface.
+func (c *Cable) GetNetboxObject() *NetboxObject {
+	return &c.NetboxObject
+}
+
+// InventoryItem represents a hardware component installed within a device,
+// such as a power supply, fan, line card or transceiver.
+type InventoryItem struct {
+	NetboxObject
+	// Device that the InventoryItem is installed in. This field is required.
+	Device *Device `json:"device,omitempty"`
+	// Name of the InventoryItem. It is unique within a device. This field is required.
+	Name string `json:"name,omitempty"`
+	// Label is the physical label of the InventoryItem.
+	Label string `json:"label,omitempty"`
+	// Manufacturer of the InventoryItem.
+	Manufacturer *Manufacturer `json:"manufacturer,omitempty"`
+	// PartID is the manufacturer-assigned part identifier.
+	PartID string `json:"part_id,omitempty"`
+	// SerialNumber of the InventoryItem.
+	SerialNumber string `json:"serial,omitempty"`
+	// Discovered is true, when the InventoryItem was discovered automatically.
+	Discovered bool `json:"discovered,omitempty"`
+}
+
+func (ii InventoryItem) String() string {
+	return fmt.Sprintf(
+		"InventoryItem{Name: %s, Device: %s, PartID: %s, SerialNumber: %s}",
+		ii.Name,
+		ii.Device,
+		ii.PartID,
+		ii.SerialNumber,
+	)
+}
+
+// InventoryItem implements IDItem interface.
+func (ii *InventoryItem) GetID() int {
+	return ii.ID
+}
+func (ii *InventoryItem) GetObjectType() constants.ContentType {
+	return constants.ContentTypeDcimInventoryItem
+}
+func (ii *InventoryItem) GetAPIPath() constants.APIPath {
+	return constants.InventoryItemsAPIPath
+}
+
+// InventoryItem implements OrphanItem interface.
+func (ii *InventoryItem) GetNetboxObject() *NetboxObject {
+	return &ii.NetboxObject
+}
@@ -181,8 +181,51 @@ func (v *Vlan) GetNetboxObject() *NetboxObject {
 	return &v.NetboxObject
 }
 
+type IPRangeStatus struct {
+	Choice
+}
+
+var (
+	IPRangeStatusActive     = IPRangeStatus{Choice{Value: "active", Label: "Active"}}
+	IPRangeStatusReserved   = IPRangeStatus{Choice{Value: "reserved", Label: "Reserved"}}
+	IPRangeStatusDeprecated = IPRangeStatus{Choice{Value: "deprecated", Label: "Deprecated"}}
+)
+
+// IPRange is a range of IP addresses, e.g. a DHCP pool.
 type IPRange struct {
 	NetboxObject
+	// StartAddress is the first IPv4 or IPv6 address of the range (with mask). This field is required.
+	StartAddress string `json:"start_address,omitempty"`
+	// EndAddress is the last IPv4 or IPv6 address of the range (with mask). This field is required.
+	EndAddress string `json:"end_address,omitempty"`
+	// Status of the IP range (default "active").
+	Status *IPRangeStatus `json:"status,omitempty"`
+	// Tenant that this IP range belongs to.
+	Tenant *Tenant `json:"tenant,omitempty"`
+	// VRF that this IP range belongs to.
+	VRF *VRF `json:"vrf,omitempty"`
+	// Comments about this IP range.
+	Comments string `json:"comments,omitempty"`
+}
+
+func (r IPRange) String() string {
+	return fmt.Sprintf("IPRange{StartAddress: %s, EndAddress: %s}", r.StartAddress, r.EndAddress)
+}
+
+// IPRange implements IDItem interface.
+func (r *IPRange) GetID() int {
+	return r.ID
+}
+func (r *IPRange) GetObjectType() constants.ContentType {
+	return constants.ContentTypeIpamIPRange
+}
+func (r *IPRange) GetAPIPath() constants.APIPath {
+	return constants.IPRangesAPIPath
+}
+
+// IPRange implements OrphanItem interface.
+func (r *IPRange) GetNetboxObject() *NetboxObject {
+	return &r.NetboxObject
 }
 
 type PrefixStatus struct {
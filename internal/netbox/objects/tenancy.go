@@ -12,7 +12,10 @@ type TenantGroup struct {
 	Name string `json:"name,omitempty"`
 	// Slug is the URL-friendly version of the tenant group name. This field is read-only.
 	Slug string `json:"slug,omitempty"`
-	// Description is a description of the tenant group.
+}
+
+func (tg TenantGroup) String() string {
+	return fmt.Sprintf("TenantGroup{Name: %s}", tg.Name)
 }
 
 // TenantGroup implements IDItem interface.
@@ -82,6 +85,9 @@ func (cg *ContactGroup) GetID() int {
 func (cg *ContactGroup) GetObjectType() constants.ContentType {
 	return constants.ContentTypeTenancyContactGroup
 }
+func (cg *ContactGroup) GetAPIPath() constants.APIPath {
+	return constants.ContactGroupsAPIPath
+}
 
 // ContactGroup implements OrphanItem interface.
 func (cg *ContactGroup) GetNetboxObject() *NetboxObject {
@@ -114,6 +120,9 @@ func (cr *ContactRole) GetID() int {
 func (cr *ContactRole) GetObjectType() constants.ContentType {
 	return constants.ContentTypeTenancyContactRole
 }
+func (cr *ContactRole) GetAPIPath() constants.APIPath {
+	return constants.ContactRolesAPIPath
+}
 
 // ContactRole implements OrphanItem interface.
 func (cr *ContactRole) GetNetboxObject() *NetboxObject {
@@ -214,6 +214,9 @@ type VMInterface struct {
 	Name string `json:"name,omitempty"`
 	// PrimaryMACAddress is the primary MAC address of the interface.
 	PrimaryMACAddress *MACAddress `json:"primary_mac_address,omitempty"`
+	// MAC is the MAC address of the interface. It is set only for netbox < 4.2,
+	// newer versions derive it from PrimaryMACAddress.
+	MAC string `json:"mac_address,omitempty"`
 	// MTU of the interface.
 	MTU int `json:"mtu,omitempty"`
 	// Enabled is true if interface is enabled, false otherwise.
@@ -0,0 +1,39 @@
+package service
+
+import (
+	"context"
+
+	"github.com/src-doo/netbox-ssot/internal/audit"
+	"github.com/src-doo/netbox-ssot/internal/constants"
+	"github.com/src-doo/netbox-ssot/internal/netbox/mapper"
+)
+
+// auditWrite records the write in the AuditLog of the client, if it is set.
+// Failed writes to the audit log are logged, but don't fail the request,
+// because the object has already been written to netbox.
+func (api *NetboxClient) auditWrite(
+	ctx context.Context,
+	action string,
+	objectPath constants.APIPath,
+	objectID int,
+	data map[string]interface{},
+) {
+	if api.AuditLog == nil {
+		return
+	}
+	objectType := string(objectPath)
+	if contentType, ok := mapper.ContentType(objectPath); ok {
+		objectType = string(contentType)
+	}
+	sourceName, _ := ctx.Value(constants.CtxSourceKey).(string)
+	err := api.AuditLog.Write(audit.Entry{
+		Source:     sourceName,
+		ObjectType: objectType,
+		ObjectID:   objectID,
+		Action:     action,
+		Data:       data,
+	})
+	if err != nil {
+		api.Logger.Errorf(ctx, "audit log: %s", err)
+	}
+}
@@ -0,0 +1,85 @@
+package service
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"log"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/src-doo/netbox-ssot/internal/audit"
+	"github.com/src-doo/netbox-ssot/internal/constants"
+	"github.com/src-doo/netbox-ssot/internal/logger"
+	"github.com/src-doo/netbox-ssot/internal/netbox/netboxtest"
+	"github.com/src-doo/netbox-ssot/internal/netbox/objects"
+)
+
+func TestNetboxClient_AuditLog(t *testing.T) {
+	s := netboxtest.NewServer()
+	defer s.Close()
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	auditLog, err := audit.Open(path, true, "run1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	api := &NetboxClient{
+		HTTPClient: s.Client(),
+		Logger:     &logger.Logger{Logger: log.Default()},
+		BaseURL:    s.URL,
+		Timeout:    constants.DefaultAPITimeout,
+		AuditLog:   auditLog,
+	}
+	ctx := context.WithValue(context.Background(), constants.CtxSourceKey, "testsource")
+
+	tag, err := Create(ctx, api, &objects.Tag{Name: "tag", Slug: "tag"})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	_, err = Patch[objects.Tag](ctx, api, tag.ID, map[string]interface{}{"description": "patched"})
+	if err != nil {
+		t.Fatalf("Patch() error = %v", err)
+	}
+	err = api.DeleteObject(ctx, tag)
+	if err != nil {
+		t.Fatalf("DeleteObject() error = %v", err)
+	}
+	// Writes in dry run mode are not audited
+	api.DryRun = true
+	_, err = Create(ctx, api, &objects.Tag{Name: "planned", Slug: "planned"})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if err = auditLog.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	var entries []audit.Entry
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var entry audit.Entry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			t.Fatal(err)
+		}
+		entries = append(entries, entry)
+	}
+	wantActions := []string{audit.ActionCreate, audit.ActionUpdate, audit.ActionDelete}
+	if len(entries) != len(wantActions) {
+		t.Fatalf("audit log has %d entries, want %d", len(entries), len(wantActions))
+	}
+	for i, entry := range entries {
+		if entry.Action != wantActions[i] || entry.ObjectType != "extras.tag" || entry.ObjectID != tag.ID ||
+			entry.Source != "testsource" || entry.RunID != "run1" {
+			t.Errorf("entry %d = %+v", i, entry)
+		}
+	}
+	if entries[0].Data["name"] != "tag" || entries[1].Data["description"] != "patched" {
+		t.Errorf("entries don't contain sent data: %+v, %+v", entries[0].Data, entries[1].Data)
+	}
+}
@@ -0,0 +1,242 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/src-doo/netbox-ssot/internal/constants"
+)
+
+// branchHeader selects the branch of the netbox branching plugin,
+// that a request is applied to.
+const branchHeader = "X-NetBox-Branch"
+
+// Statuses of a branch of the netbox branching plugin.
+const (
+	BranchStatusNew          = "new"
+	BranchStatusProvisioning = "provisioning"
+	BranchStatusReady        = "ready"
+	BranchStatusMerged       = "merged"
+	BranchStatusArchived     = "archived"
+)
+
+var (
+	// branchPollInterval is the interval, in which the status of a branch
+	// is checked while it is being provisioned.
+	branchPollInterval = 2 * time.Second
+	// branchProvisionTimeout limits how long to wait for a branch to be provisioned.
+	branchProvisionTimeout = 10 * time.Minute
+)
+
+// Branch is a branch of the netbox branching plugin.
+type Branch struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+	// SchemaID identifies the branch in the branch header.
+	SchemaID string `json:"schema_id"`
+	Status   struct {
+		Value string `json:"value"`
+	} `json:"status"`
+}
+
+// UseBranch makes all following requests of the client read from and write
+// to the branch with the given name. Reads target the branch as well, so the
+// inventory of a reused branch contains objects created in it by previous runs.
+// The branch is created, if it doesn't exist yet, and provisioned before UseBranch
+// returns. Branch names are unique, so an already merged branch is renamed to
+// <name>-merged-<id>, and a new branch is created with the given name.
+// In dry run mode, an existing branch is used, but a missing one isn't created.
+func (api *NetboxClient) UseBranch(ctx context.Context, name string) (*Branch, error) {
+	branch, err := api.getBranch(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if branch != nil && (branch.Status.Value == BranchStatusMerged || branch.Status.Value == BranchStatusArchived) {
+		if api.DryRun {
+			api.Logger.Infof(ctx, "Branch %s is already merged, planning changes against main", name)
+			return nil, nil
+		}
+		mergedName := fmt.Sprintf("%s-merged-%d", name, branch.ID)
+		err = api.renameBranch(ctx, branch, mergedName)
+		if err != nil {
+			return nil, fmt.Errorf("rename merged branch %s: %s", name, err)
+		}
+		api.Logger.Infof(ctx, "Branch %s is already merged, renamed it to %s", name, mergedName)
+		branch = nil
+	}
+	if branch == nil {
+		if api.DryRun {
+			api.Logger.Infof(ctx, "Branch %s doesn't exist, planning changes against main", name)
+			return nil, nil
+		}
+		branch, err = api.createBranch(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		api.Logger.Infof(ctx, "Created branch %s", name)
+	}
+	branch, err = api.waitForBranch(ctx, branch)
+	if err != nil {
+		return nil, err
+	}
+	api.branch = branch
+	api.Logger.Infof(ctx, "Using branch %s (%s)", branch.Name, branch.SchemaID)
+	return branch, nil
+}
+
+// Branch returns the branch used by the client, or nil if it writes to main.
+func (api *NetboxClient) Branch() *Branch {
+	return api.branch
+}
+
+// MergeBranch enqueues merging of the branch used by the client into main.
+// The merge itself is run by a netbox background job.
+func (api *NetboxClient) MergeBranch(ctx context.Context) error {
+	if api.branch == nil {
+		return errors.New("no branch is used")
+	}
+	if api.DryRun {
+		return nil
+	}
+	requestBody, err := json.Marshal(map[string]interface{}{"commit": true})
+	if err != nil {
+		return err
+	}
+	path := fmt.Sprintf("%s%d/merge/", constants.BranchesAPIPath, api.branch.ID)
+	response, err := api.doRequest(ctx, http.MethodPost, path, bytes.NewBuffer(requestBody))
+	if err != nil {
+		return err
+	}
+	if response.StatusCode != http.StatusOK && response.StatusCode != http.StatusAccepted {
+		return fmt.Errorf("unexpected status code: %d: %s", response.StatusCode, response.Body)
+	}
+	return nil
+}
+
+func (api *NetboxClient) getBranch(ctx context.Context, name string) (*Branch, error) {
+	path := fmt.Sprintf("%s?name=%s", constants.BranchesAPIPath, url.QueryEscape(name))
+	response, err := api.doRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, err
+	}
+	if response.StatusCode == http.StatusNotFound {
+		return nil, errors.New("netbox branching plugin is not installed")
+	}
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code: %d: %s", response.StatusCode, response.Body)
+	}
+	var branches Response[Branch]
+	err = json.Unmarshal(response.Body, &branches)
+	if err != nil {
+		return nil, err
+	}
+	if len(branches.Results) == 0 {
+		return nil, nil
+	}
+	return &branches.Results[0], nil
+}
+
+func (api *NetboxClient) getBranchByID(ctx context.Context, id int) (*Branch, error) {
+	path := fmt.Sprintf("%s%d/", constants.BranchesAPIPath, id)
+	response, err := api.doRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, err
+	}
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code: %d: %s", response.StatusCode, response.Body)
+	}
+	var branch Branch
+	err = json.Unmarshal(response.Body, &branch)
+	if err != nil {
+		return nil, err
+	}
+	return &branch, nil
+}
+
+func (api *NetboxClient) createBranch(ctx context.Context, name string) (*Branch, error) {
+	requestBody, err := json.Marshal(map[string]interface{}{
+		"name":        name,
+		"description": "Changes synced by netbox-ssot",
+	})
+	if err != nil {
+		return nil, err
+	}
+	response, err := api.doRequest(
+		ctx,
+		http.MethodPost,
+		string(constants.BranchesAPIPath),
+		bytes.NewBuffer(requestBody),
+	)
+	if err != nil {
+		return nil, err
+	}
+	if response.StatusCode != http.StatusCreated {
+		return nil, fmt.Errorf("unexpected status code: %d: %s", response.StatusCode, response.Body)
+	}
+	var branch Branch
+	err = json.Unmarshal(response.Body, &branch)
+	if err != nil {
+		return nil, err
+	}
+	return &branch, nil
+}
+
+// renameBranch changes the name of the branch.
+func (api *NetboxClient) renameBranch(ctx context.Context, branch *Branch, name string) error {
+	requestBody, err := json.Marshal(map[string]interface{}{"name": name})
+	if err != nil {
+		return err
+	}
+	path := fmt.Sprintf("%s%d/", constants.BranchesAPIPath, branch.ID)
+	response, err := api.doRequest(ctx, http.MethodPatch, path, bytes.NewBuffer(requestBody))
+	if err != nil {
+		return err
+	}
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status code: %d: %s", response.StatusCode, response.Body)
+	}
+	return nil
+}
+
+// waitForBranch polls the branch until it is provisioned.
+func (api *NetboxClient) waitForBranch(ctx context.Context, branch *Branch) (*Branch, error) {
+	ctx, cancel := context.WithTimeout(ctx, branchProvisionTimeout)
+	defer cancel()
+	for {
+		switch branch.Status.Value {
+		case BranchStatusReady:
+			return branch, nil
+		case BranchStatusNew, BranchStatusProvisioning:
+		default:
+			return nil, fmt.Errorf(
+				"branch %s has status %s, only ready branches can be used",
+				branch.Name,
+				branch.Status.Value,
+			)
+		}
+		api.Logger.Debugf(ctx, "Waiting for branch %s to be provisioned", branch.Name)
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("wait for branch %s: %s", branch.Name, ctx.Err())
+		case <-time.After(branchPollInterval):
+		}
+		var err error
+		branch, err = api.getBranchByID(ctx, branch.ID)
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// isBranchingRequest returns true for requests managing branches,
+// which must not be sent to a branch.
+func isBranchingRequest(path string) bool {
+	return strings.HasPrefix(path, string(constants.BranchesAPIPath))
+}
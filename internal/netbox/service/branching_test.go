@@ -0,0 +1,181 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/src-doo/netbox-ssot/internal/constants"
+	"github.com/src-doo/netbox-ssot/internal/logger"
+	"github.com/src-doo/netbox-ssot/internal/netbox/objects"
+)
+
+// branchingServer emulates the api of the netbox branching plugin.
+// New branches are provisioned on the first status check.
+type branchingServer struct {
+	lock     sync.Mutex
+	branches []map[string]interface{}
+	// requests are method, path and branch header of all requests.
+	requests []string
+}
+
+func (b *branchingServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	b.requests = append(b.requests, fmt.Sprintf("%s %s %s", r.Method, r.URL.Path, r.Header.Get(branchHeader)))
+	branchesPath := string(constants.BranchesAPIPath)
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == branchesPath:
+		results := []map[string]interface{}{}
+		for _, branch := range b.branches {
+			if branch["name"] == r.URL.Query().Get("name") {
+				results = append(results, branch)
+			}
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{"count": len(results), "results": results})
+	case r.Method == http.MethodPost && r.URL.Path == branchesPath:
+		var branch map[string]interface{}
+		_ = json.NewDecoder(r.Body).Decode(&branch)
+		branch["id"] = len(b.branches) + 1
+		branch["schema_id"] = fmt.Sprintf("schema%d", len(b.branches)+1)
+		branch["status"] = map[string]interface{}{"value": BranchStatusProvisioning}
+		b.branches = append(b.branches, branch)
+		writeJSON(w, http.StatusCreated, branch)
+	case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/merge/"):
+		branch := b.branchByPath(strings.TrimSuffix(r.URL.Path, "merge/"))
+		branch["status"] = map[string]interface{}{"value": BranchStatusMerged}
+		writeJSON(w, http.StatusOK, map[string]interface{}{"id": 1})
+	case r.Method == http.MethodGet && strings.HasPrefix(r.URL.Path, branchesPath):
+		branch := b.branchByPath(r.URL.Path)
+		branch["status"] = map[string]interface{}{"value": BranchStatusReady}
+		writeJSON(w, http.StatusOK, branch)
+	case r.Method == http.MethodPatch && strings.HasPrefix(r.URL.Path, branchesPath):
+		branch := b.branchByPath(r.URL.Path)
+		_ = json.NewDecoder(r.Body).Decode(&branch)
+		writeJSON(w, http.StatusOK, branch)
+	case r.Method == http.MethodPost && r.URL.Path == string(constants.TagsAPIPath):
+		writeJSON(w, http.StatusCreated, map[string]interface{}{"id": 1, "name": "tag"})
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+// branchByPath returns the branch with the id at the end of the path.
+func (b *branchingServer) branchByPath(urlPath string) map[string]interface{} {
+	id, _ := strconv.Atoi(path.Base(urlPath))
+	return b.branches[id-1]
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func newBranchingClient(t *testing.T, dryRun bool) (*NetboxClient, *branchingServer) {
+	t.Helper()
+	branchPollInterval = time.Millisecond
+	b := &branchingServer{}
+	s := httptest.NewServer(b)
+	t.Cleanup(s.Close)
+	return &NetboxClient{
+		HTTPClient: s.Client(),
+		Logger:     &logger.Logger{Logger: log.Default()},
+		BaseURL:    s.URL,
+		Timeout:    constants.DefaultAPITimeout,
+		DryRun:     dryRun,
+	}, b
+}
+
+func TestNetboxClient_UseBranch(t *testing.T) {
+	ctx := context.Background()
+	api, server := newBranchingClient(t, false)
+	branch, err := api.UseBranch(ctx, "netbox-ssot")
+	if err != nil {
+		t.Fatalf("UseBranch() error = %v", err)
+	}
+	if branch.SchemaID != "schema1" || branch.Status.Value != BranchStatusReady {
+		t.Errorf("UseBranch() = %+v, want ready branch schema1", branch)
+	}
+	_, err = Create(ctx, api, &objects.Tag{Name: "tag"})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	// The branch is reused by the next run
+	api.branch = nil
+	branch, err = api.UseBranch(ctx, "netbox-ssot")
+	if err != nil {
+		t.Fatalf("UseBranch() error = %v", err)
+	}
+	if branch.ID != 1 || len(server.branches) != 1 {
+		t.Errorf("UseBranch() = %+v, want existing branch to be reused", branch)
+	}
+	err = api.MergeBranch(ctx)
+	if err != nil {
+		t.Fatalf("MergeBranch() error = %v", err)
+	}
+
+	// The merged branch is renamed and a new one is created by the next run
+	api.branch = nil
+	branch, err = api.UseBranch(ctx, "netbox-ssot")
+	if err != nil {
+		t.Fatalf("UseBranch() error = %v", err)
+	}
+	if branch.ID != 2 || branch.SchemaID != "schema2" || branch.Status.Value != BranchStatusReady {
+		t.Errorf("UseBranch() = %+v, want new ready branch schema2", branch)
+	}
+	if name := server.branches[0]["name"]; name != "netbox-ssot-merged-1" {
+		t.Errorf("merged branch name = %v, want netbox-ssot-merged-1", name)
+	}
+
+	want := []string{
+		"GET /api/plugins/branching/branches/ ",
+		"POST /api/plugins/branching/branches/ ",
+		"GET /api/plugins/branching/branches/1/ ",
+		"POST /api/extras/tags/ schema1",
+		"GET /api/plugins/branching/branches/ ",
+		"POST /api/plugins/branching/branches/1/merge/ ",
+		"GET /api/plugins/branching/branches/ ",
+		"PATCH /api/plugins/branching/branches/1/ ",
+		"POST /api/plugins/branching/branches/ ",
+		"GET /api/plugins/branching/branches/2/ ",
+	}
+	if got := strings.Join(server.requests, "\n"); got != strings.Join(want, "\n") {
+		t.Errorf("requests = \n%s\nwant\n%s", got, strings.Join(want, "\n"))
+	}
+}
+
+func TestNetboxClient_UseBranchDryRun(t *testing.T) {
+	api, server := newBranchingClient(t, true)
+	branch, err := api.UseBranch(context.Background(), "netbox-ssot")
+	if err != nil {
+		t.Fatalf("UseBranch() error = %v", err)
+	}
+	if branch != nil || api.Branch() != nil || len(server.branches) != 0 {
+		t.Errorf("UseBranch() in dry run = %+v, want no branch to be created", branch)
+	}
+
+	// A merged branch is neither renamed nor replaced
+	server.branches = append(server.branches, map[string]interface{}{
+		"id":     1,
+		"name":   "netbox-ssot",
+		"status": map[string]interface{}{"value": BranchStatusMerged},
+	})
+	branch, err = api.UseBranch(context.Background(), "netbox-ssot")
+	if err != nil {
+		t.Fatalf("UseBranch() error = %v", err)
+	}
+	if branch != nil || len(server.branches) != 1 || server.branches[0]["name"] != "netbox-ssot" {
+		t.Errorf("UseBranch() in dry run = %+v, want merged branch to be left as is", branch)
+	}
+}
@@ -0,0 +1,258 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/src-doo/netbox-ssot/internal/constants"
+)
+
+// cacheFormatVersion is increased on incompatible changes of the cache file.
+const cacheFormatVersion = 1
+
+// cacheClockSkew is subtracted from the time of the previous query, when
+// querying changed objects, so clock differences between netbox-ssot and
+// netbox don't cause changes to be missed.
+const cacheClockSkew = 5 * time.Minute
+
+// Cache stores results of GetAll queries in a local file. On the next run,
+// only objects with last_updated since the previous query are fetched
+// and merged with the cached ones. Deleted objects are reconciled
+// with the list of ids of all objects, which is queried with fields=id.
+type Cache struct {
+	path   string
+	maxAge time.Duration
+
+	lock    sync.Mutex
+	content cacheContent
+}
+
+type cacheContent struct {
+	FormatVersion int    `json:"format_version"`
+	BaseURL       string `json:"base_url"`
+	NetboxVersion string `json:"netbox_version"`
+	Branch        string `json:"branch,omitempty"`
+	// Queries maps api path with extra params of the query to its results.
+	Queries map[string]*cachedQuery `json:"queries"`
+}
+
+type cachedQuery struct {
+	FetchedAt time.Time               `json:"fetched_at"`
+	Objects   map[int]json.RawMessage `json:"objects"`
+}
+
+// LoadCache loads the cache from the file at path. If the file doesn't exist,
+// can't be decoded, or it was written for another netbox instance, version or
+// branch, an empty cache is returned. Queries older than maxAge are always fully refreshed.
+func LoadCache(
+	path string,
+	baseURL string,
+	netboxVersion string,
+	branch string,
+	maxAge time.Duration,
+) (*Cache, error) {
+	cache := &Cache{
+		path:   path,
+		maxAge: maxAge,
+		content: cacheContent{
+			FormatVersion: cacheFormatVersion,
+			BaseURL:       baseURL,
+			NetboxVersion: netboxVersion,
+			Branch:        branch,
+			Queries:       map[string]*cachedQuery{},
+		},
+	}
+	cacheJSON, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return cache, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read cache: %s", err)
+	}
+	var content cacheContent
+	err = json.Unmarshal(cacheJSON, &content)
+	if err == nil &&
+		content.FormatVersion == cacheFormatVersion &&
+		content.BaseURL == baseURL &&
+		content.NetboxVersion == netboxVersion &&
+		content.Branch == branch &&
+		content.Queries != nil {
+		cache.content.Queries = content.Queries
+	}
+	return cache, nil
+}
+
+// Save writes the cache to its file.
+func (c *Cache) Save() error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	cacheJSON, err := json.Marshal(c.content)
+	if err != nil {
+		return fmt.Errorf("marshal cache: %s", err)
+	}
+	// Write to a temporary file first, so a failed write
+	// doesn't leave a truncated cache behind
+	tmpPath := c.path + ".tmp"
+	err = os.WriteFile(tmpPath, cacheJSON, 0o600) //nolint:mnd
+	if err != nil {
+		return fmt.Errorf("write cache: %s", err)
+	}
+	err = os.Rename(tmpPath, c.path)
+	if err != nil {
+		return fmt.Errorf("write cache: %s", err)
+	}
+	return nil
+}
+
+// get returns the cached results of the query, if they are not older than maxAge.
+func (c *Cache) get(key string) (*cachedQuery, bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	query, ok := c.content.Queries[key]
+	if !ok || time.Since(query.FetchedAt) > c.maxAge {
+		return nil, false
+	}
+	return query, true
+}
+
+func (c *Cache) set(key string, query *cachedQuery) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.content.Queries[key] = query
+}
+
+// getAllCached returns all objects on the api path, like getAllRaw,
+// but fetches only objects changed since the cached query, if there is one.
+func (api *NetboxClient) getAllCached(
+	ctx context.Context,
+	path constants.APIPath,
+	extraParams string,
+) ([]json.RawMessage, error) {
+	key := string(path) + "?" + extraParams
+	fetchedAt := time.Now()
+	query, ok := api.Cache.get(key)
+	var objects map[int]json.RawMessage
+	if ok {
+		var err error
+		objects, err = api.refreshCachedQuery(ctx, path, extraParams, query)
+		if err != nil {
+			api.Logger.Warningf(ctx, "Refreshing cached %s failed, fetching all objects: %s", path, err)
+		}
+	}
+	if objects == nil {
+		results, err := api.getAllRaw(ctx, path, extraParams)
+		if err != nil {
+			return nil, err
+		}
+		objects, err = indexByID(results)
+		if err != nil {
+			return nil, err
+		}
+	}
+	api.Cache.set(key, &cachedQuery{FetchedAt: fetchedAt, Objects: objects})
+
+	ids := make([]int, 0, len(objects))
+	for id := range objects {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	results := make([]json.RawMessage, 0, len(ids))
+	for _, id := range ids {
+		results = append(results, objects[id])
+	}
+	return results, nil
+}
+
+// refreshCachedQuery merges objects changed since the cached query with the
+// cached objects, and drops cached objects, that no longer exist. It returns
+// nil, if an object is neither changed nor cached, so all must be fetched.
+func (api *NetboxClient) refreshCachedQuery(
+	ctx context.Context,
+	path constants.APIPath,
+	extraParams string,
+	query *cachedQuery,
+) (map[int]json.RawMessage, error) {
+	since := query.FetchedAt.Add(-cacheClockSkew).UTC().Format(time.RFC3339)
+	changedResults, err := api.getAllRaw(ctx, path, extraParams+"&last_updated__gte="+since)
+	if err != nil {
+		return nil, err
+	}
+	changed, err := indexByID(changedResults)
+	if err != nil {
+		return nil, err
+	}
+	idParams, err := idListParams(extraParams)
+	if err != nil {
+		return nil, err
+	}
+	idResults, err := api.getAllRaw(ctx, path, idParams)
+	if err != nil {
+		return nil, err
+	}
+	existing, err := indexByID(idResults)
+	if err != nil {
+		return nil, err
+	}
+
+	objects := make(map[int]json.RawMessage, len(existing))
+	deleted := 0
+	for id := range query.Objects {
+		if _, ok := existing[id]; !ok {
+			deleted++
+		}
+	}
+	for id := range existing {
+		if object, ok := changed[id]; ok {
+			objects[id] = object
+		} else if object, ok := query.Objects[id]; ok {
+			objects[id] = object
+		} else {
+			// Created after the changed objects were queried
+			return nil, nil
+		}
+	}
+	api.Logger.Debugf(
+		ctx,
+		"Refreshed cached %s: %d changed, %d deleted, %d total",
+		path,
+		len(changed),
+		deleted,
+		len(objects),
+	)
+	return objects, nil
+}
+
+// idListParams returns extraParams of the query with fields replaced by id,
+// so only the ids of the matching objects are returned.
+func idListParams(extraParams string) (string, error) {
+	params, err := url.ParseQuery(strings.TrimPrefix(extraParams, "&"))
+	if err != nil {
+		return "", fmt.Errorf("parse query params: %s", err)
+	}
+	params.Set("fields", "id")
+	return "&" + params.Encode(), nil
+}
+
+// indexByID maps undecoded objects by their id.
+func indexByID(results []json.RawMessage) (map[int]json.RawMessage, error) {
+	objects := make(map[int]json.RawMessage, len(results))
+	for _, result := range results {
+		var object struct {
+			ID int `json:"id"`
+		}
+		err := json.Unmarshal(result, &object)
+		if err != nil {
+			return nil, err
+		}
+		objects[object.ID] = result
+	}
+	return objects, nil
+}
@@ -0,0 +1,241 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/src-doo/netbox-ssot/internal/constants"
+	"github.com/src-doo/netbox-ssot/internal/logger"
+	"github.com/src-doo/netbox-ssot/internal/netbox/netboxtest"
+	"github.com/src-doo/netbox-ssot/internal/netbox/objects"
+)
+
+// queryRecorder records queries of all requests sent through it,
+// and the fields of the objects returned by list queries.
+type queryRecorder struct {
+	lock    sync.Mutex
+	queries []string
+	fields  map[string][]string
+}
+
+func (r *queryRecorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := http.DefaultTransport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	var list struct {
+		Results []map[string]json.RawMessage `json:"results"`
+	}
+	fieldSet := map[string]bool{}
+	if json.Unmarshal(body, &list) == nil {
+		for _, result := range list.Results {
+			for field := range result {
+				fieldSet[field] = true
+			}
+		}
+	}
+	fields := make([]string, 0, len(fieldSet))
+	for field := range fieldSet {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.queries = append(r.queries, req.URL.RawQuery)
+	if r.fields == nil {
+		r.fields = map[string][]string{}
+	}
+	r.fields[req.URL.RawQuery] = fields
+	return resp, nil
+}
+
+// returnedFields returns the fields of the objects returned
+// by the first recorded query matching match.
+func (r *queryRecorder) returnedFields(match func(query url.Values) bool) (string, bool) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	for _, rawQuery := range r.queries {
+		query, err := url.ParseQuery(rawQuery)
+		if err == nil && match(query) {
+			return strings.Join(r.fields[rawQuery], ","), true
+		}
+	}
+	return "", false
+}
+
+func (r *queryRecorder) contains(param string) bool {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	for _, query := range r.queries {
+		if strings.Contains(query, param) {
+			return true
+		}
+	}
+	return false
+}
+
+func newCachedClient(
+	t *testing.T,
+	s *netboxtest.Server,
+	cachePath string,
+	maxAge time.Duration,
+) (*NetboxClient, *queryRecorder) {
+	t.Helper()
+	cache, err := LoadCache(cachePath, s.URL, s.Version, "", maxAge)
+	if err != nil {
+		t.Fatalf("LoadCache() error = %v", err)
+	}
+	recorder := &queryRecorder{}
+	return &NetboxClient{
+		HTTPClient: &http.Client{Transport: recorder},
+		Logger:     &logger.Logger{Logger: log.Default()},
+		BaseURL:    s.URL,
+		Timeout:    constants.DefaultAPITimeout,
+		Cache:      cache,
+	}, recorder
+}
+
+func tagNames(tags []objects.Tag) []string {
+	names := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		names = append(names, tag.Name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func TestCache_IncrementalRefresh(t *testing.T) {
+	s := netboxtest.NewServer()
+	defer s.Close()
+	ctx := context.Background()
+	cachePath := filepath.Join(t.TempDir(), "cache.json")
+	for _, name := range []string{"a", "b", "c"} {
+		_, err := netboxtest.Seed(s, &objects.Tag{Name: name, Slug: name})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Like the inventory, only the needed fields are queried
+	extraParams := "&fields=id,name,slug"
+
+	// First run fills the cache with all objects
+	api, recorder := newCachedClient(t, s, cachePath, time.Hour)
+	tags, err := GetAll[objects.Tag](ctx, api, extraParams)
+	if err != nil {
+		t.Fatalf("GetAll() error = %v", err)
+	}
+	if got := strings.Join(tagNames(tags), ","); got != "a,b,c" {
+		t.Errorf("GetAll() = %s, want a,b,c", got)
+	}
+	if recorder.contains("last_updated__gte") {
+		t.Errorf("first run queried changed objects, want full fetch")
+	}
+	err = api.Cache.Save()
+	if err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	// Objects are created, updated and deleted between runs
+	_, err = Patch[objects.Tag](ctx, api, tags[0].ID, map[string]interface{}{"name": "a2"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = api.DeleteObject(ctx, &tags[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = Create(ctx, api, &objects.Tag{Name: "d", Slug: "d"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Second run only fetches changes and reconciles deletions
+	api, recorder = newCachedClient(t, s, cachePath, time.Hour)
+	tags, err = GetAll[objects.Tag](ctx, api, extraParams)
+	if err != nil {
+		t.Fatalf("GetAll() error = %v", err)
+	}
+	if got := strings.Join(tagNames(tags), ","); got != "a2,c,d" {
+		t.Errorf("GetAll() with cache = %s, want a2,c,d", got)
+	}
+	changedFields, ok := recorder.returnedFields(func(query url.Values) bool {
+		return query.Has("last_updated__gte")
+	})
+	if !ok {
+		t.Errorf("queries with cache = %v, want query of changed objects", recorder.queries)
+	} else if changedFields != "id,name,slug" {
+		t.Errorf("changed objects returned fields %s, want id,name,slug", changedFields)
+	}
+	idFields, ok := recorder.returnedFields(func(query url.Values) bool {
+		return query.Get("fields") == "id"
+	})
+	if !ok {
+		t.Errorf("queries with cache = %v, want query of id list", recorder.queries)
+	} else if idFields != "id" {
+		t.Errorf("id list returned fields %s, want id", idFields)
+	}
+}
+
+func TestCache_Invalidation(t *testing.T) {
+	s := netboxtest.NewServer()
+	defer s.Close()
+	ctx := context.Background()
+	cachePath := filepath.Join(t.TempDir(), "cache.json")
+	_, err := netboxtest.Seed(s, &objects.Tag{Name: "a", Slug: "a"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	api, _ := newCachedClient(t, s, cachePath, time.Hour)
+	_, err = GetAll[objects.Tag](ctx, api, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = api.Cache.Save()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		baseURL string
+		version string
+		branch  string
+		maxAge  time.Duration
+	}{
+		{name: "Expired cache", baseURL: s.URL, version: s.Version, maxAge: 0},
+		{name: "Other netbox instance", baseURL: "https://other.example.com", version: s.Version, maxAge: time.Hour},
+		{name: "Other netbox version", baseURL: s.URL, version: "4.4.0", maxAge: time.Hour},
+		{name: "Other branch", baseURL: s.URL, version: s.Version, branch: "review", maxAge: time.Hour},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cache, err := LoadCache(cachePath, tt.baseURL, tt.version, tt.branch, tt.maxAge)
+			if err != nil {
+				t.Fatalf("LoadCache() error = %v", err)
+			}
+			if _, ok := cache.get(string(constants.TagsAPIPath) + "?"); ok {
+				t.Errorf("LoadCache() returned cached query, want full refresh")
+			}
+		})
+	}
+}
@@ -0,0 +1,220 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/src-doo/netbox-ssot/internal/constants"
+)
+
+// Feature is a feature of the netbox api, that is not available
+// in all supported netbox versions.
+type Feature int
+
+const (
+	// FeatureMACAddressObjects are standalone MAC address objects, assigned
+	// to interfaces. Older versions store the MAC address on the interface.
+	FeatureMACAddressObjects Feature = iota
+	// FeatureScopes are scopes of prefixes and clusters.
+	// Older versions assign them directly to a site.
+	FeatureScopes
+)
+
+// featureInfo describes since which netbox version a feature is available,
+// and how netbox-ssot behaves on older versions.
+type featureInfo struct {
+	major    int
+	minor    int
+	fallback string
+}
+
+// features is indexed by Feature.
+var features = []featureInfo{
+	FeatureMACAddressObjects: {
+		major:    4, //nolint:mnd
+		minor:    2, //nolint:mnd
+		fallback: "MAC addresses are set on interfaces instead of MAC address objects",
+	},
+	FeatureScopes: {
+		major:    4, //nolint:mnd
+		minor:    2, //nolint:mnd
+		fallback: "prefixes and clusters are assigned to sites instead of scopes",
+	},
+}
+
+// Range of netbox versions supported by netbox-ssot.
+const (
+	minSupportedMajorVersion = 4
+	minSupportedMinorVersion = 0
+	maxKnownMajorVersion     = 4
+)
+
+// Capabilities are features of the netbox api, which depend on the netbox version.
+type Capabilities struct {
+	Version string
+	major   int
+	minor   int
+}
+
+// NewCapabilities returns capabilities of the given netbox version.
+// Versions with a newer major version than known are assumed to support all features.
+func NewCapabilities(version string) (*Capabilities, error) {
+	versionComponents := strings.Split(version, ".")
+	if len(versionComponents) < 2 { //nolint:mnd
+		return nil, fmt.Errorf("invalid netbox version: %s", version)
+	}
+	major, err := strconv.Atoi(versionComponents[0])
+	if err != nil {
+		return nil, fmt.Errorf("parse major version: %s", err)
+	}
+	minor, err := strconv.Atoi(versionComponents[1])
+	if err != nil {
+		return nil, fmt.Errorf("parse minor version: %s", err)
+	}
+	if major < minSupportedMajorVersion ||
+		(major == minSupportedMajorVersion && minor < minSupportedMinorVersion) {
+		return nil, fmt.Errorf(
+			"this version of netbox-ssot works only with netbox version >= %d.%d.0, but received version: %s",
+			minSupportedMajorVersion,
+			minSupportedMinorVersion,
+			version,
+		)
+	}
+	return &Capabilities{Version: version, major: major, minor: minor}, nil
+}
+
+// Supports returns true, if the feature is available in the netbox version.
+func (c *Capabilities) Supports(feature Feature) bool {
+	info := features[feature]
+	return c.major > info.major || (c.major == info.major && c.minor >= info.minor)
+}
+
+// Warnings returns a warning for each feature missing in the netbox version,
+// describing how netbox-ssot works around it.
+func (c *Capabilities) Warnings() []string {
+	var warnings []string
+	if c.major > maxKnownMajorVersion {
+		warnings = append(warnings, fmt.Sprintf(
+			"netbox %s is newer than the versions netbox-ssot was tested with, it may not work as expected",
+			c.Version,
+		))
+	}
+	for feature, info := range features {
+		if !c.Supports(Feature(feature)) {
+			warnings = append(warnings, fmt.Sprintf(
+				"netbox %s is older than %d.%d: %s",
+				c.Version,
+				info.major,
+				info.minor,
+				info.fallback,
+			))
+		}
+	}
+	return warnings
+}
+
+// Supports returns true, if the feature is available in netbox.
+// Clients without detected capabilities support all features.
+func (api *NetboxClient) Supports(feature Feature) bool {
+	return api.Capabilities == nil || api.Capabilities.Supports(feature)
+}
+
+// siteScopedPaths are api paths of objects, which are assigned
+// to a site instead of a scope, if FeatureScopes is not supported.
+var siteScopedPaths = []constants.APIPath{
+	constants.PrefixesAPIPath,
+	constants.ClustersAPIPath,
+}
+
+// isSiteScopedRequest returns true, if the request has to be translated
+// between scopes used by netbox-ssot and sites used by netbox.
+func (api *NetboxClient) isSiteScopedRequest(path string) bool {
+	if api.Supports(FeatureScopes) {
+		return false
+	}
+	for _, siteScopedPath := range siteScopedPaths {
+		if strings.HasPrefix(path, string(siteScopedPath)) {
+			return true
+		}
+	}
+	return false
+}
+
+// scopeToSiteRequest translates the path and body of a request from scope to site.
+// Site is added to the requested fields, and scope fields of a written object are
+// replaced with its site.
+func scopeToSiteRequest(method string, path string, body io.Reader) (string, io.Reader, error) {
+	path = strings.Replace(path, "fields=", "fields=site,", 1)
+	if body == nil || (method != http.MethodPost && method != http.MethodPatch) {
+		return path, body, nil
+	}
+	requestBody, err := io.ReadAll(body)
+	if err != nil {
+		return "", nil, err
+	}
+	var object map[string]interface{}
+	if err := json.Unmarshal(requestBody, &object); err != nil {
+		// Not a single object, e.g. a list of objects
+		return path, bytes.NewBuffer(requestBody), nil //nolint:nilerr
+	}
+	scopeType, hasScopeType := object["scope_type"]
+	scopeID, hasScopeID := object["scope_id"]
+	if !hasScopeType && !hasScopeID {
+		return path, bytes.NewBuffer(requestBody), nil
+	}
+	delete(object, "scope_type")
+	delete(object, "scope_id")
+	if scopeType != nil && scopeType != string(constants.ContentTypeDcimSite) {
+		return "", nil, fmt.Errorf("scope type %s requires netbox >= 4.2", scopeType)
+	}
+	switch {
+	case hasScopeID:
+		object["site"] = scopeID
+	case scopeType == nil:
+		object["site"] = nil
+	}
+	requestBody, err = json.Marshal(object)
+	if err != nil {
+		return "", nil, err
+	}
+	return path, bytes.NewBuffer(requestBody), nil
+}
+
+// siteToScopeResponse translates site of objects in a response into their scope.
+func siteToScopeResponse(body []byte) ([]byte, error) {
+	var response map[string]interface{}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Errorf("unmarshal response: %s", err)
+	}
+	if results, ok := response["results"].([]interface{}); ok {
+		for _, result := range results {
+			if object, ok := result.(map[string]interface{}); ok {
+				siteToScope(object)
+			}
+		}
+	} else {
+		siteToScope(response)
+	}
+	return json.Marshal(response)
+}
+
+func siteToScope(object map[string]interface{}) {
+	site, ok := object["site"]
+	if !ok {
+		return
+	}
+	delete(object, "site")
+	if site, ok := site.(map[string]interface{}); ok {
+		object["scope_type"] = constants.ContentTypeDcimSite
+		object["scope_id"] = site["id"]
+		object["scope"] = site
+		return
+	}
+	object["scope_type"] = nil
+	object["scope_id"] = nil
+}
@@ -0,0 +1,261 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/src-doo/netbox-ssot/internal/constants"
+	"github.com/src-doo/netbox-ssot/internal/logger"
+	"github.com/src-doo/netbox-ssot/internal/netbox/objects"
+)
+
+func TestNewCapabilities(t *testing.T) {
+	tests := []struct {
+		name              string
+		version           string
+		wantErr           bool
+		macAddressObjects bool
+		scopes            bool
+		wantWarnings      int
+	}{
+		{
+			name:              "Netbox 4.2",
+			version:           "4.2.3",
+			macAddressObjects: true,
+			scopes:            true,
+		},
+		{
+			name:              "Netbox 4.3 with docker suffix",
+			version:           "4.3.1-Docker-3.3.0",
+			macAddressObjects: true,
+			scopes:            true,
+		},
+		{
+			name:         "Netbox 4.1",
+			version:      "4.1.11",
+			wantWarnings: 2,
+		},
+		{
+			name:         "Netbox 4.0",
+			version:      "4.0.0",
+			wantWarnings: 2,
+		},
+		{
+			name:              "Newer major version",
+			version:           "5.0.0",
+			macAddressObjects: true,
+			scopes:            true,
+			wantWarnings:      1,
+		},
+		{
+			name:    "Netbox 3.7",
+			version: "3.7.8",
+			wantErr: true,
+		},
+		{
+			name:    "Invalid version",
+			version: "4",
+			wantErr: true,
+		},
+		{
+			name:    "Invalid minor version",
+			version: "4.x.1",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			capabilities, err := NewCapabilities(tt.version)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewCapabilities() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got := capabilities.Supports(FeatureMACAddressObjects); got != tt.macAddressObjects {
+				t.Errorf("Supports(FeatureMACAddressObjects) = %v, want %v", got, tt.macAddressObjects)
+			}
+			if got := capabilities.Supports(FeatureScopes); got != tt.scopes {
+				t.Errorf("Supports(FeatureScopes) = %v, want %v", got, tt.scopes)
+			}
+			if got := capabilities.Warnings(); len(got) != tt.wantWarnings {
+				t.Errorf("Warnings() = %v, want %d warnings", got, tt.wantWarnings)
+			}
+		})
+	}
+}
+
+func TestNetboxClient_SupportsWithoutCapabilities(t *testing.T) {
+	api := &NetboxClient{}
+	if !api.Supports(FeatureMACAddressObjects) || !api.Supports(FeatureScopes) {
+		t.Errorf("client without capabilities should support all features")
+	}
+}
+
+func TestScopeToSiteRequest(t *testing.T) {
+	tests := []struct {
+		name     string
+		method   string
+		path     string
+		body     string
+		wantPath string
+		wantBody map[string]interface{}
+		wantErr  bool
+	}{
+		{
+			name:     "Site is added to requested fields",
+			method:   http.MethodGet,
+			path:     "/api/ipam/prefixes/?limit=250&offset=0&fields=id,prefix,scope_id",
+			wantPath: "/api/ipam/prefixes/?limit=250&offset=0&fields=site,id,prefix,scope_id",
+		},
+		{
+			name:     "Site scope is replaced with site",
+			method:   http.MethodPost,
+			path:     "/api/ipam/prefixes/",
+			body:     `{"prefix":"10.0.0.0/24","scope_type":"dcim.site","scope_id":3}`,
+			wantPath: "/api/ipam/prefixes/",
+			wantBody: map[string]interface{}{"prefix": "10.0.0.0/24", "site": float64(3)},
+		},
+		{
+			name:     "Changed scope id is replaced with site",
+			method:   http.MethodPatch,
+			path:     "/api/virtualization/clusters/1/",
+			body:     `{"scope_id":4}`,
+			wantPath: "/api/virtualization/clusters/1/",
+			wantBody: map[string]interface{}{"site": float64(4)},
+		},
+		{
+			name:     "Removed scope removes site",
+			method:   http.MethodPatch,
+			path:     "/api/virtualization/clusters/1/",
+			body:     `{"scope_type":null,"scope_id":null}`,
+			wantPath: "/api/virtualization/clusters/1/",
+			wantBody: map[string]interface{}{"site": nil},
+		},
+		{
+			name:     "Object without scope is unchanged",
+			method:   http.MethodPatch,
+			path:     "/api/ipam/prefixes/1/",
+			body:     `{"description":"test"}`,
+			wantPath: "/api/ipam/prefixes/1/",
+			wantBody: map[string]interface{}{"description": "test"},
+		},
+		{
+			name:    "Other scope types are not supported",
+			method:  http.MethodPost,
+			path:    "/api/ipam/prefixes/",
+			body:    `{"scope_type":"dcim.location","scope_id":1}`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var body io.Reader
+			if tt.body != "" {
+				body = strings.NewReader(tt.body)
+			}
+			gotPath, gotBody, err := scopeToSiteRequest(tt.method, tt.path, body)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("scopeToSiteRequest() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if gotPath != tt.wantPath {
+				t.Errorf("scopeToSiteRequest() path = %s, want %s", gotPath, tt.wantPath)
+			}
+			if tt.wantBody == nil {
+				return
+			}
+			var gotBodyMap map[string]interface{}
+			if err := json.NewDecoder(gotBody).Decode(&gotBodyMap); err != nil {
+				t.Fatalf("decode body: %s", err)
+			}
+			if !reflect.DeepEqual(gotBodyMap, tt.wantBody) {
+				t.Errorf("scopeToSiteRequest() body = %v, want %v", gotBodyMap, tt.wantBody)
+			}
+		})
+	}
+}
+
+func TestSiteToScopeResponse(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want string
+	}{
+		{
+			name: "Single object with site",
+			body: `{"id":1,"site":{"id":3,"name":"site"}}`,
+			want: `{"id":1,"scope":{"id":3,"name":"site"},"scope_id":3,"scope_type":"dcim.site"}`,
+		},
+		{
+			name: "List of objects",
+			body: `{"count":2,"next":null,"results":[{"id":1,"site":null},{"id":2,"site":{"id":3}}]}`,
+			want: `{"count":2,"next":null,"results":[{"id":1,"scope_id":null,"scope_type":null},` +
+				`{"id":2,"scope":{"id":3},"scope_id":3,"scope_type":"dcim.site"}]}`,
+		},
+		{
+			name: "Object without site field",
+			body: `{"id":1}`,
+			want: `{"id":1}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := siteToScopeResponse([]byte(tt.body))
+			if err != nil {
+				t.Fatalf("siteToScopeResponse() error = %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("siteToScopeResponse() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNetboxClient_CreateSiteScopedPrefix(t *testing.T) {
+	var requestBody map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&requestBody)
+		writeJSON(w, http.StatusCreated, map[string]interface{}{
+			"id":     1,
+			"prefix": requestBody["prefix"],
+			"site":   map[string]interface{}{"id": requestBody["site"], "name": "site"},
+		})
+	}))
+	defer server.Close()
+	capabilities, err := NewCapabilities("4.1.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	api := &NetboxClient{
+		HTTPClient:   server.Client(),
+		Logger:       &logger.Logger{Logger: log.Default()},
+		BaseURL:      server.URL,
+		Timeout:      constants.DefaultAPITimeout,
+		Capabilities: capabilities,
+	}
+
+	prefix, err := Create(context.Background(), api, &objects.Prefix{
+		Prefix:    "10.0.0.0/24",
+		ScopeType: constants.ContentTypeDcimSite,
+		ScopeID:   3,
+	})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if _, ok := requestBody["scope_id"]; ok || requestBody["site"] != float64(3) {
+		t.Errorf("request body = %v, want site instead of scope", requestBody)
+	}
+	if prefix.ScopeType != constants.ContentTypeDcimSite || prefix.ScopeID != 3 {
+		t.Errorf("Create() = %+v, want scope from site", prefix)
+	}
+}
@@ -5,10 +5,15 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"sync"
 	"time"
 
+	"github.com/src-doo/netbox-ssot/internal/audit"
+	"github.com/src-doo/netbox-ssot/internal/constants"
 	"github.com/src-doo/netbox-ssot/internal/logger"
+	"github.com/src-doo/netbox-ssot/internal/tracing"
 	"github.com/src-doo/netbox-ssot/internal/utils"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 // NetboxClient is a service used for communicating with the Netbox API.
@@ -20,6 +25,35 @@ type NetboxClient struct {
 	APIToken   string
 	Timeout    int // in seconds
 	MaxRetires int
+	// DryRun prevents any writes to the Netbox API. Instead of
+	// sending them, changes are recorded and can be retrieved
+	// with PlannedChanges.
+	DryRun bool
+	// Cache, if set, is used by GetAll to fetch only objects
+	// changed since the previous run.
+	Cache *Cache
+
+	// AuditLog, if set, records every write to the Netbox API.
+	// Writes skipped in dry run mode are not recorded.
+	AuditLog *audit.Log
+
+	// Capabilities of the netbox version. If nil, all features are supported.
+	Capabilities *Capabilities
+
+	// branch, if set, is the branch of the netbox branching plugin,
+	// that all requests are sent to. It is set by UseBranch.
+	branch *Branch
+
+	dryRunLock     sync.Mutex
+	dryRunID       int
+	dryRunObjects  map[constants.APIPath]map[int][]byte
+	plannedChanges []PlannedChange
+
+	conflictsLock sync.Mutex
+	conflicts     []Conflict
+
+	statsLock  sync.Mutex
+	writeStats WriteStats
 }
 
 // APIResponse is a struct that represents a response from the Netbox API.
@@ -33,11 +67,10 @@ func NewNetboxClient(
 	logger *logger.Logger,
 	baseURL string,
 	apiToken string,
-	validateCert bool,
 	timeout int,
-	caCert string,
+	httpClientConfig utils.HTTPClientConfig,
 ) (*NetboxClient, error) {
-	httpClient, err := utils.NewHTTPClient(validateCert, caCert)
+	httpClient, err := utils.NewHTTPClient(httpClientConfig)
 	if err != nil {
 		return nil, fmt.Errorf("create new HTTP client: %s", err)
 	}
@@ -51,16 +84,38 @@ func NewNetboxClient(
 }
 
 func (api *NetboxClient) doRequest(
+	ctx context.Context,
 	method string,
 	path string,
 	body io.Reader,
-) (*APIResponse, error) {
+) (response *APIResponse, err error) {
+	ctx, span := tracing.Start(
+		ctx,
+		"netbox "+method,
+		attribute.String("http.request.method", method),
+		attribute.String("url.path", path),
+	)
+	defer func() {
+		if response != nil {
+			span.SetAttributes(attribute.Int("http.response.status_code", response.StatusCode))
+		}
+		tracing.End(span, err)
+	}()
+
 	ctx, cancelCtx := context.WithTimeout(
-		context.Background(),
+		ctx,
 		time.Second*time.Duration(api.Timeout),
 	)
 	defer cancelCtx()
 
+	siteScoped := api.isSiteScopedRequest(path)
+	if siteScoped {
+		path, body, err = scopeToSiteRequest(method, path, body)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	req, err := http.NewRequestWithContext(ctx, method, api.BaseURL+path, body)
 	if err != nil {
 		return nil, err
@@ -69,6 +124,9 @@ func (api *NetboxClient) doRequest(
 	// We add necessary headers to the request
 	req.Header.Add("Authorization", "Token "+api.APIToken)
 	req.Header.Add("Content-Type", "application/json")
+	if api.branch != nil && !isBranchingRequest(path) {
+		req.Header.Add(branchHeader, api.branch.SchemaID)
+	}
 
 	resp, err := api.HTTPClient.Do(req)
 	if err != nil {
@@ -81,6 +139,13 @@ func (api *NetboxClient) doRequest(
 		return nil, err
 	}
 
+	if siteScoped && (resp.StatusCode == http.StatusOK || resp.StatusCode == http.StatusCreated) {
+		responseBody, err = siteToScopeResponse(responseBody)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	return &APIResponse{
 		StatusCode: resp.StatusCode,
 		Body:       responseBody,
@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"crypto/tls"
 	"io"
 	"log"
@@ -10,16 +11,16 @@ import (
 
 	"github.com/src-doo/netbox-ssot/internal/constants"
 	"github.com/src-doo/netbox-ssot/internal/logger"
+	"github.com/src-doo/netbox-ssot/internal/utils"
 )
 
 func TestNewNetBoxAPI(t *testing.T) {
 	type args struct {
-		logger       *logger.Logger
-		baseURL      string
-		apiToken     string
-		validateCert bool
-		timeout      int
-		caCert       string
+		logger           *logger.Logger
+		baseURL          string
+		apiToken         string
+		timeout          int
+		httpClientConfig utils.HTTPClientConfig
 	}
 	tests := []struct {
 		name string
@@ -29,12 +30,10 @@ func TestNewNetBoxAPI(t *testing.T) {
 		{
 			name: "test new API creation without ssl verify",
 			args: args{
-				logger:       &logger.Logger{Logger: log.Default()},
-				baseURL:      "netbox.example.com",
-				apiToken:     "apitoken",
-				validateCert: false,
-				timeout:      constants.DefaultAPITimeout,
-				caCert:       "",
+				logger:   &logger.Logger{Logger: log.Default()},
+				baseURL:  "netbox.example.com",
+				apiToken: "apitoken",
+				timeout:  constants.DefaultAPITimeout,
 			},
 			want: &NetboxClient{
 				Logger:   &logger.Logger{Logger: log.Default()},
@@ -51,12 +50,11 @@ func TestNewNetBoxAPI(t *testing.T) {
 		{
 			name: "test new API creation with ssl verify",
 			args: args{
-				logger:       &logger.Logger{Logger: log.Default()},
-				baseURL:      "netbox.example.com",
-				apiToken:     "apitoken",
-				validateCert: true,
-				timeout:      constants.DefaultAPITimeout,
-				caCert:       "",
+				logger:           &logger.Logger{Logger: log.Default()},
+				baseURL:          "netbox.example.com",
+				apiToken:         "apitoken",
+				timeout:          constants.DefaultAPITimeout,
+				httpClientConfig: utils.HTTPClientConfig{ValidateCert: true},
 			},
 			want: &NetboxClient{
 				Logger:   &logger.Logger{Logger: log.Default()},
@@ -75,9 +73,8 @@ func TestNewNetBoxAPI(t *testing.T) {
 				tt.args.logger,
 				tt.args.baseURL,
 				tt.args.apiToken,
-				tt.args.validateCert,
 				tt.args.timeout,
-				tt.args.caCert,
+				tt.args.httpClientConfig,
 			)
 			if err != nil {
 				t.Errorf("NewNetboxClient() error = %v", err)
@@ -159,7 +156,7 @@ func TestNetboxAPI_doRequest(t *testing.T) {
 	MockNetboxClient.BaseURL = mockServer.URL
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := tt.netboxClient.doRequest(tt.args.method, tt.args.path, tt.args.body)
+			got, err := tt.netboxClient.doRequest(context.Background(), tt.args.method, tt.args.path, tt.args.body)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("NetboxAPI.doRequest() error = %v, wantErr %v", err, tt.wantErr)
 				return
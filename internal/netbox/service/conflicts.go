@@ -0,0 +1,167 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/src-doo/netbox-ssot/internal/constants"
+)
+
+// Netbox reports errors, that are not related to a single field, under these keys.
+var nonFieldErrorKeys = []string{"__all__", "non_field_errors", "detail"}
+
+// ValidationError is a structured representation of a validation error
+// (status code 400) returned by the Netbox API. Usually it is caused by
+// a conflict with an existing object, e.g. unique constraint violation.
+type ValidationError struct {
+	// ObjectPath is the api path of the object (e.g. /api/dcim/devices/).
+	ObjectPath constants.APIPath `json:"object_path"`
+	// Action is either PlannedActionCreate or PlannedActionUpdate.
+	Action string `json:"action"`
+	// ObjectID is the id of the updated object. It is 0 for created objects.
+	ObjectID int `json:"object_id,omitempty"`
+	// FieldErrors maps field names to their error messages.
+	FieldErrors map[string][]string `json:"field_errors,omitempty"`
+	// NonFieldErrors are error messages, not related to a single field.
+	NonFieldErrors []string `json:"non_field_errors,omitempty"`
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("netbox validation error on %s of %s: %s", e.Action, e.ObjectPath, e.Messages())
+}
+
+// Messages returns all error messages joined into a single string.
+func (e *ValidationError) Messages() string {
+	messages := make([]string, 0, len(e.FieldErrors)+len(e.NonFieldErrors))
+	messages = append(messages, e.NonFieldErrors...)
+	fields := make([]string, 0, len(e.FieldErrors))
+	for field := range e.FieldErrors {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	for _, field := range fields {
+		messages = append(
+			messages,
+			fmt.Sprintf("%s: %s", field, strings.Join(e.FieldErrors[field], " ")),
+		)
+	}
+	return strings.Join(messages, "; ")
+}
+
+// Conflict is a ValidationError that was encountered while syncing
+// the object Object from the source Source.
+type Conflict struct {
+	ValidationError
+	// Source is the name of the source that tried to write the object.
+	Source string `json:"source"`
+	// Object is a string representation of the object.
+	Object string `json:"object"`
+}
+
+// Conflicts returns all conflicts encountered by the client,
+// sorted by object path and source.
+func (api *NetboxClient) Conflicts() []Conflict {
+	api.conflictsLock.Lock()
+	defer api.conflictsLock.Unlock()
+	conflicts := make([]Conflict, len(api.conflicts))
+	copy(conflicts, api.conflicts)
+	sort.SliceStable(conflicts, func(i, j int) bool {
+		if conflicts[i].ObjectPath != conflicts[j].ObjectPath {
+			return conflicts[i].ObjectPath < conflicts[j].ObjectPath
+		}
+		return conflicts[i].Source < conflicts[j].Source
+	})
+	return conflicts
+}
+
+// writeError converts unsuccessful response of a write request to an error.
+// Validation errors are parsed into ValidationError and recorded as conflicts.
+func (api *NetboxClient) writeError(
+	ctx context.Context,
+	response *APIResponse,
+	action string,
+	objectPath constants.APIPath,
+	objectID int,
+	object interface{},
+) error {
+	if response.StatusCode != http.StatusBadRequest {
+		return fmt.Errorf("unexpected status code: %d: %s", response.StatusCode, response.Body)
+	}
+	validationErr, err := parseValidationError(response.Body)
+	if err != nil {
+		return fmt.Errorf("unexpected status code: %d: %s", response.StatusCode, response.Body)
+	}
+	validationErr.Action = action
+	validationErr.ObjectPath = objectPath
+	validationErr.ObjectID = objectID
+	api.RecordConflict(ctx, validationErr, object)
+	return validationErr
+}
+
+// RecordConflict records a conflict of the object, that was detected
+// before it was written to netbox, so it is reported with the conflicts
+// returned by netbox.
+func (api *NetboxClient) RecordConflict(ctx context.Context, validationErr *ValidationError, object interface{}) {
+	sourceName, _ := ctx.Value(constants.CtxSourceKey).(string)
+	api.conflictsLock.Lock()
+	defer api.conflictsLock.Unlock()
+	api.conflicts = append(api.conflicts, Conflict{
+		ValidationError: *validationErr,
+		Source:          sourceName,
+		Object:          fmt.Sprintf("%v", object),
+	})
+}
+
+// parseValidationError parses body of a Netbox response with status code 400.
+// Netbox returns validation errors in the following format:
+// {"name": ["device with this name already exists."], "__all__": ["..."]}.
+func parseValidationError(body []byte) (*ValidationError, error) {
+	var errorMap map[string]interface{}
+	err := json.Unmarshal(body, &errorMap)
+	if err != nil {
+		return nil, err
+	}
+	validationErr := &ValidationError{FieldErrors: map[string][]string{}}
+	for key, value := range errorMap {
+		messages := errorMessages(value)
+		isNonFieldError := false
+		for _, nonFieldErrorKey := range nonFieldErrorKeys {
+			if key == nonFieldErrorKey {
+				isNonFieldError = true
+				break
+			}
+		}
+		if isNonFieldError {
+			validationErr.NonFieldErrors = append(validationErr.NonFieldErrors, messages...)
+		} else {
+			validationErr.FieldErrors[key] = messages
+		}
+	}
+	sort.Strings(validationErr.NonFieldErrors)
+	return validationErr, nil
+}
+
+// errorMessages flattens error messages of a single field. Nested
+// serializers return errors as objects, so they are stringified.
+func errorMessages(value interface{}) []string {
+	switch value := value.(type) {
+	case string:
+		return []string{value}
+	case []interface{}:
+		messages := make([]string, 0, len(value))
+		for _, message := range value {
+			messages = append(messages, errorMessages(message)...)
+		}
+		return messages
+	default:
+		messageJSON, err := json.Marshal(value)
+		if err != nil {
+			return []string{fmt.Sprintf("%v", value)}
+		}
+		return []string{string(messageJSON)}
+	}
+}
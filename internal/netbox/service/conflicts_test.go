@@ -0,0 +1,100 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/src-doo/netbox-ssot/internal/constants"
+	"github.com/src-doo/netbox-ssot/internal/logger"
+	"github.com/src-doo/netbox-ssot/internal/netbox/objects"
+)
+
+func TestParseValidationError(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		want    *ValidationError
+		wantErr bool
+	}{
+		{
+			name: "Field and non field errors",
+			body: `{"name": ["device with this name already exists."], ` +
+				`"__all__": ["Duplicate IP address found in global table: 10.0.0.1/24"]}`,
+			want: &ValidationError{
+				FieldErrors: map[string][]string{
+					"name": {"device with this name already exists."},
+				},
+				NonFieldErrors: []string{"Duplicate IP address found in global table: 10.0.0.1/24"},
+			},
+		},
+		{
+			name: "Nested errors",
+			body: `{"site": {"slug": ["This field is required."]}, "vid": "Invalid value."}`,
+			want: &ValidationError{
+				FieldErrors: map[string][]string{
+					"site": {`{"slug":["This field is required."]}`},
+					"vid":  {"Invalid value."},
+				},
+			},
+		},
+		{
+			name:    "Not a json object",
+			body:    `<html>Bad request</html>`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseValidationError([]byte(tt.body))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseValidationError() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseValidationError() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNetboxClient_Conflicts(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"name": ["tag with this name already exists."]}`))
+	}))
+	defer mockServer.Close()
+	api := &NetboxClient{
+		HTTPClient: mockServer.Client(),
+		Logger:     &logger.Logger{Logger: log.Default()},
+		BaseURL:    mockServer.URL,
+		Timeout:    constants.DefaultAPITimeout,
+	}
+	ctx := context.WithValue(context.Background(), constants.CtxSourceKey, "vcenter")
+
+	_, err := Create(ctx, api, &objects.Tag{Name: "production"})
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("Create() error = %v, want ValidationError", err)
+	}
+
+	wantConflicts := []Conflict{
+		{
+			ValidationError: ValidationError{
+				ObjectPath: constants.TagsAPIPath,
+				Action:     PlannedActionCreate,
+				FieldErrors: map[string][]string{
+					"name": {"tag with this name already exists."},
+				},
+			},
+			Source: "vcenter",
+			Object: (&objects.Tag{Name: "production"}).String(),
+		},
+	}
+	if got := api.Conflicts(); !reflect.DeepEqual(got, wantConflicts) {
+		t.Errorf("Conflicts() = %+v, want %+v", got, wantConflicts)
+	}
+}
@@ -0,0 +1,159 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/src-doo/netbox-ssot/internal/constants"
+)
+
+// Actions that can be recorded in dry run mode.
+const (
+	PlannedActionCreate = "create"
+	PlannedActionUpdate = "update"
+	PlannedActionDelete = "delete"
+)
+
+// PlannedChange represents a single write request, that would have been
+// sent to the Netbox API if the client wasn't in dry run mode.
+type PlannedChange struct {
+	// Action is one of PlannedActionCreate, PlannedActionUpdate or PlannedActionDelete.
+	Action string `json:"action"`
+	// ObjectPath is the api path of the object (e.g. /api/dcim/devices/).
+	ObjectPath constants.APIPath `json:"object_path"`
+	// ObjectID is the id of the object. For created objects this is
+	// a negative placeholder id.
+	ObjectID int `json:"object_id"`
+	// Object is a string representation of the object.
+	Object string `json:"object,omitempty"`
+	// Data contains the fields that would be sent to the API.
+	Data map[string]interface{} `json:"data,omitempty"`
+}
+
+// PlannedChanges returns all changes recorded while the client was in dry run mode.
+func (api *NetboxClient) PlannedChanges() []PlannedChange {
+	api.dryRunLock.Lock()
+	defer api.dryRunLock.Unlock()
+	plannedChanges := make([]PlannedChange, len(api.plannedChanges))
+	copy(plannedChanges, api.plannedChanges)
+	return plannedChanges
+}
+
+func (api *NetboxClient) recordChange(ctx context.Context, change PlannedChange) {
+	api.dryRunLock.Lock()
+	defer api.dryRunLock.Unlock()
+	api.plannedChanges = append(api.plannedChanges, change)
+	api.Logger.Debugf(
+		ctx,
+		"Dry run: skipping %s of %s with id %d",
+		change.Action,
+		change.ObjectPath,
+		change.ObjectID,
+	)
+}
+
+// dryRunCreate records the creation of the object and returns its copy
+// with a unique negative placeholder id. Placeholder ids are necessary,
+// because some inventory indexes are keyed by the id of the related object.
+func dryRunCreate[T any](
+	ctx context.Context,
+	api *NetboxClient,
+	objectPath constants.APIPath,
+	object *T,
+	body map[string]interface{},
+) (*T, error) {
+	objectJSON, err := json.Marshal(object)
+	if err != nil {
+		return nil, err
+	}
+	var objectMap map[string]interface{}
+	err = json.Unmarshal(objectJSON, &objectMap)
+	if err != nil {
+		return nil, err
+	}
+
+	api.dryRunLock.Lock()
+	api.dryRunID--
+	objectID := api.dryRunID
+	api.dryRunLock.Unlock()
+
+	objectMap["id"] = objectID
+	objectJSON, err = json.Marshal(objectMap)
+	if err != nil {
+		return nil, err
+	}
+	var createdObject T
+	err = json.Unmarshal(objectJSON, &createdObject)
+	if err != nil {
+		return nil, err
+	}
+
+	api.dryRunLock.Lock()
+	if api.dryRunObjects == nil {
+		api.dryRunObjects = map[constants.APIPath]map[int][]byte{}
+	}
+	if api.dryRunObjects[objectPath] == nil {
+		api.dryRunObjects[objectPath] = map[int][]byte{}
+	}
+	api.dryRunObjects[objectPath][objectID] = objectJSON
+	api.dryRunLock.Unlock()
+
+	api.recordChange(ctx, PlannedChange{
+		Action:     PlannedActionCreate,
+		ObjectPath: objectPath,
+		ObjectID:   objectID,
+		Object:     fmt.Sprintf("%v", object),
+		Data:       body,
+	})
+	return &createdObject, nil
+}
+
+// dryRunPatch records the update of the object and returns the object
+// in its current (unpatched) state.
+func dryRunPatch[T any](
+	ctx context.Context,
+	api *NetboxClient,
+	objectPath constants.APIPath,
+	objectID int,
+	body map[string]interface{},
+) (*T, error) {
+	api.dryRunLock.Lock()
+	objectJSON, ok := api.dryRunObjects[objectPath][objectID]
+	api.dryRunLock.Unlock()
+	if !ok {
+		response, err := api.doRequest(
+			ctx,
+			http.MethodGet,
+			fmt.Sprintf("%s%d/", objectPath, objectID),
+			nil,
+		)
+		if err != nil {
+			return nil, err
+		}
+		if response.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf(
+				"unexpected status code: %d: %s",
+				response.StatusCode,
+				response.Body,
+			)
+		}
+		objectJSON = response.Body
+	}
+
+	var object T
+	err := json.Unmarshal(objectJSON, &object)
+	if err != nil {
+		return nil, err
+	}
+
+	api.recordChange(ctx, PlannedChange{
+		Action:     PlannedActionUpdate,
+		ObjectPath: objectPath,
+		ObjectID:   objectID,
+		Object:     fmt.Sprintf("%v", object),
+		Data:       body,
+	})
+	return &object, nil
+}
@@ -0,0 +1,96 @@
+package service
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/src-doo/netbox-ssot/internal/constants"
+	"github.com/src-doo/netbox-ssot/internal/logger"
+	"github.com/src-doo/netbox-ssot/internal/netbox/objects"
+)
+
+func TestDryRun(t *testing.T) {
+	ctx := context.WithValue(context.Background(), constants.CtxSourceKey, "test")
+	// Every request sent to the api fails, so dry run must not send any.
+	api := &NetboxClient{
+		HTTPClient: &http.Client{Transport: &FailingHTTPClient{}},
+		Logger:     &logger.Logger{Logger: log.Default()},
+		APIToken:   "testtoken",
+		Timeout:    constants.DefaultAPITimeout,
+		DryRun:     true,
+	}
+
+	tenant := &objects.Tenant{Name: "DryRunTenant", Slug: "dry-run-tenant"}
+	createdTenant, err := Create(ctx, api, tenant)
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if createdTenant.ID >= 0 {
+		t.Errorf("Create() id = %d, want negative placeholder id", createdTenant.ID)
+	}
+	if createdTenant.Name != tenant.Name {
+		t.Errorf("Create() name = %s, want %s", createdTenant.Name, tenant.Name)
+	}
+
+	secondTenant, err := Create(ctx, api, &objects.Tenant{Name: "DryRunTenant2"})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if secondTenant.ID == createdTenant.ID {
+		t.Errorf("Create() returned duplicate placeholder id %d", secondTenant.ID)
+	}
+
+	patchedTenant, err := Patch[objects.Tenant](
+		ctx,
+		api,
+		createdTenant.ID,
+		map[string]interface{}{"description": "patched"},
+	)
+	if err != nil {
+		t.Fatalf("Patch() error = %v", err)
+	}
+	if !reflect.DeepEqual(patchedTenant, createdTenant) {
+		t.Errorf("Patch() = %v, want %v", patchedTenant, createdTenant)
+	}
+
+	err = api.DeleteObject(ctx, createdTenant)
+	if err != nil {
+		t.Fatalf("DeleteObject() error = %v", err)
+	}
+
+	err = api.BulkDeleteObjects(ctx, constants.TenantsAPIPath, map[int]bool{secondTenant.ID: true})
+	if err != nil {
+		t.Fatalf("BulkDeleteObjects() error = %v", err)
+	}
+
+	wantActions := []string{
+		PlannedActionCreate,
+		PlannedActionCreate,
+		PlannedActionUpdate,
+		PlannedActionDelete,
+		PlannedActionDelete,
+	}
+	plannedChanges := api.PlannedChanges()
+	if len(plannedChanges) != len(wantActions) {
+		t.Fatalf("PlannedChanges() = %v, want %d changes", plannedChanges, len(wantActions))
+	}
+	for i, change := range plannedChanges {
+		if change.Action != wantActions[i] {
+			t.Errorf("PlannedChanges()[%d].Action = %s, want %s", i, change.Action, wantActions[i])
+		}
+		if change.ObjectPath != constants.TenantsAPIPath {
+			t.Errorf(
+				"PlannedChanges()[%d].ObjectPath = %s, want %s",
+				i,
+				change.ObjectPath,
+				constants.TenantsAPIPath,
+			)
+		}
+	}
+	if plannedChanges[2].Data["description"] != "patched" {
+		t.Errorf("PlannedChanges()[2].Data = %v, want patched description", plannedChanges[2].Data)
+	}
+}
@@ -0,0 +1,99 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/src-doo/netbox-ssot/internal/constants"
+	"github.com/src-doo/netbox-ssot/internal/netbox/mapper"
+)
+
+// PermissionAction is an action of netbox object permissions.
+type PermissionAction string
+
+const (
+	PermissionView   PermissionAction = "view"
+	PermissionAdd    PermissionAction = "add"
+	PermissionChange PermissionAction = "change"
+	PermissionDelete PermissionAction = "delete"
+)
+
+// PermissionName returns the name of the netbox permission for the action
+// on objects of the api path, e.g. dcim.add_interface.
+func PermissionName(path constants.APIPath, action PermissionAction) string {
+	contentType, ok := mapper.ContentType(path)
+	if !ok {
+		return fmt.Sprintf("%s %s", action, path)
+	}
+	appLabel, model, _ := strings.Cut(string(contentType), ".")
+	return fmt.Sprintf("%s.%s_%s", appLabel, action, model)
+}
+
+// permissionProbe returns a request, which netbox authorizes with the permission
+// for the action on objects of the api path, but which doesn't change any objects.
+// Write actions are probed with bulk requests without any objects.
+func permissionProbe(path constants.APIPath, action PermissionAction) (string, string, io.Reader) {
+	switch action {
+	case PermissionAdd:
+		return http.MethodPost, string(path), bytes.NewBufferString("[]")
+	case PermissionChange:
+		return http.MethodPatch, string(path), bytes.NewBufferString("[]")
+	case PermissionDelete:
+		return http.MethodDelete, string(path), bytes.NewBufferString("[]")
+	default:
+		return http.MethodGet, fmt.Sprintf("%s?limit=1&brief=true", path), nil
+	}
+}
+
+// MissingPermissions probes the permissions of the api token for the required
+// actions on objects of each api path, and returns sorted names of missing ones.
+// Permissions, which can't be verified, because netbox neither authorized nor
+// denied the probe, are logged and not reported as missing.
+// Probes of write actions are sent to netbox even in dry run mode.
+func (api *NetboxClient) MissingPermissions(
+	ctx context.Context,
+	required map[constants.APIPath][]PermissionAction,
+) ([]string, error) {
+	var lock sync.Mutex
+	var missing []string
+	var probeErr error
+	var wg sync.WaitGroup
+	for path, actions := range required {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for _, action := range actions {
+				method, probePath, body := permissionProbe(path, action)
+				response, err := api.doRequest(ctx, method, probePath, body)
+				lock.Lock()
+				switch {
+				case err != nil:
+					probeErr = fmt.Errorf("probe permission %s: %s", PermissionName(path, action), err)
+				case response.StatusCode == http.StatusForbidden:
+					missing = append(missing, PermissionName(path, action))
+				case response.StatusCode >= http.StatusBadRequest:
+					api.Logger.Warningf(
+						ctx,
+						"Can't verify permission %s, netbox responded with status %d: %s",
+						PermissionName(path, action),
+						response.StatusCode,
+						response.Body,
+					)
+				}
+				lock.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	if probeErr != nil {
+		return nil, probeErr
+	}
+	slices.Sort(missing)
+	return missing, nil
+}
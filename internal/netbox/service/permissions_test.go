@@ -0,0 +1,103 @@
+package service
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/src-doo/netbox-ssot/internal/constants"
+	"github.com/src-doo/netbox-ssot/internal/logger"
+	"github.com/src-doo/netbox-ssot/internal/netbox/netboxtest"
+	"github.com/src-doo/netbox-ssot/internal/netbox/objects"
+)
+
+func TestPermissionName(t *testing.T) {
+	tests := []struct {
+		path   constants.APIPath
+		action PermissionAction
+		want   string
+	}{
+		{constants.InterfacesAPIPath, PermissionAdd, "dcim.add_interface"},
+		{constants.VirtualMachinesAPIPath, PermissionView, "virtualization.view_virtualmachine"},
+		{constants.CustomFieldsAPIPath, PermissionChange, "extras.change_customfield"},
+		{constants.BranchesAPIPath, PermissionDelete, "delete /api/plugins/branching/branches/"},
+	}
+	for _, tt := range tests {
+		if got := PermissionName(tt.path, tt.action); got != tt.want {
+			t.Errorf("PermissionName(%s, %s) = %s, want %s", tt.path, tt.action, got, tt.want)
+		}
+	}
+}
+
+func TestNetboxClient_MissingPermissions(t *testing.T) {
+	s := netboxtest.NewServer()
+	defer s.Close()
+	s.DeniedPermissions = map[string]bool{
+		"dcim.add_interface":    true,
+		"dcim.delete_interface": true,
+		"extras.view_tag":       true,
+	}
+	ctx := context.Background()
+	api := &NetboxClient{
+		HTTPClient: s.Client(),
+		Logger:     &logger.Logger{Logger: log.Default()},
+		BaseURL:    s.URL,
+		Timeout:    constants.DefaultAPITimeout,
+	}
+	device, err := Create(ctx, api, &objects.Device{Name: "device"})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	missing, err := api.MissingPermissions(ctx, map[constants.APIPath][]PermissionAction{
+		constants.InterfacesAPIPath: {PermissionView, PermissionAdd, PermissionChange, PermissionDelete},
+		constants.TagsAPIPath:       {PermissionView},
+		constants.DevicesAPIPath:    {PermissionView, PermissionAdd, PermissionChange, PermissionDelete},
+	})
+	if err != nil {
+		t.Fatalf("MissingPermissions() error = %v", err)
+	}
+	want := []string{"dcim.add_interface", "dcim.delete_interface", "extras.view_tag"}
+	if !reflect.DeepEqual(missing, want) {
+		t.Errorf("MissingPermissions() = %v, want %v", missing, want)
+	}
+
+	// Probes don't change any objects
+	devices, err := GetAll[objects.Device](ctx, api, "")
+	if err != nil {
+		t.Fatalf("GetAll() error = %v", err)
+	}
+	if len(devices) != 1 || devices[0].ID != device.ID || devices[0].Name != "device" {
+		t.Errorf("GetAll() = %v, want only the created device", devices)
+	}
+}
+
+func TestNetboxClient_MissingPermissionsUnverifiable(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"detail": "Method not allowed."})
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{"count": 0, "results": []interface{}{}})
+	}))
+	defer server.Close()
+	api := &NetboxClient{
+		HTTPClient: server.Client(),
+		Logger:     &logger.Logger{Logger: log.Default()},
+		BaseURL:    server.URL,
+		Timeout:    constants.DefaultAPITimeout,
+	}
+
+	missing, err := api.MissingPermissions(context.Background(), map[constants.APIPath][]PermissionAction{
+		constants.DevicesAPIPath: {PermissionView, PermissionAdd},
+	})
+	if err != nil {
+		t.Fatalf("MissingPermissions() error = %v", err)
+	}
+	if len(missing) != 0 {
+		t.Errorf("MissingPermissions() = %v, want none", missing)
+	}
+}
@@ -8,7 +8,9 @@ import (
 	"net/http"
 	"reflect"
 
+	"github.com/src-doo/netbox-ssot/internal/audit"
 	"github.com/src-doo/netbox-ssot/internal/constants"
+	"github.com/src-doo/netbox-ssot/internal/logger"
 	"github.com/src-doo/netbox-ssot/internal/netbox/mapper"
 	"github.com/src-doo/netbox-ssot/internal/netbox/objects"
 	"github.com/src-doo/netbox-ssot/internal/utils"
@@ -30,7 +32,7 @@ type Response[T any] struct {
 func GetVersion(ctx context.Context, netboxClient *NetboxClient) (string, error) {
 	var versionResponse VersionResponse
 	netboxClient.Logger.Debugf(ctx, "Getting netbox's version")
-	response, err := netboxClient.doRequest(http.MethodGet, "/api/status", nil)
+	response, err := netboxClient.doRequest(ctx, http.MethodGet, "/api/status", nil)
 	if err != nil {
 		return "", err
 	}
@@ -46,7 +48,9 @@ func GetVersion(ctx context.Context, netboxClient *NetboxClient) (string, error)
 }
 
 // GetAll queries all objects of type T from Netbox's API.
-// It is querying objects via pagination of limit=200.
+// It is querying objects via pagination of limit=250.
+// If the client has a Cache, only objects changed since
+// the previous query are fetched.
 //
 // extraParams in a string format of: &extraParam1=...&extraParam2=...
 func GetAll[T any](
@@ -60,21 +64,54 @@ func GetAll[T any](
 	if path == "" {
 		return nil, fmt.Errorf("path not found for type %T", dummy)
 	}
-	limit := 250
-	offset := 0
 
 	netboxClient.Logger.Debugf(ctx, "Getting all %T from Netbox", dummy)
 
+	var rawResults []json.RawMessage
+	var err error
+	if netboxClient.Cache != nil {
+		rawResults, err = netboxClient.getAllCached(ctx, path, extraParams)
+	} else {
+		rawResults, err = netboxClient.getAllRaw(ctx, path, extraParams)
+	}
+	if err != nil {
+		return nil, err
+	}
+	for _, rawResult := range rawResults {
+		var result T
+		err = json.Unmarshal(rawResult, &result)
+		if err != nil {
+			return nil, err
+		}
+		allResults = append(allResults, result)
+	}
+
+	netboxClient.Logger.Debugf(ctx, "Successfully received all %T: %v", dummy, allResults)
+
+	return allResults, nil
+}
+
+// getAllRaw queries all objects on the api path, page by page,
+// and returns them undecoded.
+func (api *NetboxClient) getAllRaw(
+	ctx context.Context,
+	path constants.APIPath,
+	extraParams string,
+) ([]json.RawMessage, error) {
+	var allResults []json.RawMessage
+	limit := 250
+	offset := 0
+
 	for {
-		netboxClient.Logger.Debugf(
+		api.Logger.Debugf(
 			ctx,
-			"Getting %T with limit=%d and offset=%d",
-			dummy,
+			"Getting %s with limit=%d and offset=%d",
+			path,
 			limit,
 			offset,
 		)
 		queryPath := fmt.Sprintf("%s?limit=%d&offset=%d%s", path, limit, offset, extraParams)
-		response, err := netboxClient.doRequest(http.MethodGet, queryPath, nil)
+		response, err := api.doRequest(ctx, http.MethodGet, queryPath, nil)
 		if err != nil {
 			return nil, err
 		}
@@ -87,7 +124,7 @@ func GetAll[T any](
 			)
 		}
 
-		var responseObj Response[T]
+		var responseObj Response[json.RawMessage]
 		err = json.Unmarshal(response.Body, &responseObj)
 		if err != nil {
 			return nil, err
@@ -100,9 +137,6 @@ func GetAll[T any](
 		}
 		offset += limit
 	}
-
-	netboxClient.Logger.Debugf(ctx, "Successfully received all %T: %v", dummy, allResults)
-
 	return allResults, nil
 }
 
@@ -120,6 +154,11 @@ func Patch[T any](
 		return nil, fmt.Errorf("path not found for type %T", dummy)
 	}
 	path := fmt.Sprintf("%s%d/", objectPath, objectID)
+	name, ok := body["name"].(string)
+	if !ok {
+		name = fmt.Sprintf("id %d", objectID)
+	}
+	ctx = logger.WithObject(ctx, fmt.Sprintf("%T", dummy), name, logger.ActionPatch)
 	netboxClient.Logger.Debugf(
 		ctx,
 		"Patching %T with path %s with data: %v",
@@ -128,19 +167,30 @@ func Patch[T any](
 		body,
 	)
 
+	if netboxClient.DryRun {
+		return dryRunPatch[T](ctx, netboxClient, objectPath, objectID, body)
+	}
+
 	requestBody, err := json.Marshal(body)
 	if err != nil {
 		return nil, err
 	}
 
 	requestBodyBuffer := bytes.NewBuffer(requestBody)
-	response, err := netboxClient.doRequest(http.MethodPatch, path, requestBodyBuffer)
+	response, err := netboxClient.doRequest(ctx, http.MethodPatch, path, requestBodyBuffer)
 	if err != nil {
 		return nil, err
 	}
 
 	if response.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("unexpected status code: %d: %s", response.StatusCode, response.Body)
+		return nil, netboxClient.writeError(
+			ctx,
+			response,
+			PlannedActionUpdate,
+			objectPath,
+			objectID,
+			body,
+		)
 	}
 
 	var objectResponse T
@@ -149,6 +199,8 @@ func Patch[T any](
 		return nil, err
 	}
 
+	netboxClient.countWrite(PlannedActionUpdate, 1)
+	netboxClient.auditWrite(ctx, audit.ActionUpdate, objectPath, objectID, body)
 	netboxClient.Logger.Debugf(ctx, "Successfully patched %T: %v", dummy, objectResponse)
 	return &objectResponse, nil
 }
@@ -160,6 +212,7 @@ func Create[T any](ctx context.Context, netboxClient *NetboxClient, object *T) (
 	if objectPath == "" {
 		return nil, fmt.Errorf("path not found for type %T", dummy)
 	}
+	ctx = logger.WithObject(ctx, fmt.Sprintf("%T", dummy), objectName(object), logger.ActionCreate)
 
 	netboxClient.Logger.Debugf(
 		ctx,
@@ -168,19 +221,22 @@ func Create[T any](ctx context.Context, netboxClient *NetboxClient, object *T) (
 		objectPath,
 		object,
 	)
+	if netboxClient.DryRun {
+		return dryRunCreate(ctx, netboxClient, objectPath, object, utils.StructToNetboxJSONMap(object))
+	}
 	requestBody, err := utils.NetboxJSONMarshal(object)
 	if err != nil {
 		return nil, err
 	}
 
 	requestBodyBuffer := bytes.NewBuffer(requestBody)
-	response, err := netboxClient.doRequest(http.MethodPost, string(objectPath), requestBodyBuffer)
+	response, err := netboxClient.doRequest(ctx, http.MethodPost, string(objectPath), requestBodyBuffer)
 	if err != nil {
 		return nil, err
 	}
 
 	if response.StatusCode != http.StatusCreated {
-		return nil, fmt.Errorf("unexpected status code: %d: %s", response.StatusCode, response.Body)
+		return nil, netboxClient.writeError(ctx, response, PlannedActionCreate, objectPath, 0, object)
 	}
 
 	var objectResponse T
@@ -189,6 +245,16 @@ func Create[T any](ctx context.Context, netboxClient *NetboxClient, object *T) (
 		return nil, err
 	}
 
+	netboxClient.countWrite(PlannedActionCreate, 1)
+	if createdObject, ok := any(&objectResponse).(objects.IDItem); ok {
+		netboxClient.auditWrite(
+			ctx,
+			audit.ActionCreate,
+			objectPath,
+			createdObject.GetID(),
+			utils.StructToNetboxJSONMap(object),
+		)
+	}
 	netboxClient.Logger.Debugf(ctx, "Successfully created %T: %v", dummy, objectResponse)
 	return &objectResponse, nil
 }
@@ -209,6 +275,18 @@ func (api *NetboxClient) BulkDeleteObjects(
 		ids = append(ids, id)
 	}
 
+	if api.DryRun {
+		for _, id := range ids {
+			api.recordChange(ctx, PlannedChange{
+				Action:     PlannedActionDelete,
+				ObjectPath: objectPath,
+				ObjectID:   id,
+			})
+		}
+		return nil
+	}
+
+	ctx = logger.WithObject(ctx, string(objectPath), "", logger.ActionDelete)
 	for i := 0; i < len(ids); i += pageSize {
 		api.Logger.Debugf(
 			ctx,
@@ -235,7 +313,7 @@ func (api *NetboxClient) BulkDeleteObjects(
 		}
 
 		requestBodyBuffer := bytes.NewBuffer(requestBody)
-		response, err := api.doRequest(http.MethodDelete, string(objectPath), requestBodyBuffer)
+		response, err := api.doRequest(ctx, http.MethodDelete, string(objectPath), requestBodyBuffer)
 		if err != nil {
 			return err
 		}
@@ -243,6 +321,10 @@ func (api *NetboxClient) BulkDeleteObjects(
 		if response.StatusCode != http.StatusNoContent {
 			return fmt.Errorf("unexpected status code: %d: %s", response.StatusCode, response.Body)
 		}
+		api.countWrite(PlannedActionDelete, end-i)
+		for _, id := range ids[i:end] {
+			api.auditWrite(ctx, audit.ActionDelete, objectPath, id, nil)
+		}
 	}
 	api.Logger.Debugf(ctx, "Successfully deleted all objects of path %s", objectPath)
 
@@ -255,9 +337,20 @@ func (api *NetboxClient) BulkDeleteObjects(
 func (api *NetboxClient) DeleteObject(ctx context.Context, idItem objects.IDItem) error {
 	id := idItem.GetID()
 	objectPath := idItem.GetAPIPath()
+	ctx = logger.WithObject(ctx, fmt.Sprintf("%T", idItem), objectName(idItem), logger.ActionDelete)
 	api.Logger.Debugf(ctx, "Deleting object with id %d on route %s", id, objectPath)
 
-	response, err := api.doRequest(http.MethodDelete, fmt.Sprintf("%s%d/", objectPath, id), nil)
+	if api.DryRun {
+		api.recordChange(ctx, PlannedChange{
+			Action:     PlannedActionDelete,
+			ObjectPath: objectPath,
+			ObjectID:   id,
+			Object:     fmt.Sprintf("%v", idItem),
+		})
+		return nil
+	}
+
+	response, err := api.doRequest(ctx, http.MethodDelete, fmt.Sprintf("%s%d/", objectPath, id), nil)
 	if err != nil {
 		return err
 	}
@@ -265,5 +358,22 @@ func (api *NetboxClient) DeleteObject(ctx context.Context, idItem objects.IDItem
 	if response.StatusCode != http.StatusNoContent {
 		return fmt.Errorf("unexpected status code: %d: %s", response.StatusCode, response.Body)
 	}
+	api.countWrite(PlannedActionDelete, 1)
+	api.auditWrite(ctx, audit.ActionDelete, objectPath, id, nil)
 	return nil
 }
+
+// objectName returns a human readable name of the object,
+// which is used in log lines.
+func objectName(object interface{}) string {
+	value := reflect.Indirect(reflect.ValueOf(object))
+	if value.Kind() == reflect.Struct {
+		for _, fieldName := range []string{"Name", "Address", "Prefix", "Model", "Slug"} {
+			field := value.FieldByName(fieldName)
+			if field.IsValid() && field.Kind() == reflect.String && field.String() != "" {
+				return field.String()
+			}
+		}
+	}
+	return fmt.Sprintf("%v", object)
+}
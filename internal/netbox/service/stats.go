@@ -0,0 +1,30 @@
+package service
+
+// WriteStats holds the number of objects written to the Netbox API.
+// Writes skipped in dry run mode are not counted.
+type WriteStats struct {
+	Created int `json:"created"`
+	Updated int `json:"updated"`
+	Deleted int `json:"deleted"`
+}
+
+// WriteStats returns the number of objects created, updated
+// and deleted by the client so far.
+func (api *NetboxClient) WriteStats() WriteStats {
+	api.statsLock.Lock()
+	defer api.statsLock.Unlock()
+	return api.writeStats
+}
+
+func (api *NetboxClient) countWrite(action string, count int) {
+	api.statsLock.Lock()
+	defer api.statsLock.Unlock()
+	switch action {
+	case PlannedActionCreate:
+		api.writeStats.Created += count
+	case PlannedActionUpdate:
+		api.writeStats.Updated += count
+	case PlannedActionDelete:
+		api.writeStats.Deleted += count
+	}
+}
@@ -0,0 +1,235 @@
+// Package notify sends a summary of each netbox-ssot run to the
+// configured notification targets (generic json webhook, Slack or MS Teams).
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/src-doo/netbox-ssot/internal/constants"
+	"github.com/src-doo/netbox-ssot/internal/logger"
+	"github.com/src-doo/netbox-ssot/internal/parser"
+)
+
+// Statuses of a run.
+const (
+	StatusSuccess = "success"
+	StatusFailure = "failure"
+)
+
+// Summary of a run, that is sent to notification targets.
+type Summary struct {
+	Status    string    `json:"status"`
+	StartTime time.Time `json:"start_time"`
+	// Duration of the run in seconds
+	Duration float64 `json:"duration"`
+	// Errors of the failed sources, indexed by the source name
+	FailedSources map[string]string `json:"failed_sources,omitempty"`
+	// Error of the run, that is not related to a single source
+	// (e.g. failed removal of orphans)
+	Error    string `json:"error,omitempty"`
+	Created  int    `json:"created"`
+	Updated  int    `json:"updated"`
+	Orphaned int    `json:"orphaned"`
+}
+
+// NewSummary creates a summary of the run started at startTime.
+// Status is derived from failed sources and runErr.
+func NewSummary(
+	startTime time.Time,
+	sourceErrors map[string]error,
+	runErr error,
+	created, updated, orphaned int,
+) Summary {
+	summary := Summary{
+		Status:    StatusSuccess,
+		StartTime: startTime,
+		Duration:  time.Since(startTime).Round(time.Second).Seconds(),
+		Created:   created,
+		Updated:   updated,
+		Orphaned:  orphaned,
+	}
+	if len(sourceErrors) > 0 {
+		summary.FailedSources = make(map[string]string, len(sourceErrors))
+		for sourceName, err := range sourceErrors {
+			summary.FailedSources[sourceName] = err.Error()
+		}
+	}
+	if runErr != nil {
+		summary.Error = runErr.Error()
+	}
+	if len(summary.FailedSources) > 0 || summary.Error != "" {
+		summary.Status = StatusFailure
+	}
+	return summary
+}
+
+// Failed reports whether a source or the run failed.
+func (s Summary) Failed() bool {
+	return s.Status == StatusFailure
+}
+
+// Changed reports whether any object was created, updated or orphaned.
+func (s Summary) Changed() bool {
+	return s.Created > 0 || s.Updated > 0 || s.Orphaned > 0
+}
+
+// Title returns a one line description of the run.
+func (s Summary) Title() string {
+	if s.Failed() {
+		return fmt.Sprintf("%s netbox-ssot run failed", constants.WarningSign)
+	}
+	return fmt.Sprintf("%s netbox-ssot run succeeded", constants.CheckMark)
+}
+
+// Lines returns the details of the run, one per line.
+func (s Summary) Lines() []string {
+	lines := []string{
+		fmt.Sprintf("Started at %s, took %s", s.StartTime.Format(time.RFC3339), time.Duration(s.Duration)*time.Second),
+		fmt.Sprintf("Created: %d, updated: %d, orphaned: %d", s.Created, s.Updated, s.Orphaned),
+	}
+	sourceNames := make([]string, 0, len(s.FailedSources))
+	for sourceName := range s.FailedSources {
+		sourceNames = append(sourceNames, sourceName)
+	}
+	sort.Strings(sourceNames)
+	for _, sourceName := range sourceNames {
+		lines = append(lines, fmt.Sprintf("Source %s failed: %s", sourceName, s.FailedSources[sourceName]))
+	}
+	if s.Error != "" {
+		lines = append(lines, "Error: "+s.Error)
+	}
+	return lines
+}
+
+// Notifier sends run summaries to notification targets.
+type Notifier struct {
+	logger     *logger.Logger
+	httpClient *http.Client
+	targets    []parser.NotificationConfig
+}
+
+// New creates a Notifier for the given targets.
+func New(logger *logger.Logger, targets []parser.NotificationConfig) *Notifier {
+	return &Notifier{
+		logger:     logger,
+		httpClient: &http.Client{Timeout: constants.DefaultAPITimeout * time.Second},
+		targets:    targets,
+	}
+}
+
+// Notify sends the summary to all targets, whose policy matches the summary.
+// Failure of one target doesn't prevent sending to the others.
+func (n *Notifier) Notify(ctx context.Context, summary Summary) error {
+	var errs []error
+	for _, target := range n.targets {
+		if !shouldNotify(target.Policy, summary) {
+			n.logger.Debugf(ctx, "Skipping notification %s because of its policy %s", target.Name, target.Policy)
+			continue
+		}
+		err := n.send(ctx, target, summary)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("notification %s: %s", target.Name, err))
+			continue
+		}
+		n.logger.Debugf(ctx, "Sent notification %s", target.Name)
+	}
+	return errors.Join(errs...)
+}
+
+func shouldNotify(policy string, summary Summary) bool {
+	switch policy {
+	case constants.NotificationPolicyFailure:
+		return summary.Failed()
+	case constants.NotificationPolicyChange:
+		return summary.Failed() || summary.Changed()
+	default:
+		return true
+	}
+}
+
+func (n *Notifier) send(ctx context.Context, target parser.NotificationConfig, summary Summary) error {
+	body, err := payload(target.Type, summary)
+	if err != nil {
+		return fmt.Errorf("create payload: %s", err)
+	}
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, target.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("create request: %s", err)
+	}
+	request.Header.Set("Content-Type", "application/json")
+	response, err := n.httpClient.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	if response.StatusCode < http.StatusOK || response.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("unexpected status code: %d", response.StatusCode)
+	}
+	return nil
+}
+
+// payload returns the summary in the format expected by the target type.
+func payload(targetType string, summary Summary) ([]byte, error) {
+	switch targetType {
+	case constants.NotificationTypeSlack:
+		return json.Marshal(slackPayload(summary))
+	case constants.NotificationTypeTeams:
+		return json.Marshal(teamsPayload(summary))
+	default:
+		return json.Marshal(summary)
+	}
+}
+
+// slackPayload formats the summary as a Slack incoming webhook message.
+func slackPayload(summary Summary) map[string]interface{} {
+	return map[string]interface{}{
+		"text": fmt.Sprintf("*%s*\n%s", summary.Title(), strings.Join(summary.Lines(), "\n")),
+	}
+}
+
+// teamsPayload formats the summary as an adaptive card, accepted
+// by MS Teams workflow webhooks.
+func teamsPayload(summary Summary) map[string]interface{} {
+	titleColor := "Good"
+	if summary.Failed() {
+		titleColor = "Attention"
+	}
+	body := []map[string]interface{}{
+		{
+			"type":   "TextBlock",
+			"text":   summary.Title(),
+			"weight": "Bolder",
+			"size":   "Medium",
+			"color":  titleColor,
+		},
+	}
+	for _, line := range summary.Lines() {
+		body = append(body, map[string]interface{}{
+			"type": "TextBlock",
+			"text": line,
+			"wrap": true,
+		})
+	}
+	return map[string]interface{}{
+		"type": "message",
+		"attachments": []map[string]interface{}{
+			{
+				"contentType": "application/vnd.microsoft.card.adaptive",
+				"content": map[string]interface{}{
+					"$schema": "http://adaptivecards.io/schemas/adaptive-card.json",
+					"type":    "AdaptiveCard",
+					"version": "1.4",
+					"body":    body,
+				},
+			},
+		},
+	}
+}
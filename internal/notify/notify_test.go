@@ -0,0 +1,169 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/src-doo/netbox-ssot/internal/constants"
+	"github.com/src-doo/netbox-ssot/internal/logger"
+	"github.com/src-doo/netbox-ssot/internal/parser"
+)
+
+func TestNewSummary(t *testing.T) {
+	startTime := time.Now().Add(-90 * time.Second)
+	tests := []struct {
+		name         string
+		sourceErrors map[string]error
+		runErr       error
+		created      int
+		wantStatus   string
+		wantChanged  bool
+	}{
+		{
+			name:       "Successful run without changes",
+			wantStatus: StatusSuccess,
+		},
+		{
+			name:        "Successful run with changes",
+			created:     3,
+			wantStatus:  StatusSuccess,
+			wantChanged: true,
+		},
+		{
+			name:         "Failed source",
+			sourceErrors: map[string]error{"prodvmware": errors.New("connection refused")},
+			wantStatus:   StatusFailure,
+		},
+		{
+			name:       "Failed run",
+			runErr:     errors.New("orphans"),
+			wantStatus: StatusFailure,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			summary := NewSummary(startTime, tt.sourceErrors, tt.runErr, tt.created, 0, 0)
+			if summary.Status != tt.wantStatus {
+				t.Errorf("Status = %s, want %s", summary.Status, tt.wantStatus)
+			}
+			if summary.Changed() != tt.wantChanged {
+				t.Errorf("Changed() = %v, want %v", summary.Changed(), tt.wantChanged)
+			}
+			if summary.Duration != 90 { //nolint:mnd
+				t.Errorf("Duration = %v, want 90", summary.Duration)
+			}
+		})
+	}
+}
+
+func TestShouldNotify(t *testing.T) {
+	success := Summary{Status: StatusSuccess}
+	changed := Summary{Status: StatusSuccess, Updated: 1}
+	failure := Summary{Status: StatusFailure}
+	tests := []struct {
+		policy  string
+		summary Summary
+		want    bool
+	}{
+		{constants.NotificationPolicyAlways, success, true},
+		{constants.NotificationPolicyFailure, success, false},
+		{constants.NotificationPolicyFailure, changed, false},
+		{constants.NotificationPolicyFailure, failure, true},
+		{constants.NotificationPolicyChange, success, false},
+		{constants.NotificationPolicyChange, changed, true},
+		{constants.NotificationPolicyChange, failure, true},
+	}
+	for _, tt := range tests {
+		if got := shouldNotify(tt.policy, tt.summary); got != tt.want {
+			t.Errorf("shouldNotify(%s, %+v) = %v, want %v", tt.policy, tt.summary, got, tt.want)
+		}
+	}
+}
+
+func TestNotifier_Notify(t *testing.T) {
+	received := map[string]map[string]interface{}{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/broken" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		var payload map[string]interface{}
+		if err := json.Unmarshal(body, &payload); err != nil {
+			t.Errorf("invalid json payload: %s", body)
+		}
+		received[r.URL.Path] = payload
+	}))
+	defer server.Close()
+
+	targets := []parser.NotificationConfig{
+		{
+			Name:   "generic",
+			Type:   constants.NotificationTypeWebhook,
+			URL:    server.URL + "/generic",
+			Policy: constants.NotificationPolicyAlways,
+		},
+		{
+			Name:   "slack",
+			Type:   constants.NotificationTypeSlack,
+			URL:    server.URL + "/slack",
+			Policy: constants.NotificationPolicyFailure,
+		},
+		{
+			Name:   "teams",
+			Type:   constants.NotificationTypeTeams,
+			URL:    server.URL + "/teams",
+			Policy: constants.NotificationPolicyFailure,
+		},
+		{
+			Name:   "changes",
+			Type:   constants.NotificationTypeWebhook,
+			URL:    server.URL + "/changes",
+			Policy: constants.NotificationPolicyChange,
+		},
+		{
+			Name:   "broken",
+			Type:   constants.NotificationTypeWebhook,
+			URL:    server.URL + "/broken",
+			Policy: constants.NotificationPolicyAlways,
+		},
+	}
+	notifier := New(&logger.Logger{Logger: log.Default()}, targets)
+	summary := NewSummary(
+		time.Now(),
+		map[string]error{"prodvmware": errors.New("connection refused")},
+		nil,
+		0, 0, 0,
+	)
+
+	err := notifier.Notify(context.Background(), summary)
+	if err == nil || !strings.Contains(err.Error(), "notification broken") {
+		t.Errorf("Notify() error = %v, want error of notification broken", err)
+	}
+
+	if received["/generic"]["status"] != StatusFailure {
+		t.Errorf("generic payload status = %v, want %s", received["/generic"]["status"], StatusFailure)
+	}
+	failedSources, _ := received["/generic"]["failed_sources"].(map[string]interface{})
+	if failedSources["prodvmware"] != "connection refused" {
+		t.Errorf("generic payload failed_sources = %v", received["/generic"]["failed_sources"])
+	}
+	slackText, _ := received["/slack"]["text"].(string)
+	if !strings.Contains(slackText, "Source prodvmware failed: connection refused") {
+		t.Errorf("slack payload text = %q", slackText)
+	}
+	if received["/teams"]["type"] != "message" {
+		t.Errorf("teams payload = %v, want adaptive card message", received["/teams"])
+	}
+	if _, ok := received["/changes"]; !ok {
+		t.Errorf("change policy target wasn't notified of failed run")
+	}
+}
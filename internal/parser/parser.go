@@ -1,10 +1,13 @@
 package parser
 
 import (
+	"crypto/tls"
 	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
 	"regexp"
+	"strings"
 
 	"github.com/src-doo/netbox-ssot/internal/constants"
 	"github.com/src-doo/netbox-ssot/internal/utils"
@@ -15,11 +18,29 @@ type Config struct {
 	Logger  *LoggerConfig  `yaml:"logger"`
 	Netbox  *NetboxConfig  `yaml:"netbox"`
 	Sources []SourceConfig `yaml:"source"`
+	// RunLock is optional, if it is not set, runs are not locked.
+	RunLock *RunLockConfig `yaml:"runLock"`
+	// Tracing is optional, if it is not set, runs are not traced.
+	Tracing *TracingConfig `yaml:"tracing"`
+	// Targets notified with a summary after each sync run.
+	Notifications []NotificationConfig `yaml:"notifications"`
+	// Audit is optional, if it is not set, writes to netbox are not audited.
+	Audit *AuditConfig `yaml:"audit"`
 }
 
 type LoggerConfig struct {
 	Level int    `yaml:"level"`
 	Dest  string `yaml:"dest"`
+	// Can be text (default) or json
+	Format string `yaml:"format"`
+	// Append to the log file instead of truncating it on each run
+	Append bool `yaml:"append"`
+	// Rotate the log file once it exceeds maxSize megabytes
+	MaxSize int `yaml:"maxSize"`
+	// Remove rotated log files older than maxAge days
+	MaxAge int `yaml:"maxAge"`
+	// Max number of rotated log files to keep
+	MaxBackups int `yaml:"maxBackups"`
 }
 
 func (l *LoggerConfig) UnmarshalYAML(unmarshal func(interface{}) error) error {
@@ -35,6 +56,34 @@ func (l *LoggerConfig) UnmarshalYAML(unmarshal func(interface{}) error) error {
 		return fmt.Errorf("logger.dest: %v is not a valid type", rawMarshal["dest"])
 	}
 
+	if format, ok := rawMarshal["format"]; ok {
+		item, ok := format.(string)
+		if !ok {
+			return fmt.Errorf("logger.format: %v is not a valid type", format)
+		}
+		l.Format = item
+	}
+	if appendLogs, ok := rawMarshal["append"]; ok {
+		item, ok := appendLogs.(bool)
+		if !ok {
+			return fmt.Errorf("logger.append: %v is not a valid type", appendLogs)
+		}
+		l.Append = item
+	}
+	for key, field := range map[string]*int{
+		"maxSize":    &l.MaxSize,
+		"maxAge":     &l.MaxAge,
+		"maxBackups": &l.MaxBackups,
+	} {
+		if value, ok := rawMarshal[key]; ok {
+			item, ok := value.(int)
+			if !ok {
+				return fmt.Errorf("logger.%s: %v is not a valid type", key, value)
+			}
+			*field = item
+		}
+	}
+
 	if rawMarshal["level"] == nil || rawMarshal["level"] == "" {
 		l.Level = 1
 		return nil
@@ -68,6 +117,48 @@ func (l LoggerConfig) String() string {
 	return fmt.Sprintf("LoggerConfig{Level: %d, Dest: %s}", l.Level, l.Dest)
 }
 
+// Configuration of the lock, that prevents overlapping runs
+// against the same netbox. In runLock block.
+type RunLockConfig struct {
+	// Can be netbox or file
+	Type string `yaml:"type"`
+	// Path of the lock file, only for file type
+	Path string `yaml:"path"`
+	// Lock is considered stale if it wasn't refreshed for ttl seconds
+	TTL int `yaml:"ttl"`
+	// Max time in seconds to wait for the lock held by another run
+	Wait int `yaml:"wait"`
+}
+
+// Configuration of OpenTelemetry tracing. In tracing block.
+type TracingConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// Endpoint (host:port) of the OTLP http collector. If empty,
+	// OTEL_EXPORTER_OTLP_ENDPOINT env variable or localhost:4318 is used.
+	Endpoint string `yaml:"endpoint"`
+	// Use http instead of https for the collector
+	Insecure    bool   `yaml:"insecure"`
+	ServiceName string `yaml:"serviceName"`
+}
+
+// Configuration of the audit log of writes to netbox. In audit block.
+type AuditConfig struct {
+	// Path of the audit log file or stdout
+	Dest string `yaml:"dest"`
+	// Chain entries with hashes, so changes of the log can be detected
+	HashChain bool `yaml:"hashChain"`
+}
+
+// Configuration of a notification target. In notifications block.
+type NotificationConfig struct {
+	Name string `yaml:"name"`
+	// Can be webhook (generic json), slack or teams
+	Type string `yaml:"type"`
+	URL  string `yaml:"url"`
+	// Can be always (default), failure or change
+	Policy string `yaml:"policy"`
+}
+
 type HTTPScheme string
 
 const (
@@ -91,6 +182,44 @@ type NetboxConfig struct {
 	RemoveOrphansAfterDays int        `yaml:"removeOrphansAfterDays"`
 	SourcePriority         []string   `yaml:"sourcePriority"`
 	CAFile                 string     `yaml:"caFile"`
+	// Proxy is the url of the proxy used for requests to netbox.
+	// If empty, the proxy is taken from the environment.
+	Proxy string `yaml:"proxy"`
+	// ClientCert and ClientKey are used for mutual TLS.
+	ClientCert    string `yaml:"clientCert"`
+	ClientKey     string `yaml:"clientKey"`
+	MinTLSVersion string `yaml:"minTLSVersion"`
+	// ServerName overrides the server name used for SNI and certificate validation.
+	ServerName string `yaml:"serverName"`
+	// AdoptionMode enables taking over unmanaged objects matched by sources,
+	// and reporting of unmanaged objects that weren't matched.
+	AdoptionMode bool `yaml:"adoptionMode"`
+	// CacheFile is the path of the local cache of netbox objects. If set,
+	// only objects changed since the previous run are fetched on init.
+	CacheFile string `yaml:"cacheFile"`
+	// CacheMaxAge in hours, after which the cache is fully refreshed.
+	CacheMaxAge int `yaml:"cacheMaxAge"`
+	// Branch is the name of the branch of the netbox branching plugin, that
+	// all changes are written to. It is created if it doesn't exist.
+	// {timestamp} in the name is replaced with the start time of the run.
+	Branch string `yaml:"branch"`
+	// BranchMergeThreshold is the maximum number of changes, up to which
+	// the branch is merged automatically. 0 means the branch is never merged.
+	// It requires {timestamp} in Branch, so each run uses a new branch.
+	BranchMergeThreshold int `yaml:"branchMergeThreshold"`
+}
+
+// HTTPClientConfig returns the config of the http client connecting to netbox.
+func (n NetboxConfig) HTTPClientConfig() utils.HTTPClientConfig {
+	return utils.HTTPClientConfig{
+		ValidateCert:  n.ValidateCert,
+		CAFile:        n.CAFile,
+		Proxy:         n.Proxy,
+		ClientCert:    n.ClientCert,
+		ClientKey:     n.ClientKey,
+		MinTLSVersion: n.MinTLSVersion,
+		ServerName:    n.ServerName,
+	}
 }
 
 func (n NetboxConfig) String() string {
@@ -129,14 +258,21 @@ type SourceConfig struct {
 	InterfaceFilter     string               `yaml:"interfaceFilter"`
 	CollectArpData      bool                 `yaml:"collectArpData"`
 	CAFile              string               `yaml:"caFile"`
+	Proxy               string               `yaml:"proxy"`
+	ClientCert          string               `yaml:"clientCert"`
+	ClientKey           string               `yaml:"clientKey"`
+	MinTLSVersion       string               `yaml:"minTLSVersion"`
+	ServerName          string               `yaml:"serverName"`
 	IgnoreAssetTags     bool                 `yaml:"ignoreAssetTags"`
 	IgnoreSerialNumbers bool                 `yaml:"ignoreSerialNumbers"`
 	IgnoreVMTemplates   bool                 `yaml:"ignoreVMTemplates"`
 	AssignDomainName    string               `yaml:"assignDomainName"`
 	ContinueOnError     bool                 `yaml:"continueOnError"`
+	MaxWorkers          int                  `yaml:"maxWorkers"`
 	VlanPrefix          string               `yaml:"vlanPrefix"`
 	DefaultIPv4MaskBits int                  `yaml:"defaultIPv4MaskBits"`
 	DefaultIPv6MaskBits int                  `yaml:"defaultIPv6MaskBits"`
+	SiteHierarchy       string               `yaml:"siteHierarchy"`
 
 	// Relations
 	DatacenterClusterGroupRelations map[string]string `yaml:"datacenterClusterGroupRelations"`
@@ -153,6 +289,7 @@ type SourceConfig struct {
 	VlanSiteRelations               map[string]string `yaml:"vlanSiteRelations"`
 	IPVrfRelations                  map[string]string `yaml:"ipVrfRelations"`
 	WlanTenantRelations             map[string]string `yaml:"wlanTenantRelations"`
+	TenantGroupRelations            map[string]string `yaml:"tenantGroupRelations"`
 	CustomFieldMappings             map[string]string `yaml:"customFieldMappings"`
 }
 
@@ -178,12 +315,19 @@ func (sc *SourceConfig) UnmarshalYAML(unmarshal func(interface{}) error) error {
 		InterfaceFilter                 string               `yaml:"interfaceFilter"`
 		CollectArpData                  bool                 `yaml:"collectArpData"`
 		CAFile                          string               `yaml:"caFile"`
+		Proxy                           string               `yaml:"proxy"`
+		ClientCert                      string               `yaml:"clientCert"`
+		ClientKey                       string               `yaml:"clientKey"`
+		MinTLSVersion                   string               `yaml:"minTLSVersion"`
+		ServerName                      string               `yaml:"serverName"`
 		IgnoreSerialNumbers             bool                 `yaml:"ignoreSerialNumbers"`
 		IgnoreAssetTags                 bool                 `yaml:"ignoreAssetTags"`
 		IgnoreVMTemplates               bool                 `yaml:"ignoreVMTemplates"`
 		ContinueOnError                 bool                 `yaml:"continueOnError"`
+		MaxWorkers                      int                  `yaml:"maxWorkers"`
 		DefaultIPv4MaskBits             int                  `yaml:"defaultIPv4MaskBits"`
 		DefaultIPv6MaskBits             int                  `yaml:"defaultIPv6MaskBits"`
+		SiteHierarchy                   string               `yaml:"siteHierarchy"`
 		DatacenterClusterGroupRelations []string             `yaml:"datacenterClusterGroupRelations"`
 		HostSiteRelations               []string             `yaml:"hostSiteRelations"`
 		HostRoleRelations               []string             `yaml:"hostRoleRelations"`
@@ -198,6 +342,7 @@ func (sc *SourceConfig) UnmarshalYAML(unmarshal func(interface{}) error) error {
 		VlanSiteRelations               []string             `yaml:"vlanSiteRelations"`
 		IPVrfRelations                  []string             `yaml:"ipVrfRelations"`
 		WlanTenantRelations             []string             `yaml:"wlanTenantRelations"`
+		TenantGroupRelations            []string             `yaml:"tenantGroupRelations"`
 		CustomFieldMappings             []string             `yaml:"customFieldMappings"`
 	}
 	rawMarshal := realSourceConfig{}
@@ -222,12 +367,19 @@ func (sc *SourceConfig) UnmarshalYAML(unmarshal func(interface{}) error) error {
 	sc.InterfaceFilter = rawMarshal.InterfaceFilter
 	sc.CollectArpData = rawMarshal.CollectArpData
 	sc.CAFile = rawMarshal.CAFile
+	sc.Proxy = rawMarshal.Proxy
+	sc.ClientCert = rawMarshal.ClientCert
+	sc.ClientKey = rawMarshal.ClientKey
+	sc.MinTLSVersion = rawMarshal.MinTLSVersion
+	sc.ServerName = rawMarshal.ServerName
 	sc.IgnoreSerialNumbers = rawMarshal.IgnoreSerialNumbers
 	sc.IgnoreAssetTags = rawMarshal.IgnoreAssetTags
 	sc.IgnoreVMTemplates = rawMarshal.IgnoreVMTemplates
 	sc.ContinueOnError = rawMarshal.ContinueOnError
+	sc.MaxWorkers = rawMarshal.MaxWorkers
 	sc.DefaultIPv4MaskBits = rawMarshal.DefaultIPv4MaskBits
 	sc.DefaultIPv6MaskBits = rawMarshal.DefaultIPv6MaskBits
+	sc.SiteHierarchy = rawMarshal.SiteHierarchy
 
 	if len(rawMarshal.DatacenterClusterGroupRelations) > 0 {
 		err := utils.ValidateRegexRelations(rawMarshal.DatacenterClusterGroupRelations)
@@ -333,6 +485,13 @@ func (sc *SourceConfig) UnmarshalYAML(unmarshal func(interface{}) error) error {
 		}
 		sc.WlanTenantRelations = utils.ConvertStringsToRegexPairs(rawMarshal.WlanTenantRelations)
 	}
+	if len(rawMarshal.TenantGroupRelations) > 0 {
+		err := utils.ValidateRegexRelations(rawMarshal.TenantGroupRelations)
+		if err != nil {
+			return fmt.Errorf("%s.tenantGroupRelations: %v", rawMarshal.Name, err)
+		}
+		sc.TenantGroupRelations = utils.ConvertStringsToRegexPairs(rawMarshal.TenantGroupRelations)
+	}
 	if len(rawMarshal.CustomFieldMappings) > 0 {
 		err := utils.ValidateRegexRelations((rawMarshal.CustomFieldMappings))
 		if err != nil {
@@ -343,6 +502,19 @@ func (sc *SourceConfig) UnmarshalYAML(unmarshal func(interface{}) error) error {
 	return nil
 }
 
+// HTTPClientConfig returns the config of http clients connecting to the source.
+func (sc SourceConfig) HTTPClientConfig() utils.HTTPClientConfig {
+	return utils.HTTPClientConfig{
+		ValidateCert:  sc.ValidateCert,
+		CAFile:        sc.CAFile,
+		Proxy:         sc.Proxy,
+		ClientCert:    sc.ClientCert,
+		ClientKey:     sc.ClientKey,
+		MinTLSVersion: sc.MinTLSVersion,
+		ServerName:    sc.ServerName,
+	}
+}
+
 func (sc SourceConfig) String() string {
 	return fmt.Sprintf(
 		"SourceConfig{Name: %s, Type: %s, HTTPScheme: %s, Hostname: %s, Port: %d, "+
@@ -350,7 +522,7 @@ func (sc SourceConfig) String() string {
 			"Tag: %s, TagColor: %s, AssignDomainName: %s, VlanPrefix: %s, DatacenterClusterGroupRelations: %s, "+
 			"HostSiteRelations: %v, ClusterSiteRelations: %v, ClusterTenantRelations: %v, "+
 			"HostTenantRelations: %v, VmTenantRelations: %v, VlanGroupRelations: %v, "+
-			"VlanTenantRelations: %v, WlanTenantRelations: %v}",
+			"VlanTenantRelations: %v, WlanTenantRelations: %v, TenantGroupRelations: %v}",
 		sc.Name,
 		sc.Type,
 		sc.HTTPScheme,
@@ -373,6 +545,7 @@ func (sc SourceConfig) String() string {
 		sc.VlanGroupRelations,
 		sc.VlanTenantRelations,
 		sc.WlanTenantRelations,
+		sc.TenantGroupRelations,
 	)
 }
 
@@ -393,6 +566,96 @@ func validateConfig(config *Config) error {
 		return err
 	}
 
+	err = validateRunLockConfig(config)
+	if err != nil {
+		return err
+	}
+
+	err = validateNotificationsConfig(config)
+	if err != nil {
+		return err
+	}
+
+	if config.Audit != nil && config.Audit.Dest == "" {
+		return errors.New("audit.dest: cannot be empty")
+	}
+
+	if config.Tracing != nil && config.Tracing.ServiceName == "" {
+		config.Tracing.ServiceName = constants.TracingServiceName
+	}
+
+	return nil
+}
+
+func validateRunLockConfig(config *Config) error {
+	if config.RunLock == nil {
+		return nil
+	}
+	switch config.RunLock.Type {
+	case constants.RunLockTypeNetbox:
+		if config.RunLock.Path != "" {
+			return errors.New("runLock.path: can only be set for file type")
+		}
+	case constants.RunLockTypeFile:
+		if config.RunLock.Path == "" {
+			config.RunLock.Path = filepath.Join(os.TempDir(), constants.RunLockFileName)
+		}
+	default:
+		return fmt.Errorf(
+			"runLock.type: must be either %s or %s. Is %s",
+			constants.RunLockTypeNetbox,
+			constants.RunLockTypeFile,
+			config.RunLock.Type,
+		)
+	}
+	if config.RunLock.TTL < 0 {
+		return errors.New("runLock.ttl: cannot be negative")
+	}
+	if config.RunLock.TTL == 0 {
+		config.RunLock.TTL = constants.DefaultRunLockTTL
+	}
+	if config.RunLock.Wait < 0 {
+		return errors.New("runLock.wait: cannot be negative")
+	}
+	return nil
+}
+
+func validateNotificationsConfig(config *Config) error {
+	for i := range config.Notifications {
+		notification := &config.Notifications[i]
+		if notification.Name == "" {
+			return errors.New("notification name: cannot be empty")
+		}
+		switch notification.Type {
+		case constants.NotificationTypeWebhook, constants.NotificationTypeSlack, constants.NotificationTypeTeams:
+		default:
+			return fmt.Errorf(
+				"%s.type: must be one of %s, %s or %s. Is %s",
+				notification.Name,
+				constants.NotificationTypeWebhook,
+				constants.NotificationTypeSlack,
+				constants.NotificationTypeTeams,
+				notification.Type,
+			)
+		}
+		if notification.URL == "" {
+			return fmt.Errorf("%s.url: cannot be empty", notification.Name)
+		}
+		switch notification.Policy {
+		case "":
+			notification.Policy = constants.NotificationPolicyAlways
+		case constants.NotificationPolicyAlways, constants.NotificationPolicyFailure, constants.NotificationPolicyChange:
+		default:
+			return fmt.Errorf(
+				"%s.policy: must be one of %s, %s or %s. Is %s",
+				notification.Name,
+				constants.NotificationPolicyAlways,
+				constants.NotificationPolicyFailure,
+				constants.NotificationPolicyChange,
+				notification.Policy,
+			)
+		}
+	}
 	return nil
 }
 
@@ -400,6 +663,12 @@ func validateLoggerConfig(config *Config) error {
 	if config.Logger.Level < 0 || config.Logger.Level > 3 {
 		return errors.New("logger.level: must be between 0 and 3")
 	}
+	if config.Logger.Format != "" && config.Logger.Format != "text" && config.Logger.Format != "json" {
+		return errors.New("logger.format: must be either text or json. Is " + config.Logger.Format)
+	}
+	if config.Logger.MaxSize < 0 || config.Logger.MaxAge < 0 || config.Logger.MaxBackups < 0 {
+		return errors.New("logger.maxSize, logger.maxAge, logger.maxBackups: cannot be negative")
+	}
 	return nil
 }
 
@@ -481,6 +750,26 @@ func validateNetboxConfig(config *Config) error {
 			return fmt.Errorf("netbox.caFile: %s", err)
 		}
 	}
+	if err := validateHTTPClientConfig("netbox", config.Netbox.HTTPClientConfig()); err != nil {
+		return err
+	}
+	if config.Netbox.CacheMaxAge < 0 {
+		return errors.New("netbox.cacheMaxAge: cannot be negative")
+	}
+	if config.Netbox.CacheMaxAge == 0 {
+		config.Netbox.CacheMaxAge = constants.DefaultCacheMaxAge
+	}
+	if config.Netbox.BranchMergeThreshold < 0 {
+		return errors.New("netbox.branchMergeThreshold: cannot be negative")
+	}
+	if config.Netbox.BranchMergeThreshold > 0 && config.Netbox.Branch == "" {
+		return errors.New("netbox.branchMergeThreshold: requires netbox.branch")
+	}
+	// Changes are counted per run, so each run needs its own branch. Otherwise,
+	// changes of previous runs left in the branch for review would be merged too.
+	if config.Netbox.BranchMergeThreshold > 0 && !strings.Contains(config.Netbox.Branch, "{timestamp}") {
+		return errors.New("netbox.branchMergeThreshold: requires {timestamp} in netbox.branch")
+	}
 	return nil
 }
 
@@ -535,6 +824,11 @@ func validateSourceConfig(config *Config) error {
 		if externalSource.Password == "" && externalSource.Type != constants.Fortigate {
 			return fmt.Errorf("%s.password: cannot be empty", externalSourceStr)
 		}
+		if externalSource.MaxWorkers == 0 {
+			externalSource.MaxWorkers = constants.DefaultSourceMaxWorkers
+		} else if externalSource.MaxWorkers < 0 {
+			return fmt.Errorf("%s.maxWorkers: cannot be negative", externalSourceStr)
+		}
 		if externalSource.Tag == "" {
 			externalSource.Tag = fmt.Sprintf("Source: %s", externalSource.Name)
 		}
@@ -546,6 +840,21 @@ func validateSourceConfig(config *Config) error {
 				return fmt.Errorf("%s.caFile: %s", externalSourceStr, err)
 			}
 		}
+		httpClientConfig := externalSource.HTTPClientConfig()
+		if err := validateHTTPClientConfig(externalSourceStr, httpClientConfig); err != nil {
+			return err
+		}
+		// The ovirt sdk creates its own http client (also for sso authentication),
+		// which only supports caFile and validateCert, and ios-xe uses netconf over ssh
+		if (externalSource.Type == constants.Ovirt || externalSource.Type == constants.IOSXE) &&
+			(httpClientConfig.Proxy != "" || httpClientConfig.ClientCert != "" ||
+				httpClientConfig.MinTLSVersion != "" || httpClientConfig.ServerName != "") {
+			return fmt.Errorf(
+				"%s: proxy, clientCert, clientKey, minTLSVersion and serverName are not supported by %s sources",
+				externalSourceStr,
+				externalSource.Type,
+			)
+		}
 		if len(externalSource.IgnoredSubnets) > 0 {
 			for _, ignoredSubnet := range externalSource.IgnoredSubnets {
 				if !utils.VerifySubnet(ignoredSubnet) {
@@ -569,6 +878,23 @@ func validateSourceConfig(config *Config) error {
 			}
 		}
 
+		switch externalSource.SiteHierarchy {
+		case "", constants.SiteHierarchyFlat:
+		case constants.SiteHierarchyBuilding, constants.SiteHierarchyFloor:
+			if externalSource.Type != constants.Dnac {
+				return fmt.Errorf("%s.siteHierarchy: is only supported by dnac sources", externalSourceStr)
+			}
+		default:
+			return fmt.Errorf(
+				"%s.siteHierarchy: must be one of %s, %s or %s. Is %s",
+				externalSourceStr,
+				constants.SiteHierarchyFlat,
+				constants.SiteHierarchyBuilding,
+				constants.SiteHierarchyFloor,
+				externalSource.SiteHierarchy,
+			)
+		}
+
 		// Try to compile interfaceFilter
 		_, err := regexp.Compile(externalSource.InterfaceFilter)
 		if err != nil {
@@ -578,6 +904,32 @@ func validateSourceConfig(config *Config) error {
 	return nil
 }
 
+// validateHTTPClientConfig validates the http client config of netbox or a source.
+func validateHTTPClientConfig(configName string, config utils.HTTPClientConfig) error {
+	if config.Proxy != "" {
+		if _, err := utils.ParseProxyURL(config.Proxy); err != nil {
+			return fmt.Errorf("%s.proxy: %s", configName, err)
+		}
+	}
+	if config.ClientCert == "" && config.ClientKey != "" {
+		return fmt.Errorf("%s.clientKey: requires %s.clientCert", configName, configName)
+	}
+	if config.ClientCert != "" && config.ClientKey == "" {
+		return fmt.Errorf("%s.clientCert: requires %s.clientKey", configName, configName)
+	}
+	if config.ClientCert != "" {
+		if _, err := tls.LoadX509KeyPair(config.ClientCert, config.ClientKey); err != nil {
+			return fmt.Errorf("%s.clientCert: %s", configName, err)
+		}
+	}
+	if config.MinTLSVersion != "" {
+		if _, err := utils.ParseTLSVersion(config.MinTLSVersion); err != nil {
+			return fmt.Errorf("%s.minTLSVersion: %s", configName, err)
+		}
+	}
+	return nil
+}
+
 func ParseConfig(configFilename string) (*Config, error) {
 	// First we read the config file
 	file, err := os.Open(configFilename)
@@ -28,6 +28,7 @@ func TestValidonfig(t *testing.T) {
 			TagColor:               constants.SsotTagColor, // Default
 			RemoveOrphans:          false,                  // Default
 			RemoveOrphansAfterDays: 5,
+			CacheMaxAge:            constants.DefaultCacheMaxAge, // Default
 		},
 		Sources: []SourceConfig{
 			{
@@ -46,6 +47,7 @@ func TestValidonfig(t *testing.T) {
 				ValidateCert: true,
 				Tag:          "testing",
 				TagColor:     "ff0000",
+				MaxWorkers:   constants.DefaultSourceMaxWorkers, // Default
 			}, {
 				Name:       "paloalto",
 				Type:       "paloalto",
@@ -62,6 +64,7 @@ func TestValidonfig(t *testing.T) {
 				CollectArpData: true,
 				TagColor:       constants.SourceTagColorMap[constants.PaloAlto], // Default
 				Tag:            "Source: paloalto",                              // Default
+				MaxWorkers:     constants.DefaultSourceMaxWorkers,               // Default
 				VlanSiteRelations: map[string]string{
 					".*": "Default",
 				},
@@ -87,8 +90,9 @@ func TestValidonfig(t *testing.T) {
 					"172.16.0.0/12",
 				},
 				ValidateCert: false,
-				Tag:          "Source: prodolvm", // Default
-				TagColor:     "aa1409",           // Default
+				Tag:          "Source: prodolvm",                // Default
+				TagColor:     "aa1409",                          // Default
+				MaxWorkers:   constants.DefaultSourceMaxWorkers, // Default
 				ClusterSiteRelations: map[string]string{
 					"Cluster_NYC":         "New York",
 					"Cluster_FFM.*":       "Frankfurt",
@@ -109,6 +113,9 @@ func TestValidonfig(t *testing.T) {
 					".*Health": "Health Department",
 					".*":       "Default",
 				},
+				TenantGroupRelations: map[string]string{
+					".*Department": "Public Sector",
+				},
 				DatacenterClusterGroupRelations: map[string]string{
 					".*": "Default",
 				},
@@ -153,6 +160,9 @@ func TestParseValidConfigs(t *testing.T) {
 		{
 			filename: "valid_config7.yaml",
 		},
+		{
+			filename: "valid_config8.yaml",
+		},
 	}
 	for _, tc := range testCases {
 		t.Run(tc.filename, func(t *testing.T) {
@@ -340,6 +350,79 @@ func TestParseConfigInvalidConfigs(t *testing.T) {
 			filename:    "invalid_config48.yaml",
 			expectedErr: "wrong.vlanGroupSiteRelations: invalid regex: (wrong(), in relation: (wrong() = wwrong",
 		},
+		{
+			filename:    "invalid_config49.yaml",
+			expectedErr: "runLock.type: must be either netbox or file. Is kubernetes",
+		},
+		{
+			filename:    "invalid_config50.yaml",
+			expectedErr: "runLock.ttl: cannot be negative",
+		},
+		{
+			filename:    "invalid_config51.yaml",
+			expectedErr: "logger.format: must be either text or json. Is yaml",
+		},
+		{
+			filename:    "invalid_config52.yaml",
+			expectedErr: "ops.type: must be one of webhook, slack or teams. Is email",
+		},
+		{
+			filename:    "invalid_config53.yaml",
+			expectedErr: "ops.policy: must be one of always, failure or change. Is never",
+		},
+		{
+			filename:    "invalid_config54.yaml",
+			expectedErr: "netbox.cacheMaxAge: cannot be negative",
+		},
+		{
+			filename:    "invalid_config55.yaml",
+			expectedErr: "prodvcenter.maxWorkers: cannot be negative",
+		},
+		{
+			filename:    "invalid_config56.yaml",
+			expectedErr: "netbox.branchMergeThreshold: cannot be negative",
+		},
+		{
+			filename:    "invalid_config57.yaml",
+			expectedErr: "netbox.branchMergeThreshold: requires netbox.branch",
+		},
+		{
+			filename:    "invalid_config58.yaml",
+			expectedErr: `netbox.proxy: scheme must be one of http, https, socks5. Is "ftp"`,
+		},
+		{
+			filename:    "invalid_config59.yaml",
+			expectedErr: "dnac.clientCert: requires dnac.clientKey",
+		},
+		{
+			filename:    "invalid_config60.yaml",
+			expectedErr: "netbox.minTLSVersion: must be one of 1.0, 1.1, 1.2, 1.3. Is 1.4",
+		},
+		{
+			filename: "invalid_config61.yaml",
+			expectedErr: "prodovirt: proxy, clientCert, clientKey, minTLSVersion and serverName " +
+				"are not supported by ovirt sources",
+		},
+		{
+			filename:    "invalid_config62.yaml",
+			expectedErr: "audit.dest: cannot be empty",
+		},
+		{
+			filename:    "invalid_config63.yaml",
+			expectedErr: "testdnac.siteHierarchy: must be one of flat, building or floor. Is room",
+		},
+		{
+			filename:    "invalid_config64.yaml",
+			expectedErr: "testolvm.siteHierarchy: is only supported by dnac sources",
+		},
+		{
+			filename:    "invalid_config65.yaml",
+			expectedErr: "wrong.tenantGroupRelations: invalid regex: (wrong(), in relation: (wrong() = wwrong",
+		},
+		{
+			filename:    "invalid_config66.yaml",
+			expectedErr: "netbox.branchMergeThreshold: requires {timestamp} in netbox.branch",
+		},
 		{
 			filename:    "invalid_config1111.yaml",
 			expectedErr: "open ../../testdata/parser/invalid_config1111.yaml: no such file or directory",
@@ -0,0 +1,114 @@
+package runlock
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// fileBackend stores the lock marker in a local file. It only prevents
+// overlapping runs on the same host (or hosts sharing the file system).
+type fileBackend struct {
+	path string
+}
+
+// create links a completely written temporary file to the lock file,
+// so other runs never read a partially written marker. Linking fails
+// if the lock file already exists.
+func (b *fileBackend) create(_ context.Context, m marker) error {
+	tmpPath, err := b.writeTemp(m)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmpPath)
+	err = os.Link(tmpPath, b.path)
+	if errors.Is(err, os.ErrExist) {
+		return errLockExists
+	}
+	return err
+}
+
+func (b *fileBackend) read(_ context.Context) (marker, error) {
+	var m marker
+	content, err := os.ReadFile(b.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return m, errLockNotFound
+	}
+	if err != nil {
+		return m, err
+	}
+	err = json.Unmarshal(content, &m)
+	if err != nil {
+		return m, fmt.Errorf("parse lock file %s: %s", b.path, err)
+	}
+	return m, nil
+}
+
+func (b *fileBackend) update(ctx context.Context, m marker) error {
+	existing, err := b.read(ctx)
+	if errors.Is(err, errLockNotFound) {
+		return errLockLost
+	}
+	if err != nil {
+		return err
+	}
+	if existing.Holder != m.Holder {
+		return errLockLost
+	}
+	tmpPath, err := b.writeTemp(m)
+	if err != nil {
+		return err
+	}
+	err = os.Rename(tmpPath, b.path)
+	if err != nil {
+		_ = os.Remove(tmpPath)
+		return err
+	}
+	return nil
+}
+
+// writeTemp writes the marker to a new temporary file next to the lock file,
+// and returns its path.
+func (b *fileBackend) writeTemp(m marker) (string, error) {
+	content, err := json.Marshal(m)
+	if err != nil {
+		return "", err
+	}
+	file, err := os.CreateTemp(filepath.Dir(b.path), filepath.Base(b.path)+".*.tmp")
+	if err != nil {
+		return "", err
+	}
+	err = file.Chmod(0o644) //nolint:mnd
+	if err == nil {
+		_, err = file.Write(content)
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(file.Name())
+		return "", err
+	}
+	return file.Name(), nil
+}
+
+func (b *fileBackend) remove(ctx context.Context, holder string) error {
+	existing, err := b.read(ctx)
+	if errors.Is(err, errLockNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if existing.Holder != holder {
+		return errLockLost
+	}
+	err = os.Remove(b.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
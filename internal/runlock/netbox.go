@@ -0,0 +1,147 @@
+package runlock
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+
+	"github.com/src-doo/netbox-ssot/internal/constants"
+	"github.com/src-doo/netbox-ssot/internal/logger"
+	"github.com/src-doo/netbox-ssot/internal/netbox/objects"
+	"github.com/src-doo/netbox-ssot/internal/netbox/service"
+	"github.com/src-doo/netbox-ssot/internal/parser"
+)
+
+// netboxBackend stores the lock marker as a description of the tag
+// constants.RunLockTagName. Netbox enforces unique tag names, so only
+// one run can create it.
+type netboxBackend struct {
+	api *service.NetboxClient
+}
+
+func newNetboxBackend(logger *logger.Logger, netboxConfig *parser.NetboxConfig) (*netboxBackend, error) {
+	baseURL := fmt.Sprintf(
+		"%s://%s:%d",
+		netboxConfig.HTTPScheme,
+		netboxConfig.Hostname,
+		netboxConfig.Port,
+	)
+	api, err := service.NewNetboxClient(
+		logger,
+		baseURL,
+		netboxConfig.APIToken,
+		netboxConfig.Timeout,
+		netboxConfig.HTTPClientConfig(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("create new netbox client: %s", err)
+	}
+	return &netboxBackend{api: api}, nil
+}
+
+func (b *netboxBackend) create(ctx context.Context, m marker) error {
+	description, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+	_, err = service.Create(ctx, b.api, &objects.Tag{
+		Name:        constants.RunLockTagName,
+		Slug:        constants.RunLockTagName,
+		Color:       constants.ColorRed,
+		Description: string(description),
+	})
+	var validationErr *service.ValidationError
+	if errors.As(err, &validationErr) {
+		return errLockExists
+	}
+	return err
+}
+
+func (b *netboxBackend) getTag(ctx context.Context) (*objects.Tag, error) {
+	tags, err := service.GetAll[objects.Tag](
+		ctx,
+		b.api,
+		"&name="+url.QueryEscape(constants.RunLockTagName),
+	)
+	if err != nil {
+		return nil, err
+	}
+	if len(tags) == 0 {
+		return nil, errLockNotFound
+	}
+	return &tags[0], nil
+}
+
+// getMarker returns the lock tag and the marker stored in its description.
+func (b *netboxBackend) getMarker(ctx context.Context) (*objects.Tag, marker, error) {
+	var m marker
+	tag, err := b.getTag(ctx)
+	if err != nil {
+		return nil, m, err
+	}
+	err = json.Unmarshal([]byte(tag.Description), &m)
+	if err != nil {
+		return nil, m, fmt.Errorf("parse description of tag %s: %s", tag.Name, err)
+	}
+	return tag, m, nil
+}
+
+func (b *netboxBackend) read(ctx context.Context) (marker, error) {
+	_, m, err := b.getMarker(ctx)
+	return m, err
+}
+
+func (b *netboxBackend) update(ctx context.Context, m marker) error {
+	tag, existing, err := b.getMarker(ctx)
+	if errors.Is(err, errLockNotFound) {
+		return errLockLost
+	}
+	if err != nil {
+		return err
+	}
+	if existing.Holder != m.Holder {
+		return errLockLost
+	}
+	description, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+	_, err = service.Patch[objects.Tag](
+		ctx,
+		b.api,
+		tag.ID,
+		map[string]interface{}{"description": string(description)},
+	)
+	return err
+}
+
+// remove deletes the lock tag by its id, so a tag recreated by another run
+// after the check of the holder isn't deleted.
+func (b *netboxBackend) remove(ctx context.Context, holder string) error {
+	tag, existing, err := b.getMarker(ctx)
+	if errors.Is(err, errLockNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if existing.Holder != holder {
+		return errLockLost
+	}
+	err = b.api.DeleteObject(ctx, tag)
+	if err != nil {
+		// Tag may have been removed, or removed and recreated,
+		// by another run in the meantime
+		current, getErr := b.getTag(ctx)
+		if errors.Is(getErr, errLockNotFound) {
+			return nil
+		}
+		if getErr == nil && current.ID != tag.ID {
+			return errLockLost
+		}
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,198 @@
+// Package runlock implements a distributed lock, that prevents
+// overlapping runs of netbox-ssot against the same netbox.
+package runlock
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/src-doo/netbox-ssot/internal/constants"
+	"github.com/src-doo/netbox-ssot/internal/logger"
+	"github.com/src-doo/netbox-ssot/internal/parser"
+)
+
+var (
+	// errLockExists is returned by backend.create, when the marker already exists.
+	errLockExists = errors.New("lock already exists")
+	// errLockNotFound is returned by backend.read, when the marker doesn't exist.
+	errLockNotFound = errors.New("lock not found")
+	// errLockLost is returned by backend.update and backend.remove,
+	// when the marker is held by another run.
+	errLockLost = errors.New("lock is held by another run")
+)
+
+// marker is stored by the backend while the lock is held.
+type marker struct {
+	// Holder identifies the run holding the lock (hostname:pid).
+	Holder string `json:"holder"`
+	// Heartbeat is the last time the holder refreshed the lock.
+	Heartbeat time.Time `json:"heartbeat"`
+}
+
+// backend is a storage for the lock marker.
+type backend interface {
+	// create atomically creates the marker. It returns errLockExists
+	// if the marker already exists.
+	create(ctx context.Context, m marker) error
+	// read returns the existing marker, or errLockNotFound.
+	read(ctx context.Context) (marker, error)
+	// update overwrites the existing marker, if it is held by m.Holder.
+	// Otherwise it returns errLockLost.
+	update(ctx context.Context, m marker) error
+	// remove removes the existing marker, if it is held by holder.
+	// Otherwise it returns errLockLost. A missing marker isn't an error.
+	remove(ctx context.Context, holder string) error
+}
+
+// RunLock is a lock shared between all netbox-ssot runs against the same netbox.
+// While it is held, its marker is refreshed periodically, so locks of crashed
+// runs can be detected as stale and taken over after ttl.
+type RunLock struct {
+	backend       backend
+	logger        *logger.Logger
+	holder        string
+	ttl           time.Duration
+	wait          time.Duration
+	retryInterval time.Duration
+
+	stopHeartbeat chan struct{}
+	heartbeatDone sync.WaitGroup
+}
+
+// New creates a RunLock based on the runLockConfig. It returns nil,
+// if runLockConfig is nil, which means that runs shouldn't be locked.
+func New(
+	logger *logger.Logger,
+	runLockConfig *parser.RunLockConfig,
+	netboxConfig *parser.NetboxConfig,
+) (*RunLock, error) {
+	if runLockConfig == nil {
+		return nil, nil
+	}
+	var lockBackend backend
+	switch runLockConfig.Type {
+	case constants.RunLockTypeFile:
+		lockBackend = &fileBackend{path: runLockConfig.Path}
+	case constants.RunLockTypeNetbox:
+		var err error
+		lockBackend, err = newNetboxBackend(logger, netboxConfig)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unsupported run lock type %s", runLockConfig.Type)
+	}
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "unknown"
+	}
+	return &RunLock{
+		backend:       lockBackend,
+		logger:        logger,
+		holder:        fmt.Sprintf("%s:%d", hostname, os.Getpid()),
+		ttl:           time.Duration(runLockConfig.TTL) * time.Second,
+		wait:          time.Duration(runLockConfig.Wait) * time.Second,
+		retryInterval: constants.RunLockRetryInterval * time.Second,
+	}, nil
+}
+
+// Acquire acquires the lock. If the lock is held by another run, it waits
+// for at most wait duration. Stale locks are removed. Once acquired, the lock
+// is refreshed in the background until Release is called.
+func (l *RunLock) Acquire(ctx context.Context) error {
+	deadline := time.Now().Add(l.wait)
+	for {
+		err := l.backend.create(ctx, marker{Holder: l.holder, Heartbeat: time.Now()})
+		if err == nil {
+			l.logger.Infof(ctx, "Acquired run lock as %s", l.holder)
+			l.startHeartbeat(ctx)
+			return nil
+		}
+		if !errors.Is(err, errLockExists) {
+			return fmt.Errorf("acquire run lock: %s", err)
+		}
+
+		existing, err := l.backend.read(ctx)
+		if errors.Is(err, errLockNotFound) {
+			// Lock was released in the meantime
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("read run lock: %s", err)
+		}
+		if time.Since(existing.Heartbeat) > l.ttl {
+			l.logger.Warningf(
+				ctx,
+				"Removing stale run lock of %s, last refreshed at %s",
+				existing.Holder,
+				existing.Heartbeat.Format(time.RFC3339),
+			)
+			err = l.backend.remove(ctx, existing.Holder)
+			if errors.Is(err, errLockLost) {
+				// Stale lock was taken over by another run in the meantime
+				continue
+			}
+			if err != nil {
+				return fmt.Errorf("remove stale run lock: %s", err)
+			}
+			continue
+		}
+
+		if time.Now().Add(l.retryInterval).After(deadline) {
+			return fmt.Errorf(
+				"run lock is held by %s (last refreshed at %s)",
+				existing.Holder,
+				existing.Heartbeat.Format(time.RFC3339),
+			)
+		}
+		l.logger.Infof(ctx, "Run lock is held by %s, waiting...", existing.Holder)
+		time.Sleep(l.retryInterval)
+	}
+}
+
+// Release stops refreshing the lock and removes it, unless it
+// was taken over by another run.
+func (l *RunLock) Release(ctx context.Context) error {
+	if l.stopHeartbeat != nil {
+		close(l.stopHeartbeat)
+		l.heartbeatDone.Wait()
+		l.stopHeartbeat = nil
+	}
+	err := l.backend.remove(ctx, l.holder)
+	if err != nil {
+		return fmt.Errorf("release run lock: %s", err)
+	}
+	l.logger.Infof(ctx, "Released run lock")
+	return nil
+}
+
+// startHeartbeat refreshes the lock three times per ttl, so it isn't
+// considered stale by other runs while we are still running.
+func (l *RunLock) startHeartbeat(ctx context.Context) {
+	l.stopHeartbeat = make(chan struct{})
+	l.heartbeatDone.Add(1)
+	go func(stop <-chan struct{}) {
+		defer l.heartbeatDone.Done()
+		ticker := time.NewTicker(l.ttl / 3) //nolint:mnd
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				err := l.backend.update(ctx, marker{Holder: l.holder, Heartbeat: time.Now()})
+				if errors.Is(err, errLockLost) {
+					l.logger.Warningf(ctx, "run lock was taken over by another run, stopped refreshing it")
+					return
+				}
+				if err != nil {
+					l.logger.Warningf(ctx, "refreshing run lock: %s", err)
+				}
+			}
+		}
+	}(l.stopHeartbeat)
+}
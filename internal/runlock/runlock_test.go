@@ -0,0 +1,207 @@
+package runlock
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/src-doo/netbox-ssot/internal/constants"
+	"github.com/src-doo/netbox-ssot/internal/logger"
+	"github.com/src-doo/netbox-ssot/internal/netbox/objects"
+	"github.com/src-doo/netbox-ssot/internal/netbox/service"
+)
+
+var testLogger = &logger.Logger{Logger: log.Default()}
+
+func newTestRunLock(lockBackend backend, holder string) *RunLock {
+	return &RunLock{
+		backend:       lockBackend,
+		logger:        testLogger,
+		holder:        holder,
+		ttl:           time.Minute,
+		retryInterval: time.Millisecond,
+	}
+}
+
+func TestRunLock_File(t *testing.T) {
+	ctx := context.Background()
+	lockPath := filepath.Join(t.TempDir(), constants.RunLockFileName)
+
+	first := newTestRunLock(&fileBackend{path: lockPath}, "first")
+	if err := first.Acquire(ctx); err != nil {
+		t.Fatalf("Acquire() error = %v", err)
+	}
+
+	second := newTestRunLock(&fileBackend{path: lockPath}, "second")
+	err := second.Acquire(ctx)
+	if err == nil || !strings.Contains(err.Error(), "run lock is held by first") {
+		t.Errorf("Acquire() of held lock error = %v, want held by first", err)
+	}
+
+	if err := first.Release(ctx); err != nil {
+		t.Fatalf("Release() error = %v", err)
+	}
+	if _, err := os.Stat(lockPath); !os.IsNotExist(err) {
+		t.Errorf("lock file still exists after Release()")
+	}
+	if err := second.Acquire(ctx); err != nil {
+		t.Errorf("Acquire() after Release() error = %v", err)
+	}
+	_ = second.Release(ctx)
+}
+
+func TestRunLock_StaleLock(t *testing.T) {
+	ctx := context.Background()
+	lockPath := filepath.Join(t.TempDir(), constants.RunLockFileName)
+	lockBackend := &fileBackend{path: lockPath}
+	err := lockBackend.create(ctx, marker{Holder: "crashed", Heartbeat: time.Now().Add(-time.Hour)})
+	if err != nil {
+		t.Fatalf("create() error = %v", err)
+	}
+
+	runLock := newTestRunLock(lockBackend, "new")
+	if err := runLock.Acquire(ctx); err != nil {
+		t.Fatalf("Acquire() of stale lock error = %v", err)
+	}
+	got, err := lockBackend.read(ctx)
+	if err != nil {
+		t.Fatalf("read() error = %v", err)
+	}
+	if got.Holder != "new" {
+		t.Errorf("lock holder = %s, want new", got.Holder)
+	}
+	_ = runLock.Release(ctx)
+}
+
+func TestRunLock_TakenOver(t *testing.T) {
+	ctx := context.Background()
+	lockPath := filepath.Join(t.TempDir(), constants.RunLockFileName)
+	lockBackend := &fileBackend{path: lockPath}
+
+	first := newTestRunLock(lockBackend, "first")
+	if err := first.Acquire(ctx); err != nil {
+		t.Fatalf("Acquire() error = %v", err)
+	}
+	err := lockBackend.update(ctx, marker{Holder: "first", Heartbeat: time.Now().Add(-time.Hour)})
+	if err != nil {
+		t.Fatalf("update() error = %v", err)
+	}
+	second := newTestRunLock(lockBackend, "second")
+	if err := second.Acquire(ctx); err != nil {
+		t.Fatalf("Acquire() of stale lock error = %v", err)
+	}
+
+	// Another waiter, which saw the same stale lock, must not remove the new one
+	if err := lockBackend.remove(ctx, "first"); err != errLockLost {
+		t.Errorf("remove() of stale holder error = %v, want %v", err, errLockLost)
+	}
+	// First run neither refreshes nor releases the lock of the second run
+	if err := lockBackend.update(ctx, marker{Holder: "first", Heartbeat: time.Now()}); err != errLockLost {
+		t.Errorf("update() of previous holder error = %v, want %v", err, errLockLost)
+	}
+	if err := first.Release(ctx); err == nil {
+		t.Errorf("Release() of taken over lock error = nil, want error")
+	}
+	got, err := lockBackend.read(ctx)
+	if err != nil {
+		t.Fatalf("read() error = %v", err)
+	}
+	if got.Holder != "second" {
+		t.Errorf("lock holder = %s, want second", got.Holder)
+	}
+	if err := second.Release(ctx); err != nil {
+		t.Errorf("Release() error = %v", err)
+	}
+}
+
+// newMockTagServer emulates netbox tags endpoint with unique tag names.
+func newMockTagServer(t *testing.T) *httptest.Server {
+	var lock sync.Mutex
+	var lockTag *objects.Tag
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		defer lock.Unlock()
+		switch {
+		case r.Method == http.MethodPost:
+			if lockTag != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				_, _ = w.Write([]byte(`{"name": ["tag with this name already exists."]}`))
+				return
+			}
+			body, _ := io.ReadAll(r.Body)
+			lockTag = &objects.Tag{}
+			_ = json.Unmarshal(body, lockTag)
+			lockTag.ID = 1
+			w.WriteHeader(http.StatusCreated)
+			_ = json.NewEncoder(w).Encode(lockTag)
+		case r.Method == http.MethodGet:
+			results := []*objects.Tag{}
+			if lockTag != nil {
+				results = append(results, lockTag)
+			}
+			_ = json.NewEncoder(w).Encode(service.Response[*objects.Tag]{
+				Count:   len(results),
+				Results: results,
+			})
+		case r.Method == http.MethodPatch:
+			var body map[string]string
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			lockTag.Description = body["description"]
+			_ = json.NewEncoder(w).Encode(lockTag)
+		case r.Method == http.MethodDelete:
+			if r.URL.Path != fmt.Sprintf("%s1/", constants.TagsAPIPath) {
+				t.Errorf("unexpected delete of %s", r.URL.Path)
+			}
+			lockTag = nil
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+}
+
+func TestRunLock_Netbox(t *testing.T) {
+	ctx := context.Background()
+	mockServer := newMockTagServer(t)
+	defer mockServer.Close()
+	newBackend := func() backend {
+		return &netboxBackend{api: &service.NetboxClient{
+			HTTPClient: mockServer.Client(),
+			Logger:     testLogger,
+			BaseURL:    mockServer.URL,
+			Timeout:    constants.DefaultAPITimeout,
+		}}
+	}
+
+	first := newTestRunLock(newBackend(), "first")
+	if err := first.Acquire(ctx); err != nil {
+		t.Fatalf("Acquire() error = %v", err)
+	}
+	second := newTestRunLock(newBackend(), "second")
+	err := second.Acquire(ctx)
+	if err == nil || !strings.Contains(err.Error(), "run lock is held by first") {
+		t.Errorf("Acquire() of held lock error = %v, want held by first", err)
+	}
+
+	err = first.backend.update(ctx, marker{Holder: "first", Heartbeat: time.Now().Add(-time.Hour)})
+	if err != nil {
+		t.Fatalf("update() error = %v", err)
+	}
+	if err := second.Acquire(ctx); err != nil {
+		t.Fatalf("Acquire() of stale lock error = %v", err)
+	}
+	if err := second.Release(ctx); err != nil {
+		t.Fatalf("Release() error = %v", err)
+	}
+	if _, err := newBackend().read(ctx); err != errLockNotFound {
+		t.Errorf("read() after Release() error = %v, want %v", err, errLockNotFound)
+	}
+}
@@ -3,11 +3,15 @@ package common
 import (
 	"context"
 	"crypto/x509"
+	"fmt"
 
 	"github.com/src-doo/netbox-ssot/internal/logger"
 	"github.com/src-doo/netbox-ssot/internal/netbox/inventory"
 	"github.com/src-doo/netbox-ssot/internal/netbox/objects"
 	"github.com/src-doo/netbox-ssot/internal/parser"
+	"github.com/src-doo/netbox-ssot/internal/tracing"
+	"github.com/src-doo/netbox-ssot/internal/utils"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 // Source is an interface for all sources (e.g. oVirt, VMware, etc.).
@@ -18,6 +22,16 @@ type Source interface {
 	Sync(*inventory.NetboxInventory) error
 }
 
+// Snapshotter is implemented by sources, whose data collected in Init
+// can be recorded to a snapshot and later loaded instead of calling Init.
+type Snapshotter interface {
+	// MarshalSnapshot returns data collected by Init encoded as json.
+	MarshalSnapshot() ([]byte, error)
+	// UnmarshalSnapshot loads data returned by MarshalSnapshot, so Sync
+	// can be called without contacting the source.
+	UnmarshalSnapshot(data []byte) error
+}
+
 // Config is a common configuration that all sources share.
 type Config struct {
 	Logger         *logger.Logger
@@ -29,6 +43,24 @@ type Config struct {
 	CAFile         string          // path to the ca file
 }
 
+// StartStep starts a tracing span for the init or sync step of the source.
+// kind is either "init" or "sync" and stepFunc is the function of the step.
+// Until the returned function is called, Ctx carries the span, so spans of
+// inventory and netbox calls made by the step are nested under it.
+func (c *Config) StartStep(kind string, stepFunc interface{}) func(err error) {
+	parentCtx := c.Ctx
+	ctx, span := tracing.Start(
+		parentCtx,
+		fmt.Sprintf("%s.%s", kind, utils.ExtractFunctionNameWithTrimPrefix(stepFunc, kind)),
+		attribute.String("netbox_ssot.source_type", string(c.SourceConfig.Type)),
+	)
+	c.Ctx = ctx
+	return func(err error) {
+		tracing.End(span, err)
+		c.Ctx = parentCtx
+	}
+}
+
 func (c Config) GetSourceTags() []*objects.Tag {
 	return []*objects.Tag{c.SourceNameTag, c.SourceTypeTag}
 }
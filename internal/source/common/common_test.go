@@ -1 +1,276 @@
 package common
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/src-doo/netbox-ssot/internal/constants"
+	"github.com/src-doo/netbox-ssot/internal/logger"
+	"github.com/src-doo/netbox-ssot/internal/netbox/inventory"
+	"github.com/src-doo/netbox-ssot/internal/netbox/netboxtest"
+	"github.com/src-doo/netbox-ssot/internal/netbox/objects"
+	"github.com/src-doo/netbox-ssot/internal/parser"
+)
+
+// testInventory returns inventory initialized from the netbox emulator.
+func testInventory(t *testing.T) *inventory.NetboxInventory {
+	t.Helper()
+	s := netboxtest.NewServer()
+	t.Cleanup(s.Close)
+	netboxURL, err := url.Parse(s.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	config := fmt.Sprintf(`
+netbox:
+  apiToken: "netbox-token"
+  hostname: %s
+  port: %s
+  httpScheme: http
+`, netboxURL.Hostname(), netboxURL.Port())
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	err = os.WriteFile(configPath, []byte(config), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsedConfig, err := parser.ParseConfig(configPath)
+	if err != nil {
+		t.Fatalf("ParseConfig() error = %v", err)
+	}
+	testLogger := &logger.Logger{Logger: log.New(os.Stdout, "", log.LstdFlags)}
+	ctx := context.WithValue(context.Background(), constants.CtxSourceKey, "test")
+	nbi := inventory.NewNetboxInventory(ctx, testLogger, parsedConfig.Netbox)
+	err = nbi.Init()
+	if err != nil {
+		t.Fatalf("Init() error = %v", err)
+	}
+	return nbi
+}
+
+func TestAddDeviceType(t *testing.T) {
+	ctx := context.WithValue(context.Background(), constants.CtxSourceKey, "test")
+	nbi := testInventory(t)
+	cisco, err := nbi.AddManufacturer(ctx, &objects.Manufacturer{Name: "Cisco", Slug: "cisco"})
+	if err != nil {
+		t.Fatalf("AddManufacturer() error = %v", err)
+	}
+
+	deviceType, err := AddDeviceType(ctx, nbi, cisco, "2951 ISR")
+	if err != nil {
+		t.Fatalf("AddDeviceType() error = %v", err)
+	}
+	if deviceType.Slug != "cisco-2951-isr" || deviceType.PartNumber != "CISCO2951/K9" ||
+		deviceType.UHeight != 2 || !deviceType.IsFullDepth {
+		t.Errorf("AddDeviceType() = %+v, want device type from library", deviceType)
+	}
+	exported := nbi.Export()
+	for path, want := range map[constants.APIPath]int{
+		constants.InterfaceTemplatesAPIPath:   3,
+		constants.ConsolePortTemplatesAPIPath: 1,
+		constants.PowerPortTemplatesAPIPath:   1,
+	} {
+		if got := len(exported[path]); got != want {
+			t.Errorf("AddDeviceType() added %d objects of %s, want %d", got, path, want)
+		}
+	}
+
+	// Interfaces, which netbox created from templates, are patched instead of created again
+	site, _ := nbi.GetSite(constants.DefaultSite)
+	device, err := nbi.AddDevice(ctx, &objects.Device{Name: "router1", Site: site, DeviceType: deviceType})
+	if err != nil {
+		t.Fatalf("AddDevice() error = %v", err)
+	}
+	_, err = nbi.AddInterface(ctx, &objects.Interface{
+		Device: device,
+		Name:   "GigabitEthernet0/0",
+		Type:   &objects.GE1FixedInterfaceType,
+	})
+	if err != nil {
+		t.Errorf("AddInterface() error = %v", err)
+	}
+	if got := len(nbi.Export()[constants.InterfacesAPIPath]); got != 3 {
+		t.Errorf("device has %d interfaces, want 3", got)
+	}
+
+	unknownDeviceType, err := AddDeviceType(ctx, nbi, cisco, "Unknown model")
+	if err != nil {
+		t.Fatalf("AddDeviceType() error = %v", err)
+	}
+	if unknownDeviceType.Slug != "cisco-unknown-model" || unknownDeviceType.PartNumber != "" {
+		t.Errorf("AddDeviceType() = %+v, want generic device type", unknownDeviceType)
+	}
+}
+
+func TestAddNeighborCable(t *testing.T) {
+	ctx := context.WithValue(context.Background(), constants.CtxSourceKey, "test")
+	nbi := testInventory(t)
+	cisco, err := nbi.AddManufacturer(ctx, &objects.Manufacturer{Name: "Cisco", Slug: "cisco"})
+	if err != nil {
+		t.Fatalf("AddManufacturer() error = %v", err)
+	}
+	deviceType, err := AddDeviceType(ctx, nbi, cisco, "Unknown model")
+	if err != nil {
+		t.Fatalf("AddDeviceType() error = %v", err)
+	}
+	site, _ := nbi.GetSite(constants.DefaultSite)
+	interfaces := map[string]*objects.Interface{}
+	for _, iface := range []struct {
+		device string
+		name   string
+		ifType *objects.InterfaceType
+	}{
+		{"esxi1", "vmnic0", &objects.GE1FixedInterfaceType},
+		{"esxi1", "vmk0", &objects.VirtualInterfaceType},
+		{"switch1", "GigabitEthernet1/0/1", &objects.GE1FixedInterfaceType},
+		{"switch1", "Vlan10", &objects.VirtualInterfaceType},
+	} {
+		device, err := nbi.AddDevice(ctx, &objects.Device{Name: iface.device, Site: site, DeviceType: deviceType})
+		if err != nil {
+			t.Fatalf("AddDevice() error = %v", err)
+		}
+		interfaces[iface.name], err = nbi.AddInterface(ctx, &objects.Interface{
+			Device: device,
+			Name:   iface.name,
+			Type:   iface.ifType,
+		})
+		if err != nil {
+			t.Fatalf("AddInterface() error = %v", err)
+		}
+	}
+
+	tests := []struct {
+		name                  string
+		localInterface        string
+		neighborName          string
+		neighborInterfaceName string
+		wantCable             bool
+	}{
+		{"Unknown neighbor", "vmnic0", "switch2", "GigabitEthernet1/0/1", false},
+		{"Unknown neighbor interface", "vmnic0", "switch1", "GigabitEthernet1/0/2", false},
+		{"Virtual interface", "vmk0", "switch1", "Vlan10", false},
+		{"Neighbor with domain and abbreviated interface", "vmnic0", "switch1.example.com", "Gi1/0/1", true},
+		{"CDP device id with serial number", "vmnic0", "switch1(FOC1234X0YZ)", "GigabitEthernet1/0/1", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cable, err := AddNeighborCable(
+				ctx,
+				nbi,
+				interfaces[tt.localInterface],
+				tt.neighborName,
+				tt.neighborInterfaceName,
+				nil,
+			)
+			if err != nil {
+				t.Fatalf("AddNeighborCable() error = %v", err)
+			}
+			if (cable != nil) != tt.wantCable {
+				t.Errorf("AddNeighborCable() = %v, want cable %t", cable, tt.wantCable)
+			}
+		})
+	}
+	if got := len(nbi.Export()[constants.CablesAPIPath]); got != 1 {
+		t.Errorf("inventory has %d cables, want 1", got)
+	}
+}
+
+func TestAddDHCPPool(t *testing.T) {
+	ctx := context.WithValue(context.Background(), constants.CtxSourceKey, "test")
+	nbi := testInventory(t)
+	tests := []struct {
+		name           string
+		pool           DHCPPool
+		ipVrfRelations map[string]string
+		wantRange      string
+		wantErr        bool
+	}{
+		{
+			name:      "Pool of the global routing table",
+			pool:      DHCPPool{Name: "clients", StartAddress: "10.0.0.100", EndAddress: "10.0.0.200", MaskBits: 24},
+			wantRange: "10.0.0.100/24-10.0.0.200/24",
+		},
+		{
+			name: "VRF of the pool does not exist in netbox",
+			pool: DHCPPool{
+				Name:         "guests",
+				StartAddress: "10.0.1.100",
+				EndAddress:   "10.0.1.200",
+				MaskBits:     24,
+				VRF:          "guests",
+			},
+			wantRange: "10.0.1.100/24-10.0.1.200/24",
+		},
+		{
+			name:           "VRF matched with ipVrfRelations does not exist in netbox",
+			pool:           DHCPPool{StartAddress: "10.0.2.100", EndAddress: "10.0.2.200", MaskBits: 24},
+			ipVrfRelations: map[string]string{"^10\\.0\\.2\\.": "guests"},
+			wantErr:        true,
+		},
+		{
+			name:    "End address before start address",
+			pool:    DHCPPool{StartAddress: "10.0.3.200", EndAddress: "10.0.3.100", MaskBits: 24},
+			wantErr: true,
+		},
+		{
+			name:    "Mixed address families",
+			pool:    DHCPPool{StartAddress: "10.0.4.100", EndAddress: "2001:db8::1", MaskBits: 24},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ipRange, err := AddDHCPPool(ctx, nbi, tt.pool, nil, tt.ipVrfRelations, nil)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("AddDHCPPool() error = %v, wantErr %t", err, tt.wantErr)
+			}
+			if err == nil && ipRange.StartAddress+"-"+ipRange.EndAddress != tt.wantRange {
+				t.Errorf("AddDHCPPool() = %s, want %s", ipRange, tt.wantRange)
+			}
+		})
+	}
+}
+
+func TestAddTenant(t *testing.T) {
+	ctx := context.WithValue(context.Background(), constants.CtxSourceKey, "test")
+	nbi := testInventory(t)
+	existingTenant, err := nbi.AddTenant(ctx, &objects.Tenant{Name: "Finance Payroll", Slug: "finance-payroll"})
+	if err != nil {
+		t.Fatalf("AddTenant() error = %v", err)
+	}
+	tenantGroupRelations := map[string]string{"^Finance": "Finance"}
+	tests := []struct {
+		name       string
+		tenantName string
+		wantGroup  string
+	}{
+		{"Tenant without tenant group", "Default", ""},
+		{"New tenant in tenant group", "Finance Accounting", "Finance"},
+		{"Existing tenant is placed in tenant group", "Finance Payroll", "Finance"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tenant, err := AddTenant(ctx, nbi, tt.tenantName, tenantGroupRelations)
+			if err != nil {
+				t.Fatalf("AddTenant() error = %v", err)
+			}
+			var gotGroup string
+			if tenant.Group != nil {
+				gotGroup = tenant.Group.Name
+			}
+			if tenant.Name != tt.tenantName || gotGroup != tt.wantGroup {
+				t.Errorf("AddTenant() = %v in group %q, want %s in group %q", tenant, gotGroup, tt.tenantName, tt.wantGroup)
+			}
+		})
+	}
+	if tenant, _ := nbi.GetTenant("Finance Payroll"); tenant.ID != existingTenant.ID {
+		t.Errorf("AddTenant() created %v, want existing tenant %v patched", tenant, existingTenant)
+	}
+	if got := len(nbi.Export()[constants.TenantGroupsAPIPath]); got != 1 {
+		t.Errorf("inventory has %d tenant groups, want 1", got)
+	}
+}
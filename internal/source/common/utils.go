@@ -3,14 +3,55 @@ package common
 import (
 	"context"
 	"fmt"
+	"net/netip"
 	"strings"
 
+	devices "github.com/src-doo/go-devicetype-library/pkg"
 	"github.com/src-doo/netbox-ssot/internal/constants"
 	"github.com/src-doo/netbox-ssot/internal/netbox/inventory"
 	"github.com/src-doo/netbox-ssot/internal/netbox/objects"
+	"github.com/src-doo/netbox-ssot/internal/netbox/service"
 	"github.com/src-doo/netbox-ssot/internal/utils"
 )
 
+// AddTenant returns the tenant with the given name, and creates it if it doesn't exist.
+// If the tenant name matches tenantGroupRelations, the tenant is placed under
+// the matched tenant group, which is created if it doesn't exist.
+func AddTenant(
+	ctx context.Context,
+	nbi *inventory.NetboxInventory,
+	tenantName string,
+	tenantGroupRelations map[string]string,
+) (*objects.Tenant, error) {
+	tenantGroupName, err := utils.MatchStringToValue(tenantName, tenantGroupRelations)
+	if err != nil {
+		return nil, fmt.Errorf("matching tenant to tenant group: %s", err)
+	}
+	if tenantGroupName == "" {
+		if tenant, ok := nbi.GetTenant(tenantName); ok {
+			return tenant, nil
+		}
+	}
+	newTenant := &objects.Tenant{
+		Name: tenantName,
+		Slug: utils.Slugify(tenantName),
+	}
+	if tenantGroupName != "" {
+		newTenant.Group, err = nbi.AddTenantGroup(ctx, &objects.TenantGroup{
+			Name: tenantGroupName,
+			Slug: utils.Slugify(tenantGroupName),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("add new tenant group: %s", err)
+		}
+	}
+	tenant, err := nbi.AddTenant(ctx, newTenant)
+	if err != nil {
+		return nil, fmt.Errorf("add new tenant: %s", err)
+	}
+	return tenant, nil
+}
+
 // Function that matches cluster to tenant using regexRelationsMap.
 //
 // In case there is no match or regexRelations is nil, it will return nil.
@@ -19,6 +60,7 @@ func MatchClusterToTenant(
 	nbi *inventory.NetboxInventory,
 	clusterName string,
 	clusterTenantRelations map[string]string,
+	tenantGroupRelations map[string]string,
 ) (*objects.Tenant, error) {
 	if clusterTenantRelations == nil {
 		return nil, nil
@@ -28,18 +70,7 @@ func MatchClusterToTenant(
 		return nil, fmt.Errorf("matching cluster to tenant: %s", err)
 	}
 	if tenantName != "" {
-		tenant, ok := nbi.GetTenant(tenantName)
-		if !ok {
-			tenant, err := nbi.AddTenant(ctx, &objects.Tenant{
-				Name: tenantName,
-				Slug: utils.Slugify(tenantName),
-			})
-			if err != nil {
-				return nil, fmt.Errorf("add new tenant: %s", err)
-			}
-			return tenant, nil
-		}
-		return tenant, nil
+		return AddTenant(ctx, nbi, tenantName, tenantGroupRelations)
 	}
 	return nil, nil
 }
@@ -149,6 +180,7 @@ func MatchVlanToTenant(
 	nbi *inventory.NetboxInventory,
 	vlanName string,
 	vlanTenantRelations map[string]string,
+	tenantGroupRelations map[string]string,
 ) (*objects.Tenant, error) {
 	if vlanTenantRelations == nil {
 		return nil, nil
@@ -158,18 +190,7 @@ func MatchVlanToTenant(
 		return nil, fmt.Errorf("matching vlan to tenant: %s", err)
 	}
 	if tenantName != "" {
-		tenant, ok := nbi.GetTenant(tenantName)
-		if !ok {
-			tenant, err := nbi.AddTenant(ctx, &objects.Tenant{
-				Name: tenantName,
-				Slug: utils.Slugify(tenantName),
-			})
-			if err != nil {
-				return nil, fmt.Errorf("add new tenant: %s", err)
-			}
-			return tenant, nil
-		}
-		return tenant, nil
+		return AddTenant(ctx, nbi, tenantName, tenantGroupRelations)
 	}
 
 	return nil, nil
@@ -246,6 +267,7 @@ func MatchHostToTenant(
 	nbi *inventory.NetboxInventory,
 	hostName string,
 	hostTenantRelations map[string]string,
+	tenantGroupRelations map[string]string,
 ) (*objects.Tenant, error) {
 	if hostTenantRelations == nil {
 		return nil, nil
@@ -255,14 +277,7 @@ func MatchHostToTenant(
 		return nil, fmt.Errorf("matching host to tenant: %s", err)
 	}
 	if tenantName != "" {
-		tenant, err := nbi.AddTenant(ctx, &objects.Tenant{
-			Name: tenantName,
-			Slug: utils.Slugify(tenantName),
-		})
-		if err != nil {
-			return nil, fmt.Errorf("add new tenant: %s", err)
-		}
-		return tenant, nil
+		return AddTenant(ctx, nbi, tenantName, tenantGroupRelations)
 	}
 	return nil, nil
 }
@@ -304,6 +319,7 @@ func MatchVMToTenant(
 	nbi *inventory.NetboxInventory,
 	vmName string,
 	vmTenantRelations map[string]string,
+	tenantGroupRelations map[string]string,
 ) (*objects.Tenant, error) {
 	if vmTenantRelations == nil {
 		return nil, nil
@@ -313,18 +329,7 @@ func MatchVMToTenant(
 		return nil, fmt.Errorf("matching vm to tenant: %s", err)
 	}
 	if tenantName != "" {
-		site, ok := nbi.GetTenant(tenantName)
-		if !ok {
-			tenant, err := nbi.AddTenant(ctx, &objects.Tenant{
-				Name: tenantName,
-				Slug: utils.Slugify(tenantName),
-			})
-			if err != nil {
-				return nil, fmt.Errorf("add new tenant: %s", err)
-			}
-			return tenant, nil
-		}
-		return site, nil
+		return AddTenant(ctx, nbi, tenantName, tenantGroupRelations)
 	}
 	return nil, nil
 }
@@ -358,6 +363,91 @@ func MatchVMToRole(
 	return nil, nil
 }
 
+// AddDeviceType adds the device type of the manufacturer's model to the inventory.
+//
+// In case that the model is in the device type library, the device type is added with
+// its full definition, including interface, console port and power port templates,
+// from which netbox creates components of new devices of the device type.
+func AddDeviceType(
+	ctx context.Context,
+	nbi *inventory.NetboxInventory,
+	manufacturer *objects.Manufacturer,
+	model string,
+) (*objects.DeviceType, error) {
+	deviceData, ok := devices.DeviceTypesMap[manufacturer.Name][model]
+	if !ok {
+		return nbi.AddDeviceType(ctx, &objects.DeviceType{
+			Manufacturer: manufacturer,
+			Model:        model,
+			Slug:         utils.GenerateDeviceTypeSlug(manufacturer.Name, model),
+		})
+	}
+	newDeviceType := &objects.DeviceType{
+		Manufacturer: manufacturer,
+		Model:        model,
+		Slug:         deviceData.Slug,
+		PartNumber:   deviceData.PartNumber,
+		UHeight:      float64(deviceData.UHeight),
+		IsFullDepth:  deviceData.IsFullDepth,
+	}
+	if deviceData.Airflow != "" {
+		newDeviceType.Airflow = &objects.DeviceAirFlowType{Choice: objects.Choice{Value: deviceData.Airflow}}
+	}
+	// Netbox requires unit of the weight
+	if deviceData.Weight > 0 && deviceData.WeightUnit != "" {
+		newDeviceType.Weight = deviceData.Weight
+		newDeviceType.WeightUnit = &objects.WeightUnit{Choice: objects.Choice{Value: deviceData.WeightUnit}}
+	}
+	deviceType, err := nbi.AddDeviceType(ctx, newDeviceType)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, iface := range deviceData.Interfaces {
+		_, err := nbi.AddInterfaceTemplate(ctx, &objects.InterfaceTemplate{
+			DeviceType: deviceType,
+			Name:       iface.Name,
+			Label:      iface.Label,
+			Type:       &objects.InterfaceType{Choice: objects.Choice{Value: iface.Type}},
+			MgmtOnly:   iface.MgmtOnly,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("add interface template %s: %s", iface.Name, err)
+		}
+	}
+	for _, consolePort := range deviceData.ConsolePorts {
+		consolePortTemplate := &objects.ConsolePortTemplate{
+			DeviceType: deviceType,
+			Name:       consolePort.Name,
+			Label:      consolePort.Label,
+		}
+		if consolePort.Type != "" {
+			consolePortTemplate.Type = &objects.ConsolePortType{Choice: objects.Choice{Value: consolePort.Type}}
+		}
+		_, err := nbi.AddConsolePortTemplate(ctx, consolePortTemplate)
+		if err != nil {
+			return nil, fmt.Errorf("add console port template %s: %s", consolePort.Name, err)
+		}
+	}
+	for _, powerPort := range deviceData.PowerPorts {
+		powerPortTemplate := &objects.PowerPortTemplate{
+			DeviceType:    deviceType,
+			Name:          powerPort.Name,
+			Label:         powerPort.Label,
+			MaximumDraw:   int(powerPort.MaximumDraw),
+			AllocatedDraw: int(powerPort.AllocatedDraw),
+		}
+		if powerPort.Type != "" {
+			powerPortTemplate.Type = &objects.PowerPortType{Choice: objects.Choice{Value: powerPort.Type}}
+		}
+		_, err := nbi.AddPowerPortTemplate(ctx, powerPortTemplate)
+		if err != nil {
+			return nil, fmt.Errorf("add power port template %s: %s", powerPort.Name, err)
+		}
+	}
+	return deviceType, nil
+}
+
 // CreateMACAddressForObjectType creates MAC address for object type.
 func CreateMACAddressForObjectType(
 	ctx context.Context,
@@ -410,23 +500,35 @@ func SetPrimaryIPAddressForObject(
 	return nil
 }
 
+// SetPrimaryMACForInterface sets mac as the primary MAC address of the interface.
+// For netbox versions without MAC address objects, the MAC address is set
+// on the interface itself.
 func SetPrimaryMACForInterface(
 	ctx context.Context,
 	nbi *inventory.NetboxInventory,
 	targetInterface objects.MACAddressOwner,
 	mac *objects.MACAddress,
 ) error {
+	macAddressObjects := nbi.Supports(service.FeatureMACAddressObjects)
 	switch targetInterface := targetInterface.(type) {
 	case *objects.Interface:
 		interfaceCopy := *targetInterface
-		interfaceCopy.PrimaryMACAddress = mac
+		if macAddressObjects {
+			interfaceCopy.PrimaryMACAddress = mac
+		} else {
+			interfaceCopy.MAC = mac.MAC
+		}
 		_, err := nbi.AddInterface(ctx, &interfaceCopy)
 		if err != nil {
 			return fmt.Errorf("set primary mac for interface %+v: %s", interfaceCopy, err)
 		}
 	case *objects.VMInterface:
 		vmInterfaceCopy := *targetInterface
-		vmInterfaceCopy.PrimaryMACAddress = mac
+		if macAddressObjects {
+			vmInterfaceCopy.PrimaryMACAddress = mac
+		} else {
+			vmInterfaceCopy.MAC = mac.MAC
+		}
 		_, err := nbi.AddVMInterface(ctx, &vmInterfaceCopy)
 		if err != nil {
 			return fmt.Errorf("set primary mac for interface %+v: %s", vmInterfaceCopy, err)
@@ -469,3 +571,229 @@ func MatchIPToVRF(
 	}
 	return nil, nil
 }
+
+// interfaceAbbreviations maps abbreviated interface names, which are often
+// reported by discovery protocols, to full interface names.
+var interfaceAbbreviations = map[string]string{
+	"Et":  "Ethernet",
+	"Eth": "Ethernet",
+	"Fa":  "FastEthernet",
+	"Gi":  "GigabitEthernet",
+	"Tw":  "TwoGigabitEthernet",
+	"Te":  "TenGigabitEthernet",
+	"Twe": "TwentyFiveGigE",
+	"Fo":  "FortyGigabitEthernet",
+	"Hu":  "HundredGigE",
+	"Po":  "Port-channel",
+}
+
+// ExpandInterfaceName returns the full name of the abbreviated interface name
+// (e.g. Gi1/0/1 -> GigabitEthernet1/0/1). Other names are returned unchanged.
+func ExpandInterfaceName(interfaceName string) string {
+	prefixEnd := strings.IndexAny(interfaceName, "0123456789")
+	if prefixEnd <= 0 {
+		return interfaceName
+	}
+	if fullPrefix, ok := interfaceAbbreviations[interfaceName[:prefixEnd]]; ok {
+		return fullPrefix + interfaceName[prefixEnd:]
+	}
+	return interfaceName
+}
+
+// AddNeighborCable adds a cable between localInterface and the interface
+// neighborInterfaceName of the device neighborName, as reported by a discovery
+// protocol (LLDP or CDP). Neighbor device is matched by its name, or by its
+// name without domain, and its interface by its full or abbreviated name.
+// Neighbors, that are not synced to netbox, are skipped and nil is returned.
+func AddNeighborCable(
+	ctx context.Context,
+	nbi *inventory.NetboxInventory,
+	localInterface *objects.Interface,
+	neighborName string,
+	neighborInterfaceName string,
+	tags []*objects.Tag,
+) (*objects.Cable, error) {
+	// CDP device id of some devices includes serial number, e.g. switch1(FOC1234X0YZ)
+	neighborName, _, _ = strings.Cut(neighborName, "(")
+	neighborDevice, ok := nbi.GetDeviceByName(neighborName)
+	if !ok {
+		neighborDevice, ok = nbi.GetDeviceByName(strings.Split(neighborName, ".")[0])
+	}
+	if !ok {
+		return nil, nil
+	}
+	neighborInterface, ok := nbi.GetInterface(neighborInterfaceName, neighborDevice.ID)
+	if !ok {
+		neighborInterface, ok = nbi.GetInterface(ExpandInterfaceName(neighborInterfaceName), neighborDevice.ID)
+	}
+	if !ok || neighborInterface.ID == localInterface.ID {
+		return nil, nil
+	}
+	if !canBeCabled(localInterface) || !canBeCabled(neighborInterface) {
+		return nil, nil
+	}
+	cable, err := nbi.AddCable(ctx, &objects.Cable{
+		NetboxObject: objects.NetboxObject{
+			Tags: tags,
+		},
+		ATerminations: []*objects.CableTermination{
+			{ObjectType: constants.ContentTypeDcimInterface, ObjectID: localInterface.ID},
+		},
+		BTerminations: []*objects.CableTermination{
+			{ObjectType: constants.ContentTypeDcimInterface, ObjectID: neighborInterface.ID},
+		},
+		Status: &objects.CableStatusConnected,
+	})
+	if err != nil {
+		return nil, fmt.Errorf(
+			"add cable between %s and %s/%s: %s",
+			localInterface.Name,
+			neighborDevice.Name,
+			neighborInterface.Name,
+			err,
+		)
+	}
+	return cable, nil
+}
+
+// canBeCabled returns false for interfaces, which netbox doesn't allow
+// to be connected with a cable.
+func canBeCabled(iface *objects.Interface) bool {
+	if iface.Type == nil {
+		return true
+	}
+	switch iface.Type.Value {
+	case objects.VirtualInterfaceType.Value, objects.BridgeInterfaceType.Value, objects.LAGInterfaceType.Value:
+		return false
+	}
+	return true
+}
+
+// VirtualChassisMember describes membership of a device in a virtual chassis,
+// e.g. a switch in a stack or a firewall in a HA pair.
+type VirtualChassisMember struct {
+	// VirtualChassisName is the name of the virtual chassis.
+	VirtualChassisName string
+	// Position of the device in the virtual chassis, starting with 1.
+	Position int
+	// Priority of the device for becoming master of the virtual chassis.
+	Priority int
+	// Master is true, if the device is currently in control of the virtual chassis.
+	Master bool
+}
+
+// AddVirtualChassisMember adds the virtual chassis of the member and assigns
+// newDevice to it. It should be called before newDevice is added to the inventory.
+// Master of the virtual chassis is set afterwards with SetVirtualChassisMaster,
+// because netbox only allows members to be masters.
+func AddVirtualChassisMember(
+	ctx context.Context,
+	nbi *inventory.NetboxInventory,
+	newDevice *objects.Device,
+	member VirtualChassisMember,
+	tags []*objects.Tag,
+) error {
+	virtualChassis, err := nbi.AddVirtualChassis(ctx, &objects.VirtualChassis{
+		NetboxObject: objects.NetboxObject{
+			Tags: tags,
+		},
+		Name: member.VirtualChassisName,
+	})
+	if err != nil {
+		return fmt.Errorf("add virtual chassis %s: %s", member.VirtualChassisName, err)
+	}
+	newDevice.VirtualChassis = virtualChassis
+	newDevice.VCPosition = member.Position
+	newDevice.VCPriority = member.Priority
+	return nil
+}
+
+// SetVirtualChassisMaster sets nbDevice as the master of its virtual chassis,
+// if the member is master. nbDevice must already be added to the inventory.
+func SetVirtualChassisMaster(
+	ctx context.Context,
+	nbi *inventory.NetboxInventory,
+	nbDevice *objects.Device,
+	member VirtualChassisMember,
+	tags []*objects.Tag,
+) error {
+	if !member.Master {
+		return nil
+	}
+	_, err := nbi.AddVirtualChassis(ctx, &objects.VirtualChassis{
+		NetboxObject: objects.NetboxObject{
+			Tags: tags,
+		},
+		Name:   member.VirtualChassisName,
+		Master: nbDevice,
+	})
+	if err != nil {
+		return fmt.Errorf("set master of virtual chassis %s: %s", member.VirtualChassisName, err)
+	}
+	return nil
+}
+
+// DHCPPool is a pool of addresses, which a DHCP server leases to its clients.
+type DHCPPool struct {
+	// Name of the pool (e.g. name of the DHCP pool or interface of the DHCP server).
+	Name string
+	// StartAddress is the first address of the pool without mask.
+	StartAddress string
+	// EndAddress is the last address of the pool without mask.
+	EndAddress string
+	// MaskBits is the mask of the network, which the pool is part of.
+	MaskBits int
+	// VRF is the name of the VRF of the pool, if it is reported by the source.
+	VRF string
+}
+
+// AddDHCPPool adds the DHCP pool as an IP range of the tenant. VRF of the pool
+// is the VRF reported by the source, if it exists in netbox. Otherwise, it is
+// matched with ipVrfRelations using the start address of the pool.
+// It returns nil, if the pool overlaps with a pool of another source.
+func AddDHCPPool(
+	ctx context.Context,
+	nbi *inventory.NetboxInventory,
+	pool DHCPPool,
+	tenant *objects.Tenant,
+	ipVrfRelations map[string]string,
+	tags []*objects.Tag,
+) (*objects.IPRange, error) {
+	startAddress, err := netip.ParseAddr(pool.StartAddress)
+	if err != nil {
+		return nil, fmt.Errorf("parse start address of dhcp pool %s: %s", pool.Name, err)
+	}
+	endAddress, err := netip.ParseAddr(pool.EndAddress)
+	if err != nil {
+		return nil, fmt.Errorf("parse end address of dhcp pool %s: %s", pool.Name, err)
+	}
+	if startAddress.Is4() != endAddress.Is4() || endAddress.Less(startAddress) {
+		return nil, fmt.Errorf("invalid dhcp pool %s: %s-%s", pool.Name, startAddress, endAddress)
+	}
+	vrf, ok := nbi.GetVRF(pool.VRF)
+	if pool.VRF == "" || !ok {
+		vrf, err = MatchIPToVRF(ctx, nbi, pool.StartAddress, ipVrfRelations)
+		if err != nil {
+			return nil, fmt.Errorf("match dhcp pool %s to vrf: %s", pool.Name, err)
+		}
+	}
+	description := "DHCP pool"
+	if pool.Name != "" {
+		description = fmt.Sprintf("DHCP pool %s", pool.Name)
+	}
+	ipRange, err := nbi.AddIPRange(ctx, &objects.IPRange{
+		NetboxObject: objects.NetboxObject{
+			Tags:        tags,
+			Description: description,
+		},
+		StartAddress: fmt.Sprintf("%s/%d", startAddress, pool.MaskBits),
+		EndAddress:   fmt.Sprintf("%s/%d", endAddress, pool.MaskBits),
+		Status:       &objects.IPRangeStatusActive,
+		Tenant:       tenant,
+		VRF:          vrf,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("add dhcp pool %s: %s", pool.Name, err)
+	}
+	return ipRange, nil
+}
@@ -0,0 +1,66 @@
+package common
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"github.com/src-doo/netbox-ssot/internal/constants"
+)
+
+// SyncConcurrently calls syncItem for each of the items, using at most
+// maxWorkers goroutines of the source. Items are started in the given order,
+// so the reported errors don't depend on scheduling:
+//   - without continueOnError, no more items are started after an item fails,
+//     and the error of the first failed item is returned.
+//   - with continueOnError, all items are synced, and errors of all failed
+//     items are logged and returned in the order of the items.
+func SyncConcurrently[T any](c *Config, items []T, syncItem func(T) error) error {
+	maxWorkers := c.SourceConfig.MaxWorkers
+	if maxWorkers <= 0 {
+		maxWorkers = constants.DefaultSourceMaxWorkers
+	}
+	// Use a guard channel as semaphore to limit the number of goroutines
+	guard := make(chan struct{}, maxWorkers)
+	itemErrors := make([]error, len(items))
+	var failed atomic.Bool
+	var wg sync.WaitGroup
+	for i, item := range items {
+		if failed.Load() && !c.SourceConfig.ContinueOnError {
+			break
+		}
+		guard <- struct{}{} // Block if maxWorkers are running
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-guard }() // Release one spot in the semaphore
+			itemErrors[i] = syncItem(item)
+			if itemErrors[i] != nil {
+				failed.Store(true)
+			}
+		}()
+	}
+	wg.Wait()
+
+	var encounteredErrors []error
+	for _, err := range itemErrors {
+		if err == nil {
+			continue
+		}
+		if !c.SourceConfig.ContinueOnError {
+			return err
+		}
+		c.Logger.Errorf(c.Ctx, "%s (continuing due to continueOnError flag)", err)
+		encounteredErrors = append(encounteredErrors, err)
+	}
+	if len(encounteredErrors) > 0 {
+		return fmt.Errorf(
+			"%d of %d items failed: %w",
+			len(encounteredErrors),
+			len(items),
+			errors.Join(encounteredErrors...),
+		)
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/src-doo/netbox-ssot/internal/logger"
+	"github.com/src-doo/netbox-ssot/internal/parser"
+)
+
+func TestSyncConcurrently(t *testing.T) {
+	tests := []struct {
+		name            string
+		continueOnError bool
+		failing         map[int]bool
+		wantErr         string
+		wantSynced      int
+	}{
+		{
+			name:       "All items synced",
+			wantSynced: 100,
+		},
+		{
+			name:            "Errors are joined in order of items",
+			continueOnError: true,
+			failing:         map[int]bool{70: true, 3: true, 41: true},
+			wantErr:         "3 of 100 items failed: item 3\nitem 41\nitem 70",
+			wantSynced:      97,
+		},
+		{
+			name:    "First failed item is returned",
+			failing: map[int]bool{5: true, 6: true},
+			wantErr: "item 5",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &Config{
+				Logger: &logger.Logger{Logger: log.New(os.Stdout, "", log.LstdFlags)},
+				SourceConfig: &parser.SourceConfig{
+					MaxWorkers:      4,
+					ContinueOnError: tt.continueOnError,
+				},
+				Ctx: context.Background(),
+			}
+			items := make([]int, 100)
+			for i := range items {
+				items[i] = i
+			}
+			var lock sync.Mutex
+			running, maxRunning, synced := 0, 0, 0
+			err := SyncConcurrently(config, items, func(item int) error {
+				lock.Lock()
+				running++
+				maxRunning = max(maxRunning, running)
+				lock.Unlock()
+				time.Sleep(time.Millisecond)
+				lock.Lock()
+				defer lock.Unlock()
+				running--
+				if tt.failing[item] {
+					return fmt.Errorf("item %d", item)
+				}
+				synced++
+				return nil
+			})
+			if tt.wantErr == "" && err != nil {
+				t.Fatalf("SyncConcurrently() error = %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || err.Error() != tt.wantErr) {
+				t.Fatalf("SyncConcurrently() error = %v, want %q", err, tt.wantErr)
+			}
+			if maxRunning > config.SourceConfig.MaxWorkers {
+				t.Errorf("%d items were synced at once, want at most %d", maxRunning, config.SourceConfig.MaxWorkers)
+			}
+			if tt.wantSynced > 0 && synced != tt.wantSynced {
+				t.Errorf("synced %d items, want %d", synced, tt.wantSynced)
+			}
+			if tt.wantErr != "" && !tt.continueOnError && synced == len(items)-len(tt.failing) {
+				t.Errorf("all items were synced after failure, want no more items started")
+			}
+		})
+	}
+}
+
+func TestSyncConcurrently_DefaultMaxWorkers(t *testing.T) {
+	config := &Config{SourceConfig: &parser.SourceConfig{}}
+	errItem := errors.New("failed")
+	err := SyncConcurrently(config, []string{"a"}, func(string) error { return errItem })
+	if !errors.Is(err, errItem) {
+		t.Errorf("SyncConcurrently() error = %v, want %v", err, errItem)
+	}
+}
@@ -27,6 +27,12 @@ type DnacSource struct {
 	SSID2WlanGroupName map[string]string
 	// SSID2SecurityDetails WirelessLANName -> SSIDDetails
 	SSID2SecurityDetails map[string]dnac.ResponseItemWirelessGetEnterpriseSSIDSSIDDetails
+	// PhysicalLinks are links between device interfaces from the physical topology.
+	PhysicalLinks []dnac.ResponseTopologyGetPhysicalTopologyResponseLinks
+	// DeviceID2Modules DeviceID -> modules (e.g. power supplies, line cards) of the device
+	DeviceID2Modules map[string][]dnac.ResponseDevicesGetModulesResponse
+	// DeviceID2StackMembers DeviceID -> switches of the stack. Only set for stacked devices.
+	DeviceID2StackMembers map[string][]dnac.ResponseDevicesGetStackDetailsForDeviceResponseStackSwitchInfo
 
 	// Relations between dnac data. Initialized in init functions.
 	Site2Parent           map[string]string          // Site ID -> Parent Site ID
@@ -39,8 +45,12 @@ type DnacSource struct {
 	DeviceID2isMissingPrimaryIP sync.Map
 	// VID2nbVlan: VlanID -> nbVlan
 	VID2nbVlan sync.Map
-	// SiteID2nbSite: SiteID -> nbSite
-	SiteID2nbSite           sync.Map
+	// SiteID2nbSite: SiteID -> nbSite. With site hierarchy, floors map to the site of their building.
+	SiteID2nbSite sync.Map
+	// SiteID2nbRegion: SiteID of area -> nbRegion. Only used with site hierarchy.
+	SiteID2nbRegion sync.Map
+	// SiteID2nbLocation: SiteID of floor -> nbLocation. Only used with floor site hierarchy.
+	SiteID2nbLocation       sync.Map
 	DeviceID2nbDevice       sync.Map // DeviceID -> nbDevice
 	InterfaceID2nbInterface sync.Map // InterfaceID -> nbInterface
 }
@@ -52,7 +62,7 @@ func (ds *DnacSource) Init() error {
 		ds.Config.SourceConfig.Hostname,
 		ds.Config.SourceConfig.Port,
 	)
-	Client, err := dnac.NewClientWithOptions(
+	Client, err := dnac.NewClientWithOptionsNoAuth(
 		dnacURL,
 		ds.SourceConfig.Username,
 		ds.SourceConfig.Password,
@@ -63,18 +73,34 @@ func (ds *DnacSource) Init() error {
 	if err != nil {
 		return fmt.Errorf("creating dnac client: %s", err)
 	}
+	transport, err := utils.NewHTTPTransport(ds.SourceConfig.HTTPClientConfig())
+	if err != nil {
+		return fmt.Errorf("create http transport: %s", err)
+	}
+	// Client is created without authentication, so the token
+	// is already requested with the configured transport
+	Client.RestyClient().SetTransport(transport)
+	if err = Client.AuthClient(); err != nil {
+		return fmt.Errorf("authenticate dnac client: %s", err)
+	}
 	// Initialize items from vsphere API to local storage
 	initFunctions := []func(*dnac.Client) error{
 		ds.initSites,
 		ds.initMemberships,
 		ds.initDevices,
+		ds.initStacks,
 		ds.initInterfaces,
+		ds.initModules,
 		ds.initWirelessLANs,
+		ds.initPhysicalLinks,
 	}
 
 	for _, initFunc := range initFunctions {
 		startTime := time.Now()
-		if err := initFunc(Client); err != nil {
+		endStep := ds.StartStep("init", initFunc)
+		err := initFunc(Client)
+		endStep(err)
+		if err != nil {
 			return fmt.Errorf("dnac initialization failure: %v", err)
 		}
 		duration := time.Since(startTime)
@@ -93,7 +119,9 @@ func (ds *DnacSource) Sync(nbi *inventory.NetboxInventory) error {
 		ds.syncSites,
 		ds.syncVlans,
 		ds.syncDevices,
+		ds.syncInventoryItems,
 		ds.syncDeviceInterfaces,
+		ds.syncCables,
 		ds.syncWirelessLANs,
 		ds.syncMissingDevicePrimaryIPs,
 	}
@@ -102,7 +130,9 @@ func (ds *DnacSource) Sync(nbi *inventory.NetboxInventory) error {
 	for _, syncFunc := range syncFunctions {
 		startTime := time.Now()
 		funcName := utils.ExtractFunctionNameWithTrimPrefix(syncFunc, "sync")
+		endStep := ds.StartStep("sync", syncFunc)
 		err := syncFunc(nbi)
+		endStep(err)
 		if err != nil {
 			if ds.SourceConfig.ContinueOnError {
 				ds.Logger.Errorf(
@@ -3,6 +3,7 @@ package dnac
 import (
 	"fmt"
 	"net/http"
+	"strings"
 
 	dnac "github.com/cisco-en-programmability/dnacenter-go-sdk/v7/sdk"
 )
@@ -84,6 +85,34 @@ func (ds *DnacSource) initVlansForDevice(c *dnac.Client, deviceID string) {
 	}
 }
 
+// Collects members of switch stacks from DNAC API and stores them in the
+// local source inventory. Stacked devices are recognized by serial numbers
+// of all members, which DNAC joins with a comma. If details of any stack
+// can't be collected, initialization fails, so that stack members and
+// virtual chassis of the stack aren't removed as orphans.
+//
+// This function has to run after initDevices.
+func (ds *DnacSource) initStacks(c *dnac.Client) error {
+	ds.DeviceID2StackMembers = make(map[string][]dnac.ResponseDevicesGetStackDetailsForDeviceResponseStackSwitchInfo)
+	for deviceID, device := range ds.Devices {
+		if !strings.Contains(device.SerialNumber, ",") {
+			continue
+		}
+		stack, response, err := c.Devices.GetStackDetailsForDevice(deviceID)
+		if err != nil {
+			return fmt.Errorf("init stack details of device %s: %s", deviceID, err)
+		}
+		if response.StatusCode() != http.StatusOK {
+			return fmt.Errorf("init stack details of device %s response code: %s", deviceID, response.String())
+		}
+		if stack.Response == nil || stack.Response.StackSwitchInfo == nil {
+			return fmt.Errorf("init stack details of device %s: response without stack members", deviceID)
+		}
+		ds.DeviceID2StackMembers[deviceID] = *stack.Response.StackSwitchInfo
+	}
+	return nil
+}
+
 // Collects all interfaces from DNAC API and stores them in the
 // local source inventory.
 func (ds *DnacSource) initInterfaces(c *dnac.Client) error {
@@ -125,6 +154,42 @@ func (ds *DnacSource) initInterfaces(c *dnac.Client) error {
 	return nil
 }
 
+// Collects modules (e.g. power supplies, fans and line cards) of each device
+// from DNAC API and stores them in the local source inventory. If modules of
+// any device can't be collected, initialization fails, so that inventory items
+// of the device aren't removed as orphans.
+//
+// This function has to run after initDevices.
+func (ds *DnacSource) initModules(c *dnac.Client) error {
+	limit := 500
+	ds.DeviceID2Modules = make(map[string][]dnac.ResponseDevicesGetModulesResponse, len(ds.Devices))
+	for deviceID := range ds.Devices {
+		offset := 0
+		deviceModules := make([]dnac.ResponseDevicesGetModulesResponse, 0)
+		for {
+			modules, response, err := c.Devices.GetModules(
+				&dnac.GetModulesQueryParams{DeviceID: deviceID, Offset: offset, Limit: limit},
+			)
+			if err != nil {
+				return fmt.Errorf("init modules of device %s: %s", deviceID, err)
+			}
+			if response.StatusCode() != http.StatusOK {
+				return fmt.Errorf("init modules of device %s response code: %s", deviceID, response.String())
+			}
+			if modules.Response == nil {
+				break
+			}
+			deviceModules = append(deviceModules, *modules.Response...)
+			if len(*modules.Response) < limit {
+				break
+			}
+			offset += limit
+		}
+		ds.DeviceID2Modules[deviceID] = deviceModules
+	}
+	return nil
+}
+
 // For each site id finds the corresponding device ids.
 // This is necessary to find relations between devices and sites.
 //
@@ -228,3 +293,20 @@ func (ds *DnacSource) initWirelessLANs(c *dnac.Client) error {
 
 	return nil
 }
+
+// Collects links between devices from the physical topology
+// and stores them in the local source inventory.
+func (ds *DnacSource) initPhysicalLinks(c *dnac.Client) error {
+	topology, response, err := c.Topology.GetPhysicalTopology(nil)
+	if err != nil {
+		return fmt.Errorf("init physical topology: %s", err)
+	}
+	if response.StatusCode() != http.StatusOK {
+		return fmt.Errorf("init physical topology response code: %s", response.String())
+	}
+	ds.PhysicalLinks = make([]dnac.ResponseTopologyGetPhysicalTopologyResponseLinks, 0)
+	if topology.Response != nil && topology.Response.Links != nil {
+		ds.PhysicalLinks = append(ds.PhysicalLinks, *topology.Response.Links...)
+	}
+	return nil
+}
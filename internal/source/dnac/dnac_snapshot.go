@@ -0,0 +1,54 @@
+package dnac
+
+import (
+	"encoding/json"
+
+	dnac "github.com/cisco-en-programmability/dnacenter-go-sdk/v7/sdk"
+)
+
+// snapshot references data of the source, which is initialized in init functions.
+type snapshot struct {
+	Sites                           *map[string]dnac.ResponseSitesGetSiteResponse
+	Devices                         *map[string]dnac.ResponseDevicesGetDeviceListResponse
+	Interfaces                      *map[string]dnac.ResponseDevicesGetAllInterfacesResponse
+	Vlans                           *map[int]dnac.ResponseDevicesGetDeviceInterfaceVLANsResponse
+	WirelessLANInterfaceName2VlanID *map[string]int
+	SSID2WirelessProfileDetails     *map[string]dnac.ResponseItemWirelessGetWirelessProfileProfileDetailsSSIDDetails
+	SSID2WlanGroupName              *map[string]string
+	SSID2SecurityDetails            *map[string]dnac.ResponseItemWirelessGetEnterpriseSSIDSSIDDetails
+	PhysicalLinks                   *[]dnac.ResponseTopologyGetPhysicalTopologyResponseLinks
+	DeviceID2Modules                *map[string][]dnac.ResponseDevicesGetModulesResponse
+	DeviceID2StackMembers           *map[string][]dnac.ResponseDevicesGetStackDetailsForDeviceResponseStackSwitchInfo
+	Site2Parent                     *map[string]string
+	Site2Devices                    *map[string]map[string]bool
+	Device2Site                     *map[string]string
+	DeviceID2InterfaceIDs           *map[string][]string
+}
+
+func (ds *DnacSource) snapshot() *snapshot {
+	return &snapshot{
+		Sites:                           &ds.Sites,
+		Devices:                         &ds.Devices,
+		Interfaces:                      &ds.Interfaces,
+		Vlans:                           &ds.Vlans,
+		WirelessLANInterfaceName2VlanID: &ds.WirelessLANInterfaceName2VlanID,
+		SSID2WirelessProfileDetails:     &ds.SSID2WirelessProfileDetails,
+		SSID2WlanGroupName:              &ds.SSID2WlanGroupName,
+		SSID2SecurityDetails:            &ds.SSID2SecurityDetails,
+		PhysicalLinks:                   &ds.PhysicalLinks,
+		DeviceID2Modules:                &ds.DeviceID2Modules,
+		DeviceID2StackMembers:           &ds.DeviceID2StackMembers,
+		Site2Parent:                     &ds.Site2Parent,
+		Site2Devices:                    &ds.Site2Devices,
+		Device2Site:                     &ds.Device2Site,
+		DeviceID2InterfaceIDs:           &ds.DeviceID2InterfaceIDs,
+	}
+}
+
+func (ds *DnacSource) MarshalSnapshot() ([]byte, error) {
+	return json.Marshal(ds.snapshot())
+}
+
+func (ds *DnacSource) UnmarshalSnapshot(data []byte) error {
+	return json.Unmarshal(data, ds.snapshot())
+}
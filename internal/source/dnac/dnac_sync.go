@@ -2,9 +2,10 @@ package dnac
 
 import (
 	"fmt"
+	"maps"
+	"slices"
 	"strconv"
 	"strings"
-	"sync"
 
 	dnac "github.com/cisco-en-programmability/dnacenter-go-sdk/v7/sdk"
 	"github.com/src-doo/netbox-ssot/internal/constants"
@@ -14,38 +15,195 @@ import (
 	"github.com/src-doo/netbox-ssot/internal/utils"
 )
 
+// Types of dnac sites, stored in the Location namespace of their additional info.
+const (
+	dnacSiteTypeArea     = "area"
+	dnacSiteTypeBuilding = "building"
+	dnacSiteTypeFloor    = "floor"
+)
+
+// dnacSiteType returns the type of the dnac site. Sites without type are areas.
+func dnacSiteType(site dnac.ResponseSitesGetSiteResponse) string {
+	for _, additionalInfo := range site.AdditionalInfo {
+		if additionalInfo.Namespace == "Location" && additionalInfo.Attributes.Type != "" {
+			return additionalInfo.Attributes.Type
+		}
+	}
+	return dnacSiteTypeArea
+}
+
 // Syncs dnac sites to netbox inventory.
+// Depending on siteHierarchy, either every dnac site is synced as a site,
+// or areas are synced as regions, buildings as sites and floors as locations.
 func (ds *DnacSource) syncSites(nbi *inventory.NetboxInventory) error {
-	for _, site := range ds.Sites {
-		dnacSite := &objects.Site{
-			NetboxObject: objects.NetboxObject{
-				Tags: ds.Config.GetSourceTags(),
-				CustomFields: map[string]interface{}{
-					constants.CustomFieldSourceName: ds.SourceConfig.Name,
-				},
+	hierarchy := ds.SourceConfig.SiteHierarchy
+	if hierarchy != constants.SiteHierarchyBuilding && hierarchy != constants.SiteHierarchyFloor {
+		for _, site := range ds.Sites {
+			nbSite, err := nbi.AddSite(ds.Ctx, ds.nbSite(site, nil))
+			if err != nil {
+				return fmt.Errorf("adding site: %s", err)
+			}
+			ds.SiteID2nbSite.Store(site.ID, nbSite)
+		}
+		return nil
+	}
+	// Sites are synced in order of their ids, and parents are synced before their children
+	for _, siteID := range slices.Sorted(maps.Keys(ds.Sites)) {
+		var err error
+		switch dnacSiteType(ds.Sites[siteID]) {
+		case dnacSiteTypeBuilding:
+			_, err = ds.syncBuilding(nbi, siteID)
+		case dnacSiteTypeFloor:
+			err = ds.syncFloor(nbi, siteID)
+		default:
+			_, err = ds.syncArea(nbi, siteID)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// nbSite returns netbox site of the dnac site in the region.
+func (ds *DnacSource) nbSite(site dnac.ResponseSitesGetSiteResponse, region *objects.Region) *objects.Site {
+	dnacSite := &objects.Site{
+		NetboxObject: objects.NetboxObject{
+			Tags: ds.Config.GetSourceTags(),
+			CustomFields: map[string]interface{}{
+				constants.CustomFieldSourceName: ds.SourceConfig.Name,
 			},
-			Name: site.Name,
-			Slug: utils.Slugify(site.Name),
-		}
-		for _, additionalInfo := range site.AdditionalInfo {
-			if additionalInfo.Namespace == "Location" {
-				dnacSite.PhysicalAddress = additionalInfo.Attributes.Address
-				longitude, err := strconv.ParseFloat(additionalInfo.Attributes.Longitude, 64)
-				if err != nil {
-					dnacSite.Longitude = longitude
-				}
-				latitude, err := strconv.ParseFloat(additionalInfo.Attributes.Latitude, 64)
-				if err != nil {
-					dnacSite.Latitude = latitude
-				}
+		},
+		Name:   site.Name,
+		Slug:   utils.Slugify(site.Name),
+		Region: region,
+	}
+	for _, additionalInfo := range site.AdditionalInfo {
+		if additionalInfo.Namespace == "Location" {
+			dnacSite.PhysicalAddress = additionalInfo.Attributes.Address
+			longitude, err := strconv.ParseFloat(additionalInfo.Attributes.Longitude, 64)
+			if err == nil {
+				dnacSite.Longitude = longitude
+			}
+			latitude, err := strconv.ParseFloat(additionalInfo.Attributes.Latitude, 64)
+			if err == nil {
+				dnacSite.Latitude = latitude
 			}
 		}
-		nbSite, err := nbi.AddSite(ds.Ctx, dnacSite)
+	}
+	return dnacSite
+}
+
+// ancestorOfType returns id of the nearest ancestor of the dnac site with the site type.
+func (ds *DnacSource) ancestorOfType(siteID string, siteType string) (string, bool) {
+	visited := map[string]bool{siteID: true}
+	for parentID := ds.Site2Parent[siteID]; !visited[parentID]; parentID = ds.Site2Parent[parentID] {
+		parent, ok := ds.Sites[parentID]
+		if !ok {
+			return "", false
+		}
+		if dnacSiteType(parent) == siteType {
+			return parentID, true
+		}
+		visited[parentID] = true
+	}
+	return "", false
+}
+
+// syncArea syncs the dnac area as a region, nested in the region of its parent area.
+// The root area (Global) has no parent and isn't synced, so it returns nil region.
+func (ds *DnacSource) syncArea(nbi *inventory.NetboxInventory, siteID string) (*objects.Region, error) {
+	if region, ok := ds.SiteID2nbRegion.Load(siteID); ok {
+		if nbRegion, ok := region.(*objects.Region); ok {
+			return nbRegion, nil
+		}
+	}
+	if _, ok := ds.Sites[ds.Site2Parent[siteID]]; !ok {
+		return nil, nil
+	}
+	var parentRegion *objects.Region
+	if parentID, ok := ds.ancestorOfType(siteID, dnacSiteTypeArea); ok {
+		var err error
+		parentRegion, err = ds.syncArea(nbi, parentID)
 		if err != nil {
-			return fmt.Errorf("adding site: %s", err)
+			return nil, err
 		}
-		ds.SiteID2nbSite.Store(site.ID, nbSite)
 	}
+	area := ds.Sites[siteID]
+	nbRegion, err := nbi.AddRegion(ds.Ctx, &objects.Region{
+		NetboxObject: objects.NetboxObject{
+			Tags: ds.Config.GetSourceTags(),
+			CustomFields: map[string]interface{}{
+				constants.CustomFieldSourceName: ds.SourceConfig.Name,
+			},
+		},
+		Name:   area.Name,
+		Slug:   utils.Slugify(area.Name),
+		Parent: parentRegion,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("adding region: %s", err)
+	}
+	ds.SiteID2nbRegion.Store(siteID, nbRegion)
+	return nbRegion, nil
+}
+
+// syncBuilding syncs the dnac building as a site in the region of its area.
+func (ds *DnacSource) syncBuilding(nbi *inventory.NetboxInventory, siteID string) (*objects.Site, error) {
+	if site, ok := ds.SiteID2nbSite.Load(siteID); ok {
+		if nbSite, ok := site.(*objects.Site); ok {
+			return nbSite, nil
+		}
+	}
+	var region *objects.Region
+	if areaID, ok := ds.ancestorOfType(siteID, dnacSiteTypeArea); ok {
+		var err error
+		region, err = ds.syncArea(nbi, areaID)
+		if err != nil {
+			return nil, err
+		}
+	}
+	nbSite, err := nbi.AddSite(ds.Ctx, ds.nbSite(ds.Sites[siteID], region))
+	if err != nil {
+		return nil, fmt.Errorf("adding site: %s", err)
+	}
+	ds.SiteID2nbSite.Store(siteID, nbSite)
+	return nbSite, nil
+}
+
+// syncFloor maps the dnac floor to the site of its building,
+// and with floor site hierarchy syncs it as a location of that site.
+func (ds *DnacSource) syncFloor(nbi *inventory.NetboxInventory, siteID string) error {
+	floor := ds.Sites[siteID]
+	buildingID, ok := ds.ancestorOfType(siteID, dnacSiteTypeBuilding)
+	if !ok {
+		ds.Logger.Warningf(ds.Ctx, "Floor %s is not in any building. It will be skipped", floor.SiteNameHierarchy)
+		return nil
+	}
+	nbSite, err := ds.syncBuilding(nbi, buildingID)
+	if err != nil {
+		return err
+	}
+	ds.SiteID2nbSite.Store(siteID, nbSite)
+	if ds.SourceConfig.SiteHierarchy != constants.SiteHierarchyFloor {
+		return nil
+	}
+	nbLocation, err := nbi.AddLocation(ds.Ctx, &objects.Location{
+		NetboxObject: objects.NetboxObject{
+			Tags: ds.Config.GetSourceTags(),
+			CustomFields: map[string]interface{}{
+				constants.CustomFieldSourceName: ds.SourceConfig.Name,
+			},
+		},
+		Site:   nbSite,
+		Name:   floor.Name,
+		Slug:   utils.Slugify(floor.Name),
+		Status: &objects.SiteStatusActive,
+	})
+	if err != nil {
+		return fmt.Errorf("adding location: %s", err)
+	}
+	ds.SiteID2nbLocation.Store(siteID, nbLocation)
 	return nil
 }
 
@@ -77,6 +235,7 @@ func (ds *DnacSource) syncVlans(nbi *inventory.NetboxInventory) error {
 			nbi,
 			vlan.InterfaceName,
 			ds.SourceConfig.VlanTenantRelations,
+			ds.SourceConfig.TenantGroupRelations,
 		)
 		if err != nil {
 			return fmt.Errorf("vlanTenant: %s", err)
@@ -123,37 +282,9 @@ func (ds *DnacSource) syncVlans(nbi *inventory.NetboxInventory) error {
 	return nil
 }
 func (ds *DnacSource) syncDevices(nbi *inventory.NetboxInventory) error {
-	const maxGoroutines = 50
-	guard := make(chan struct{}, maxGoroutines)
-	errChan := make(chan error, len(ds.Devices))
-	var wg sync.WaitGroup
-
-	for deviceID, device := range ds.Devices {
-		guard <- struct{}{} // Block if maxGoroutines are running
-		wg.Add(1)
-
-		go func(deviceID string, device dnac.ResponseDevicesGetDeviceListResponse) {
-			defer wg.Done()
-			defer func() { <-guard }() // Release one spot in the semaphore
-
-			err := ds.syncDevice(nbi, deviceID, device)
-			if err != nil {
-				errChan <- err
-			}
-		}(deviceID, device)
-	}
-
-	wg.Wait()
-	close(errChan)
-	close(guard)
-
-	for err := range errChan {
-		if err != nil {
-			return err
-		}
-	}
-
-	return nil
+	return common.SyncConcurrently(&ds.Config, slices.Sorted(maps.Keys(ds.Devices)), func(deviceID string) error {
+		return ds.syncDevice(nbi, deviceID, ds.Devices[deviceID])
+	})
 }
 
 func (ds *DnacSource) syncDevice(
@@ -229,6 +360,15 @@ func (ds *DnacSource) syncDevice(
 			)
 			return nil
 		}
+	} else if _, ok := ds.SiteID2nbRegion.Load(ds.Device2Site[device.ID]); ok {
+		// With site hierarchy, devices can be assigned directly to areas, which are not sites
+		ds.Logger.Warningf(
+			ds.Ctx,
+			"Device %s is assigned to area %s, which is not a building. Using default site",
+			device.Hostname,
+			ds.Sites[ds.Device2Site[device.ID]].SiteNameHierarchy,
+		)
+		deviceSite, _ = nbi.GetSite(constants.DefaultSite)
 	} else {
 		ds.Logger.Errorf(
 			ds.Ctx,
@@ -237,6 +377,10 @@ func (ds *DnacSource) syncDevice(
 		)
 		return nil
 	}
+	var deviceLocation *objects.Location
+	if location, ok := ds.SiteID2nbLocation.Load(ds.Device2Site[device.ID]); ok {
+		deviceLocation, _ = location.(*objects.Location)
+	}
 
 	if device.Type == "" {
 		ds.Logger.Errorf(
@@ -261,6 +405,7 @@ func (ds *DnacSource) syncDevice(
 		nbi,
 		device.Hostname,
 		ds.SourceConfig.HostTenantRelations,
+		ds.SourceConfig.TenantGroupRelations,
 	)
 	if err != nil {
 		return fmt.Errorf("hostTenant: %s", err)
@@ -276,7 +421,7 @@ func (ds *DnacSource) syncDevice(
 		deviceSerialNumber = device.SerialNumber
 	}
 
-	nbDevice, err := nbi.AddDevice(ds.Ctx, &objects.Device{
+	newDevice := &objects.Device{
 		NetboxObject: objects.NetboxObject{
 			Tags:        ds.GetSourceTags(),
 			Description: description,
@@ -294,51 +439,198 @@ func (ds *DnacSource) syncDevice(
 		Platform:     platform,
 		Comments:     comments,
 		Site:         deviceSite,
+		Location:     deviceLocation,
 		DeviceType:   deviceType,
-	})
+	}
+
+	// Device of a stack represents its first member, other members
+	// are added as separate devices of the same virtual chassis
+	stackMembers := ds.getStackMembers(deviceID)
+	if len(stackMembers) > 0 {
+		if !ds.SourceConfig.IgnoreSerialNumbers {
+			newDevice.SerialNumber = stackMembers[0].SerialNumber
+		}
+		err = common.AddVirtualChassisMember(
+			ds.Ctx,
+			nbi,
+			newDevice,
+			stackVirtualChassisMember(device.Hostname, stackMembers, 0),
+			ds.GetSourceTags(),
+		)
+		if err != nil {
+			return fmt.Errorf("add stack member %s: %s", device.Hostname, err)
+		}
+	}
 
+	nbDevice, err := nbi.AddDevice(ds.Ctx, newDevice)
 	if err != nil {
 		return fmt.Errorf("adding dnac device: %s", err)
 	}
 
 	ds.DeviceID2nbDevice.Store(device.ID, nbDevice)
+
+	if len(stackMembers) > 0 {
+		err = ds.syncStackMembers(nbi, nbDevice, stackMembers)
+		if err != nil {
+			return fmt.Errorf("sync stack members of %s: %s", device.Hostname, err)
+		}
+	}
 	return nil
 }
 
-func (ds *DnacSource) syncDeviceInterfaces(nbi *inventory.NetboxInventory) error {
-	const maxGoroutines = 50
-	guard := make(chan struct{}, maxGoroutines)
-	errChan := make(chan error, len(ds.Interfaces))
-	var wg sync.WaitGroup
-
-	for ifaceID, iface := range ds.Interfaces {
-		guard <- struct{}{}
-		wg.Add(1)
+// getStackMembers returns members of the switch stack of the device sorted
+// by their member number. Nil is returned for devices, which are not stacked.
+func (ds *DnacSource) getStackMembers(
+	deviceID string,
+) []dnac.ResponseDevicesGetStackDetailsForDeviceResponseStackSwitchInfo {
+	stackMembers := make([]dnac.ResponseDevicesGetStackDetailsForDeviceResponseStackSwitchInfo, 0)
+	for _, member := range ds.DeviceID2StackMembers[deviceID] {
+		if member.StackMemberNumber != nil {
+			stackMembers = append(stackMembers, member)
+		}
+	}
+	if len(stackMembers) < 2 { //nolint:mnd
+		return nil
+	}
+	slices.SortFunc(stackMembers, func(a, b dnac.ResponseDevicesGetStackDetailsForDeviceResponseStackSwitchInfo) int {
+		return *a.StackMemberNumber - *b.StackMemberNumber
+	})
+	return stackMembers
+}
 
-		go func(ifaceID string, iface dnac.ResponseDevicesGetAllInterfacesResponse) {
-			defer wg.Done()
-			defer func() { <-guard }()
+// stackVirtualChassisMember returns membership of the i-th stack member
+// in the virtual chassis named after the stack hostname. Master is the active
+// switch of the stack, or the first member if no switch is reported as active.
+func stackVirtualChassisMember(
+	hostname string,
+	stackMembers []dnac.ResponseDevicesGetStackDetailsForDeviceResponseStackSwitchInfo,
+	i int,
+) common.VirtualChassisMember {
+	masterIndex := slices.IndexFunc(
+		stackMembers,
+		func(member dnac.ResponseDevicesGetStackDetailsForDeviceResponseStackSwitchInfo) bool {
+			return strings.EqualFold(member.Role, "active")
+		},
+	)
+	if masterIndex < 0 {
+		masterIndex = 0
+	}
+	var priority int
+	if stackMembers[i].SwitchPriority != nil {
+		priority = *stackMembers[i].SwitchPriority
+	}
+	return common.VirtualChassisMember{
+		VirtualChassisName: hostname,
+		Position:           *stackMembers[i].StackMemberNumber,
+		Priority:           priority,
+		Master:             i == masterIndex,
+	}
+}
 
-			err := ds.syncDeviceInterface(nbi, ifaceID, iface)
+// syncStackMembers adds members of the stack, except the first one, which is
+// nbDevice itself, as devices of the stack's virtual chassis and sets its master.
+func (ds *DnacSource) syncStackMembers(
+	nbi *inventory.NetboxInventory,
+	nbDevice *objects.Device,
+	stackMembers []dnac.ResponseDevicesGetStackDetailsForDeviceResponseStackSwitchInfo,
+) error {
+	for i, member := range stackMembers {
+		vcMember := stackVirtualChassisMember(nbDevice.Name, stackMembers, i)
+		memberDevice := nbDevice
+		if i > 0 {
+			var serialNumber string
+			if !ds.SourceConfig.IgnoreSerialNumbers {
+				serialNumber = member.SerialNumber
+			}
+			newMemberDevice := &objects.Device{
+				NetboxObject: objects.NetboxObject{
+					Tags: ds.GetSourceTags(),
+				},
+				Name:         fmt.Sprintf("%s-%d", nbDevice.Name, *member.StackMemberNumber),
+				Status:       nbDevice.Status,
+				Tenant:       nbDevice.Tenant,
+				DeviceRole:   nbDevice.DeviceRole,
+				SerialNumber: serialNumber,
+				Platform:     nbDevice.Platform,
+				Site:         nbDevice.Site,
+				Location:     nbDevice.Location,
+				DeviceType:   nbDevice.DeviceType,
+			}
+			err := common.AddVirtualChassisMember(ds.Ctx, nbi, newMemberDevice, vcMember, ds.GetSourceTags())
 			if err != nil {
-				errChan <- err
+				return err
 			}
-		}(ifaceID, iface)
+			memberDevice, err = nbi.AddDevice(ds.Ctx, newMemberDevice)
+			if err != nil {
+				return fmt.Errorf("add stack member %s: %s", newMemberDevice.Name, err)
+			}
+		}
+		err := common.SetVirtualChassisMaster(ds.Ctx, nbi, memberDevice, vcMember, ds.GetSourceTags())
+		if err != nil {
+			return err
+		}
 	}
+	return nil
+}
 
-	wg.Wait()
-	close(errChan)
-	close(guard)
+// syncInventoryItems syncs modules of devices with their part and serial numbers.
+// Modules, that were removed from the device, are handled by the orphan manager.
+func (ds *DnacSource) syncInventoryItems(nbi *inventory.NetboxInventory) error {
+	return common.SyncConcurrently(
+		&ds.Config,
+		slices.Sorted(maps.Keys(ds.DeviceID2Modules)),
+		func(deviceID string) error {
+			return ds.syncDeviceInventoryItems(nbi, deviceID)
+		},
+	)
+}
 
-	for err := range errChan {
+func (ds *DnacSource) syncDeviceInventoryItems(nbi *inventory.NetboxInventory, deviceID string) error {
+	nbDevice, err := ds.getDevice(deviceID)
+	if err != nil {
+		ds.Logger.Debugf(ds.Ctx, "skipping modules of device %s, that is not synced", deviceID)
+		return nil
+	}
+	manufacturer, _ := nbi.GetManufacturer("Cisco")
+	// Serial numbers of chassis, which are already synced as the device
+	// itself. Stacks have one chassis per member.
+	chassisSerials := strings.Split(ds.Devices[deviceID].SerialNumber, ",")
+	for i := range chassisSerials {
+		chassisSerials[i] = strings.TrimSpace(chassisSerials[i])
+	}
+	for _, module := range ds.DeviceID2Modules[deviceID] {
+		if module.Name == "" || module.SerialNumber != "" && slices.Contains(chassisSerials, module.SerialNumber) {
+			continue
+		}
+		var serialNumber string
+		if !ds.SourceConfig.IgnoreSerialNumbers {
+			serialNumber = module.SerialNumber
+		}
+		_, err := nbi.AddInventoryItem(ds.Ctx, &objects.InventoryItem{
+			NetboxObject: objects.NetboxObject{
+				Tags:        ds.GetSourceTags(),
+				Description: module.Description,
+			},
+			Device:       nbDevice,
+			Name:         module.Name,
+			Manufacturer: manufacturer,
+			PartID:       module.PartNumber,
+			SerialNumber: serialNumber,
+			Discovered:   true,
+		})
 		if err != nil {
-			return err
+			return fmt.Errorf("add inventory item %s: %s", module.Name, err)
 		}
 	}
-
 	return nil
 }
 
+func (ds *DnacSource) syncDeviceInterfaces(nbi *inventory.NetboxInventory) error {
+	return common.SyncConcurrently(&ds.Config, slices.Sorted(maps.Keys(ds.Interfaces)), func(ifaceID string) error {
+		return ds.syncDeviceInterface(nbi, ifaceID, ds.Interfaces[ifaceID])
+	})
+}
+
 func (ds *DnacSource) syncDeviceInterface(
 	nbi *inventory.NetboxInventory,
 	ifaceID string,
@@ -432,6 +724,44 @@ func (ds *DnacSource) syncDeviceInterface(
 	return nil
 }
 
+// syncCables connects interfaces at both ends of physical links. Links to hosts
+// and to interfaces, which were not synced, are skipped.
+func (ds *DnacSource) syncCables(nbi *inventory.NetboxInventory) error {
+	for _, link := range ds.PhysicalLinks {
+		startIface, startOk := ds.getInterface(link.StartPortID)
+		endIface, endOk := ds.getInterface(link.EndPortID)
+		if !startOk || !endOk {
+			ds.Logger.Debugf(ds.Ctx, "skipping physical link %s between interfaces, that are not synced", link.ID)
+			continue
+		}
+		_, err := nbi.AddCable(ds.Ctx, &objects.Cable{
+			NetboxObject: objects.NetboxObject{
+				Tags: ds.GetSourceTags(),
+			},
+			ATerminations: []*objects.CableTermination{
+				{ObjectType: constants.ContentTypeDcimInterface, ObjectID: startIface.ID},
+			},
+			BTerminations: []*objects.CableTermination{
+				{ObjectType: constants.ContentTypeDcimInterface, ObjectID: endIface.ID},
+			},
+			Status: &objects.CableStatusConnected,
+		})
+		if err != nil {
+			return fmt.Errorf("add cable for physical link %s: %s", link.ID, err)
+		}
+	}
+	return nil
+}
+
+// getInterface returns the synced netbox interface of the dnac interface.
+func (ds *DnacSource) getInterface(ifaceID string) (*objects.Interface, bool) {
+	if nbIface, ok := ds.InterfaceID2nbInterface.Load(ifaceID); ok {
+		iface, ok := nbIface.(*objects.Interface)
+		return iface, ok
+	}
+	return nil, false
+}
+
 func (ds *DnacSource) getDevice(deviceID string) (*objects.Device, error) {
 	if device, ok := ds.DeviceID2nbDevice.Load(deviceID); ok {
 		if ifaceDevice, ok := device.(*objects.Device); ok {
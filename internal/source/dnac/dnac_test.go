@@ -1 +1,327 @@
 package dnac
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	dnac "github.com/cisco-en-programmability/dnacenter-go-sdk/v7/sdk"
+	"github.com/src-doo/netbox-ssot/internal/constants"
+	"github.com/src-doo/netbox-ssot/internal/logger"
+	"github.com/src-doo/netbox-ssot/internal/netbox/inventory"
+	"github.com/src-doo/netbox-ssot/internal/netbox/netboxtest"
+	"github.com/src-doo/netbox-ssot/internal/netbox/objects"
+	"github.com/src-doo/netbox-ssot/internal/parser"
+	"github.com/src-doo/netbox-ssot/internal/source/common"
+)
+
+// testSite returns dnac site of the site type.
+func testSite(id string, parentID string, name string, siteType string) dnac.ResponseSitesGetSiteResponse {
+	site := dnac.ResponseSitesGetSiteResponse{ID: id, ParentID: parentID, Name: name}
+	if siteType != "" {
+		site.AdditionalInfo = []dnac.ResponseSitesGetSiteResponseAdditionalInfo{
+			{
+				Namespace:  "Location",
+				Attributes: dnac.ResponseSitesGetSiteResponseAdditionalInfoAttributes{Type: siteType},
+			},
+		}
+	}
+	return site
+}
+
+// testDnacSource returns dnac source with sites Global/Europe/Slovenia/Ljubljana HQ/Floor 1,
+// synced to inventory initialized from the netbox emulator.
+func testDnacSource(t *testing.T, siteHierarchy string) (*DnacSource, *inventory.NetboxInventory) {
+	t.Helper()
+	s := netboxtest.NewServer()
+	t.Cleanup(s.Close)
+	netboxURL, err := url.Parse(s.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	config := fmt.Sprintf(`
+netbox:
+  apiToken: "netbox-token"
+  hostname: %s
+  port: %s
+  httpScheme: http
+source:
+  - name: dnac
+    type: dnac
+    hostname: dnac.example.com
+    username: admin
+    password: adminpass
+    siteHierarchy: %s
+`, netboxURL.Hostname(), netboxURL.Port(), siteHierarchy)
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	err = os.WriteFile(configPath, []byte(config), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsedConfig, err := parser.ParseConfig(configPath)
+	if err != nil {
+		t.Fatalf("ParseConfig() error = %v", err)
+	}
+	testLogger := &logger.Logger{Logger: log.New(os.Stdout, "", log.LstdFlags)}
+	ctx := context.WithValue(context.Background(), constants.CtxSourceKey, "dnac")
+	nbi := inventory.NewNetboxInventory(ctx, testLogger, parsedConfig.Netbox)
+	err = nbi.Init()
+	if err != nil {
+		t.Fatalf("Init() error = %v", err)
+	}
+
+	ds := &DnacSource{
+		Config: common.Config{
+			Logger:        testLogger,
+			SourceConfig:  &parsedConfig.Sources[0],
+			SourceNameTag: nbi.SsotTag,
+			SourceTypeTag: nbi.SsotTag,
+			Ctx:           ctx,
+		},
+		Sites: map[string]dnac.ResponseSitesGetSiteResponse{
+			"global":    testSite("global", "", "Global", ""),
+			"europe":    testSite("europe", "global", "Europe", dnacSiteTypeArea),
+			"slovenia":  testSite("slovenia", "europe", "Slovenia", dnacSiteTypeArea),
+			"ljubljana": testSite("ljubljana", "slovenia", "Ljubljana HQ", dnacSiteTypeBuilding),
+			"floor1":    testSite("floor1", "ljubljana", "Floor 1", dnacSiteTypeFloor),
+		},
+	}
+	ds.Site2Parent = make(map[string]string, len(ds.Sites))
+	for id, site := range ds.Sites {
+		ds.Site2Parent[id] = site.ParentID
+	}
+	err = ds.syncSites(nbi)
+	if err != nil {
+		t.Fatalf("syncSites() error = %v", err)
+	}
+	return ds, nbi
+}
+
+func TestDnacSource_syncSites(t *testing.T) {
+	tests := []struct {
+		name          string
+		siteHierarchy string
+		wantSites     []string
+		wantRegions   map[string]string // Region name -> parent region name
+		wantLocations []string
+	}{
+		{
+			name:          "Flat",
+			siteHierarchy: constants.SiteHierarchyFlat,
+			wantSites:     []string{"Global", "Europe", "Slovenia", "Ljubljana HQ", "Floor 1"},
+		},
+		{
+			name:          "Building",
+			siteHierarchy: constants.SiteHierarchyBuilding,
+			wantSites:     []string{"Ljubljana HQ"},
+			wantRegions:   map[string]string{"Europe": "", "Slovenia": "Europe"},
+		},
+		{
+			name:          "Floor",
+			siteHierarchy: constants.SiteHierarchyFloor,
+			wantSites:     []string{"Ljubljana HQ"},
+			wantRegions:   map[string]string{"Europe": "", "Slovenia": "Europe"},
+			wantLocations: []string{"Floor 1"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ds, nbi := testDnacSource(t, tt.siteHierarchy)
+			exported := nbi.Export()
+
+			sites := map[string]*objects.Site{}
+			for _, item := range exported[constants.SitesAPIPath] {
+				site, _ := item.(*objects.Site)
+				sites[site.Name] = site
+			}
+			// Default site is created during Init
+			if len(sites) != len(tt.wantSites)+1 {
+				t.Errorf("syncSites() synced %d sites, want %d", len(sites)-1, len(tt.wantSites))
+			}
+			for _, name := range tt.wantSites {
+				if _, ok := sites[name]; !ok {
+					t.Errorf("syncSites() didn't sync site %s", name)
+				}
+			}
+
+			regions := exported[constants.RegionsAPIPath]
+			if len(regions) != len(tt.wantRegions) {
+				t.Errorf("syncSites() synced %d regions, want %d", len(regions), len(tt.wantRegions))
+			}
+			for _, item := range regions {
+				region, _ := item.(*objects.Region)
+				wantParent, ok := tt.wantRegions[region.Name]
+				if !ok {
+					t.Errorf("syncSites() synced unexpected region %s", region.Name)
+					continue
+				}
+				gotParent := ""
+				if region.Parent != nil {
+					gotParent = region.Parent.Name
+				}
+				if gotParent != wantParent {
+					t.Errorf("region %s has parent %q, want %q", region.Name, gotParent, wantParent)
+				}
+			}
+			if len(tt.wantRegions) > 0 {
+				hq := sites["Ljubljana HQ"]
+				if hq.Region == nil || hq.Region.Name != "Slovenia" {
+					t.Errorf("site %s has region %v, want Slovenia", hq.Name, hq.Region)
+				}
+				floorSite, _ := ds.SiteID2nbSite.Load("floor1")
+				if floorSite != hq {
+					t.Errorf("floor is mapped to site %v, want %s", floorSite, hq.Name)
+				}
+			}
+
+			locations := exported[constants.LocationsAPIPath]
+			if len(locations) != len(tt.wantLocations) {
+				t.Errorf("syncSites() synced %d locations, want %d", len(locations), len(tt.wantLocations))
+			}
+			for i, item := range locations {
+				location, _ := item.(*objects.Location)
+				if location.Name != tt.wantLocations[i] || location.Site == nil || location.Site.Name != "Ljubljana HQ" {
+					t.Errorf("syncSites() synced location %s", location)
+				}
+				if _, ok := ds.SiteID2nbLocation.Load("floor1"); !ok {
+					t.Errorf("floor is not mapped to location %s", location.Name)
+				}
+			}
+		})
+	}
+}
+
+func TestDnacSource_syncCables(t *testing.T) {
+	ds, nbi := testDnacSource(t, constants.SiteHierarchyFlat)
+	ds.InterfaceID2nbInterface.Store("switch1-gi1", &objects.Interface{NetboxObject: objects.NetboxObject{ID: 11}})
+	ds.InterfaceID2nbInterface.Store("switch2-gi1", &objects.Interface{NetboxObject: objects.NetboxObject{ID: 21}})
+	ds.PhysicalLinks = []dnac.ResponseTopologyGetPhysicalTopologyResponseLinks{
+		{ID: "link1", StartPortID: "switch1-gi1", EndPortID: "switch2-gi1"},
+		// Link to a host, which is not synced
+		{ID: "link2", StartPortID: "switch1-gi2", EndPortID: ""},
+	}
+	err := ds.syncCables(nbi)
+	if err != nil {
+		t.Fatalf("syncCables() error = %v", err)
+	}
+	cables := nbi.Export()[constants.CablesAPIPath]
+	if len(cables) != 1 {
+		t.Fatalf("syncCables() synced %d cables, want 1", len(cables))
+	}
+	cable, _ := cables[0].(*objects.Cable)
+	aInterfaceIDs, bInterfaceIDs := cable.InterfaceIDs()
+	if !reflect.DeepEqual(aInterfaceIDs, []int{11}) || !reflect.DeepEqual(bInterfaceIDs, []int{21}) {
+		t.Errorf("syncCables() synced %s, want cable between interfaces 11 and 21", cable)
+	}
+}
+
+func TestDnacSource_syncInventoryItems(t *testing.T) {
+	ds, nbi := testDnacSource(t, constants.SiteHierarchyFlat)
+	site, _ := nbi.GetSite("Ljubljana HQ")
+	nbDevice, err := nbi.AddDevice(ds.Ctx, &objects.Device{Name: "switch1", Site: site})
+	if err != nil {
+		t.Fatalf("AddDevice() error = %v", err)
+	}
+	ds.DeviceID2nbDevice.Store("switch1", nbDevice)
+	ds.Devices = map[string]dnac.ResponseDevicesGetDeviceListResponse{
+		"switch1": {ID: "switch1", SerialNumber: "FOC0001, FOC0002"},
+		"switch2": {ID: "switch2"},
+	}
+	ds.DeviceID2Modules = map[string][]dnac.ResponseDevicesGetModulesResponse{
+		"switch1": {
+			// Chassis of stack members are synced as the device itself
+			{Name: "Switch 1", SerialNumber: "FOC0001"},
+			{Name: "Switch 2", SerialNumber: "FOC0002"},
+			{Name: "Switch 1 - Power Supply A", PartNumber: "PWR-C1-350WAC", SerialNumber: "ART0001"},
+			{Name: "Switch 1 - Fan 1"},
+		},
+		// Device, which is not synced
+		"switch2": {{Name: "Switch 1 - Power Supply A", SerialNumber: "ART0002"}},
+	}
+	err = ds.syncInventoryItems(nbi)
+	if err != nil {
+		t.Fatalf("syncInventoryItems() error = %v", err)
+	}
+	inventoryItems := map[string]string{}
+	for _, item := range nbi.Export()[constants.InventoryItemsAPIPath] {
+		inventoryItem, _ := item.(*objects.InventoryItem)
+		inventoryItems[inventoryItem.Name] = inventoryItem.SerialNumber
+	}
+	want := map[string]string{"Switch 1 - Power Supply A": "ART0001", "Switch 1 - Fan 1": ""}
+	if !reflect.DeepEqual(inventoryItems, want) {
+		t.Errorf("syncInventoryItems() synced %v, want %v", inventoryItems, want)
+	}
+}
+
+func TestDnacSource_syncStackMembers(t *testing.T) {
+	ds, nbi := testDnacSource(t, constants.SiteHierarchyFlat)
+	site, _ := nbi.GetSite("Ljubljana HQ")
+	intPtr := func(i int) *int { return &i }
+	ds.DeviceID2StackMembers = map[string][]dnac.ResponseDevicesGetStackDetailsForDeviceResponseStackSwitchInfo{
+		"switch1": {
+			{StackMemberNumber: intPtr(2), Role: "ACTIVE", SerialNumber: "FOC0002", SwitchPriority: intPtr(15)},
+			{StackMemberNumber: intPtr(1), Role: "STANDBY", SerialNumber: "FOC0001", SwitchPriority: intPtr(14)},
+			{StackMemberNumber: intPtr(3), Role: "MEMBER", SerialNumber: "FOC0003", SwitchPriority: intPtr(1)},
+		},
+		// Device, which is not stacked
+		"switch2": {{StackMemberNumber: intPtr(1), Role: "ACTIVE"}},
+	}
+	if members := ds.getStackMembers("switch2"); members != nil {
+		t.Errorf("getStackMembers() = %v, want nil for device, which is not stacked", members)
+	}
+	stackMembers := ds.getStackMembers("switch1")
+	newDevice := &objects.Device{Name: "switch1", Site: site, SerialNumber: stackMembers[0].SerialNumber}
+	err := common.AddVirtualChassisMember(
+		ds.Ctx,
+		nbi,
+		newDevice,
+		stackVirtualChassisMember("switch1", stackMembers, 0),
+		nil,
+	)
+	if err != nil {
+		t.Fatalf("AddVirtualChassisMember() error = %v", err)
+	}
+	nbDevice, err := nbi.AddDevice(ds.Ctx, newDevice)
+	if err != nil {
+		t.Fatalf("AddDevice() error = %v", err)
+	}
+	err = ds.syncStackMembers(nbi, nbDevice, stackMembers)
+	if err != nil {
+		t.Fatalf("syncStackMembers() error = %v", err)
+	}
+
+	type member struct {
+		serial   string
+		position int
+		priority int
+	}
+	members := map[string]member{}
+	for _, item := range nbi.Export()[constants.DevicesAPIPath] {
+		device, _ := item.(*objects.Device)
+		if device.VirtualChassis == nil || device.VirtualChassis.Name != "switch1" {
+			t.Errorf("device %s is not a member of virtual chassis switch1", device.Name)
+			continue
+		}
+		members[device.Name] = member{device.SerialNumber, device.VCPosition, device.VCPriority}
+	}
+	want := map[string]member{
+		"switch1":   {"FOC0001", 1, 14},
+		"switch1-2": {"FOC0002", 2, 15},
+		"switch1-3": {"FOC0003", 3, 1},
+	}
+	if !reflect.DeepEqual(members, want) {
+		t.Errorf("syncStackMembers() synced %v, want %v", members, want)
+	}
+	virtualChassis := nbi.Export()[constants.VirtualChassisAPIPath]
+	if len(virtualChassis) != 1 {
+		t.Fatalf("syncStackMembers() synced %d virtual chassis, want 1", len(virtualChassis))
+	}
+	if vc, _ := virtualChassis[0].(*objects.VirtualChassis); vc.Master == nil || vc.Master.Name != "switch1-2" {
+		t.Errorf("syncStackMembers() synced %+v, want active switch1-2 as master", vc)
+	}
+}
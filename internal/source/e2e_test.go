@@ -0,0 +1,264 @@
+package source
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/src-doo/netbox-ssot/internal/constants"
+	"github.com/src-doo/netbox-ssot/internal/logger"
+	"github.com/src-doo/netbox-ssot/internal/netbox/inventory"
+	"github.com/src-doo/netbox-ssot/internal/netbox/netboxtest"
+	"github.com/src-doo/netbox-ssot/internal/netbox/objects"
+	"github.com/src-doo/netbox-ssot/internal/netbox/service"
+	"github.com/src-doo/netbox-ssot/internal/parser"
+	"github.com/src-doo/netbox-ssot/internal/source/common"
+	"github.com/src-doo/netbox-ssot/internal/source/fortigate"
+)
+
+// mockFortigate serves system info, interfaces and dhcp servers of a fortigate firewall.
+// Interfaces and dhcp servers can be changed between runs.
+type mockFortigate struct {
+	lock        sync.Mutex
+	interfaces  []fortigate.InterfaceResponse
+	dhcpServers []fortigate.DHCPServerResponse
+}
+
+func (m *mockFortigate) setInterfaces(interfaces ...fortigate.InterfaceResponse) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.interfaces = interfaces
+}
+
+func (m *mockFortigate) setDHCPServers(dhcpServers ...fortigate.DHCPServerResponse) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.dhcpServers = dhcpServers
+}
+
+func (m *mockFortigate) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	var response interface{}
+	switch r.URL.Path {
+	case "/api/v2/cmdb/system/global/":
+		response = fortigate.APIResponse[fortigate.DeviceResponse]{
+			HTTPStatus: http.StatusOK,
+			Serial:     "FGT60F0000000001",
+			Version:    "v7.2.5",
+			Results:    fortigate.DeviceResponse{Hostname: "fw1"},
+		}
+	case "/api/v2/cmdb/system/interface/":
+		response = fortigate.APIResponse[[]fortigate.InterfaceResponse]{
+			HTTPStatus: http.StatusOK,
+			Results:    m.interfaces,
+		}
+	case "/api/v2/cmdb/system.dhcp/server/":
+		response = fortigate.APIResponse[[]fortigate.DHCPServerResponse]{
+			HTTPStatus: http.StatusOK,
+			Results:    m.dhcpServers,
+		}
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(response)
+}
+
+// writeE2EConfig writes config of netbox-ssot pointing to the emulator and
+// the mocked fortigate and returns the parsed config.
+func writeE2EConfig(t *testing.T, netboxURL string, fortigateURL string) *parser.Config {
+	t.Helper()
+	netbox, err := url.Parse(netboxURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fortigateHost, err := url.Parse(fortigateURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	config := fmt.Sprintf(`
+logger:
+  level: 3
+  dest: ""
+netbox:
+  apiToken: "netbox-token"
+  hostname: %s
+  port: %s
+  httpScheme: http
+  removeOrphans: true
+source:
+  - name: fw
+    type: fortigate
+    hostname: %s
+    port: %s
+    httpScheme: http
+    apiToken: "fortigate-token"
+    hostSiteRelations:
+      - ".* = HQ"
+`, netbox.Hostname(), netbox.Port(), fortigateHost.Hostname(), fortigateHost.Port())
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	err = os.WriteFile(configPath, []byte(config), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsedConfig, err := parser.ParseConfig(configPath)
+	if err != nil {
+		t.Fatalf("ParseConfig() error = %v", err)
+	}
+	return parsedConfig
+}
+
+// runE2E runs inventory initialization, sync of all sources and
+// removal of orphans, like the sync command. Sources are initialized
+// with initSource, or with their Init if it is nil.
+func runE2E(
+	t *testing.T,
+	config *parser.Config,
+	initSource func(common.Source, *parser.SourceConfig) error,
+) *inventory.NetboxInventory {
+	t.Helper()
+	testLogger := &logger.Logger{Logger: log.Default()}
+	ctx := context.WithValue(context.Background(), constants.CtxSourceKey, "inventory")
+	nbi := inventory.NewNetboxInventory(ctx, testLogger, config.Netbox)
+	err := nbi.Init()
+	if err != nil {
+		t.Fatalf("Init() error = %v", err)
+	}
+	for i := range config.Sources {
+		sourceCtx := context.WithValue(context.Background(), constants.CtxSourceKey, config.Sources[i].Name)
+		nbSource, err := NewSource(sourceCtx, &config.Sources[i], testLogger, nbi)
+		if err != nil {
+			t.Fatalf("NewSource() error = %v", err)
+		}
+		if initSource != nil {
+			err = initSource(nbSource, &config.Sources[i])
+		} else {
+			err = nbSource.Init()
+		}
+		if err != nil {
+			t.Fatalf("source Init() error = %v", err)
+		}
+		err = nbSource.Sync(nbi)
+		if err != nil {
+			t.Fatalf("source Sync() error = %v", err)
+		}
+	}
+	_, err = nbi.DeleteOrphans(config.Netbox.RemoveOrphans)
+	if err != nil {
+		t.Fatalf("DeleteOrphans() error = %v", err)
+	}
+	return nbi
+}
+
+func TestEndToEnd_Fortigate(t *testing.T) {
+	netbox := netboxtest.NewServer()
+	defer netbox.Close()
+	firewall := &mockFortigate{}
+	fortigateServer := httptest.NewServer(firewall)
+	defer fortigateServer.Close()
+	config := writeE2EConfig(t, netbox.URL, fortigateServer.URL)
+
+	firewall.setInterfaces(
+		fortigate.InterfaceResponse{Name: "wan1", IP: "192.0.2.1 255.255.255.0", Status: "up", MTU: 1500},
+		fortigate.InterfaceResponse{Name: "lan", IP: "10.0.0.1 255.255.255.0", Status: "up", MTU: 1500},
+	)
+	firewall.setDHCPServers(fortigate.DHCPServerResponse{
+		ID:        1,
+		Status:    "enable",
+		Interface: "lan",
+		Netmask:   "255.255.255.0",
+		IPRange:   []fortigate.DHCPIPRange{{ID: 1, StartIP: "10.0.0.100", EndIP: "10.0.0.200"}},
+	})
+	runE2E(t, config, nil)
+
+	devices, err := netboxtest.Objects[objects.Device](netbox)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(devices) != 1 {
+		t.Fatalf("got %d devices, want 1", len(devices))
+	}
+	firewallDevice := devices[0]
+	if firewallDevice.Name != "fw1" || firewallDevice.SerialNumber != "FGT60F0000000001" {
+		t.Errorf("device = %v, want fw1 with serial", firewallDevice)
+	}
+	if firewallDevice.Platform == nil || firewallDevice.Platform.Name != "FortiOS v7.2.5" {
+		t.Errorf("device platform = %v, want FortiOS v7.2.5", firewallDevice.Platform)
+	}
+	if !firewallDevice.HasTagByName(constants.SsotTagName) {
+		t.Errorf("device tags = %v, want %s tag", firewallDevice.Tags, constants.SsotTagName)
+	}
+	assertInterfaces(t, netbox, "lan", "wan1")
+	ipAddresses, err := netboxtest.Objects[objects.IPAddress](netbox)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ipAddresses) != 2 { //nolint:mnd
+		t.Errorf("got %d ip addresses, want 2", len(ipAddresses))
+	}
+	ipRanges, err := netboxtest.Objects[objects.IPRange](netbox)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ipRanges) != 1 || ipRanges[0].StartAddress != "10.0.0.100/24" || ipRanges[0].EndAddress != "10.0.0.200/24" {
+		t.Errorf("ip ranges = %v, want 10.0.0.100/24-10.0.0.200/24", ipRanges)
+	}
+
+	// Interface lan was removed from the firewall, so it is orphaned
+	// and removed from netbox, together with its ip address
+	firewall.setInterfaces(
+		fortigate.InterfaceResponse{Name: "wan1", IP: "192.0.2.1 255.255.255.0", Status: "up", MTU: 1500},
+	)
+	runE2E(t, config, nil)
+
+	assertInterfaces(t, netbox, "wan1")
+	ipAddresses, err = netboxtest.Objects[objects.IPAddress](netbox)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ipAddresses) != 1 || ipAddresses[0].Address != "192.0.2.1/24" {
+		t.Errorf("ip addresses = %v, want only 192.0.2.1/24", ipAddresses)
+	}
+	devices, err = netboxtest.Objects[objects.Device](netbox)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(devices) != 1 || devices[0].ID != firewallDevice.ID {
+		t.Errorf("devices after second run = %v, want unchanged fw1", devices)
+	}
+
+	// Nothing changed on the firewall, so nothing is written to netbox
+	nbi := runE2E(t, config, nil)
+	if writeStats := nbi.NetboxAPI.WriteStats(); writeStats != (service.WriteStats{}) {
+		t.Errorf("write stats of unchanged run = %+v, want no writes", writeStats)
+	}
+}
+
+func assertInterfaces(t *testing.T, netbox *netboxtest.Server, wantNames ...string) {
+	t.Helper()
+	interfaces, err := netboxtest.Objects[objects.Interface](netbox)
+	if err != nil {
+		t.Fatal(err)
+	}
+	names := map[string]bool{}
+	for _, iface := range interfaces {
+		names[iface.Name] = true
+	}
+	if len(names) != len(wantNames) {
+		t.Errorf("interfaces = %v, want %v", interfaces, wantNames)
+	}
+	for _, name := range wantNames {
+		if !names[name] {
+			t.Errorf("interface %s not found in %v", name, interfaces)
+		}
+	}
+}
@@ -296,6 +296,74 @@ func (fmcc *FMCClient) GetDevices(domainUUID string) ([]Device, error) {
 	return devices, nil
 }
 
+// GetDeviceHAPairs returns a list of FTD high availability pairs from the FMC API
+// for the specified domain.
+func (fmcc *FMCClient) GetDeviceHAPairs(domainUUID string) ([]DeviceHAPair, error) {
+	offset := 0
+	limit := 25
+	haPairs := []DeviceHAPair{}
+	ctx := context.Background()
+
+	for {
+		haPairsURL := fmt.Sprintf(
+			"fmc_config/v1/domain/%s/devicehapairs/ftddevicehapairs?expanded=true&offset=%d&limit=%d",
+			domainUUID,
+			offset,
+			limit,
+		)
+		var marshaledResponse APIResponse[DeviceHAPair]
+		err := fmcc.MakeRequest(ctx, http.MethodGet, haPairsURL, nil, &marshaledResponse)
+		if err != nil {
+			return nil, fmt.Errorf("make request for device ha pairs (%s): %w", haPairsURL, err)
+		}
+
+		if len(marshaledResponse.Items) > 0 {
+			haPairs = append(haPairs, marshaledResponse.Items...)
+		}
+
+		if len(marshaledResponse.Items) < limit {
+			break
+		}
+		offset += limit
+	}
+
+	return haPairs, nil
+}
+
+// GetDeviceClusters returns a list of FTD clusters from the FMC API
+// for the specified domain.
+func (fmcc *FMCClient) GetDeviceClusters(domainUUID string) ([]DeviceCluster, error) {
+	offset := 0
+	limit := 25
+	clusters := []DeviceCluster{}
+	ctx := context.Background()
+
+	for {
+		clustersURL := fmt.Sprintf(
+			"fmc_config/v1/domain/%s/deviceclusters/ftddevicecluster?expanded=true&offset=%d&limit=%d",
+			domainUUID,
+			offset,
+			limit,
+		)
+		var marshaledResponse APIResponse[DeviceCluster]
+		err := fmcc.MakeRequest(ctx, http.MethodGet, clustersURL, nil, &marshaledResponse)
+		if err != nil {
+			return nil, fmt.Errorf("make request for device clusters (%s): %w", clustersURL, err)
+		}
+
+		if len(marshaledResponse.Items) > 0 {
+			clusters = append(clusters, marshaledResponse.Items...)
+		}
+
+		if len(marshaledResponse.Items) < limit {
+			break
+		}
+		offset += limit
+	}
+
+	return clusters, nil
+}
+
 // GetDevicePhysicalInterfaces returns a list of physical interfaces for the specified device in the specified domain.
 func (fmcc *FMCClient) GetDevicePhysicalInterfaces(
 	domainUUID string,
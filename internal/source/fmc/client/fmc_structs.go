@@ -81,6 +81,35 @@ type Device struct {
 	Name string `json:"name"`
 }
 
+// DeviceHAPair represents a high availability pair of FTD devices.
+type DeviceHAPair struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	Primary   Device `json:"primary"`
+	Secondary Device `json:"secondary"`
+	Metadata  struct {
+		PrimaryStatus struct {
+			CurrentStatus string `json:"currentStatus"`
+		} `json:"primaryStatus"`
+		SecondaryStatus struct {
+			CurrentStatus string `json:"currentStatus"`
+		} `json:"secondaryStatus"`
+	} `json:"metadata"`
+}
+
+// DeviceCluster represents a cluster of FTD devices.
+type DeviceCluster struct {
+	ID            string          `json:"id"`
+	Name          string          `json:"name"`
+	ControlDevice ClusterMember   `json:"controlDevice"`
+	DataDevices   []ClusterMember `json:"dataDevices"`
+}
+
+// ClusterMember represents a member device of a FTD cluster.
+type ClusterMember struct {
+	DeviceDetails Device `json:"deviceDetails"`
+}
+
 type InterfaceIPv4 struct {
 	Static *struct {
 		Address string `json:"address"`
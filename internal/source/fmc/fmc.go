@@ -30,6 +30,10 @@ type FMCSource struct {
 	DeviceEtherChannelIfaces map[string][]*client.EtherChannelInterfaceInfo
 	// DeviceSubIfaces is a map of device IDs to a slice of SubInterfaceInfo objects.
 	DeviceSubIfaces map[string][]*client.SubInterfaceInfo
+	// DeviceHAPairs is a slice of high availability pairs of devices.
+	DeviceHAPairs []client.DeviceHAPair
+	// DeviceClusters is a slice of clusters of devices.
+	DeviceClusters []client.DeviceCluster
 
 	// Netbox devices representing firewalls.
 	NBDevices map[string]*objects.Device
@@ -39,7 +43,7 @@ type FMCSource struct {
 }
 
 func (fmcs *FMCSource) Init() error {
-	httpClient, err := utils.NewHTTPClient(fmcs.SourceConfig.ValidateCert, fmcs.CAFile)
+	httpClient, err := utils.NewHTTPClient(fmcs.SourceConfig.HTTPClientConfig())
 	if err != nil {
 		return fmt.Errorf("create new http client: %s", err)
 	}
@@ -65,7 +69,10 @@ func (fmcs *FMCSource) Init() error {
 	}
 	for _, initFunc := range initFunctions {
 		startTime := time.Now()
-		if err := initFunc(c); err != nil {
+		endStep := fmcs.StartStep("init", initFunc)
+		err := initFunc(c)
+		endStep(err)
+		if err != nil {
 			return fmt.Errorf("fmc initialization failure: %v", err)
 		}
 		duration := time.Since(startTime)
@@ -88,7 +95,9 @@ func (fmcs *FMCSource) Sync(nbi *inventory.NetboxInventory) error {
 	for _, syncFunc := range syncFunctions {
 		startTime := time.Now()
 		funcName := utils.ExtractFunctionNameWithTrimPrefix(syncFunc, "sync")
+		endStep := fmcs.StartStep("sync", syncFunc)
 		err := syncFunc(nbi)
+		endStep(err)
 		if err != nil {
 			if fmcs.SourceConfig.ContinueOnError {
 				fmcs.Logger.Errorf(
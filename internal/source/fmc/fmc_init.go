@@ -13,14 +13,36 @@ func (fmcs *FMCSource) initObjects(c *client.FMCClient) error {
 		return fmt.Errorf("init domains: %s", err)
 	}
 
+	fmcs.DeviceHAPairs = []client.DeviceHAPair{}
+	fmcs.DeviceClusters = []client.DeviceCluster{}
 	for _, domain := range domains {
 		if err := fmcs.initDevices(c, domain); err != nil {
 			return fmt.Errorf("init devices: %s", err)
 		}
+		fmcs.initDeviceGroups(c, domain)
 	}
 	return nil
 }
 
+// initDeviceGroups collects high availability pairs and clusters of devices
+// in the domain. Groups, which can't be collected, are skipped, so devices
+// are synced as standalone devices.
+func (fmcs *FMCSource) initDeviceGroups(c *client.FMCClient, domain client.Domain) {
+	fmcs.Logger.Debugf(fmcs.Ctx, "Getting device ha pairs for %s domain...", domain.Name)
+	haPairs, err := c.GetDeviceHAPairs(domain.UUID)
+	if err != nil {
+		fmcs.Logger.Warningf(fmcs.Ctx, "skipping device ha pairs of %s domain: %s", domain.Name, err)
+	}
+	fmcs.DeviceHAPairs = append(fmcs.DeviceHAPairs, haPairs...)
+
+	fmcs.Logger.Debugf(fmcs.Ctx, "Getting device clusters for %s domain...", domain.Name)
+	clusters, err := c.GetDeviceClusters(domain.UUID)
+	if err != nil {
+		fmcs.Logger.Warningf(fmcs.Ctx, "skipping device clusters of %s domain: %s", domain.Name, err)
+	}
+	fmcs.DeviceClusters = append(fmcs.DeviceClusters, clusters...)
+}
+
 func (fmcs *FMCSource) initDomains(c *client.FMCClient) ([]client.Domain, error) {
 	fmcs.Logger.Debug(fmcs.Ctx, "Getting domains from fmc...")
 	domains, err := c.GetDomains()
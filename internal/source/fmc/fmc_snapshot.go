@@ -0,0 +1,42 @@
+package fmc
+
+import (
+	"encoding/json"
+
+	"github.com/src-doo/netbox-ssot/internal/netbox/objects"
+	"github.com/src-doo/netbox-ssot/internal/source/fmc/client"
+)
+
+// snapshot references data of the source, which is initialized in init functions.
+type snapshot struct {
+	Domains                  *map[string]client.Domain
+	Devices                  *map[string]*client.DeviceInfo
+	DevicePhysicalIfaces     *map[string][]*client.PhysicalInterfaceInfo
+	DeviceVlanIfaces         *map[string][]*client.VLANInterfaceInfo
+	DeviceEtherChannelIfaces *map[string][]*client.EtherChannelInterfaceInfo
+	DeviceSubIfaces          *map[string][]*client.SubInterfaceInfo
+	DeviceHAPairs            *[]client.DeviceHAPair
+	DeviceClusters           *[]client.DeviceCluster
+}
+
+func (fmcs *FMCSource) snapshot() *snapshot {
+	return &snapshot{
+		Domains:                  &fmcs.Domains,
+		Devices:                  &fmcs.Devices,
+		DevicePhysicalIfaces:     &fmcs.DevicePhysicalIfaces,
+		DeviceVlanIfaces:         &fmcs.DeviceVlanIfaces,
+		DeviceEtherChannelIfaces: &fmcs.DeviceEtherChannelIfaces,
+		DeviceSubIfaces:          &fmcs.DeviceSubIfaces,
+		DeviceHAPairs:            &fmcs.DeviceHAPairs,
+		DeviceClusters:           &fmcs.DeviceClusters,
+	}
+}
+
+func (fmcs *FMCSource) MarshalSnapshot() ([]byte, error) {
+	return json.Marshal(fmcs.snapshot())
+}
+
+func (fmcs *FMCSource) UnmarshalSnapshot(data []byte) error {
+	fmcs.Name2NBInterface = make(map[string]*objects.Interface)
+	return json.Unmarshal(data, fmcs.snapshot())
+}
@@ -2,6 +2,7 @@ package fmc
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/src-doo/netbox-ssot/internal/constants"
 	"github.com/src-doo/netbox-ssot/internal/netbox/inventory"
@@ -12,6 +13,7 @@ import (
 )
 
 func (fmcs *FMCSource) syncDevices(nbi *inventory.NetboxInventory) error {
+	vcMembers := fmcs.virtualChassisMembers()
 	for deviceUUID, device := range fmcs.Devices {
 		deviceName := device.Name
 		if deviceName == "" {
@@ -47,6 +49,7 @@ func (fmcs *FMCSource) syncDevices(nbi *inventory.NetboxInventory) error {
 			nbi,
 			deviceName,
 			fmcs.SourceConfig.HostTenantRelations,
+			fmcs.SourceConfig.TenantGroupRelations,
 		)
 		if err != nil {
 			return fmt.Errorf("match host to tenant %s", err)
@@ -91,7 +94,7 @@ func (fmcs *FMCSource) syncDevices(nbi *inventory.NetboxInventory) error {
 		if err != nil {
 			return fmt.Errorf("add platform: %s", err)
 		}
-		NBDevice, err := nbi.AddDevice(fmcs.Ctx, &objects.Device{
+		newDevice := &objects.Device{
 			NetboxObject: objects.NetboxObject{
 				Description: device.Description,
 				Tags:        fmcs.GetSourceTags(),
@@ -113,10 +116,24 @@ func (fmcs *FMCSource) syncDevices(nbi *inventory.NetboxInventory) error {
 			Tenant:       deviceTenant,
 			Platform:     devicePlatform,
 			SerialNumber: deviceSerialNumber,
-		})
+		}
+		vcMember, isVCMember := vcMembers[deviceUUID]
+		if isVCMember {
+			err = common.AddVirtualChassisMember(fmcs.Ctx, nbi, newDevice, vcMember, fmcs.GetSourceTags())
+			if err != nil {
+				return fmt.Errorf("add virtual chassis member: %s", err)
+			}
+		}
+		NBDevice, err := nbi.AddDevice(fmcs.Ctx, newDevice)
 		if err != nil {
 			return fmt.Errorf("add device: %s", err)
 		}
+		if isVCMember {
+			err = common.SetVirtualChassisMaster(fmcs.Ctx, nbi, NBDevice, vcMember, fmcs.GetSourceTags())
+			if err != nil {
+				return fmt.Errorf("set virtual chassis master: %s", err)
+			}
+		}
 		err = fmcs.syncPhysicalInterfaces(nbi, NBDevice, deviceUUID)
 		if err != nil {
 			return fmt.Errorf("sync physical interfaces: %s", err)
@@ -139,6 +156,42 @@ func (fmcs *FMCSource) syncDevices(nbi *inventory.NetboxInventory) error {
 	return nil
 }
 
+// virtualChassisMembers returns virtual chassis membership of devices, which are
+// part of a high availability pair or a cluster, indexed by device ID.
+// Master of a HA pair is its active device, and master of a cluster
+// is its control device.
+func (fmcs *FMCSource) virtualChassisMembers() map[string]common.VirtualChassisMember {
+	vcMembers := make(map[string]common.VirtualChassisMember)
+	for _, haPair := range fmcs.DeviceHAPairs {
+		secondaryActive := strings.EqualFold(haPair.Metadata.SecondaryStatus.CurrentStatus, "active") &&
+			!strings.EqualFold(haPair.Metadata.PrimaryStatus.CurrentStatus, "active")
+		vcMembers[haPair.Primary.ID] = common.VirtualChassisMember{
+			VirtualChassisName: haPair.Name,
+			Position:           1,
+			Master:             !secondaryActive,
+		}
+		vcMembers[haPair.Secondary.ID] = common.VirtualChassisMember{
+			VirtualChassisName: haPair.Name,
+			Position:           2, //nolint:mnd
+			Master:             secondaryActive,
+		}
+	}
+	for _, cluster := range fmcs.DeviceClusters {
+		vcMembers[cluster.ControlDevice.DeviceDetails.ID] = common.VirtualChassisMember{
+			VirtualChassisName: cluster.Name,
+			Position:           1,
+			Master:             true,
+		}
+		for i, dataDevice := range cluster.DataDevices {
+			vcMembers[dataDevice.DeviceDetails.ID] = common.VirtualChassisMember{
+				VirtualChassisName: cluster.Name,
+				Position:           i + 2, //nolint:mnd
+			}
+		}
+	}
+	return vcMembers
+}
+
 // Helper function to extract IP address from the given interface.
 // If interface doesn't have an IP address, empty string is returned.
 func getIPAddressForIface(ipv4 *client.InterfaceIPv4) string {
@@ -205,6 +258,7 @@ func (fmcs *FMCSource) syncVlanInterfaces(
 					nbi,
 					vlanIface.Name,
 					fmcs.SourceConfig.VlanTenantRelations,
+					fmcs.SourceConfig.TenantGroupRelations,
 				)
 				if err != nil {
 					return fmt.Errorf("match vlan to tenant: %s", err)
@@ -452,6 +506,7 @@ func (fmcs *FMCSource) syncSubInterfaces(
 					nbi,
 					subIface.Name,
 					fmcs.SourceConfig.VlanTenantRelations,
+					fmcs.SourceConfig.TenantGroupRelations,
 				)
 				if err != nil {
 					return fmt.Errorf("match subiface vlan to tenant: %s", err)
@@ -1 +1,42 @@
 package fmc
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/src-doo/netbox-ssot/internal/source/common"
+	"github.com/src-doo/netbox-ssot/internal/source/fmc/client"
+)
+
+func TestFMCSource_virtualChassisMembers(t *testing.T) {
+	haPair := client.DeviceHAPair{
+		Name:      "ftd-ha",
+		Primary:   client.Device{ID: "ftd1"},
+		Secondary: client.Device{ID: "ftd2"},
+	}
+	haPair.Metadata.PrimaryStatus.CurrentStatus = "Standby"
+	haPair.Metadata.SecondaryStatus.CurrentStatus = "Active"
+	fmcs := &FMCSource{
+		DeviceHAPairs: []client.DeviceHAPair{haPair},
+		DeviceClusters: []client.DeviceCluster{
+			{
+				Name:          "ftd-cluster",
+				ControlDevice: client.ClusterMember{DeviceDetails: client.Device{ID: "ftd3"}},
+				DataDevices: []client.ClusterMember{
+					{DeviceDetails: client.Device{ID: "ftd4"}},
+					{DeviceDetails: client.Device{ID: "ftd5"}},
+				},
+			},
+		},
+	}
+	want := map[string]common.VirtualChassisMember{
+		"ftd1": {VirtualChassisName: "ftd-ha", Position: 1},
+		"ftd2": {VirtualChassisName: "ftd-ha", Position: 2, Master: true},
+		"ftd3": {VirtualChassisName: "ftd-cluster", Position: 1, Master: true},
+		"ftd4": {VirtualChassisName: "ftd-cluster", Position: 2},
+		"ftd5": {VirtualChassisName: "ftd-cluster", Position: 3},
+	}
+	if got := fmcs.virtualChassisMembers(); !reflect.DeepEqual(got, want) {
+		t.Errorf("virtualChassisMembers() = %v, want %v", got, want)
+	}
+}
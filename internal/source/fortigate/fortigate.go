@@ -17,8 +17,9 @@ import (
 type FortigateSource struct {
 	common.Config
 	// Fortinet data. Initialized in init functions.
-	SystemInfo FortiSystemInfo              // Map storing system information
-	Ifaces     map[string]InterfaceResponse // iface name -> FortigateInterface
+	SystemInfo  FortiSystemInfo              // Map storing system information
+	Ifaces      map[string]InterfaceResponse // iface name -> FortigateInterface
+	DHCPServers []DHCPServerResponse         // Array of dhcp servers
 
 	// NBFirewall representing fortinet firewall created in syncDevice func.
 	NBFirewall *objects.Device
@@ -59,7 +60,7 @@ func (c FortiClient) MakeRequest(
 }
 
 func (fs *FortigateSource) Init() error {
-	httpClient, err := utils.NewHTTPClient(fs.SourceConfig.ValidateCert, fs.CAFile)
+	httpClient, err := utils.NewHTTPClient(fs.SourceConfig.HTTPClientConfig())
 	if err != nil {
 		return fmt.Errorf("create new http client: %s", err)
 	}
@@ -79,10 +80,14 @@ func (fs *FortigateSource) Init() error {
 	initFunctions := []func(context.Context, *FortiClient) error{
 		fs.initSystemInfo,
 		fs.initInterfaces,
+		fs.initDHCPServers,
 	}
 	for _, initFunc := range initFunctions {
 		startTime := time.Now()
-		if err := initFunc(ctx, c); err != nil {
+		endStep := fs.StartStep("init", initFunc)
+		err := initFunc(ctx, c)
+		endStep(err)
+		if err != nil {
 			return fmt.Errorf("fortigate initialization failure: %v", err)
 		}
 		duration := time.Since(startTime)
@@ -100,13 +105,16 @@ func (fs *FortigateSource) Sync(nbi *inventory.NetboxInventory) error {
 	syncFunctions := []func(*inventory.NetboxInventory) error{
 		fs.syncDevice,
 		fs.syncInterfaces,
+		fs.syncDHCPServers,
 	}
 
 	var encounteredErrors []error
 	for _, syncFunc := range syncFunctions {
 		startTime := time.Now()
 		funcName := utils.ExtractFunctionNameWithTrimPrefix(syncFunc, "sync")
+		endStep := fs.StartStep("sync", syncFunc)
 		err := syncFunc(nbi)
+		endStep(err)
 		if err != nil {
 			if fs.SourceConfig.ContinueOnError {
 				fs.Logger.Errorf(
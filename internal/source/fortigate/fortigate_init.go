@@ -41,6 +41,20 @@ type VRRPIP struct {
 	VRIP string `json:"vrip"`
 }
 
+type DHCPServerResponse struct {
+	ID        int           `json:"id"`
+	Status    string        `json:"status"`
+	Interface string        `json:"interface"`
+	Netmask   string        `json:"netmask"`
+	IPRange   []DHCPIPRange `json:"ip-range"`
+}
+
+type DHCPIPRange struct {
+	ID      int    `json:"id"`
+	StartIP string `json:"start-ip"`
+	EndIP   string `json:"end-ip"`
+}
+
 // Init system info collects system info from paloalto.
 func (fs *FortigateSource) initSystemInfo(ctx context.Context, c *FortiClient) error {
 	res, err := c.MakeRequest(ctx, http.MethodGet, "cmdb/system/global/", nil)
@@ -102,3 +116,28 @@ func (fs *FortigateSource) initInterfaces(ctx context.Context, c *FortiClient) e
 
 	return nil
 }
+
+// Fetches all dhcp servers from fortigate api.
+func (fs *FortigateSource) initDHCPServers(ctx context.Context, c *FortiClient) error {
+	res, err := c.MakeRequest(ctx, http.MethodGet, "cmdb/system.dhcp/server/", nil)
+	if err != nil {
+		return fmt.Errorf("request error: %s", err)
+	}
+	defer res.Body.Close()
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return fmt.Errorf("body read error: %s", err)
+	}
+	var dhcpServerResponse APIResponse[[]DHCPServerResponse]
+	err = json.Unmarshal(body, &dhcpServerResponse)
+	if err != nil {
+		return fmt.Errorf("body unmarshal error: %s", err)
+	}
+
+	if dhcpServerResponse.HTTPStatus != http.StatusOK {
+		return fmt.Errorf("got http status: %d", dhcpServerResponse.HTTPStatus)
+	}
+
+	fs.DHCPServers = dhcpServerResponse.Results
+	return nil
+}
@@ -0,0 +1,26 @@
+package fortigate
+
+import "encoding/json"
+
+// snapshot references data of the source, which is initialized in init functions.
+type snapshot struct {
+	SystemInfo  *FortiSystemInfo
+	Ifaces      *map[string]InterfaceResponse
+	DHCPServers *[]DHCPServerResponse
+}
+
+func (fs *FortigateSource) snapshot() *snapshot {
+	return &snapshot{
+		SystemInfo:  &fs.SystemInfo,
+		Ifaces:      &fs.Ifaces,
+		DHCPServers: &fs.DHCPServers,
+	}
+}
+
+func (fs *FortigateSource) MarshalSnapshot() ([]byte, error) {
+	return json.Marshal(fs.snapshot())
+}
+
+func (fs *FortigateSource) UnmarshalSnapshot(data []byte) error {
+	return json.Unmarshal(data, fs.snapshot())
+}
@@ -48,6 +48,7 @@ func (fs *FortigateSource) syncDevice(nbi *inventory.NetboxInventory) error {
 		nbi,
 		deviceName,
 		fs.SourceConfig.HostTenantRelations,
+		fs.SourceConfig.TenantGroupRelations,
 	)
 	if err != nil {
 		return fmt.Errorf("match host to tenant: %s", err)
@@ -223,6 +224,7 @@ func (fs *FortigateSource) syncInterfaces(nbi *inventory.NetboxInventory) error
 				nbi,
 				vlanName,
 				fs.SourceConfig.VlanTenantRelations,
+				fs.SourceConfig.TenantGroupRelations,
 			)
 			if err != nil {
 				return fmt.Errorf("match vlan to tenant: %s", err)
@@ -373,3 +375,43 @@ func syncInterfaceIPs(
 	}
 	return NBIPAddress, nil
 }
+
+// syncDHCPServers syncs ip ranges of enabled dhcp servers as IP ranges
+// of the firewall's tenant.
+func (fs *FortigateSource) syncDHCPServers(nbi *inventory.NetboxInventory) error {
+	for _, dhcpServer := range fs.DHCPServers {
+		if dhcpServer.Status != "enable" {
+			continue
+		}
+		maskBits, err := utils.MaskToBits(dhcpServer.Netmask)
+		if err != nil {
+			return fmt.Errorf("mask of dhcp server %d: %s", dhcpServer.ID, err)
+		}
+		for _, ipRange := range dhcpServer.IPRange {
+			if !utils.IsPermittedIPAddress(
+				ipRange.StartIP,
+				fs.SourceConfig.PermittedSubnets,
+				fs.SourceConfig.IgnoredSubnets,
+			) {
+				continue
+			}
+			_, err := common.AddDHCPPool(
+				fs.Ctx,
+				nbi,
+				common.DHCPPool{
+					Name:         dhcpServer.Interface,
+					StartAddress: ipRange.StartIP,
+					EndAddress:   ipRange.EndIP,
+					MaskBits:     maskBits,
+				},
+				fs.NBFirewall.Tenant,
+				fs.SourceConfig.IPVrfRelations,
+				fs.GetSourceTags(),
+			)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
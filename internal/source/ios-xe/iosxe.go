@@ -17,10 +17,13 @@ type IOSXESource struct {
 	common.Config
 
 	// IOSXE fetched data. Initialized in init functions.
-	HardwareInfo hardwareReply
-	SystemInfo   systemReply
-	Interfaces   map[string]iface
-	ArpEntries   []arpEntry
+	HardwareInfo          hardwareReply
+	SystemInfo            systemReply
+	Interfaces            map[string]iface
+	ArpEntries            []arpEntry
+	LldpEntries           []lldpEntry
+	DHCPPools             []dhcpPool
+	DHCPExcludedAddresses []dhcpExcludedAddress
 
 	// IOSXE synced data. Created in sync functions.
 	NBDevice     *objects.Device
@@ -52,11 +55,16 @@ func (is *IOSXESource) Init() error {
 		is.initDeviceHardwareInfo,
 		is.initInterfaces,
 		is.initArpData,
+		is.initLldpData,
+		is.initDHCPPools,
 	}
 
 	for _, initFunc := range initFunctions {
 		startTime := time.Now()
-		if err := initFunc(d); err != nil {
+		endStep := is.StartStep("init", initFunc)
+		err := initFunc(d)
+		endStep(err)
+		if err != nil {
 			return fmt.Errorf("iosxe initialization failure: %v", err)
 		}
 		duration := time.Since(startTime)
@@ -73,15 +81,20 @@ func (is *IOSXESource) Init() error {
 func (is *IOSXESource) Sync(nbi *inventory.NetboxInventory) error {
 	syncFunctions := []func(*inventory.NetboxInventory) error{
 		is.syncDevice,
+		is.syncInventoryItems,
 		is.syncInterfaces,
 		is.syncArpTable,
+		is.syncDHCPPools,
+		is.syncCables,
 	}
 
 	var encounteredErrors []error
 	for _, syncFunc := range syncFunctions {
 		startTime := time.Now()
 		funcName := utils.ExtractFunctionNameWithTrimPrefix(syncFunc, "sync")
+		endStep := is.StartStep("sync", syncFunc)
 		err := syncFunc(nbi)
+		endStep(err)
 		if err != nil {
 			if is.SourceConfig.ContinueOnError {
 				is.Logger.Errorf(
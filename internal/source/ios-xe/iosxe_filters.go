@@ -36,3 +36,24 @@ const interfaceFilter = `<interfaces xmlns="http://openconfig.net/yang/interface
   </interfaces>`
 
 const arpFilter = `<arp-data xmlns="http://cisco.com/ns/yang/Cisco-IOS-XE-arp-oper"/>`
+
+const lldpFilter = `<lldp-entries xmlns="http://cisco.com/ns/yang/Cisco-IOS-XE-lldp-oper">
+    <lldp-entry>
+      <device-id/>
+      <local-interface/>
+      <connecting-interface/>
+    </lldp-entry>
+  </lldp-entries>`
+
+const dhcpFilter = `<native xmlns="http://cisco.com/ns/yang/Cisco-IOS-XE-native">
+    <ip>
+      <dhcp xmlns="http://cisco.com/ns/yang/Cisco-IOS-XE-dhcp">
+        <excluded-address/>
+        <pool>
+          <id/>
+          <vrf/>
+          <network/>
+        </pool>
+      </dhcp>
+    </ip>
+  </native>`
@@ -64,3 +64,32 @@ func (is *IOSXESource) initArpData(d *netconf.Driver) error {
 	}
 	return nil
 }
+
+func (is *IOSXESource) initLldpData(d *netconf.Driver) error {
+	var lldpReply lldpReply
+	r, err := d.Get(lldpFilter)
+	if err != nil {
+		return fmt.Errorf("error with lldp filter: %s", err)
+	}
+	err = xml.Unmarshal(r.RawResult, &lldpReply)
+	if err != nil {
+		return fmt.Errorf("error with unmarshaling lldp reply: %s", err)
+	}
+	is.LldpEntries = lldpReply.Entries
+	return nil
+}
+
+func (is *IOSXESource) initDHCPPools(d *netconf.Driver) error {
+	var dhcpReply dhcpReply
+	r, err := d.Get(dhcpFilter)
+	if err != nil {
+		return fmt.Errorf("error with dhcp filter: %s", err)
+	}
+	err = xml.Unmarshal(r.RawResult, &dhcpReply)
+	if err != nil {
+		return fmt.Errorf("error with unmarshaling dhcp reply: %s", err)
+	}
+	is.DHCPPools = dhcpReply.Pools
+	is.DHCPExcludedAddresses = dhcpReply.ExcludedAddresses
+	return nil
+}
@@ -75,3 +75,39 @@ type arpEntry struct {
 	HWType    string `xml:"hwtype"`
 	MAC       string `xml:"hardware"`
 }
+
+type lldpReply struct {
+	XMLName   xml.Name    `xml:"urn:ietf:params:xml:ns:netconf:base:1.0 rpc-reply"`
+	MessageID string      `xml:"message-id,attr"`
+	Entries   []lldpEntry `xml:"data>lldp-entries>lldp-entry"`
+}
+
+// lldpEntry is a neighbor discovered with LLDP.
+type lldpEntry struct {
+	// DeviceID is the system name of the neighbor.
+	DeviceID       string `xml:"device-id"`
+	LocalInterface string `xml:"local-interface"`
+	// ConnectingInterface is the port id of the neighbor.
+	ConnectingInterface string `xml:"connecting-interface"`
+}
+
+type dhcpReply struct {
+	XMLName           xml.Name              `xml:"urn:ietf:params:xml:ns:netconf:base:1.0 rpc-reply"`
+	MessageID         string                `xml:"message-id,attr"`
+	ExcludedAddresses []dhcpExcludedAddress `xml:"data>native>ip>dhcp>excluded-address>low-address-list"`
+	Pools             []dhcpPool            `xml:"data>native>ip>dhcp>pool"`
+}
+
+// dhcpExcludedAddress is a range of addresses, which dhcp server doesn't lease to clients.
+// HighAddress is empty, if a single address is excluded.
+type dhcpExcludedAddress struct {
+	LowAddress  string `xml:"low-address"`
+	HighAddress string `xml:"high-address"`
+}
+
+type dhcpPool struct {
+	ID      string `xml:"id"`
+	Vrf     string `xml:"vrf"`
+	Network string `xml:"network>primary-network>number"`
+	Mask    string `xml:"network>primary-network>mask"`
+}
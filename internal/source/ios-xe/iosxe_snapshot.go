@@ -0,0 +1,34 @@
+package iosxe
+
+import "encoding/json"
+
+// snapshot references data of the source, which is initialized in init functions.
+type snapshot struct {
+	HardwareInfo          *hardwareReply
+	SystemInfo            *systemReply
+	Interfaces            *map[string]iface
+	ArpEntries            *[]arpEntry
+	LldpEntries           *[]lldpEntry
+	DHCPPools             *[]dhcpPool
+	DHCPExcludedAddresses *[]dhcpExcludedAddress
+}
+
+func (is *IOSXESource) snapshot() *snapshot {
+	return &snapshot{
+		HardwareInfo:          &is.HardwareInfo,
+		SystemInfo:            &is.SystemInfo,
+		Interfaces:            &is.Interfaces,
+		ArpEntries:            &is.ArpEntries,
+		LldpEntries:           &is.LldpEntries,
+		DHCPPools:             &is.DHCPPools,
+		DHCPExcludedAddresses: &is.DHCPExcludedAddresses,
+	}
+}
+
+func (is *IOSXESource) MarshalSnapshot() ([]byte, error) {
+	return json.Marshal(is.snapshot())
+}
+
+func (is *IOSXESource) UnmarshalSnapshot(data []byte) error {
+	return json.Unmarshal(data, is.snapshot())
+}
@@ -2,9 +2,9 @@ package iosxe
 
 import (
 	"fmt"
+	"net/netip"
 	"time"
 
-	devices "github.com/src-doo/go-devicetype-library/pkg"
 	"github.com/src-doo/netbox-ssot/internal/constants"
 	"github.com/src-doo/netbox-ssot/internal/netbox/inventory"
 	"github.com/src-doo/netbox-ssot/internal/netbox/objects"
@@ -37,28 +37,12 @@ func (is *IOSXESource) syncDevice(nbi *inventory.NetboxInventory) error {
 		Name: "Cisco",
 		Slug: utils.Slugify("Cisco"),
 	})
-	var deviceType *objects.DeviceType
-	if deviceData, ok := devices.DeviceTypesMap[deviceManufacturer.Name][deviceModel]; ok {
-		deviceType, err = nbi.AddDeviceType(is.Ctx, &objects.DeviceType{
-			Manufacturer: deviceManufacturer,
-			Model:        deviceModel,
-			Slug:         deviceData.Slug,
-		})
-		if err != nil {
-			return fmt.Errorf("add device type: %s", err)
-		}
-	} else {
-		if err != nil {
-			return fmt.Errorf("failed adding manufacturer: %s", err)
-		}
-		deviceType, err = nbi.AddDeviceType(is.Ctx, &objects.DeviceType{
-			Manufacturer: deviceManufacturer,
-			Model:        deviceModel,
-			Slug:         utils.GenerateDeviceTypeSlug(deviceManufacturer.Name, deviceModel),
-		})
-		if err != nil {
-			return fmt.Errorf("add device type: %s", err)
-		}
+	if err != nil {
+		return fmt.Errorf("failed adding manufacturer: %s", err)
+	}
+	deviceType, err := common.AddDeviceType(is.Ctx, nbi, deviceManufacturer, deviceModel)
+	if err != nil {
+		return fmt.Errorf("add device type: %s", err)
 	}
 
 	deviceTenant, err := common.MatchHostToTenant(
@@ -66,6 +50,7 @@ func (is *IOSXESource) syncDevice(nbi *inventory.NetboxInventory) error {
 		nbi,
 		deviceName,
 		is.SourceConfig.HostTenantRelations,
+		is.SourceConfig.TenantGroupRelations,
 	)
 	if err != nil {
 		return fmt.Errorf("match host to tenant: %s", err)
@@ -133,6 +118,51 @@ func (is *IOSXESource) syncDevice(nbi *inventory.NetboxInventory) error {
 	return nil
 }
 
+// inventoryItemHWTypes are hardware types of replaceable parts,
+// which are synced as inventory items of the device.
+var inventoryItemHWTypes = map[string]bool{
+	"hw-type-module":      true, // supervisors and line cards
+	"hw-type-pm":          true, // power supplies
+	"hw-type-fan":         true,
+	"hw-type-transceiver": true,
+}
+
+// syncInventoryItems syncs replaceable parts of the device with their
+// part and serial numbers. Parts, that were removed from the device,
+// are handled by the orphan manager.
+func (is *IOSXESource) syncInventoryItems(nbi *inventory.NetboxInventory) error {
+	manufacturer, _ := nbi.GetManufacturer("Cisco")
+	for _, inv := range is.HardwareInfo.Inventory {
+		if !inventoryItemHWTypes[inv.Type] {
+			continue
+		}
+		if inv.DevName == "" {
+			is.Logger.Debugf(is.Ctx, "skipping hardware inventory entry %s without name", inv.DevIndex)
+			continue
+		}
+		var serialNumber string
+		if !is.SourceConfig.IgnoreSerialNumbers {
+			serialNumber = inv.SerialNumber
+		}
+		_, err := nbi.AddInventoryItem(is.Ctx, &objects.InventoryItem{
+			NetboxObject: objects.NetboxObject{
+				Tags:        is.GetSourceTags(),
+				Description: inv.Description,
+			},
+			Device:       is.NBDevice,
+			Name:         inv.DevName,
+			Manufacturer: manufacturer,
+			PartID:       inv.PartNumber,
+			SerialNumber: serialNumber,
+			Discovered:   true,
+		})
+		if err != nil {
+			return fmt.Errorf("add inventory item %s: %s", inv.DevName, err)
+		}
+	}
+	return nil
+}
+
 func (is *IOSXESource) syncInterfaces(nbi *inventory.NetboxInventory) error {
 	is.NBInterfaces = make(map[string]*objects.Interface)
 	for ifaceName, iface := range is.Interfaces {
@@ -265,3 +295,115 @@ func (is *IOSXESource) syncArpTable(nbi *inventory.NetboxInventory) error {
 	}
 	return nil
 }
+
+// syncDHCPPools syncs dhcp pools of the device as ip ranges.
+func (is *IOSXESource) syncDHCPPools(nbi *inventory.NetboxInventory) error {
+	for _, pool := range is.DHCPPools {
+		if pool.Network == "" {
+			continue
+		}
+		if !utils.IsPermittedIPAddress(
+			pool.Network,
+			is.SourceConfig.PermittedSubnets,
+			is.SourceConfig.IgnoredSubnets,
+		) {
+			continue
+		}
+		dhcpPool, ok, err := getDHCPPool(pool, is.DHCPExcludedAddresses)
+		if err != nil {
+			is.Logger.Warningf(is.Ctx, "skipping dhcp pool %s: %s", pool.ID, err)
+			continue
+		}
+		if !ok {
+			is.Logger.Debugf(is.Ctx, "skipping dhcp pool %s with all addresses excluded", pool.ID)
+			continue
+		}
+		_, err = common.AddDHCPPool(
+			is.Ctx,
+			nbi,
+			dhcpPool,
+			is.NBDevice.Tenant,
+			is.SourceConfig.IPVrfRelations,
+			is.GetSourceTags(),
+		)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// getDHCPPool returns addresses of the pool network, which can be leased to clients.
+// Excluded addresses at the beginning and at the end of the network are trimmed
+// from the range. If all addresses of the network are excluded, ok is false.
+func getDHCPPool(pool dhcpPool, excludedAddresses []dhcpExcludedAddress) (common.DHCPPool, bool, error) {
+	maskBits, err := utils.MaskToBits(pool.Mask)
+	if err != nil {
+		return common.DHCPPool{}, false, fmt.Errorf("mask %s: %s", pool.Mask, err)
+	}
+	firstHost, lastHost, err := utils.GetHostRangeFromPrefix(fmt.Sprintf("%s/%d", pool.Network, maskBits))
+	if err != nil {
+		return common.DHCPPool{}, false, err
+	}
+	startAddress := netip.MustParseAddr(firstHost)
+	endAddress := netip.MustParseAddr(lastHost)
+	for trimmed := true; trimmed && startAddress.Compare(endAddress) <= 0; {
+		trimmed = false
+		for _, excluded := range excludedAddresses {
+			low, err := netip.ParseAddr(excluded.LowAddress)
+			if err != nil {
+				continue
+			}
+			high := low
+			if excluded.HighAddress != "" {
+				high, err = netip.ParseAddr(excluded.HighAddress)
+				if err != nil {
+					continue
+				}
+			}
+			if low.Compare(startAddress) <= 0 && high.Compare(startAddress) >= 0 {
+				startAddress, trimmed = high.Next(), true
+			}
+			if low.Compare(endAddress) <= 0 && high.Compare(endAddress) >= 0 {
+				endAddress, trimmed = low.Prev(), true
+			}
+		}
+	}
+	if !startAddress.IsValid() || !endAddress.IsValid() || startAddress.Compare(endAddress) > 0 {
+		return common.DHCPPool{}, false, nil
+	}
+	return common.DHCPPool{
+		Name:         pool.ID,
+		StartAddress: startAddress.String(),
+		EndAddress:   endAddress.String(),
+		MaskBits:     maskBits,
+		VRF:          pool.Vrf,
+	}, true, nil
+}
+
+// syncCables connects interfaces of the device with interfaces of its
+// LLDP neighbors, that are already synced to netbox.
+func (is *IOSXESource) syncCables(nbi *inventory.NetboxInventory) error {
+	for _, lldpEntry := range is.LldpEntries {
+		nbIface, ok := is.NBInterfaces[lldpEntry.LocalInterface]
+		if !ok {
+			nbIface, ok = is.NBInterfaces[common.ExpandInterfaceName(lldpEntry.LocalInterface)]
+		}
+		if !ok {
+			is.Logger.Debugf(is.Ctx, "skipping lldp neighbor on unknown interface %s", lldpEntry.LocalInterface)
+			continue
+		}
+		_, err := common.AddNeighborCable(
+			is.Ctx,
+			nbi,
+			nbIface,
+			lldpEntry.DeviceID,
+			lldpEntry.ConnectingInterface,
+			is.GetSourceTags(),
+		)
+		if err != nil {
+			return fmt.Errorf("add neighbor cable: %s", err)
+		}
+	}
+	return nil
+}
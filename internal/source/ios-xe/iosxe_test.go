@@ -1 +1,73 @@
 package iosxe
+
+import (
+	"testing"
+
+	"github.com/src-doo/netbox-ssot/internal/source/common"
+)
+
+func Test_getDHCPPool(t *testing.T) {
+	pool := dhcpPool{ID: "clients", Vrf: "guests", Network: "10.0.0.0", Mask: "255.255.255.0"}
+	tests := []struct {
+		name              string
+		pool              dhcpPool
+		excludedAddresses []dhcpExcludedAddress
+		want              common.DHCPPool
+		wantOk            bool
+		wantErr           bool
+	}{
+		{
+			name: "Pool without excluded addresses",
+			pool: pool,
+			want: common.DHCPPool{
+				Name:         "clients",
+				StartAddress: "10.0.0.1",
+				EndAddress:   "10.0.0.254",
+				MaskBits:     24,
+				VRF:          "guests",
+			},
+			wantOk: true,
+		},
+		{
+			name: "Excluded addresses at the edges of the network",
+			pool: pool,
+			excludedAddresses: []dhcpExcludedAddress{
+				{LowAddress: "10.0.0.1", HighAddress: "10.0.0.10"},
+				{LowAddress: "10.0.0.11"},
+				{LowAddress: "10.0.0.100", HighAddress: "10.0.0.110"},
+				{LowAddress: "10.0.0.250", HighAddress: "10.0.0.255"},
+				{LowAddress: "10.0.1.1", HighAddress: "10.0.1.10"},
+			},
+			want: common.DHCPPool{
+				Name:         "clients",
+				StartAddress: "10.0.0.12",
+				EndAddress:   "10.0.0.249",
+				MaskBits:     24,
+				VRF:          "guests",
+			},
+			wantOk: true,
+		},
+		{
+			name:              "All addresses excluded",
+			pool:              pool,
+			excludedAddresses: []dhcpExcludedAddress{{LowAddress: "10.0.0.0", HighAddress: "10.0.0.255"}},
+			wantOk:            false,
+		},
+		{
+			name:    "Invalid mask",
+			pool:    dhcpPool{ID: "clients", Network: "10.0.0.0", Mask: "invalid"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok, err := getDHCPPool(tt.pool, tt.excludedAddresses)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("getDHCPPool() error = %v, wantErr %t", err, tt.wantErr)
+			}
+			if ok != tt.wantOk || got != tt.want {
+				t.Errorf("getDHCPPool() = %+v, %t, want %+v, %t", got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
@@ -73,7 +73,10 @@ func (o *OVirtSource) Init() error {
 
 	for _, initFunc := range initFunctions {
 		startTime := time.Now()
-		if err := initFunc(conn); err != nil {
+		endStep := o.StartStep("init", initFunc)
+		err := initFunc(conn)
+		endStep(err)
+		if err != nil {
 			return fmt.Errorf(
 				"failed to initialize oVirt %s: %v",
 				strings.TrimPrefix(fmt.Sprintf("%T", initFunc), "*source.OVirtSource.Init"),
@@ -104,7 +107,9 @@ func (o *OVirtSource) Sync(nbi *inventory.NetboxInventory) error {
 	for _, syncFunc := range syncFunctions {
 		startTime := time.Now()
 		funcName := utils.ExtractFunctionNameWithTrimPrefix(syncFunc, "sync")
+		endStep := o.StartStep("sync", syncFunc)
 		err := syncFunc(nbi)
+		endStep(err)
 		if err != nil {
 			if o.SourceConfig.ContinueOnError {
 				o.Logger.Errorf(
@@ -2,11 +2,11 @@ package ovirt
 
 import (
 	"fmt"
+	"maps"
+	"slices"
 	"strings"
-	"sync"
 
 	ovirtsdk4 "github.com/ovirt/go-ovirt"
-	devices "github.com/src-doo/go-devicetype-library/pkg"
 	"github.com/src-doo/netbox-ssot/internal/constants"
 	"github.com/src-doo/netbox-ssot/internal/netbox/inventory"
 	"github.com/src-doo/netbox-ssot/internal/netbox/objects"
@@ -52,6 +52,7 @@ func (o *OVirtSource) syncNetworks(nbi *inventory.NetboxInventory) error {
 				nbi,
 				name,
 				o.SourceConfig.VlanTenantRelations,
+				o.SourceConfig.TenantGroupRelations,
 			)
 			if err != nil {
 				return err
@@ -174,6 +175,7 @@ func (o *OVirtSource) syncClusters(nbi *inventory.NetboxInventory) error {
 			nbi,
 			clusterName,
 			o.SourceConfig.ClusterTenantRelations,
+			o.SourceConfig.TenantGroupRelations,
 		)
 		if err != nil {
 			return fmt.Errorf("match cluster to tenant: %s", err)
@@ -207,22 +209,31 @@ func (o *OVirtSource) syncClusters(nbi *inventory.NetboxInventory) error {
 // syncHosts synces collected hosts from ovirt api to netbox inventory
 // as devices.
 func (o *OVirtSource) syncHosts(nbi *inventory.NetboxInventory) error {
-	for hostID, host := range o.Hosts {
-		hostStruct, err := extractHostData(o, nbi, host, hostID)
-		if err != nil {
-			return fmt.Errorf("extract host data: %s", err)
-		}
+	return common.SyncConcurrently(&o.Config, slices.Sorted(maps.Keys(o.Hosts)), func(hostID string) error {
+		return o.syncHost(nbi, hostID, o.Hosts[hostID])
+	})
+}
 
-		nbHost, err := nbi.AddDevice(o.Ctx, hostStruct)
-		if err != nil {
-			return fmt.Errorf("failed to add oVirt host %+v with error: %v", hostStruct, err)
-		}
+// syncHost synces a single ovirt host and its nics to netbox inventory.
+func (o *OVirtSource) syncHost(
+	nbi *inventory.NetboxInventory,
+	hostID string,
+	host *ovirtsdk4.Host,
+) error {
+	hostStruct, err := extractHostData(o, nbi, host, hostID)
+	if err != nil {
+		return fmt.Errorf("extract host data: %s", err)
+	}
 
-		// We also need to sync nics separately, because nic is a separate object in netbox
-		err = o.syncHostNics(nbi, host, nbHost)
-		if err != nil {
-			return fmt.Errorf("failed to sync oVirt host %s nics with error: %v", nbHost.Name, err)
-		}
+	nbHost, err := nbi.AddDevice(o.Ctx, hostStruct)
+	if err != nil {
+		return fmt.Errorf("failed to add oVirt host %+v with error: %v", hostStruct, err)
+	}
+
+	// We also need to sync nics separately, because nic is a separate object in netbox
+	err = o.syncHostNics(nbi, host, nbHost)
+	if err != nil {
+		return fmt.Errorf("failed to sync oVirt host %s nics with error: %v", nbHost.Name, err)
 	}
 	return nil
 }
@@ -250,6 +261,7 @@ func extractHostData(
 		nbi,
 		hostName,
 		o.SourceConfig.HostTenantRelations,
+		o.SourceConfig.TenantGroupRelations,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("hostTenant: %s", err)
@@ -273,14 +285,6 @@ func extractHostData(
 		}
 	}
 
-	var deviceSlug string
-	deviceData, hasDeviceData := devices.DeviceTypesMap[hostManufacturerName][hostModel]
-	if hasDeviceData {
-		deviceSlug = deviceData.Slug
-	} else {
-		deviceSlug = utils.GenerateDeviceTypeSlug(hostManufacturerName, hostModel)
-	}
-
 	hostManufacturerStruct := &objects.Manufacturer{
 		Name: hostManufacturerName,
 		Slug: utils.Slugify(hostManufacturerName),
@@ -294,17 +298,11 @@ func extractHostData(
 		)
 	}
 
-	var hostDeviceType *objects.DeviceType
-	hostDeviceTypeStruct := &objects.DeviceType{
-		Manufacturer: hostManufacturer,
-		Model:        hostModel,
-		Slug:         deviceSlug,
-	}
-	hostDeviceType, err = nbi.AddDeviceType(o.Ctx, hostDeviceTypeStruct)
+	hostDeviceType, err := common.AddDeviceType(o.Ctx, nbi, hostManufacturer, hostModel)
 	if err != nil {
 		return nil, fmt.Errorf(
-			"failed adding oVirt DeviceType %v with error: %s",
-			hostDeviceTypeStruct,
+			"failed adding oVirt DeviceType %s with error: %s",
+			hostModel,
 			err,
 		)
 	}
@@ -972,36 +970,9 @@ func (o *OVirtSource) collectHostNicsData(
 
 // syncVMs synces ovirt vms into netbox inventory.
 func (o *OVirtSource) syncVMs(nbi *inventory.NetboxInventory) error {
-	const maxGoroutines = 50
-	guard := make(chan struct{}, maxGoroutines)
-	errChan := make(chan error, len(o.Vms))
-	var wg sync.WaitGroup
-
-	for vmID, ovirtVM := range o.Vms {
-		guard <- struct{}{} // Block if maxGoroutines are running
-		wg.Add(1)
-
-		go func(vmID string, ovirtVM *ovirtsdk4.Vm) {
-			defer wg.Done()
-			defer func() { <-guard }() // Release one spot in the semaphore
-
-			if err := o.syncVM(nbi, vmID, ovirtVM); err != nil {
-				errChan <- err
-			}
-		}(vmID, ovirtVM)
-	}
-
-	wg.Wait()
-	close(errChan)
-	close(guard)
-
-	for err := range errChan {
-		if err != nil {
-			return err
-		}
-	}
-
-	return nil
+	return common.SyncConcurrently(&o.Config, slices.Sorted(maps.Keys(o.Vms)), func(vmID string) error {
+		return o.syncVM(nbi, vmID, o.Vms[vmID])
+	})
 }
 
 // syncVM synces a single ovirt vm into netbox inventory.
@@ -2,7 +2,6 @@ package paloalto
 
 import (
 	"fmt"
-	"net/http"
 	"time"
 
 	"github.com/PaloAltoNetworks/pango"
@@ -31,19 +30,17 @@ type PaloAltoSource struct {
 	Iface2SubIfaces     map[string][]layer3.Entry // Iface name -> SubIfaces
 	VirtualRouters      map[string]router.Entry   // VirtualRouter name -> VirutalRouter
 	ArpData             []ArpEntry                // Array of arp entreies
+	HAState             *HAGroup                  // High availability state. Nil, if HA is not enabled
+	DHCPInterfaces      []DHCPInterface           // Interfaces with dhcp configuration
 
 	// NBFirewall representing paloalto firewall created in syncDevice func.
 	NBFirewall *objects.Device
 }
 
 func (pas *PaloAltoSource) Init() error {
-	var transport *http.Transport
-	var err error
-	if pas.Config.CAFile != "" {
-		transport, err = utils.LoadExtraCertInTransportConfig(pas.Config.CAFile)
-		if err != nil {
-			return fmt.Errorf("load extra cert in transport config: %s", err)
-		}
+	transport, err := utils.NewHTTPTransport(pas.SourceConfig.HTTPClientConfig())
+	if err != nil {
+		return fmt.Errorf("create http transport: %s", err)
 	}
 	c := &pango.Firewall{Client: pango.Client{
 		Hostname:          pas.SourceConfig.Hostname,
@@ -64,13 +61,18 @@ func (pas *PaloAltoSource) Init() error {
 	initFunctions := []func(*pango.Firewall) error{
 		pas.initArpData,
 		pas.initSystemInfo,
+		pas.initHAState,
+		pas.initDHCPServers,
 		pas.initVirtualSystems,
 		pas.initInterfaces,
 		pas.initVirtualRouters,
 	}
 	for _, initFunc := range initFunctions {
 		startTime := time.Now()
-		if err := initFunc(c); err != nil {
+		endStep := pas.StartStep("init", initFunc)
+		err := initFunc(c)
+		endStep(err)
+		if err != nil {
 			return fmt.Errorf("paloalto initialization failure: %v", err)
 		}
 		duration := time.Since(startTime)
@@ -89,6 +91,7 @@ func (pas *PaloAltoSource) Sync(nbi *inventory.NetboxInventory) error {
 		pas.syncDevice,
 		pas.syncSecurityZones,
 		pas.syncInterfaces,
+		pas.syncDHCPServers,
 		pas.syncArpTable,
 	}
 
@@ -96,7 +99,9 @@ func (pas *PaloAltoSource) Sync(nbi *inventory.NetboxInventory) error {
 	for _, syncFunc := range syncFunctions {
 		startTime := time.Now()
 		funcName := utils.ExtractFunctionNameWithTrimPrefix(syncFunc, "sync")
+		endStep := pas.StartStep("sync", syncFunc)
 		err := syncFunc(nbi)
+		endStep(err)
 		if err != nil {
 			if pas.SourceConfig.ContinueOnError {
 				pas.Logger.Errorf(
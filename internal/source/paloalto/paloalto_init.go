@@ -126,3 +126,79 @@ func (pas *PaloAltoSource) initArpData(c *pango.Firewall) error {
 	}
 	return nil
 }
+
+// Structs to parse xml high availability state response.
+type HAStateData struct {
+	XMLName xml.Name      `xml:"response"`
+	Status  string        `xml:"status,attr"`
+	Result  HAStateResult `xml:"result"`
+}
+
+type HAStateResult struct {
+	Enabled string  `xml:"enabled"`
+	Group   HAGroup `xml:"group"`
+}
+
+type HAGroup struct {
+	Mode      string `xml:"mode"`
+	LocalInfo HAPeer `xml:"local-info"`
+	PeerInfo  HAPeer `xml:"peer-info"`
+}
+
+type HAPeer struct {
+	State    string `xml:"state"`
+	Priority string `xml:"priority"`
+	MgmtIP   string `xml:"mgmt-ip"`
+}
+
+// initHAState collects high availability state of the firewall and its peer.
+// If the state can't be collected, the firewall is synced as a standalone device.
+func (pas *PaloAltoSource) initHAState(c *pango.Firewall) error {
+	pas.HAState = nil
+	haXMLResponse, err := c.Op("<show><high-availability><state></state></high-availability></show>", "", nil, nil)
+	if err != nil {
+		pas.Logger.Warningf(pas.Ctx, "skipping high availability state: %s", err)
+		return nil
+	}
+	var haState HAStateData
+	err = xml.Unmarshal(haXMLResponse, &haState)
+	if err != nil {
+		return fmt.Errorf("init ha state: %s", err)
+	}
+	if haState.Result.Enabled == "yes" {
+		pas.HAState = &haState.Result.Group
+	}
+	return nil
+}
+
+// Structs to parse xml dhcp configuration response.
+type DHCPData struct {
+	XMLName    xml.Name        `xml:"response"`
+	Status     string          `xml:"status,attr"`
+	Interfaces []DHCPInterface `xml:"result>dhcp>interface>entry"`
+}
+
+type DHCPInterface struct {
+	Name   string      `xml:"name,attr"`
+	Server *DHCPServer `xml:"server"`
+}
+
+type DHCPServer struct {
+	Mode    string   `xml:"mode"`
+	IPPools []string `xml:"ip-pool>member"`
+}
+
+// initDHCPServers collects configuration of dhcp servers on the firewall interfaces.
+func (pas *PaloAltoSource) initDHCPServers(c *pango.Firewall) error {
+	dhcpXMLResponse, err := c.Get("/config/devices/entry[@name='localhost.localdomain']/network/dhcp", nil, nil)
+	if err != nil {
+		return fmt.Errorf("init dhcp servers: %s", err)
+	}
+	var dhcpData DHCPData
+	err = xml.Unmarshal(dhcpXMLResponse, &dhcpData)
+	if err != nil {
+		return fmt.Errorf("init dhcp servers: %s", err)
+	}
+	pas.DHCPInterfaces = dhcpData.Interfaces
+	return nil
+}
@@ -0,0 +1,50 @@
+package paloalto
+
+import (
+	"encoding/json"
+
+	"github.com/PaloAltoNetworks/pango/netw/interface/eth"
+	"github.com/PaloAltoNetworks/pango/netw/interface/subinterface/layer3"
+	"github.com/PaloAltoNetworks/pango/netw/routing/router"
+	"github.com/PaloAltoNetworks/pango/netw/zone"
+	"github.com/PaloAltoNetworks/pango/vsys"
+)
+
+// snapshot references data of the source, which is initialized in init functions.
+type snapshot struct {
+	SystemInfo          *map[string]string
+	VirtualSystems      *map[string]vsys.Entry
+	SecurityZones       *map[string]zone.Entry
+	Iface2SecurityZone  *map[string]string
+	Iface2VirtualRouter *map[string]string
+	Ifaces              *map[string]eth.Entry
+	Iface2SubIfaces     *map[string][]layer3.Entry
+	VirtualRouters      *map[string]router.Entry
+	ArpData             *[]ArpEntry
+	HAState             **HAGroup
+	DHCPInterfaces      *[]DHCPInterface
+}
+
+func (pas *PaloAltoSource) snapshot() *snapshot {
+	return &snapshot{
+		SystemInfo:          &pas.SystemInfo,
+		VirtualSystems:      &pas.VirtualSystems,
+		SecurityZones:       &pas.SecurityZones,
+		Iface2SecurityZone:  &pas.Iface2SecurityZone,
+		Iface2VirtualRouter: &pas.Iface2VirtualRouter,
+		Ifaces:              &pas.Ifaces,
+		Iface2SubIfaces:     &pas.Iface2SubIfaces,
+		VirtualRouters:      &pas.VirtualRouters,
+		ArpData:             &pas.ArpData,
+		HAState:             &pas.HAState,
+		DHCPInterfaces:      &pas.DHCPInterfaces,
+	}
+}
+
+func (pas *PaloAltoSource) MarshalSnapshot() ([]byte, error) {
+	return json.Marshal(pas.snapshot())
+}
+
+func (pas *PaloAltoSource) UnmarshalSnapshot(data []byte) error {
+	return json.Unmarshal(data, pas.snapshot())
+}
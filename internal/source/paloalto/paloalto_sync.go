@@ -2,7 +2,9 @@ package paloalto
 
 import (
 	"fmt"
+	"slices"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 
@@ -53,6 +55,7 @@ func (pas *PaloAltoSource) syncDevice(nbi *inventory.NetboxInventory) error {
 		nbi,
 		deviceName,
 		pas.SourceConfig.HostTenantRelations,
+		pas.SourceConfig.TenantGroupRelations,
 	)
 	if err != nil {
 		return fmt.Errorf("match host %s to tenant: %s", deviceName, err)
@@ -108,15 +111,57 @@ func (pas *PaloAltoSource) syncDevice(nbi *inventory.NetboxInventory) error {
 		Platform:     devicePlatform,
 		SerialNumber: deviceSerialNumber,
 	}
+	vcMember, isVCMember := pas.virtualChassisMember()
+	if isVCMember {
+		err = common.AddVirtualChassisMember(pas.Ctx, nbi, deviceStruct, vcMember, pas.GetSourceTags())
+		if err != nil {
+			return fmt.Errorf("add virtual chassis member: %s", err)
+		}
+	}
 	NBDevice, err := nbi.AddDevice(pas.Ctx, deviceStruct)
 	if err != nil {
 		return fmt.Errorf("add device: %s", err)
 	}
+	if isVCMember {
+		err = common.SetVirtualChassisMaster(pas.Ctx, nbi, NBDevice, vcMember, pas.GetSourceTags())
+		if err != nil {
+			return fmt.Errorf("set virtual chassis master: %s", err)
+		}
+	}
 
 	pas.NBFirewall = NBDevice
 	return nil
 }
 
+// virtualChassisMember returns membership of the firewall in the virtual
+// chassis representing its HA pair. Both peers are synced as separate sources,
+// so the virtual chassis is named after management IPs of both peers,
+// which are the same from the perspective of each peer.
+func (pas *PaloAltoSource) virtualChassisMember() (common.VirtualChassisMember, bool) {
+	if pas.HAState == nil {
+		return common.VirtualChassisMember{}, false
+	}
+	localIP, _, _ := strings.Cut(pas.HAState.LocalInfo.MgmtIP, "/")
+	peerIP, _, _ := strings.Cut(pas.HAState.PeerInfo.MgmtIP, "/")
+	if localIP == "" || peerIP == "" {
+		return common.VirtualChassisMember{}, false
+	}
+	peerIPs := []string{localIP, peerIP}
+	slices.Sort(peerIPs)
+	position := 1
+	if peerIPs[0] != localIP {
+		position = 2 //nolint:mnd
+	}
+	priority, _ := strconv.Atoi(pas.HAState.LocalInfo.Priority)
+	localState := strings.ToLower(pas.HAState.LocalInfo.State)
+	return common.VirtualChassisMember{
+		VirtualChassisName: "HA " + strings.Join(peerIPs, ", "),
+		Position:           position,
+		Priority:           priority,
+		Master:             localState == "active" || localState == "active-primary",
+	}, true
+}
+
 func (pas *PaloAltoSource) syncInterfaces(nbi *inventory.NetboxInventory) error {
 	for _, iface := range pas.Ifaces {
 		if iface.Name == "" {
@@ -217,6 +262,7 @@ func (pas *PaloAltoSource) syncInterfaces(nbi *inventory.NetboxInventory) error
 					nbi,
 					vlanName,
 					pas.SourceConfig.VlanTenantRelations,
+					pas.SourceConfig.TenantGroupRelations,
 				)
 				if err != nil {
 					return fmt.Errorf("match vlan to tenant: %s", err)
@@ -328,6 +374,103 @@ func (pas *PaloAltoSource) syncIPs(
 	}
 }
 
+// syncDHCPServers syncs ip pools of dhcp servers on firewall interfaces as ip ranges.
+// Virtual router of the interface is used as VRF of the pool.
+func (pas *PaloAltoSource) syncDHCPServers(nbi *inventory.NetboxInventory) error {
+	for _, dhcpInterface := range pas.DHCPInterfaces {
+		if dhcpInterface.Server == nil || dhcpInterface.Server.Mode == "disabled" {
+			continue
+		}
+		for _, ipPool := range dhcpInterface.Server.IPPools {
+			startAddress, endAddress, maskBits, err := parseDHCPIPPool(ipPool)
+			if err != nil {
+				pas.Logger.Warningf(pas.Ctx, "skipping ip pool of interface %s: %s", dhcpInterface.Name, err)
+				continue
+			}
+			if !utils.IsPermittedIPAddress(
+				startAddress,
+				pas.SourceConfig.PermittedSubnets,
+				pas.SourceConfig.IgnoredSubnets,
+			) {
+				continue
+			}
+			if maskBits == 0 {
+				maskBits = pas.getInterfaceMaskBits(dhcpInterface.Name, startAddress)
+			}
+			if maskBits == 0 {
+				pas.Logger.Warningf(
+					pas.Ctx,
+					"skipping ip pool %s: no address of interface %s is in the same subnet",
+					ipPool,
+					dhcpInterface.Name,
+				)
+				continue
+			}
+			_, err = common.AddDHCPPool(
+				pas.Ctx,
+				nbi,
+				common.DHCPPool{
+					Name:         dhcpInterface.Name,
+					StartAddress: startAddress,
+					EndAddress:   endAddress,
+					MaskBits:     maskBits,
+					VRF:          pas.Iface2VirtualRouter[dhcpInterface.Name],
+				},
+				pas.NBFirewall.Tenant,
+				pas.SourceConfig.IPVrfRelations,
+				pas.GetSourceTags(),
+			)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// parseDHCPIPPool parses ip pool member of the dhcp server, which is either
+// a range (192.168.1.10-192.168.1.20), a subnet (192.168.1.0/24) or a single address.
+// Mask bits are returned only for subnets, otherwise they are 0.
+func parseDHCPIPPool(ipPool string) (string, string, int, error) {
+	if startAddress, endAddress, ok := strings.Cut(ipPool, "-"); ok {
+		return startAddress, endAddress, 0, nil
+	}
+	if strings.Contains(ipPool, "/") {
+		startAddress, endAddress, err := utils.GetHostRangeFromPrefix(ipPool)
+		if err != nil {
+			return "", "", 0, fmt.Errorf("parse ip pool %s: %s", ipPool, err)
+		}
+		_, maskBits, err := utils.GetPrefixAndMaskFromIPAddress(ipPool)
+		if err != nil {
+			return "", "", 0, fmt.Errorf("parse ip pool %s: %s", ipPool, err)
+		}
+		return startAddress, endAddress, maskBits, nil
+	}
+	return ipPool, ipPool, 0, nil
+}
+
+// getInterfaceMaskBits returns mask of the static ip of the interface (or subinterface),
+// which is in the same subnet as the given ip address. If there is no such ip, it returns 0.
+func (pas *PaloAltoSource) getInterfaceMaskBits(ifaceName string, ipAddress string) int {
+	staticIPs := pas.Ifaces[ifaceName].StaticIps
+	for _, subIfaces := range pas.Iface2SubIfaces {
+		for _, subIface := range subIfaces {
+			if subIface.Name == ifaceName {
+				staticIPs = subIface.StaticIps
+			}
+		}
+	}
+	for _, staticIP := range staticIPs {
+		if utils.SubnetContainsIPAddress(ipAddress, staticIP) {
+			_, maskBits, err := utils.GetPrefixAndMaskFromIPAddress(staticIP)
+			if err == nil {
+				return maskBits
+			}
+		}
+	}
+	return 0
+}
+
 // syncSecurityZones syncs all security zones from palo alto as virtual device context in netbox.
 // They are all added as part of main paloalto firewall device.
 func (pas *PaloAltoSource) syncSecurityZones(nbi *inventory.NetboxInventory) error {
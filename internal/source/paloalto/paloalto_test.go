@@ -1 +1,97 @@
 package paloalto
+
+import (
+	"testing"
+
+	"github.com/src-doo/netbox-ssot/internal/source/common"
+)
+
+func TestPaloAltoSource_virtualChassisMember(t *testing.T) {
+	tests := []struct {
+		name         string
+		haState      *HAGroup
+		want         common.VirtualChassisMember
+		wantIsMember bool
+	}{
+		{
+			name:         "HA is not enabled",
+			haState:      nil,
+			wantIsMember: false,
+		},
+		{
+			name: "Active peer",
+			haState: &HAGroup{
+				LocalInfo: HAPeer{State: "active", Priority: "100", MgmtIP: "10.0.0.2/24"},
+				PeerInfo:  HAPeer{State: "passive", Priority: "110", MgmtIP: "10.0.0.1/24"},
+			},
+			want: common.VirtualChassisMember{
+				VirtualChassisName: "HA 10.0.0.1, 10.0.0.2",
+				Position:           2,
+				Priority:           100,
+				Master:             true,
+			},
+			wantIsMember: true,
+		},
+		{
+			name: "Passive peer",
+			haState: &HAGroup{
+				LocalInfo: HAPeer{State: "passive", Priority: "110", MgmtIP: "10.0.0.1/24"},
+				PeerInfo:  HAPeer{State: "active", Priority: "100", MgmtIP: "10.0.0.2/24"},
+			},
+			want: common.VirtualChassisMember{
+				VirtualChassisName: "HA 10.0.0.1, 10.0.0.2",
+				Position:           1,
+				Priority:           110,
+				Master:             false,
+			},
+			wantIsMember: true,
+		},
+		{
+			name: "Unknown peer management ip",
+			haState: &HAGroup{
+				LocalInfo: HAPeer{State: "active", MgmtIP: "10.0.0.1/24"},
+			},
+			wantIsMember: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pas := &PaloAltoSource{HAState: tt.haState}
+			got, isMember := pas.virtualChassisMember()
+			if isMember != tt.wantIsMember || got != tt.want {
+				t.Errorf("virtualChassisMember() = %v, %t, want %v, %t", got, isMember, tt.want, tt.wantIsMember)
+			}
+		})
+	}
+}
+
+func Test_parseDHCPIPPool(t *testing.T) {
+	tests := []struct {
+		name             string
+		ipPool           string
+		wantStartAddress string
+		wantEndAddress   string
+		wantMaskBits     int
+		wantErr          bool
+	}{
+		{"Range", "192.168.1.10-192.168.1.20", "192.168.1.10", "192.168.1.20", 0, false},
+		{"Subnet", "192.168.1.0/25", "192.168.1.1", "192.168.1.126", 25, false},
+		{"Single address", "192.168.1.5", "192.168.1.5", "192.168.1.5", 0, false},
+		{"Invalid subnet", "192.168.1.0/33", "", "", 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			startAddress, endAddress, maskBits, err := parseDHCPIPPool(tt.ipPool)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseDHCPIPPool() error = %v, wantErr %t", err, tt.wantErr)
+			}
+			if startAddress != tt.wantStartAddress || endAddress != tt.wantEndAddress || maskBits != tt.wantMaskBits {
+				t.Errorf(
+					"parseDHCPIPPool() = %s, %s, %d, want %s, %s, %d",
+					startAddress, endAddress, maskBits,
+					tt.wantStartAddress, tt.wantEndAddress, tt.wantMaskBits,
+				)
+			}
+		})
+	}
+}
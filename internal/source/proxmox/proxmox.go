@@ -39,7 +39,7 @@ func (ps *ProxmoxSource) Init() error {
 	}
 
 	// Create http client depending on ssl configuration
-	HTTPClient, err := utils.NewHTTPClient(ps.SourceConfig.ValidateCert, ps.SourceConfig.CAFile)
+	HTTPClient, err := utils.NewHTTPClient(ps.SourceConfig.HTTPClientConfig())
 	if err != nil {
 		return fmt.Errorf("error creating new HTTP client: %s", err)
 	}
@@ -61,7 +61,10 @@ func (ps *ProxmoxSource) Init() error {
 
 	for _, initFunc := range initFuncs {
 		startTime := time.Now()
-		if err := initFunc(ctx, client); err != nil {
+		endStep := ps.StartStep("init", initFunc)
+		err := initFunc(ctx, client)
+		endStep(err)
+		if err != nil {
 			return fmt.Errorf("proxmox initialization failure: %v", err)
 		}
 		duration := time.Since(startTime)
@@ -88,7 +91,9 @@ func (ps *ProxmoxSource) Sync(nbi *inventory.NetboxInventory) error {
 	for _, syncFunc := range syncFunctions {
 		startTime := time.Now()
 		funcName := utils.ExtractFunctionNameWithTrimPrefix(syncFunc, "sync")
+		endStep := ps.StartStep("sync", syncFunc)
 		err := syncFunc(nbi)
+		endStep(err)
 		if err != nil {
 			if ps.SourceConfig.ContinueOnError {
 				ps.Logger.Errorf(
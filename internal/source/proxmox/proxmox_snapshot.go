@@ -0,0 +1,38 @@
+package proxmox
+
+import (
+	"encoding/json"
+
+	"github.com/luthermonson/go-proxmox"
+)
+
+// snapshot references data of the source, which is initialized in init functions.
+type snapshot struct {
+	Cluster         **proxmox.Cluster
+	Nodes           *[]*proxmox.Node
+	NodeIfaces      *map[string][]*proxmox.NodeNetwork
+	Vms             *map[string][]*proxmox.VirtualMachine
+	VMIfaces        *map[string][]*proxmox.AgentNetworkIface
+	Containers      *map[string][]*proxmox.Container
+	ContainerIfaces *map[string][]*proxmox.ContainerInterface
+}
+
+func (ps *ProxmoxSource) snapshot() *snapshot {
+	return &snapshot{
+		Cluster:         &ps.Cluster,
+		Nodes:           &ps.Nodes,
+		NodeIfaces:      &ps.NodeIfaces,
+		Vms:             &ps.Vms,
+		VMIfaces:        &ps.VMIfaces,
+		Containers:      &ps.Containers,
+		ContainerIfaces: &ps.ContainerIfaces,
+	}
+}
+
+func (ps *ProxmoxSource) MarshalSnapshot() ([]byte, error) {
+	return json.Marshal(ps.snapshot())
+}
+
+func (ps *ProxmoxSource) UnmarshalSnapshot(data []byte) error {
+	return json.Unmarshal(data, ps.snapshot())
+}
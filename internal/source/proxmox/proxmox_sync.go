@@ -2,9 +2,10 @@ package proxmox
 
 import (
 	"fmt"
+	"maps"
+	"slices"
 	"strconv"
 	"strings"
-	"sync"
 
 	"github.com/luthermonson/go-proxmox"
 	"github.com/src-doo/netbox-ssot/internal/constants"
@@ -38,6 +39,7 @@ func (ps *ProxmoxSource) syncCluster(nbi *inventory.NetboxInventory) error {
 		nbi,
 		ps.Cluster.Name,
 		ps.SourceConfig.ClusterTenantRelations,
+		ps.SourceConfig.TenantGroupRelations,
 	)
 	if err != nil {
 		return err
@@ -115,6 +117,7 @@ func (ps *ProxmoxSource) syncNodes(nbi *inventory.NetboxInventory) error {
 			nbi,
 			node.Name,
 			ps.SourceConfig.HostTenantRelations,
+			ps.SourceConfig.TenantGroupRelations,
 		)
 		if err != nil {
 			return fmt.Errorf("match host to tenant: %s", err)
@@ -242,51 +245,25 @@ func (ps *ProxmoxSource) syncNodeNetworks(
 
 // Function that synces proxmox vms to the netbox inventory.
 func (ps *ProxmoxSource) syncVMs(nbi *inventory.NetboxInventory) error {
-	// Maximum number of goroutines to run concurrently
-	const maxGoroutines = 50
-	// Use a guard channel as semaphore to limit the number of goroutines
-	guard := make(chan struct{}, maxGoroutines)
-	// Use errChan to collect errors from goroutines
-	errChan := make(chan error, len(ps.Vms))
-	// Use a WaitGroup to wait for all goroutines to complete
-	var wg sync.WaitGroup
-
-	for nodeName, vms := range ps.Vms {
+	type nodeVM struct {
+		vm     *proxmox.VirtualMachine
+		nbHost *objects.Device
+	}
+	var nodeVMs []nodeVM
+	for _, nodeName := range slices.Sorted(maps.Keys(ps.Vms)) {
+		vms := ps.Vms[nodeName]
 		// Add domain name suffix if needed
 		if ps.SourceConfig.AssignDomainName != "" {
 			nodeName += ps.SourceConfig.AssignDomainName
 		}
 		nbHost := ps.NetboxNodes[nodeName]
-
-		// Iterate over each VM and start a goroutine to sync it
 		for _, vm := range vms {
-			guard <- struct{}{} // Block if maxGoroutines are running
-			wg.Add(1)
-
-			go func(vm *proxmox.VirtualMachine, nbHost *objects.Device) {
-				defer wg.Done()
-				defer func() { <-guard }() // Release one spot in the semaphore
-
-				err := ps.syncVM(nbi, vm, nbHost)
-				if err != nil {
-					errChan <- err
-				}
-			}(vm, nbHost)
+			nodeVMs = append(nodeVMs, nodeVM{vm: vm, nbHost: nbHost})
 		}
 	}
-
-	// Wait for all goroutines to complete
-	wg.Wait()
-	close(errChan)
-	close(guard)
-
-	for err := range errChan {
-		if err != nil {
-			return err
-		}
-	}
-
-	return nil
+	return common.SyncConcurrently(&ps.Config, nodeVMs, func(item nodeVM) error {
+		return ps.syncVM(nbi, item.vm, item.nbHost)
+	})
 }
 
 func (ps *ProxmoxSource) syncVM( //nolint:gocyclo
@@ -340,7 +317,13 @@ func (ps *ProxmoxSource) syncVM( //nolint:gocyclo
 	}
 
 	// Determine VM tenant
-	vmTenant, err := common.MatchVMToTenant(ps.Ctx, nbi, vm.Name, ps.SourceConfig.VMTenantRelations)
+	vmTenant, err := common.MatchVMToTenant(
+		ps.Ctx,
+		nbi,
+		vm.Name,
+		ps.SourceConfig.VMTenantRelations,
+		ps.SourceConfig.TenantGroupRelations,
+	)
 	if err != nil {
 		return fmt.Errorf("failed to match vm to tenant: %s", err)
 	}
@@ -802,6 +785,7 @@ func (ps *ProxmoxSource) syncContainers(nbi *inventory.NetboxInventory) error {
 					nbi,
 					container.Name,
 					ps.SourceConfig.VMTenantRelations,
+					ps.SourceConfig.TenantGroupRelations,
 				)
 				if err != nil {
 					return fmt.Errorf("match vm to tenant: %s", err)
@@ -0,0 +1,146 @@
+package source
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/src-doo/netbox-ssot/internal/constants"
+	"github.com/src-doo/netbox-ssot/internal/parser"
+	"github.com/src-doo/netbox-ssot/internal/source/common"
+)
+
+// RedactedValue replaces values of sensitive attributes in recorded snapshots.
+const RedactedValue = "REDACTED"
+
+// sensitiveKeys are substrings of attribute names (lowercased, without
+// "_" and "-"), whose values are redacted from recorded snapshots.
+var sensitiveKeys = []string{
+	"password",
+	"passwd",
+	"passphrase",
+	"secret",
+	"token",
+	"psk",
+	"community",
+	"apikey",
+	"privatekey",
+}
+
+// Snapshot is the content of a snapshot file. Data holds the data
+// collected by Init of the source, encoded by the source itself.
+type Snapshot struct {
+	SourceType constants.SourceType `json:"source_type"`
+	RecordedAt time.Time            `json:"recorded_at"`
+	Data       json.RawMessage      `json:"data"`
+}
+
+// SnapshotPath returns path of the snapshot file of the source in dir.
+func SnapshotPath(dir string, sourceName string) string {
+	return filepath.Join(dir, sourceName+".json")
+}
+
+// RecordSnapshot writes data collected by Init of the initialized source
+// to the snapshot file at path. Credentials are redacted from the data.
+func RecordSnapshot(nbSource common.Source, config *parser.SourceConfig, path string) error {
+	snapshotter, ok := nbSource.(common.Snapshotter)
+	if !ok {
+		return fmt.Errorf("source type %s doesn't support snapshots", config.Type)
+	}
+	data, err := snapshotter.MarshalSnapshot()
+	if err != nil {
+		return fmt.Errorf("marshal snapshot: %s", err)
+	}
+	data, err = sanitizeSnapshot(data)
+	if err != nil {
+		return fmt.Errorf("sanitize snapshot: %s", err)
+	}
+	snapshotJSON, err := json.MarshalIndent(Snapshot{
+		SourceType: config.Type,
+		RecordedAt: time.Now().UTC(),
+		Data:       data,
+	}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal snapshot: %s", err)
+	}
+	err = os.WriteFile(path, snapshotJSON, 0o600) //nolint:mnd
+	if err != nil {
+		return fmt.Errorf("write snapshot: %s", err)
+	}
+	return nil
+}
+
+// ReplaySnapshot loads data from the snapshot file at path into the source,
+// so it can be synced without calling Init.
+func ReplaySnapshot(nbSource common.Source, config *parser.SourceConfig, path string) error {
+	snapshotter, ok := nbSource.(common.Snapshotter)
+	if !ok {
+		return fmt.Errorf("source type %s doesn't support snapshots", config.Type)
+	}
+	snapshotJSON, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("read snapshot: %s", err)
+	}
+	var snapshot Snapshot
+	err = json.Unmarshal(snapshotJSON, &snapshot)
+	if err != nil {
+		return fmt.Errorf("unmarshal snapshot: %s", err)
+	}
+	if snapshot.SourceType != config.Type {
+		return fmt.Errorf(
+			"snapshot %s was recorded from source type %s, not %s",
+			path,
+			snapshot.SourceType,
+			config.Type,
+		)
+	}
+	err = snapshotter.UnmarshalSnapshot(snapshot.Data)
+	if err != nil {
+		return fmt.Errorf("unmarshal snapshot data: %s", err)
+	}
+	return nil
+}
+
+// sanitizeSnapshot redacts string values of sensitive attributes in data.
+func sanitizeSnapshot(data []byte) ([]byte, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var decoded interface{}
+	err := decoder.Decode(&decoded)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(redact(decoded))
+}
+
+func redact(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			if _, isString := item.(string); isString && item != "" && isSensitiveKey(key) {
+				v[key] = RedactedValue
+				continue
+			}
+			v[key] = redact(item)
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = redact(item)
+		}
+	}
+	return value
+}
+
+func isSensitiveKey(key string) bool {
+	normalized := strings.NewReplacer("_", "", "-", "").Replace(strings.ToLower(key))
+	for _, sensitiveKey := range sensitiveKeys {
+		if strings.Contains(normalized, sensitiveKey) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,142 @@
+package source
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/src-doo/netbox-ssot/internal/constants"
+	"github.com/src-doo/netbox-ssot/internal/netbox/netboxtest"
+	"github.com/src-doo/netbox-ssot/internal/netbox/objects"
+	"github.com/src-doo/netbox-ssot/internal/parser"
+	"github.com/src-doo/netbox-ssot/internal/source/common"
+	"github.com/src-doo/netbox-ssot/internal/source/fortigate"
+	"github.com/src-doo/netbox-ssot/internal/source/ovirt"
+)
+
+func TestSanitizeSnapshot(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want string
+	}{
+		{
+			name: "Sensitive keys are redacted",
+			data: `{"name":"wlan","passphrase":"secret1","Auth_Token":"abc","snmp-community":"public"}`,
+			want: `{"Auth_Token":"REDACTED","name":"wlan","passphrase":"REDACTED","snmp-community":"REDACTED"}`,
+		},
+		{
+			name: "Nested objects and lists are redacted",
+			data: `{"ssids":[{"ssid":"guest","psk":"guestpass"}],"device":{"apiKey":"key","mtu":1500}}`,
+			want: `{"device":{"apiKey":"REDACTED","mtu":1500},"ssids":[{"psk":"REDACTED","ssid":"guest"}]}`,
+		},
+		{
+			name: "Empty and non string values are kept",
+			data: `{"password":"","secret":{"name":"kept"},"tokenCount":12345678901234567890}`,
+			want: `{"password":"","secret":{"name":"kept"},"tokenCount":12345678901234567890}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := sanitizeSnapshot([]byte(tt.data))
+			if err != nil {
+				t.Fatalf("sanitizeSnapshot() error = %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("sanitizeSnapshot() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRecordAndReplaySnapshot(t *testing.T) {
+	config := &parser.SourceConfig{Name: "fw", Type: constants.Fortigate}
+	recorded := &fortigate.FortigateSource{
+		SystemInfo: fortigate.FortiSystemInfo{Hostname: "fw1", Version: "v7.2.5", Serial: "FGT60F0000000001"},
+		Ifaces: map[string]fortigate.InterfaceResponse{
+			"wan1": {Name: "wan1", IP: "192.0.2.1 255.255.255.0", Status: "up", MTU: 1500},
+		},
+	}
+	snapshotPath := SnapshotPath(t.TempDir(), config.Name)
+	err := RecordSnapshot(recorded, config, snapshotPath)
+	if err != nil {
+		t.Fatalf("RecordSnapshot() error = %v", err)
+	}
+
+	replayed := &fortigate.FortigateSource{}
+	err = ReplaySnapshot(replayed, config, snapshotPath)
+	if err != nil {
+		t.Fatalf("ReplaySnapshot() error = %v", err)
+	}
+	if !reflect.DeepEqual(replayed.SystemInfo, recorded.SystemInfo) ||
+		!reflect.DeepEqual(replayed.Ifaces, recorded.Ifaces) {
+		t.Errorf("ReplaySnapshot() = %+v, %+v, want %+v, %+v",
+			replayed.SystemInfo, replayed.Ifaces, recorded.SystemInfo, recorded.Ifaces)
+	}
+
+	otherConfig := &parser.SourceConfig{Name: "fw", Type: constants.PaloAlto}
+	err = ReplaySnapshot(replayed, otherConfig, snapshotPath)
+	if err == nil || !strings.Contains(err.Error(), "recorded from source type fortigate") {
+		t.Errorf("ReplaySnapshot() with other source type error = %v, want type mismatch", err)
+	}
+
+	ovirtConfig := &parser.SourceConfig{Name: "ovirt", Type: constants.Ovirt}
+	err = RecordSnapshot(&ovirt.OVirtSource{}, ovirtConfig, SnapshotPath(t.TempDir(), ovirtConfig.Name))
+	if err == nil {
+		t.Errorf("RecordSnapshot() of ovirt source error = nil, want unsupported")
+	}
+}
+
+func TestEndToEnd_FortigateReplay(t *testing.T) {
+	firewall := &mockFortigate{}
+	fortigateServer := httptest.NewServer(firewall)
+	firewall.setInterfaces(
+		fortigate.InterfaceResponse{Name: "wan1", IP: "192.0.2.1 255.255.255.0", Status: "up", MTU: 1500},
+		fortigate.InterfaceResponse{Name: "lan", IP: "10.0.0.1 255.255.255.0", Status: "up", MTU: 1500},
+	)
+	snapshotDir := t.TempDir()
+
+	// Record the fortigate while syncing it to netbox
+	recordNetbox := netboxtest.NewServer()
+	defer recordNetbox.Close()
+	config := writeE2EConfig(t, recordNetbox.URL, fortigateServer.URL)
+	runE2E(t, config, func(nbSource common.Source, sourceConfig *parser.SourceConfig) error {
+		err := nbSource.Init()
+		if err != nil {
+			return err
+		}
+		return RecordSnapshot(nbSource, sourceConfig, SnapshotPath(snapshotDir, sourceConfig.Name))
+	})
+	fortigateServer.Close()
+
+	snapshotJSON, err := os.ReadFile(filepath.Join(snapshotDir, "fw.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var snapshot Snapshot
+	err = json.Unmarshal(snapshotJSON, &snapshot)
+	if err != nil || snapshot.SourceType != constants.Fortigate {
+		t.Fatalf("recorded snapshot = %s, %v, want fortigate snapshot", snapshotJSON, err)
+	}
+
+	// Replay the snapshot against an empty netbox, while the fortigate is unreachable
+	replayNetbox := netboxtest.NewServer()
+	defer replayNetbox.Close()
+	config = writeE2EConfig(t, replayNetbox.URL, fortigateServer.URL)
+	runE2E(t, config, func(nbSource common.Source, sourceConfig *parser.SourceConfig) error {
+		return ReplaySnapshot(nbSource, sourceConfig, SnapshotPath(snapshotDir, sourceConfig.Name))
+	})
+
+	devices, err := netboxtest.Objects[objects.Device](replayNetbox)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(devices) != 1 || devices[0].Name != "fw1" {
+		t.Errorf("replayed devices = %v, want fw1", devices)
+	}
+	assertInterfaces(t, replayNetbox, "lan", "wan1")
+}
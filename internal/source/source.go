@@ -35,7 +35,7 @@ func NewSource(
 		Slug:  utils.Slugify("source-" + config.Name),
 		Color: constants.Color(config.TagColor),
 		Description: fmt.Sprintf(
-			"Automatically created tag by netbox-ssot for source %s",
+			constants.SourceTagDescriptionPrefix+" for source %s",
 			config.Name,
 		),
 	})
@@ -47,7 +47,7 @@ func NewSource(
 		Slug:  utils.Slugify("type-" + string(config.Type)),
 		Color: constants.Color(constants.SourceTypeTagColorMap[config.Type]),
 		Description: fmt.Sprintf(
-			"Automatically created tag by netbox-ssot for source type %s",
+			constants.SourceTagDescriptionPrefix+" for source type %s",
 			config.Type,
 		),
 	})
@@ -39,6 +39,9 @@ type VmwareSource struct {
 	Host2Cluster       map[string]string // HostKey -> ClusterKey
 	VM2Host            map[string]string // VmKey ->  HostKey
 
+	// HostPnicNeighbors: HostKey -> pnic device -> neighbor discovered with CDP or LLDP
+	HostPnicNeighbors map[string]map[string]PnicNeighbor
+
 	// CustomField2Name is a map of custom field ids to their names
 	CustomFieldID2Name map[int32]string
 	// Object2Tags is a map of object ids to their tags
@@ -67,6 +70,14 @@ type DistributedPortgroupData struct {
 	Tenant       *objects.Tenant
 }
 
+// PnicNeighbor is a network device connected to a physical nic of a host.
+type PnicNeighbor struct {
+	// DeviceName is the system name of the neighbor.
+	DeviceName string
+	// PortName is the port id of the neighbor.
+	PortName string
+}
+
 type HostVirtualSwitchData struct {
 	mtu   int
 	pnics []string
@@ -110,12 +121,14 @@ func (vc *VmwareSource) Init() error {
 
 	// How to set custom ca certificates for govmomi: https://github.com/vmware/govmomi/issues/1200#issuecomment-412950179
 	soapClient := soap.NewClient(url, !vc.SourceConfig.ValidateCert)
-	if vc.Config.CAFile != "" {
-		err = soapClient.SetRootCAs(vc.Config.CAFile)
-		if err != nil {
-			return fmt.Errorf("set root CAs: %s", err)
-		}
+	transport := soapClient.DefaultTransport()
+	err = utils.ConfigureTransport(transport, vc.SourceConfig.HTTPClientConfig())
+	if err != nil {
+		return fmt.Errorf("configure transport: %s", err)
 	}
+	// Custom tls dialer of govmomi is only needed for thumbprint verification,
+	// which isn't used. It would also bypass the proxy for https requests.
+	transport.DialTLSContext = nil
 	vim25Client, err := vim25.NewClient(ctx, soapClient)
 	if err != nil {
 		return fmt.Errorf("failed creating a govmomi client with an error: %s", err)
@@ -185,12 +198,16 @@ func (vc *VmwareSource) Init() error {
 		vc.initDataCenters,
 		vc.initClusters,
 		vc.initHosts,
+		vc.initPnicNeighbors,
 		vc.initVms,
 	}
 
 	for _, initFunc := range initFunctions {
 		startTime := time.Now()
-		if err := initFunc(ctx, containerView); err != nil {
+		endStep := vc.StartStep("init", initFunc)
+		err := initFunc(ctx, containerView)
+		endStep(err)
+		if err != nil {
 			return fmt.Errorf("vmware initialization failure: %v", err)
 		}
 		duration := time.Since(startTime)
@@ -240,7 +257,9 @@ func (vc *VmwareSource) Sync(nbi *inventory.NetboxInventory) error {
 	for _, syncFunc := range syncFunctions {
 		startTime := time.Now()
 		funcName := utils.ExtractFunctionNameWithTrimPrefix(syncFunc, "sync")
+		endStep := vc.StartStep("sync", syncFunc)
 		err := syncFunc(nbi)
+		endStep(err)
 		if err != nil {
 			if vc.SourceConfig.ContinueOnError {
 				vc.Logger.Errorf(
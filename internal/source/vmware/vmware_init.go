@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/src-doo/netbox-ssot/internal/constants"
+	"github.com/vmware/govmomi/object"
 	"github.com/vmware/govmomi/view"
 	"github.com/vmware/govmomi/vim25/mo"
 	"github.com/vmware/govmomi/vim25/types"
@@ -151,6 +152,7 @@ func (vc *VmwareSource) initHosts(ctx context.Context, containerView *view.Conta
 			"summary.config",
 			"vm",
 			"config.network",
+			"configManager.networkSystem",
 		},
 		&hosts,
 	)
@@ -220,6 +222,46 @@ func (vc *VmwareSource) initHosts(ctx context.Context, containerView *view.Conta
 	return nil
 }
 
+// initPnicNeighbors collects CDP and LLDP neighbors of hosts' physical nics.
+// Hosts, which can't be queried (e.g. disconnected hosts), are skipped.
+func (vc *VmwareSource) initPnicNeighbors(ctx context.Context, containerView *view.ContainerView) error {
+	vc.HostPnicNeighbors = make(map[string]map[string]PnicNeighbor, len(vc.Hosts))
+	for hostKey, host := range vc.Hosts {
+		if host.ConfigManager.NetworkSystem == nil {
+			continue
+		}
+		networkSystem := object.NewHostNetworkSystem(containerView.Client(), *host.ConfigManager.NetworkSystem)
+		hints, err := networkSystem.QueryNetworkHint(ctx, nil)
+		if err != nil {
+			vc.Logger.Warningf(vc.Ctx, "failed querying network hints of host %s: %s", host.Name, err)
+			continue
+		}
+		vc.HostPnicNeighbors[hostKey] = make(map[string]PnicNeighbor)
+		for _, hint := range hints {
+			if neighbor, ok := pnicNeighbor(hint); ok {
+				vc.HostPnicNeighbors[hostKey][hint.Device] = neighbor
+			}
+		}
+	}
+	return nil
+}
+
+// pnicNeighbor returns the neighbor from the network hint of a physical nic,
+// preferring CDP over LLDP data.
+func pnicNeighbor(hint types.PhysicalNicHintInfo) (PnicNeighbor, bool) {
+	if cdp := hint.ConnectedSwitchPort; cdp != nil && cdp.DevId != "" && cdp.PortId != "" {
+		return PnicNeighbor{DeviceName: cdp.DevId, PortName: cdp.PortId}, true
+	}
+	if lldp := hint.LldpInfo; lldp != nil && lldp.PortId != "" {
+		for _, parameter := range lldp.Parameter {
+			if systemName, ok := parameter.Value.(string); ok && parameter.Key == "System Name" && systemName != "" {
+				return PnicNeighbor{DeviceName: systemName, PortName: lldp.PortId}, true
+			}
+		}
+	}
+	return PnicNeighbor{}, false
+}
+
 func (vc *VmwareSource) initVms(ctx context.Context, containerView *view.ContainerView) error {
 	var vms []mo.VirtualMachine
 	err := containerView.Retrieve(
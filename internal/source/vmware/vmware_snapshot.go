@@ -0,0 +1,65 @@
+package vmware
+
+import (
+	"bytes"
+
+	"github.com/vmware/govmomi/vapi/tags"
+	vjson "github.com/vmware/govmomi/vim25/json"
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+// snapshot references data of the source, which is initialized in init functions.
+type snapshot struct {
+	Disks              *map[string]mo.Datastore
+	DataCenters        *map[string]mo.Datacenter
+	Clusters           *map[string]mo.ClusterComputeResource
+	Hosts              *map[string]mo.HostSystem
+	Vms                *map[string]mo.VirtualMachine
+	Networks           *NetworkData
+	Cluster2Datacenter *map[string]string
+	Host2Cluster       *map[string]string
+	VM2Host            *map[string]string
+	HostPnicNeighbors  *map[string]map[string]PnicNeighbor
+	CustomFieldID2Name *map[int32]string
+	Object2Tags        *map[string][]*tags.Tag
+}
+
+func (vc *VmwareSource) snapshot() *snapshot {
+	return &snapshot{
+		Disks:              &vc.Disks,
+		DataCenters:        &vc.DataCenters,
+		Clusters:           &vc.Clusters,
+		Hosts:              &vc.Hosts,
+		Vms:                &vc.Vms,
+		Networks:           &vc.Networks,
+		Cluster2Datacenter: &vc.Cluster2Datacenter,
+		Host2Cluster:       &vc.Host2Cluster,
+		VM2Host:            &vc.VM2Host,
+		HostPnicNeighbors:  &vc.HostPnicNeighbors,
+		CustomFieldID2Name: &vc.CustomFieldID2Name,
+		Object2Tags:        &vc.Object2Tags,
+	}
+}
+
+// MarshalSnapshot encodes the data with the type discriminators of govmomi,
+// because vmware managed objects contain values of interface types.
+func (vc *VmwareSource) MarshalSnapshot() ([]byte, error) {
+	var buf bytes.Buffer
+	enc := vjson.NewEncoder(&buf)
+	enc.SetDiscriminator(
+		"_typeName",
+		"_value",
+		vjson.DiscriminatorEncodeTypeNameIfRequired,
+	)
+	enc.SetTypeToDiscriminatorFunc(types.VmomiTypeName)
+	err := enc.Encode(vc.snapshot())
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (vc *VmwareSource) UnmarshalSnapshot(data []byte) error {
+	return types.NewJSONDecoder(bytes.NewReader(data)).Decode(vc.snapshot())
+}
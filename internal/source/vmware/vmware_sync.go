@@ -2,12 +2,11 @@ package vmware
 
 import (
 	"fmt"
+	"maps"
 	"slices"
 	"strconv"
 	"strings"
-	"sync"
 
-	devices "github.com/src-doo/go-devicetype-library/pkg"
 	"github.com/src-doo/netbox-ssot/internal/constants"
 	"github.com/src-doo/netbox-ssot/internal/netbox/inventory"
 	"github.com/src-doo/netbox-ssot/internal/netbox/objects"
@@ -73,6 +72,7 @@ func (vc *VmwareSource) syncNetworks(nbi *inventory.NetboxInventory) error {
 			nbi,
 			dvpg.Name,
 			vc.SourceConfig.VlanTenantRelations,
+			vc.SourceConfig.TenantGroupRelations,
 		)
 		if err != nil {
 			return fmt.Errorf("vlanTenant: %s", err)
@@ -180,6 +180,7 @@ func (vc *VmwareSource) syncClusters(nbi *inventory.NetboxInventory) error {
 			nbi,
 			clusterName,
 			vc.SourceConfig.ClusterTenantRelations,
+			vc.SourceConfig.TenantGroupRelations,
 		)
 		if err != nil {
 			return fmt.Errorf("match cluster to tenant: %s", err)
@@ -215,204 +216,200 @@ func (vc *VmwareSource) syncClusters(nbi *inventory.NetboxInventory) error {
 // Host in vmware is a represented as device in netbox with a
 // custom role Server.
 func (vc *VmwareSource) syncHosts(nbi *inventory.NetboxInventory) error {
-	for hostID, host := range vc.Hosts {
-		var err error
-		hostName := host.Name
+	return common.SyncConcurrently(&vc.Config, slices.Sorted(maps.Keys(vc.Hosts)), func(hostID string) error {
+		return vc.syncHost(nbi, hostID, vc.Hosts[hostID])
+	})
+}
 
-		hostSite, err := common.MatchHostToSite(
-			vc.Ctx,
-			nbi,
-			hostName,
-			vc.SourceConfig.HostSiteRelations,
-		)
-		if err != nil {
-			return fmt.Errorf("hostSite: %s", err)
-		}
+// syncHost synces a single vmware host and its nics to netbox inventory.
+func (vc *VmwareSource) syncHost(
+	nbi *inventory.NetboxInventory,
+	hostID string,
+	host mo.HostSystem,
+) error {
+	var err error
+	hostName := host.Name
 
-		hostTenant, err := common.MatchHostToTenant(
-			vc.Ctx,
-			nbi,
-			hostName,
-			vc.SourceConfig.HostTenantRelations,
-		)
-		if err != nil {
-			return fmt.Errorf("hostTenant: %s", err)
-		}
+	hostSite, err := common.MatchHostToSite(
+		vc.Ctx,
+		nbi,
+		hostName,
+		vc.SourceConfig.HostSiteRelations,
+	)
+	if err != nil {
+		return fmt.Errorf("hostSite: %s", err)
+	}
 
-		hostCluster, _ := nbi.GetCluster(vc.Clusters[vc.Host2Cluster[hostID]].Name)
-		if hostCluster == nil {
-			// Create a hypothetical cluster https://github.com/src-doo/netbox-ssot/issues/141
-			hostCluster, err = vc.createHypotheticalCluster(nbi, hostName, hostSite, hostTenant)
-			if err != nil {
-				return fmt.Errorf("add hypothetical cluster: %s", err)
-			}
-		}
+	hostTenant, err := common.MatchHostToTenant(
+		vc.Ctx,
+		nbi,
+		hostName,
+		vc.SourceConfig.HostTenantRelations,
+		vc.SourceConfig.TenantGroupRelations,
+	)
+	if err != nil {
+		return fmt.Errorf("hostTenant: %s", err)
+	}
 
-		hostTags := vc.Object2NBTags[hostID]
-
-		// Extract host hardware info
-		var hostUUID, hostModel, hostManufacturerName string
-		if host.Summary.Hardware != nil {
-			hostUUID = host.Summary.Hardware.Uuid
-			hostModel = host.Summary.Hardware.Model
-			hostManufacturerName = host.Summary.Hardware.Vendor
-			// Serialize manufacturer names so they match device type library
-			if hostManufacturerName != "" {
-				hostManufacturerName = utils.SerializeManufacturerName(hostManufacturerName)
-			}
+	hostCluster, _ := nbi.GetCluster(vc.Clusters[vc.Host2Cluster[hostID]].Name)
+	if hostCluster == nil {
+		// Create a hypothetical cluster https://github.com/src-doo/netbox-ssot/issues/141
+		hostCluster, err = vc.createHypotheticalCluster(nbi, hostName, hostSite, hostTenant)
+		if err != nil {
+			return fmt.Errorf("add hypothetical cluster: %s", err)
 		}
+	}
 
-		if hostModel == "" {
-			hostModel = constants.DefaultModel
-		}
-		if hostManufacturerName == "" {
-			hostManufacturerName = constants.DefaultManufacturer
-		}
+	hostTags := vc.Object2NBTags[hostID]
 
-		// Enrich data from device type library if possible
-		var deviceSlug string
-		deviceData, hasDeviceData := devices.DeviceTypesMap[hostManufacturerName][hostModel]
-		if hasDeviceData {
-			deviceSlug = deviceData.Slug
-		} else {
-			deviceSlug = utils.GenerateDeviceTypeSlug(hostManufacturerName, hostModel)
+	// Extract host hardware info
+	var hostUUID, hostModel, hostManufacturerName string
+	if host.Summary.Hardware != nil {
+		hostUUID = host.Summary.Hardware.Uuid
+		hostModel = host.Summary.Hardware.Model
+		hostManufacturerName = host.Summary.Hardware.Vendor
+		// Serialize manufacturer names so they match device type library
+		if hostManufacturerName != "" {
+			hostManufacturerName = utils.SerializeManufacturerName(hostManufacturerName)
 		}
+	}
 
-		manufacturerStruct := &objects.Manufacturer{
-			Name: hostManufacturerName,
-			Slug: utils.Slugify(hostManufacturerName),
-		}
-		hostManufacturer, err := nbi.AddManufacturer(vc.Ctx, manufacturerStruct)
-		if err != nil {
-			return fmt.Errorf(
-				"failed adding vmware Manufacturer %v with error: %s",
-				manufacturerStruct,
-				err,
-			)
-		}
+	if hostModel == "" {
+		hostModel = constants.DefaultModel
+	}
+	if hostManufacturerName == "" {
+		hostManufacturerName = constants.DefaultManufacturer
+	}
 
-		// Create device type
-		deviceTypeStruct := &objects.DeviceType{
-			Manufacturer: hostManufacturer,
-			Model:        hostModel,
-			Slug:         deviceSlug,
-		}
-		hostDeviceType, err := nbi.AddDeviceType(vc.Ctx, deviceTypeStruct)
-		if err != nil {
-			return fmt.Errorf(
-				"failed adding vmware DeviceType %+v with error: %s",
-				deviceTypeStruct,
-				err,
-			)
-		}
+	manufacturerStruct := &objects.Manufacturer{
+		Name: hostManufacturerName,
+		Slug: utils.Slugify(hostManufacturerName),
+	}
+	hostManufacturer, err := nbi.AddManufacturer(vc.Ctx, manufacturerStruct)
+	if err != nil {
+		return fmt.Errorf(
+			"failed adding vmware Manufacturer %v with error: %s",
+			manufacturerStruct,
+			err,
+		)
+	}
 
-		// Find serial number from host summary.hardware.OtherIdentifyingInfo (vmware specific logic)
-		var hostSerialNumber string
-		serialInfoTypes := map[string]bool{
-			"EnclosureSerialNumberTag": true,
-			"ServiceTag":               true,
-			"SerialNumberTag":          true,
-		}
-		var assetTag string
-		for _, info := range host.Summary.Hardware.OtherIdentifyingInfo {
-			infoType := info.IdentifierType.GetElementDescription().Key
-			infoValue := strings.Trim(info.IdentifierValue, " ") // remove blank spaces from value
-			if infoType == "AssetTag" {
-				if infoValue == "No Asset Tag" {
-					infoValue = ""
-				}
-				if !vc.SourceConfig.IgnoreAssetTags {
-					assetTag = infoValue
-				}
-			} else if serialInfoTypes[infoType] {
-				if info.IdentifierValue != "" {
-					if !vc.SourceConfig.IgnoreSerialNumbers {
-						hostSerialNumber = infoValue
-					}
-					break
+	// Create device type, enriched from device type library if possible
+	hostDeviceType, err := common.AddDeviceType(vc.Ctx, nbi, hostManufacturer, hostModel)
+	if err != nil {
+		return fmt.Errorf(
+			"failed adding vmware DeviceType %s with error: %s",
+			hostModel,
+			err,
+		)
+	}
+
+	// Find serial number from host summary.hardware.OtherIdentifyingInfo (vmware specific logic)
+	var hostSerialNumber string
+	serialInfoTypes := map[string]bool{
+		"EnclosureSerialNumberTag": true,
+		"ServiceTag":               true,
+		"SerialNumberTag":          true,
+	}
+	var assetTag string
+	for _, info := range host.Summary.Hardware.OtherIdentifyingInfo {
+		infoType := info.IdentifierType.GetElementDescription().Key
+		infoValue := strings.Trim(info.IdentifierValue, " ") // remove blank spaces from value
+		if infoType == "AssetTag" {
+			if infoValue == "No Asset Tag" {
+				infoValue = ""
+			}
+			if !vc.SourceConfig.IgnoreAssetTags {
+				assetTag = infoValue
+			}
+		} else if serialInfoTypes[infoType] {
+			if info.IdentifierValue != "" {
+				if !vc.SourceConfig.IgnoreSerialNumbers {
+					hostSerialNumber = infoValue
 				}
+				break
 			}
 		}
+	}
 
-		var hostStatus *objects.DeviceStatus
-		switch host.Summary.Runtime.ConnectionState {
-		case "connected":
-			hostStatus = &objects.DeviceStatusActive
-		default:
-			hostStatus = &objects.DeviceStatusOffline
-		}
+	var hostStatus *objects.DeviceStatus
+	switch host.Summary.Runtime.ConnectionState {
+	case "connected":
+		hostStatus = &objects.DeviceStatusActive
+	default:
+		hostStatus = &objects.DeviceStatusOffline
+	}
 
-		var hostPlatform *objects.Platform
-		osType := host.Summary.Config.Product.Name
-		osVersion := host.Summary.Config.Product.Version
-		platformName := utils.GeneratePlatformName(osType, osVersion, "")
-		platformStruct := &objects.Platform{
-			Name: platformName,
-			Slug: utils.Slugify(platformName),
-		}
-		hostPlatform, err = nbi.AddPlatform(vc.Ctx, platformStruct)
-		if err != nil {
-			return fmt.Errorf(
-				"failed adding vmware Platform %+v with error: %s",
-				platformStruct,
-				err,
-			)
-		}
+	var hostPlatform *objects.Platform
+	osType := host.Summary.Config.Product.Name
+	osVersion := host.Summary.Config.Product.Version
+	platformName := utils.GeneratePlatformName(osType, osVersion, "")
+	platformStruct := &objects.Platform{
+		Name: platformName,
+		Slug: utils.Slugify(platformName),
+	}
+	hostPlatform, err = nbi.AddPlatform(vc.Ctx, platformStruct)
+	if err != nil {
+		return fmt.Errorf(
+			"failed adding vmware Platform %+v with error: %s",
+			platformStruct,
+			err,
+		)
+	}
 
-		// Match host to a role. First test if user provided relations, if not
-		// use default server role.
-		var hostRole *objects.DeviceRole
-		if len(vc.SourceConfig.HostRoleRelations) > 0 {
-			hostRole, err = common.MatchHostToRole(
-				vc.Ctx,
-				nbi,
-				hostName,
-				vc.SourceConfig.HostRoleRelations,
-			)
-			if err != nil {
-				return fmt.Errorf("match host to role: %s", err)
-			}
+	// Match host to a role. First test if user provided relations, if not
+	// use default server role.
+	var hostRole *objects.DeviceRole
+	if len(vc.SourceConfig.HostRoleRelations) > 0 {
+		hostRole, err = common.MatchHostToRole(
+			vc.Ctx,
+			nbi,
+			hostName,
+			vc.SourceConfig.HostRoleRelations,
+		)
+		if err != nil {
+			return fmt.Errorf("match host to role: %s", err)
 		}
-		if hostRole == nil {
-			hostRole, err = nbi.AddServerDeviceRole(vc.Ctx)
-			if err != nil {
-				return fmt.Errorf("add server device role %s", err)
-			}
+	}
+	if hostRole == nil {
+		hostRole, err = nbi.AddServerDeviceRole(vc.Ctx)
+		if err != nil {
+			return fmt.Errorf("add server device role %s", err)
 		}
+	}
 
-		hostCPUCores := host.Summary.Hardware.NumCpuCores
-		hostMemGB := host.Summary.Hardware.MemorySize / constants.KiB / constants.KiB / constants.KiB
+	hostCPUCores := host.Summary.Hardware.NumCpuCores
+	hostMemGB := host.Summary.Hardware.MemorySize / constants.KiB / constants.KiB / constants.KiB
 
-		hostStruct := &objects.Device{
-			NetboxObject: objects.NetboxObject{
-				Tags: append(vc.Config.GetSourceTags(), hostTags...),
-				CustomFields: map[string]interface{}{
-					constants.CustomFieldSourceIDName:     hostID,
-					constants.CustomFieldDeviceUUIDName:   hostUUID,
-					constants.CustomFieldHostCPUCoresName: fmt.Sprintf("%d", hostCPUCores),
-					constants.CustomFieldHostMemoryName:   fmt.Sprintf("%d GB", hostMemGB),
-				}},
-			Name:         hostName,
-			Status:       hostStatus,
-			Platform:     hostPlatform,
-			DeviceRole:   hostRole,
-			Site:         hostSite,
-			Tenant:       hostTenant,
-			Cluster:      hostCluster,
-			SerialNumber: hostSerialNumber,
-			AssetTag:     assetTag,
-			DeviceType:   hostDeviceType,
-		}
-		nbHost, err := nbi.AddDevice(vc.Ctx, hostStruct)
-		if err != nil {
-			return fmt.Errorf("failed to add vmware host %+v with error: %v", hostStruct, err)
-		}
+	hostStruct := &objects.Device{
+		NetboxObject: objects.NetboxObject{
+			Tags: append(vc.Config.GetSourceTags(), hostTags...),
+			CustomFields: map[string]interface{}{
+				constants.CustomFieldSourceIDName:     hostID,
+				constants.CustomFieldDeviceUUIDName:   hostUUID,
+				constants.CustomFieldHostCPUCoresName: fmt.Sprintf("%d", hostCPUCores),
+				constants.CustomFieldHostMemoryName:   fmt.Sprintf("%d GB", hostMemGB),
+			}},
+		Name:         hostName,
+		Status:       hostStatus,
+		Platform:     hostPlatform,
+		DeviceRole:   hostRole,
+		Site:         hostSite,
+		Tenant:       hostTenant,
+		Cluster:      hostCluster,
+		SerialNumber: hostSerialNumber,
+		AssetTag:     assetTag,
+		DeviceType:   hostDeviceType,
+	}
+	nbHost, err := nbi.AddDevice(vc.Ctx, hostStruct)
+	if err != nil {
+		return fmt.Errorf("failed to add vmware host %+v with error: %v", hostStruct, err)
+	}
 
-		// We also need to sync nics separately, because nic is a separate object in netbox
-		err = vc.syncHostNics(nbi, host, nbHost, deviceData)
-		if err != nil {
-			return fmt.Errorf("failed to sync vmware host %s nics with error: %v", host.Name, err)
-		}
+	// We also need to sync nics separately, because nic is a separate object in netbox
+	err = vc.syncHostNics(nbi, host, nbHost)
+	if err != nil {
+		return fmt.Errorf("failed to sync vmware host %s nics with error: %v", host.Name, err)
 	}
 	return nil
 }
@@ -421,7 +418,6 @@ func (vc *VmwareSource) syncHostNics(
 	nbi *inventory.NetboxInventory,
 	vcHost mo.HostSystem,
 	nbHost *objects.Device,
-	deviceData *devices.DeviceData,
 ) error {
 	// Variable for storeing all ipAddresses from all host interfaces,
 	// we use them to determine the primary ip of the host.
@@ -429,7 +425,7 @@ func (vc *VmwareSource) syncHostNics(
 	hostIPv6Addresses := []*objects.IPAddress{}
 
 	// Sync host's physical interfaces
-	err := vc.syncHostPhysicalNics(nbi, vcHost, nbHost, deviceData)
+	err := vc.syncHostPhysicalNics(nbi, vcHost, nbHost)
 	if err != nil {
 		return fmt.Errorf("physical interfaces sync: %s", err)
 	}
@@ -453,13 +449,12 @@ func (vc *VmwareSource) syncHostPhysicalNics(
 	nbi *inventory.NetboxInventory,
 	vcHost mo.HostSystem,
 	nbHost *objects.Device,
-	deviceData *devices.DeviceData,
 ) error {
 	// Collect data over all physical interfaces
 	if vcHost.Config != nil && vcHost.Config.Network != nil && vcHost.Config.Network.Pnic != nil {
 		for _, pnic := range vcHost.Config.Network.Pnic {
 			// Fetch host pnic data
-			hostPnic, macAddress, err := vc.collectHostPhysicalNicData(nbi, nbHost, pnic, deviceData)
+			hostPnic, macAddress, err := vc.collectHostPhysicalNicData(nbi, nbHost, pnic)
 			if err != nil {
 				return err
 			}
@@ -496,6 +491,21 @@ func (vc *VmwareSource) syncHostPhysicalNics(
 					return fmt.Errorf("set primary mac for interface %+v: %s", nbHostPnic, err)
 				}
 			}
+
+			// Connect pnic to the switch port, if the switch is already synced
+			if neighbor, ok := vc.HostPnicNeighbors[vcHost.Self.Value][pnic.Device]; ok {
+				_, err := common.AddNeighborCable(
+					vc.Ctx,
+					nbi,
+					nbHostPnic,
+					neighbor.DeviceName,
+					neighbor.PortName,
+					vc.GetSourceTags(),
+				)
+				if err != nil {
+					return fmt.Errorf("add cable for physical interface %s: %s", nbHostPnic.Name, err)
+				}
+			}
 		}
 	}
 	return nil
@@ -507,7 +517,6 @@ func (vc *VmwareSource) collectHostPhysicalNicData(
 	nbi *inventory.NetboxInventory,
 	nbHost *objects.Device,
 	pnic types.PhysicalNic,
-	_ *devices.DeviceData,
 ) (*objects.Interface, string, error) {
 	pnicName := pnic.Device
 	var pnicLinkSpeedMb int32
@@ -604,7 +613,13 @@ func (vc *VmwareSource) collectHostPhysicalNicData(
 				if err != nil {
 					return nil, "", fmt.Errorf("match vlan to group: %s", err)
 				}
-				vlanTenant, err := common.MatchVlanToTenant(vc.Ctx, nbi, vlanName, vc.SourceConfig.VlanTenantRelations)
+				vlanTenant, err := common.MatchVlanToTenant(
+					vc.Ctx,
+					nbi,
+					vlanName,
+					vc.SourceConfig.VlanTenantRelations,
+					vc.SourceConfig.TenantGroupRelations,
+				)
 				if err != nil {
 					return nil, "", fmt.Errorf("match vlan to tenant: %s", err)
 				}
@@ -988,42 +1003,9 @@ func (vc *VmwareSource) collectHostVirtualNicData(
 
 // syncVMs syncs VMs from the source to Netbox.
 func (vc *VmwareSource) syncVMs(nbi *inventory.NetboxInventory) error {
-	const maxGoroutines = 50 // Maximum number of goroutines to run concurrently
-	// Use a guard channel as semaphore to limit the number of goroutines
-	guard := make(chan struct{}, maxGoroutines)
-	// Use errChan to collect errors from goroutines
-	errChan := make(chan error, len(vc.Vms))
-	// Use a WaitGroup to wait for all goroutines to complete
-	var wg sync.WaitGroup
-
-	// Iterate over each VM and start a goroutine to sync it
-	for vmKey, vm := range vc.Vms {
-		guard <- struct{}{} // Block if max goroutines are running
-		wg.Add(1)
-
-		go func(vmKey string, vm mo.VirtualMachine) {
-			defer wg.Done()
-			defer func() { <-guard }() // Release one spot in the semaphore
-
-			err := vc.syncVM(nbi, vmKey, vm)
-			if err != nil {
-				errChan <- err
-			}
-		}(vmKey, vm)
-	}
-
-	// Wait for all goroutines to complete
-	wg.Wait()
-	close(errChan)
-
-	// Collect any errors
-	for err := range errChan {
-		if err != nil {
-			return err
-		}
-	}
-
-	return nil
+	return common.SyncConcurrently(&vc.Config, slices.Sorted(maps.Keys(vc.Vms)), func(vmKey string) error {
+		return vc.syncVM(nbi, vmKey, vc.Vms[vmKey])
+	})
 }
 
 // syncVM synces VM from the source to Netbox.
@@ -1070,7 +1052,13 @@ func (vc *VmwareSource) syncVM(
 	}
 
 	// Tenant is received from VmTenantRelations
-	vmTenant, err := common.MatchVMToTenant(vc.Ctx, nbi, vmName, vc.SourceConfig.VMTenantRelations)
+	vmTenant, err := common.MatchVMToTenant(
+		vc.Ctx,
+		nbi,
+		vmName,
+		vc.SourceConfig.VMTenantRelations,
+		vc.SourceConfig.TenantGroupRelations,
+	)
 	if err != nil {
 		return fmt.Errorf("vm's Tenant: %s", err)
 	}
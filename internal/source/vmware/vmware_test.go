@@ -1 +1,54 @@
 package vmware
+
+import (
+	"testing"
+
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+func TestVmwareSource_Snapshot(t *testing.T) {
+	recorded := &VmwareSource{
+		Vms: map[string]mo.VirtualMachine{
+			"vm-1": {
+				ManagedEntity: mo.ManagedEntity{Name: "vm1"},
+				Config: &types.VirtualMachineConfigInfo{
+					Hardware: types.VirtualHardware{
+						NumCPU: 2, //nolint:mnd
+						Device: []types.BaseVirtualDevice{
+							&types.VirtualE1000{
+								VirtualEthernetCard: types.VirtualEthernetCard{MacAddress: "00:50:56:00:00:01"},
+							},
+						},
+					},
+				},
+			},
+		},
+		VM2Host:            map[string]string{"vm-1": "host-1"},
+		CustomFieldID2Name: map[int32]string{1: "owner"},
+	}
+	data, err := recorded.MarshalSnapshot()
+	if err != nil {
+		t.Fatalf("MarshalSnapshot() error = %v", err)
+	}
+
+	replayed := &VmwareSource{}
+	err = replayed.UnmarshalSnapshot(data)
+	if err != nil {
+		t.Fatalf("UnmarshalSnapshot() error = %v", err)
+	}
+	vm, ok := replayed.Vms["vm-1"]
+	if !ok || vm.Name != "vm1" || vm.Config == nil || vm.Config.Hardware.NumCPU != 2 {
+		t.Fatalf("replayed vm = %+v, want vm1 with 2 cpus", vm)
+	}
+	if len(vm.Config.Hardware.Device) != 1 {
+		t.Fatalf("replayed devices = %v, want 1 device", vm.Config.Hardware.Device)
+	}
+	nic, ok := vm.Config.Hardware.Device[0].(*types.VirtualE1000)
+	if !ok || nic.MacAddress != "00:50:56:00:00:01" {
+		t.Errorf("replayed device = %#v, want VirtualE1000 with mac", vm.Config.Hardware.Device[0])
+	}
+	if replayed.VM2Host["vm-1"] != "host-1" || replayed.CustomFieldID2Name[1] != "owner" {
+		t.Errorf("replayed relations = %v, %v", replayed.VM2Host, replayed.CustomFieldID2Name)
+	}
+}
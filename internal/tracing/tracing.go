@@ -0,0 +1,87 @@
+// Package tracing configures OpenTelemetry tracing of netbox-ssot runs.
+// Spans are exported via OTLP over http to a collector.
+package tracing
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/src-doo/netbox-ssot/internal/constants"
+	"github.com/src-doo/netbox-ssot/internal/parser"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "github.com/src-doo/netbox-ssot"
+
+// Span attributes set by netbox-ssot.
+const (
+	SourceKey     = attribute.Key("netbox_ssot.source")
+	ObjectTypeKey = attribute.Key("netbox_ssot.object_type")
+)
+
+// Setup registers a global tracer provider, that exports spans to the
+// collector configured in tracingConfig. It returns a function that flushes
+// remaining spans and must be called before exiting. If tracingConfig is nil
+// or disabled, spans are not recorded.
+func Setup(ctx context.Context, tracingConfig *parser.TracingConfig) (func(context.Context) error, error) {
+	if tracingConfig == nil || !tracingConfig.Enabled {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	// Unset options fall back to standard OTEL_EXPORTER_OTLP_* env variables
+	options := []otlptracehttp.Option{}
+	if tracingConfig.Endpoint != "" {
+		options = append(options, otlptracehttp.WithEndpoint(tracingConfig.Endpoint))
+	}
+	if tracingConfig.Insecure {
+		options = append(options, otlptracehttp.WithInsecure())
+	}
+	exporter, err := otlptracehttp.New(ctx, options...)
+	if err != nil {
+		return nil, fmt.Errorf("create otlp exporter: %s", err)
+	}
+
+	res, err := resource.Merge(
+		resource.Default(),
+		resource.NewSchemaless(semconv.ServiceName(tracingConfig.ServiceName)),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("create tracing resource: %s", err)
+	}
+
+	tracerProvider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+	)
+	otel.SetTracerProvider(tracerProvider)
+	return tracerProvider.Shutdown, nil
+}
+
+// Start starts a new span with the given name as a child of the span in ctx.
+// Name of the source, stored in ctx, is added as an attribute.
+func Start(
+	ctx context.Context,
+	spanName string,
+	attributes ...attribute.KeyValue,
+) (context.Context, trace.Span) {
+	if sourceName, ok := ctx.Value(constants.CtxSourceKey).(string); ok {
+		attributes = append(attributes, SourceKey.String(sourceName))
+	}
+	return otel.Tracer(tracerName).Start(ctx, spanName, trace.WithAttributes(attributes...))
+}
+
+// End ends the span and marks it as failed, if err is not nil.
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
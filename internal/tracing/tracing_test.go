@@ -0,0 +1,68 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/src-doo/netbox-ssot/internal/constants"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestStart(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+
+	ctx := context.WithValue(context.Background(), constants.CtxSourceKey, "testvmware")
+	ctx, parent := Start(ctx, "source testvmware")
+	_, child := Start(ctx, "AddDevice", ObjectTypeKey.String("Device"))
+	End(child, errors.New("failed"))
+	End(parent, nil)
+
+	spans := recorder.Ended()
+	if len(spans) != 2 { //nolint:mnd
+		t.Fatalf("got %d ended spans, want 2", len(spans))
+	}
+	childSpan, parentSpan := spans[0], spans[1]
+	if childSpan.Name() != "AddDevice" {
+		t.Errorf("span name = %s, want AddDevice", childSpan.Name())
+	}
+	if childSpan.Parent().SpanID() != parentSpan.SpanContext().SpanID() {
+		t.Errorf("AddDevice span is not a child of the source span")
+	}
+	wantAttributes := map[attribute.Key]string{
+		ObjectTypeKey: "Device",
+		SourceKey:     "testvmware",
+	}
+	for _, attr := range childSpan.Attributes() {
+		if want, ok := wantAttributes[attr.Key]; ok {
+			if attr.Value.AsString() != want {
+				t.Errorf("attribute %s = %s, want %s", attr.Key, attr.Value.AsString(), want)
+			}
+			delete(wantAttributes, attr.Key)
+		}
+	}
+	if len(wantAttributes) > 0 {
+		t.Errorf("missing attributes %v", wantAttributes)
+	}
+	if childSpan.Status().Code != codes.Error {
+		t.Errorf("span status = %v, want %v", childSpan.Status().Code, codes.Error)
+	}
+	if parentSpan.Status().Code != codes.Unset {
+		t.Errorf("span status = %v, want %v", parentSpan.Status().Code, codes.Unset)
+	}
+}
+
+func TestSetupDisabled(t *testing.T) {
+	shutdown, err := Setup(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("Setup() error = %v", err)
+	}
+	if err := shutdown(context.Background()); err != nil {
+		t.Errorf("shutdown() error = %v", err)
+	}
+}
@@ -421,9 +421,10 @@ func addStructDiff(
 		return nil
 	}
 
-	// We check if struct is a objects.Choice (special netbox struct)
+	// We check if struct is a objects.Choice (special netbox struct).
+	// Choices are compared by their values, because only values are sent to netbox.
 	if isChoiceEmbedded(newObj) {
-		if !existingObj.IsValid() || newObj.Interface() != existingObj.Interface() {
+		if !existingObj.IsValid() || choiceValue(newObj) != choiceValue(existingObj) {
 			diffMap[jsonTag] = choiceValue(newObj)
 		}
 		return nil
@@ -151,6 +151,17 @@ func TestChoicesAttributesDiff(t *testing.T) {
 				"status": objects.DeviceStatusActive.Value,
 			},
 		},
+		{
+			name:        "Choices with same value and different label",
+			resetFields: false,
+			newStruct: &objects.DeviceType{
+				Airflow: &objects.DeviceAirFlowType{Choice: objects.Choice{Value: "front-to-rear"}},
+			},
+			existingStruct: &objects.DeviceType{
+				Airflow: &objects.FrontToRear,
+			},
+			expectedDiff: map[string]interface{}{},
+		},
 	}
 
 	for _, tt := range tests {
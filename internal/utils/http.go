@@ -4,28 +4,135 @@ import (
 	"crypto/tls"
 	"fmt"
 	"net/http"
+	"net/url"
+	"slices"
+	"strings"
 )
 
-// NewHTTPClient creates an http client with tls config depending on validateCert
-// and caFile parameter.
-func NewHTTPClient(validateCert bool, caFile string) (*http.Client, error) {
-	httpClient := &http.Client{}
-	if validateCert {
-		customCertPool, err := LoadExtraCert(caFile)
+// HTTPClientConfig configures how a client connects to an endpoint
+// over http(s). It is shared by netbox and all of the sources.
+type HTTPClientConfig struct {
+	// ValidateCert enables validation of the server certificate.
+	ValidateCert bool
+	// CAFile is the path of an extra CA certificate to trust.
+	CAFile string
+	// Proxy is the url of the proxy used for all requests. If empty,
+	// HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables are used.
+	Proxy string
+	// ClientCert and ClientKey are paths of the PEM encoded certificate
+	// and key, which the client authenticates itself with (mutual TLS).
+	ClientCert string
+	ClientKey  string
+	// MinTLSVersion is the minimum accepted TLS version, e.g. 1.2.
+	MinTLSVersion string
+	// ServerName overrides the server name sent in SNI,
+	// which is also used to validate the server certificate.
+	ServerName string
+}
+
+// tlsVersions maps supported values of minTLSVersion to TLS versions.
+var tlsVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// ParseTLSVersion returns the TLS version for the version string, e.g. 1.2.
+func ParseTLSVersion(version string) (uint16, error) {
+	tlsVersion, ok := tlsVersions[version]
+	if !ok {
+		versions := make([]string, 0, len(tlsVersions))
+		for v := range tlsVersions {
+			versions = append(versions, v)
+		}
+		slices.Sort(versions)
+		return 0, fmt.Errorf("must be one of %s. Is %s", strings.Join(versions, ", "), version)
+	}
+	return tlsVersion, nil
+}
+
+// ParseProxyURL parses the url of a proxy. Only http, https and socks5 proxies are supported.
+func ParseProxyURL(proxy string) (*url.URL, error) {
+	proxyURL, err := url.Parse(proxy)
+	if err != nil {
+		return nil, err
+	}
+	switch proxyURL.Scheme {
+	case "http", "https", "socks5":
+	default:
+		return nil, fmt.Errorf("scheme must be one of http, https, socks5. Is %q", proxyURL.Scheme)
+	}
+	if proxyURL.Host == "" {
+		return nil, fmt.Errorf("host of proxy %s cannot be empty", proxy)
+	}
+	return proxyURL, nil
+}
+
+// TLSConfig returns the tls config for connecting to the endpoint.
+func (c HTTPClientConfig) TLSConfig() (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		InsecureSkipVerify: !c.ValidateCert, //nolint:gosec
+		ServerName:         c.ServerName,
+	}
+	if c.ValidateCert {
+		customCertPool, err := LoadExtraCert(c.CAFile)
 		if err != nil {
 			return nil, fmt.Errorf("load extra cert: %s", err)
 		}
-		httpClient.Transport = &http.Transport{
-			TLSClientConfig: &tls.Config{
-				RootCAs: customCertPool,
-			},
+		tlsConfig.RootCAs = customCertPool
+	}
+	if c.ClientCert != "" || c.ClientKey != "" {
+		clientCert, err := tls.LoadX509KeyPair(c.ClientCert, c.ClientKey)
+		if err != nil {
+			return nil, fmt.Errorf("load client certificate: %s", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{clientCert}
+	}
+	if c.MinTLSVersion != "" {
+		minVersion, err := ParseTLSVersion(c.MinTLSVersion)
+		if err != nil {
+			return nil, fmt.Errorf("min tls version: %s", err)
 		}
-	} else {
-		httpClient.Transport = &http.Transport{
-			TLSClientConfig: &tls.Config{
-				InsecureSkipVerify: true,
-			},
+		tlsConfig.MinVersion = minVersion
+	}
+	return tlsConfig, nil
+}
+
+// ConfigureTransport sets the proxy and tls config of the transport.
+// It is used for transports created by sdks of the sources.
+func ConfigureTransport(transport *http.Transport, config HTTPClientConfig) error {
+	tlsConfig, err := config.TLSConfig()
+	if err != nil {
+		return err
+	}
+	transport.TLSClientConfig = tlsConfig
+	transport.Proxy = http.ProxyFromEnvironment
+	if config.Proxy != "" {
+		proxyURL, err := ParseProxyURL(config.Proxy)
+		if err != nil {
+			return fmt.Errorf("parse proxy url: %s", err)
 		}
+		transport.Proxy = http.ProxyURL(proxyURL)
+	}
+	return nil
+}
+
+// NewHTTPTransport creates an http transport with default settings
+// of the http package, configured by config.
+func NewHTTPTransport(config HTTPClientConfig) (*http.Transport, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone() //nolint:forcetypeassert
+	if err := ConfigureTransport(transport, config); err != nil {
+		return nil, err
+	}
+	return transport, nil
+}
+
+// NewHTTPClient creates an http client configured by config.
+func NewHTTPClient(config HTTPClientConfig) (*http.Client, error) {
+	transport, err := NewHTTPTransport(config)
+	if err != nil {
+		return nil, err
 	}
-	return httpClient, nil
+	return &http.Client{Transport: transport}, nil
 }
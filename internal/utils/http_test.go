@@ -1,24 +1,35 @@
 package utils
 
 import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
 	"net/http"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 )
 
 func TestNewHTTPClient(t *testing.T) {
-	_, err := NewHTTPClient(false, "")
+	_, err := NewHTTPClient(HTTPClientConfig{})
 	if err != nil {
 		t.Errorf("not expecting error, but got: %s", err)
 	}
 
 	// wrong path
-	_, err = NewHTTPClient(true, "\\//")
+	_, err = NewHTTPClient(HTTPClientConfig{ValidateCert: true, CAFile: "\\//"})
 	if err == nil {
 		t.Error("expected error but got none")
 	}
 
 	// Check if `InsecureSkipVerify` is set correctly
-	insecureClient, err := NewHTTPClient(false, "")
+	insecureClient, err := NewHTTPClient(HTTPClientConfig{})
 	if err != nil {
 		t.Fatalf("Expected no error, got %v", err)
 	}
@@ -26,9 +37,12 @@ func TestNewHTTPClient(t *testing.T) {
 	if transport.TLSClientConfig.InsecureSkipVerify != true {
 		t.Errorf("expected InsecureSkipVerify to be true, got false")
 	}
+	if transport.Proxy == nil {
+		t.Errorf("expected proxy to be taken from environment, got nil")
+	}
 
 	// Check if RootCAs is set when expected
-	certClient, err := NewHTTPClient(true, "")
+	certClient, err := NewHTTPClient(HTTPClientConfig{ValidateCert: true})
 	if err != nil {
 		t.Fatalf("Expected no error, got %v", err)
 	}
@@ -37,3 +51,111 @@ func TestNewHTTPClient(t *testing.T) {
 		t.Errorf("expected RootCAs to be set, got nil")
 	}
 }
+
+func TestNewHTTPTransport(t *testing.T) {
+	certFile, keyFile := writeClientCert(t)
+	transport, err := NewHTTPTransport(HTTPClientConfig{
+		ValidateCert:  true,
+		Proxy:         "http://proxy.example.com:3128",
+		ClientCert:    certFile,
+		ClientKey:     keyFile,
+		MinTLSVersion: "1.3",
+		ServerName:    "netbox.example.com",
+	})
+	if err != nil {
+		t.Fatalf("NewHTTPTransport() error = %v", err)
+	}
+	request, _ := http.NewRequest(http.MethodGet, "https://10.0.0.1/api/", nil)
+	proxyURL, err := transport.Proxy(request)
+	if err != nil || proxyURL == nil || proxyURL.Host != "proxy.example.com:3128" {
+		t.Errorf("Proxy() = %v, %v, want proxy.example.com:3128", proxyURL, err)
+	}
+	tlsConfig := transport.TLSClientConfig
+	if len(tlsConfig.Certificates) != 1 {
+		t.Errorf("expected client certificate to be set, got %d certificates", len(tlsConfig.Certificates))
+	}
+	if tlsConfig.MinVersion != tls.VersionTLS13 {
+		t.Errorf("MinVersion = %x, want %x", tlsConfig.MinVersion, tls.VersionTLS13)
+	}
+	if tlsConfig.ServerName != "netbox.example.com" {
+		t.Errorf("ServerName = %s, want netbox.example.com", tlsConfig.ServerName)
+	}
+}
+
+func TestNewHTTPTransport_Errors(t *testing.T) {
+	tests := []struct {
+		name   string
+		config HTTPClientConfig
+	}{
+		{
+			name:   "Invalid proxy scheme",
+			config: HTTPClientConfig{Proxy: "ftp://proxy.example.com"},
+		},
+		{
+			name:   "Proxy without host",
+			config: HTTPClientConfig{Proxy: "http://"},
+		},
+		{
+			name:   "Missing client key",
+			config: HTTPClientConfig{ClientCert: "../../testdata/certificate/cert.pem"},
+		},
+		{
+			name:   "Invalid tls version",
+			config: HTTPClientConfig{MinTLSVersion: "1.4"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewHTTPTransport(tt.config); err == nil {
+				t.Errorf("NewHTTPTransport() expected error, got none")
+			}
+		})
+	}
+}
+
+func TestParseTLSVersion(t *testing.T) {
+	version, err := ParseTLSVersion("1.2")
+	if err != nil || version != tls.VersionTLS12 {
+		t.Errorf("ParseTLSVersion() = %x, %v, want %x", version, err, tls.VersionTLS12)
+	}
+	_, err = ParseTLSVersion("2")
+	if err == nil || err.Error() != "must be one of 1.0, 1.1, 1.2, 1.3. Is 2" {
+		t.Errorf("ParseTLSVersion() error = %v", err)
+	}
+}
+
+// writeClientCert writes a self signed certificate and its key to a temporary dir.
+func writeClientCert(t *testing.T) (string, string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "netbox-ssot"},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	certDER, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "client.pem")
+	keyFile := filepath.Join(dir, "client.key")
+	err = os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certDER}), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return certFile, keyFile
+}
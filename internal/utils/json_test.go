@@ -68,6 +68,9 @@ func TestExtractJSONTagsFromStruct(t *testing.T) {
 				"primary_ip6",
 				"cluster",
 				"tenant",
+				"virtual_chassis",
+				"vc_position",
+				"vc_priority",
 				"comments",
 			},
 		},
@@ -84,6 +87,7 @@ func TestExtractJSONTagsFromStruct(t *testing.T) {
 				"virtual_machine",
 				"name",
 				"primary_mac_address",
+				"mac_address",
 				"mtu",
 				"enabled",
 				"parent",
@@ -113,6 +117,7 @@ func TestExtractJSONTagsFromStruct(t *testing.T) {
 				"lag",
 				"mtu",
 				"primary_mac_address",
+				"mac_address",
 				"duplex",
 				"mode",
 				"tagged_vlans",
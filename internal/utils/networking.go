@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"net"
+	"net/netip"
 	"strings"
 	"time"
 
@@ -152,3 +153,46 @@ func GetPrefixAndMaskFromIPAddress(ipAddress string) (string, int, error) {
 	maskBits, _ := ipNet.Mask.Size()
 	return ipNet.String(), maskBits, err
 }
+
+// GetHostRangeFromPrefix returns the first and the last host address of the prefix.
+// Network and broadcast addresses are excluded for IPv4 prefixes with more than two addresses:
+// 192.168.1.0/24 --> (192.168.1.1, 192.168.1.254).
+func GetHostRangeFromPrefix(prefix string) (string, string, error) {
+	parsedPrefix, err := netip.ParsePrefix(prefix)
+	if err != nil {
+		return "", "", err
+	}
+	parsedPrefix = parsedPrefix.Masked()
+	first := parsedPrefix.Addr()
+	lastBytes := first.AsSlice()
+	for i := range lastBytes {
+		networkBits := parsedPrefix.Bits() - i*8 //nolint:mnd
+		switch {
+		case networkBits <= 0:
+			lastBytes[i] = 0xff
+		case networkBits < 8: //nolint:mnd
+			lastBytes[i] |= 0xff >> networkBits
+		}
+	}
+	last, _ := netip.AddrFromSlice(lastBytes)
+	if first.Is4() && parsedPrefix.Bits() < 31 {
+		first, last = first.Next(), last.Prev()
+	}
+	return first.String(), last.String(), nil
+}
+
+// IPRangesOverlap returns true if the ranges of addresses from start1 to end1
+// and from start2 to end2 share at least one address. Addresses may contain a mask:
+// (10.0.0.10/24, 10.0.0.20/24) and (10.0.0.15/24, 10.0.0.30/24) --> true.
+func IPRangesOverlap(start1, end1, start2, end2 string) (bool, error) {
+	var addresses [4]netip.Addr
+	for i, address := range []string{start1, end1, start2, end2} {
+		var err error
+		addresses[i], err = netip.ParseAddr(strings.Split(address, "/")[0])
+		if err != nil {
+			return false, err
+		}
+	}
+	start1Addr, end1Addr, start2Addr, end2Addr := addresses[0], addresses[1], addresses[2], addresses[3]
+	return start1Addr.Compare(end2Addr) <= 0 && start2Addr.Compare(end1Addr) <= 0, nil
+}
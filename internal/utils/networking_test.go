@@ -513,3 +513,61 @@ func TestSerializeMask(t *testing.T) {
 		})
 	}
 }
+
+func TestGetHostRangeFromPrefix(t *testing.T) {
+	tests := []struct {
+		name      string
+		prefix    string
+		wantFirst string
+		wantLast  string
+		wantErr   bool
+	}{
+		{"IPv4 prefix", "192.168.1.0/24", "192.168.1.1", "192.168.1.254", false},
+		{"IPv4 prefix with host bits set", "10.0.0.77/26", "10.0.0.65", "10.0.0.126", false},
+		{"IPv4 point to point prefix", "10.0.0.0/31", "10.0.0.0", "10.0.0.1", false},
+		{"IPv4 host prefix", "10.0.0.1/32", "10.0.0.1", "10.0.0.1", false},
+		{"IPv6 prefix", "2001:db8::/120", "2001:db8::", "2001:db8::ff", false},
+		{"Invalid prefix", "10.0.0.0", "", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			first, last, err := GetHostRangeFromPrefix(tt.prefix)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetHostRangeFromPrefix() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if first != tt.wantFirst || last != tt.wantLast {
+				t.Errorf("GetHostRangeFromPrefix() = (%s, %s), want (%s, %s)", first, last, tt.wantFirst, tt.wantLast)
+			}
+		})
+	}
+}
+
+func TestIPRangesOverlap(t *testing.T) {
+	tests := []struct {
+		name    string
+		start1  string
+		end1    string
+		start2  string
+		end2    string
+		want    bool
+		wantErr bool
+	}{
+		{"Same range", "10.0.0.10/24", "10.0.0.20/24", "10.0.0.10/24", "10.0.0.20/24", true, false},
+		{"Moved start address", "10.0.0.10/24", "10.0.0.20/24", "10.0.0.15/24", "10.0.0.30/24", true, false},
+		{"Contained range", "10.0.0.10", "10.0.0.200", "10.0.0.50", "10.0.0.60", true, false},
+		{"Adjacent ranges", "10.0.0.10/24", "10.0.0.20/24", "10.0.0.21/24", "10.0.0.30/24", false, false},
+		{"IPv6 ranges", "2001:db8::10/64", "2001:db8::20/64", "2001:db8::20/64", "2001:db8::30/64", true, false},
+		{"Invalid address", "10.0.0.10/24", "10.0.0.20/24", "invalid", "10.0.0.30/24", false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := IPRangesOverlap(tt.start1, tt.end1, tt.start2, tt.end2)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("IPRangesOverlap() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("IPRangesOverlap() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}